@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/yuritomanek/seekarr/internal/api"
+	"github.com/yuritomanek/seekarr/internal/processor"
+)
+
+// daemon schedules processor runs and implements api.Controller
+type daemon struct {
+	ctx      context.Context
+	proc     *processor.Processor
+	interval time.Duration
+	logger   *slog.Logger
+
+	// running holds a single token; a run may only start after taking it
+	running chan struct{}
+
+	mu      sync.Mutex
+	paused  bool
+	nextRun time.Time
+}
+
+func newDaemon(ctx context.Context, proc *processor.Processor, interval time.Duration, logger *slog.Logger) *daemon {
+	d := &daemon{
+		ctx:      ctx,
+		proc:     proc,
+		interval: interval,
+		logger:   logger,
+		running:  make(chan struct{}, 1),
+	}
+	d.running <- struct{}{} // Initially not running (token available)
+	return d
+}
+
+// TriggerRun starts a processor run in the background
+// Returns api.ErrRunActive if a run is already in progress
+func (d *daemon) TriggerRun() error {
+	select {
+	case <-d.running:
+		// Acquired the token, start the run
+		go func() {
+			defer func() {
+				d.running <- struct{}{} // Release token when done
+			}()
+
+			if err := d.proc.Run(d.ctx); err != nil && err != context.Canceled {
+				d.logger.Error("processor failed", "error", err)
+			} else if err == nil {
+				d.logger.Info("processor completed successfully")
+			}
+		}()
+		return nil
+	default:
+		return api.ErrRunActive
+	}
+}
+
+// wait blocks until no run is in progress or the timeout passes
+// It reports whether the run finished in time
+func (d *daemon) wait(timeout time.Duration) bool {
+	select {
+	case token := <-d.running:
+		d.running <- token
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// scheduledRun is called on every tick; it honours the paused flag
+func (d *daemon) scheduledRun() {
+	d.mu.Lock()
+	d.nextRun = time.Now().Add(d.interval)
+	paused := d.paused
+	d.mu.Unlock()
+
+	if paused {
+		d.logger.Info("skipping scheduled run - daemon is paused")
+		return
+	}
+
+	d.logger.Info("starting periodic processor run")
+	if err := d.TriggerRun(); err != nil {
+		d.logger.Warn("skipping scheduled run - processor is still running from previous interval")
+	}
+}
+
+// Pause stops scheduled runs until Resume is called
+// A run already in progress is left to finish
+func (d *daemon) Pause() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.paused = true
+}
+
+// Resume re-enables scheduled runs
+func (d *daemon) Resume() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.paused = false
+}
+
+// Status reports the processor status together with scheduler state
+func (d *daemon) Status() api.Status {
+	d.mu.Lock()
+	paused := d.paused
+	nextRun := d.nextRun
+	d.mu.Unlock()
+
+	status := api.Status{
+		Status:  d.proc.Status(),
+		Running: len(d.running) == 0,
+		Paused:  paused,
+	}
+	if !paused && !nextRun.IsZero() {
+		status.NextRun = &nextRun
+	}
+	return status
+}
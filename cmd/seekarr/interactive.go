@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/yuritomanek/seekarr/internal/processor"
+)
+
+// console serializes log output and holds it back while a prompt is waiting
+// for an answer, so the prompt isn't buried under log lines from other albums
+type console struct {
+	mu   sync.Mutex
+	w    io.Writer
+	held bool
+	buf  bytes.Buffer
+}
+
+func newConsole(w io.Writer) *console {
+	return &console{w: w}
+}
+
+func (c *console) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.held {
+		return c.buf.Write(p)
+	}
+	return c.w.Write(p)
+}
+
+// hold buffers log output until release
+func (c *console) hold() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.held = true
+}
+
+// release writes the log output held back since hold
+func (c *console) release() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.held = false
+	if _, err := c.buf.WriteTo(c.w); err != nil {
+		c.buf.Reset()
+	}
+}
+
+// promptApprover asks on the terminal before each match is enqueued
+type promptApprover struct {
+	mu      sync.Mutex // One prompt at a time when albums are searched concurrently
+	in      *bufio.Reader
+	out     io.Writer
+	console *console
+}
+
+func newPromptApprover(in io.Reader, out io.Writer, console *console) *promptApprover {
+	return &promptApprover{in: bufio.NewReader(in), out: out, console: console}
+}
+
+func (a *promptApprover) Approve(ctx context.Context, match processor.Match) (processor.Approval, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.console.hold()
+	defer a.console.release()
+
+	printMatch(a.out, match)
+	for {
+		fmt.Fprint(a.out, "Download? [y]es / [n]o / [s]kip all: ")
+
+		answer, err := a.readLine(ctx)
+		if errors.Is(err, io.EOF) {
+			// Without a terminal nothing more can be approved
+			fmt.Fprintln(a.out)
+			return processor.DeclineAll, nil
+		}
+		if err != nil {
+			return processor.DeclineAll, err
+		}
+
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "yes":
+			return processor.Approve, nil
+		case "n", "no":
+			return processor.Decline, nil
+		case "s", "skip", "skip-all", "skip all":
+			return processor.DeclineAll, nil
+		}
+	}
+}
+
+// readLine reads an answer, giving up when ctx is cancelled
+// The read itself can't be interrupted, so a cancelled prompt leaves it pending
+func (a *promptApprover) readLine(ctx context.Context) (string, error) {
+	type line struct {
+		text string
+		err  error
+	}
+	read := make(chan line, 1)
+	go func() {
+		text, err := a.in.ReadString('\n')
+		if err != nil && text != "" {
+			err = nil // Answer on the last line without a newline
+		}
+		read <- line{text, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		fmt.Fprintln(a.out)
+		return "", ctx.Err()
+	case l := <-read:
+		return l.text, l.err
+	}
+}
+
+// printMatch shows a match grouped by the user and directory its files come from
+func printMatch(w io.Writer, match processor.Match) {
+	fmt.Fprintf(w, "\n%s - %s\n", match.Artist, match.Album)
+	fmt.Fprintf(w, "  %d files for %d tracks, %s, match ratio %.2f\n",
+		len(match.Files), match.Tracks, formatBytes(match.TotalSize), match.Ratio)
+
+	var source string
+	for _, file := range match.Files {
+		if next := file.Username + "\x00" + file.Directory; next != source {
+			source = next
+			fmt.Fprintf(w, "  %s: %s\n", file.Username, file.Directory)
+		}
+		fmt.Fprintf(w, "    %-10s %9s  %s\n", file.Quality, formatBytes(file.Size), file.Filename)
+	}
+}
+
+// formatBytes renders a size in MB, or KB for small files
+func formatBytes(size int64) string {
+	if size < 1024*1024 {
+		return fmt.Sprintf("%d KB", size/1024)
+	}
+	return fmt.Sprintf("%.1f MB", float64(size)/(1024*1024))
+}
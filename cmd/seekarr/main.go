@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -9,14 +10,19 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/yuritomanek/seekarr/internal/api"
 	"github.com/yuritomanek/seekarr/internal/config"
+	"github.com/yuritomanek/seekarr/internal/httpclient"
 	"github.com/yuritomanek/seekarr/internal/lidarr"
 	"github.com/yuritomanek/seekarr/internal/processor"
 	"github.com/yuritomanek/seekarr/internal/slskd"
 	"github.com/yuritomanek/seekarr/internal/state"
+	"github.com/yuritomanek/seekarr/internal/telemetry"
 )
 
 // Version information (set by goreleaser at build time)
@@ -34,6 +40,10 @@ func main() {
 func run() int {
 	// Parse command line flags
 	showVersion := flag.Bool("version", false, "Show version information and exit")
+	dryRun := flag.Bool("dry-run", false, "Search and match albums without downloading anything")
+	var albumIDs albumIDList
+	flag.Var(&albumIDs, "album-id", "Search for this Lidarr album ID instead of the wanted list, even if denylisted (repeatable)")
+	interactive := flag.Bool("interactive", false, "Ask before enqueueing each matched album (single-run mode only)")
 	flag.Parse()
 
 	if *showVersion {
@@ -44,7 +54,19 @@ func run() int {
 	}
 
 	// Set up structured logging
-	logger := setupLogger()
+	console := newConsole(os.Stdout)
+	logger := setupLogger(console)
+
+	// Subcommands
+	if flag.NArg() > 0 {
+		switch flag.Arg(0) {
+		case "migrate-soularr":
+			return runMigrateSoularr(flag.Args()[1:], logger)
+		default:
+			logger.Error("unknown command", "command", flag.Arg(0))
+			return 2
+		}
+	}
 
 	logger.Info("starting seekarr", "version", version)
 
@@ -55,13 +77,26 @@ func run() int {
 		return 1
 	}
 
+	if *dryRun {
+		cfg.DryRun = true
+	}
+	if cfg.DryRun {
+		logger.Info("dry run: albums will be searched and matched but nothing will be downloaded")
+	}
+
+	// Prompts need someone at the terminal, which a daemon doesn't have
+	if *interactive && cfg.Daemon.Enabled && len(albumIDs) == 0 {
+		logger.Error("--interactive only works in single-run mode; disable daemon mode or use --album-id")
+		return 2
+	}
+
 	logger.Info("configuration loaded",
 		"lidarr_url", cfg.Lidarr.HostURL,
 		"slskd_url", cfg.Slskd.HostURL,
 		"search_type", cfg.Search.SearchType)
 
 	// Acquire lock file to prevent concurrent runs
-	lockPath := filepath.Join(cfg.Slskd.DownloadDir, ".seekarr.lock")
+	lockPath := filepath.Join(cfg.LocalDownloadDir(), ".seekarr.lock")
 	lockFile := state.NewLockFile(lockPath)
 
 	if err := lockFile.Acquire(); err != nil {
@@ -77,19 +112,78 @@ func run() int {
 
 	logger.Info("lock file acquired", "path", lockPath)
 
-	// Create API clients
+	// Set up tracing (no-op unless an OTLP endpoint is configured)
+	shutdownTracing, err := telemetry.Setup(context.Background(), cfg.Telemetry.OTLPEndpoint, version)
+	if err != nil {
+		logger.Error("failed to set up tracing", "error", err)
+		return 1
+	}
+	defer func() {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer shutdownCancel()
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			logger.Warn("failed to flush traces", "error", err)
+		}
+	}()
+
+	// Create API clients. The TLS settings were checked when the config was loaded
+	lidarrTLS, _ := cfg.Lidarr.ClientConfig()
+	slskdTLS, _ := cfg.Slskd.ClientConfig()
+	lidarrOpts := []lidarr.Option{
+		lidarr.WithRetry(lidarr.RetryPolicy{MaxRetries: cfg.Lidarr.RequestRetries()}),
+		lidarr.WithLogger(logger),
+		lidarr.WithTimeout(cfg.Lidarr.RequestTimeout()),
+		lidarr.WithTLSConfig(lidarrTLS),
+		lidarr.WithConnectionPool(httpclient.ConnectionPool{
+			MaxIdleConns:    cfg.Lidarr.MaxIdleConns,
+			IdleConnTimeout: cfg.Lidarr.IdleConnTimeout(),
+			DisableHTTP2:    cfg.Lidarr.DisableHTTP2,
+		}),
+	}
+	slskdOpts := []slskd.Option{
+		slskd.WithRetry(slskd.RetryPolicy{MaxAttempts: cfg.Slskd.RequestAttempts(), RetryPOST: cfg.Slskd.RetryPosts}),
+		slskd.WithLogger(logger),
+		slskd.WithTimeout(cfg.Slskd.RequestTimeout()),
+		slskd.WithTLSConfig(slskdTLS),
+		slskd.WithConnectionPool(httpclient.ConnectionPool{
+			MaxIdleConns:    cfg.Slskd.MaxIdleConns,
+			IdleConnTimeout: cfg.Slskd.IdleConnTimeout(),
+			DisableHTTP2:    cfg.Slskd.DisableHTTP2,
+		}),
+	}
+	if cfg.Slskd.Username != "" {
+		slskdOpts = append(slskdOpts, slskd.WithCredentials(cfg.Slskd.Username, cfg.Slskd.Password))
+	}
+	if cfg.Lidarr.SkipVerify || cfg.Slskd.SkipVerify {
+		logger.Warn("TLS certificate verification disabled", "lidarr", cfg.Lidarr.SkipVerify, "slskd", cfg.Slskd.SkipVerify)
+	}
+	if cfg.Telemetry.OTLPEndpoint != "" {
+		logger.Info("tracing enabled", "otlp_endpoint", cfg.Telemetry.OTLPEndpoint)
+		lidarrOpts = append(lidarrOpts, lidarr.WrapTransport(telemetry.Transport))
+		slskdOpts = append(slskdOpts, slskd.WrapTransport(telemetry.Transport))
+	}
+
 	lidarrClient := lidarr.NewClient(
 		cfg.Lidarr.HostURL,
 		cfg.Lidarr.APIKey,
+		cfg.Lidarr.URLBase,
+		lidarrOpts...,
 	)
 
 	slskdClient := slskd.NewClient(
 		cfg.Slskd.HostURL,
 		cfg.Slskd.APIKey,
 		cfg.Slskd.URLBase,
+		slskdOpts...,
 	)
 
 	// Verify connectivity
+	logger.Info("verifying connectivity to lidarr")
+	if err := verifyLidarrConnection(lidarrClient); err != nil {
+		logger.Error("failed to connect to lidarr", "error", err)
+		return 1
+	}
+
 	logger.Info("verifying connectivity to slskd")
 	if err := verifySlskdConnection(slskdClient); err != nil {
 		logger.Error("failed to connect to slskd", "error", err)
@@ -103,6 +197,16 @@ func run() int {
 		return 1
 	}
 
+	// Progress is worth seeing on the console but would flood structured logs
+	if _, ok := logger.Handler().(*cleanHandler); ok {
+		proc.SetProgressLevel(slog.LevelInfo)
+	}
+
+	if *interactive && !cfg.DryRun {
+		proc.SetApprover(newPromptApprover(os.Stdin, os.Stdout, console))
+		logger.Info("interactive mode: each match needs approval before it is downloaded")
+	}
+
 	// Set up context with cancellation for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -111,6 +215,14 @@ func run() int {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
+	// Grab the requested albums once, even when daemon mode is configured
+	if len(albumIDs) > 0 {
+		logger.Info("processing requested albums", "album_ids", albumIDs.String())
+		return runOnce(ctx, cancel, func(ctx context.Context) error {
+			return proc.RunAlbums(ctx, albumIDs)
+		}, sigChan, logger)
+	}
+
 	// Run processor - either once or in daemon mode
 	if cfg.Daemon.Enabled {
 		logger.Info("starting daemon mode", "interval_minutes", cfg.Daemon.IntervalMinutes)
@@ -118,15 +230,35 @@ func run() int {
 	}
 
 	// Single run mode
-	return runOnce(ctx, cancel, proc, sigChan, logger)
+	return runOnce(ctx, cancel, proc.Run, sigChan, logger)
+}
+
+// albumIDList collects the Lidarr album IDs given with repeated --album-id flags
+type albumIDList []int
+
+func (l *albumIDList) String() string {
+	ids := make([]string, len(*l))
+	for i, id := range *l {
+		ids[i] = strconv.Itoa(id)
+	}
+	return strings.Join(ids, ",")
+}
+
+func (l *albumIDList) Set(value string) error {
+	id, err := strconv.Atoi(value)
+	if err != nil || id <= 0 {
+		return fmt.Errorf("invalid album ID %q", value)
+	}
+	*l = append(*l, id)
+	return nil
 }
 
 // runOnce executes a single processor run
-func runOnce(ctx context.Context, cancel context.CancelFunc, proc *processor.Processor, sigChan chan os.Signal, logger *slog.Logger) int {
+func runOnce(ctx context.Context, cancel context.CancelFunc, run func(context.Context) error, sigChan chan os.Signal, logger *slog.Logger) int {
 	// Run processor in goroutine
 	errChan := make(chan error, 1)
 	go func() {
-		errChan <- proc.Run(ctx)
+		errChan <- run(ctx)
 	}()
 
 	// Wait for completion or signal
@@ -156,36 +288,31 @@ func runOnce(ctx context.Context, cancel context.CancelFunc, proc *processor.Pro
 
 // runDaemon executes the processor in a loop with periodic intervals
 func runDaemon(ctx context.Context, cancel context.CancelFunc, proc *processor.Processor, sigChan chan os.Signal, cfg *config.Config, logger *slog.Logger) int {
-	ticker := time.NewTicker(time.Duration(cfg.Daemon.IntervalMinutes) * time.Minute)
+	interval := time.Duration(cfg.Daemon.IntervalMinutes) * time.Minute
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
-	// Track whether a processor run is currently active
-	running := make(chan struct{}, 1)
-	running <- struct{}{} // Initially not running (token available)
+	d := newDaemon(ctx, proc, interval, logger)
+	shutdownTimeout := time.Duration(cfg.Daemon.ShutdownTimeoutSeconds) * time.Second
 
-	// Helper function to run processor asynchronously
-	runProcessor := func() {
-		select {
-		case <-running:
-			// Acquired the token, start the run
-			go func() {
-				defer func() {
-					running <- struct{}{} // Release token when done
-				}()
-
-				if err := proc.Run(ctx); err != nil && err != context.Canceled {
-					logger.Error("processor failed", "error", err)
-				} else if err == nil {
-					logger.Info("processor completed successfully")
-				}
-			}()
-		default:
-			logger.Warn("skipping scheduled run - processor is still running from previous interval")
+	// Start the control API alongside the scheduler
+	if cfg.API.Enabled {
+		server := api.NewServer(cfg.API.Listen, cfg.API.Token, d, logger)
+		if err := server.Start(); err != nil {
+			logger.Error("failed to start control api", "error", err)
+			return 1
 		}
+		defer func() {
+			shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer shutdownCancel()
+			if err := server.Shutdown(shutdownCtx); err != nil {
+				logger.Warn("failed to shut down control api", "error", err)
+			}
+		}()
 	}
 
 	// Run immediately on startup
-	runProcessor()
+	d.scheduledRun()
 
 	for {
 		select {
@@ -196,15 +323,16 @@ func runDaemon(ctx context.Context, cancel context.CancelFunc, proc *processor.P
 				logger.Info("context cancelled, skipping scheduled run")
 				return 0
 			default:
-				logger.Info("starting periodic processor run")
-				runProcessor()
+				d.scheduledRun()
 			}
 
 		case sig := <-sigChan:
 			logger.Warn("received signal, shutting down daemon", "signal", sig)
 			cancel()
-			// Give processor a moment to finish cleanup (but don't block indefinitely)
-			time.Sleep(500 * time.Millisecond)
+			// Let a run in progress finish its cleanup, but don't block indefinitely
+			if !d.wait(shutdownTimeout) {
+				logger.Warn("run did not finish cleaning up before the shutdown timeout", "timeout", shutdownTimeout)
+			}
 			logger.Info("shutdown complete")
 			return 0
 
@@ -216,7 +344,8 @@ func runDaemon(ctx context.Context, cancel context.CancelFunc, proc *processor.P
 }
 
 // setupLogger creates a structured logger with appropriate output format
-func setupLogger() *slog.Logger {
+// Output goes through console so interactive prompts can hold it back
+func setupLogger(console *console) *slog.Logger {
 	var handler slog.Handler
 	opts := &slog.HandlerOptions{
 		Level: slog.LevelInfo,
@@ -232,13 +361,13 @@ func setupLogger() *slog.Logger {
 	switch logFormat {
 	case "json":
 		// Full structured JSON output
-		handler = slog.NewJSONHandler(os.Stdout, opts)
+		handler = slog.NewJSONHandler(console, opts)
 	case "structured":
 		// Full structured text output with timestamps
-		handler = slog.NewTextHandler(os.Stdout, opts)
+		handler = slog.NewTextHandler(console, opts)
 	default:
 		// Clean output for CLI usage
-		handler = newCleanHandler(os.Stdout, opts)
+		handler = newCleanHandler(console, opts)
 	}
 
 	return slog.New(handler)
@@ -365,14 +494,43 @@ func loadConfig(logger *slog.Logger) (*config.Config, error) {
 	return cfg, nil
 }
 
+// verifyLidarrConnection checks that we can reach Lidarr with the configured API key
+func verifyLidarrConnection(client lidarr.Client) error {
+	status, err := client.GetSystemStatus(context.Background())
+	if errors.Is(err, lidarr.ErrUnauthorized) {
+		return fmt.Errorf("get lidarr system status, check lidarr.api_key: %w", err)
+	}
+	if err != nil {
+		return fmt.Errorf("get lidarr system status: %w", err)
+	}
+
+	slog.Info("connected to lidarr", "version", status.Version)
+	return nil
+}
+
 // verifySlskdConnection checks that we can connect to slskd
 func verifySlskdConnection(client slskd.Client) error {
 	ctx := context.Background()
 	version, err := client.GetVersion(ctx)
+	if errors.Is(err, slskd.ErrUnauthorized) {
+		return fmt.Errorf("get slskd version, check slskd.api_key or slskd.username and password: %w", err)
+	}
 	if err != nil {
 		return fmt.Errorf("get slskd version: %w", err)
 	}
+	if err := slskd.CheckVersion(version); err != nil {
+		return err
+	}
 
-	slog.Info("connected to slskd", "version", version)
+	state, err := client.GetServerState(ctx)
+	if err != nil {
+		return fmt.Errorf("get slskd server state: %w", err)
+	}
+
+	slog.Info("connected to slskd", "version", version, "serverState", state.State)
+	if !state.Ready() {
+		// Runs are skipped until slskd logs in again
+		slog.Warn("slskd is not connected to Soulseek", "state", state.State)
+	}
 	return nil
 }
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/yuritomanek/seekarr/internal/config"
+)
+
+// runMigrateSoularr converts a Soularr config.ini into a seekarr config.yaml
+func runMigrateSoularr(args []string, logger *slog.Logger) int {
+	fs := flag.NewFlagSet("migrate-soularr", flag.ContinueOnError)
+	output := fs.String("o", "config.yaml", "Path to write the converted config to")
+	force := fs.Bool("force", false, "Overwrite the output file if it exists")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: seekarr migrate-soularr [-o config.yaml] [-force] <path-to-config.ini>")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	inputPath := fs.Arg(0)
+
+	if _, err := os.Stat(*output); err == nil && !*force {
+		logger.Error("output file already exists, use -force to overwrite", "path", *output)
+		return 1
+	}
+
+	f, err := os.Open(inputPath)
+	if err != nil {
+		logger.Error("failed to open soularr config", "error", err)
+		return 1
+	}
+	defer f.Close()
+
+	cfg, warnings, err := config.FromSoularr(f)
+	for _, warning := range warnings {
+		logger.Warn(warning)
+	}
+	if err != nil {
+		logger.Error("failed to convert soularr config", "path", inputPath, "error", err)
+		return 1
+	}
+
+	if err := cfg.Save(*output); err != nil {
+		logger.Error("failed to write config", "error", err)
+		return 1
+	}
+
+	logger.Info("converted soularr config", "from", inputPath, "to", *output, "warnings", len(warnings))
+	return 0
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/yuritomanek/seekarr/internal/processor"
+)
+
+// ErrRunActive is returned by Controller.TriggerRun when a run is already in progress
+var ErrRunActive = errors.New("a run is already in progress")
+
+// Controller is implemented by the daemon loop the API drives
+type Controller interface {
+	TriggerRun() error
+	Pause()
+	Resume()
+	Status() Status
+}
+
+// Status is the response body of GET /api/status
+type Status struct {
+	processor.Status
+	Running bool       `json:"running"`
+	Paused  bool       `json:"paused"`
+	NextRun *time.Time `json:"next_run,omitempty"`
+}
+
+// Server exposes the daemon control endpoints over HTTP
+// Other endpoints can be mounted on the same listener with Handle
+type Server struct {
+	mux        *http.ServeMux
+	httpServer *http.Server
+	token      string
+	controller Controller
+	logger     *slog.Logger
+}
+
+// NewServer creates a control API server listening on addr
+// All /api/ endpoints require "Authorization: Bearer <token>"
+func NewServer(addr, token string, controller Controller, logger *slog.Logger) *Server {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	s := &Server{
+		mux:        http.NewServeMux(),
+		token:      token,
+		controller: controller,
+		logger:     logger,
+	}
+
+	s.mux.Handle("POST /api/run", s.authenticated(s.handleRun))
+	s.mux.Handle("POST /api/pause", s.authenticated(s.handlePause))
+	s.mux.Handle("POST /api/resume", s.authenticated(s.handleResume))
+	s.mux.Handle("GET /api/status", s.authenticated(s.handleStatus))
+
+	s.httpServer = &http.Server{
+		Addr:              addr,
+		Handler:           s.mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	return s
+}
+
+// Handle mounts an additional handler on the server's listener
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Handler returns the root handler (used by tests)
+func (s *Server) Handler() http.Handler {
+	return s.mux
+}
+
+// Start begins listening in the background
+// It returns once the listener is bound so address errors surface immediately
+func (s *Server) Start() error {
+	ln, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", s.httpServer.Addr, err)
+	}
+
+	go func() {
+		if err := s.httpServer.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("api server stopped", "error", err)
+		}
+	}()
+
+	s.logger.Info("control api listening", "address", ln.Addr().String())
+	return nil
+}
+
+// Shutdown gracefully stops the server
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.httpServer.Shutdown(ctx)
+}
+
+// authenticated wraps a handler with bearer token checking
+func (s *Server) authenticated(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+			return
+		}
+		next(w, r)
+	})
+}
+
+func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
+	if err := s.controller.TriggerRun(); err != nil {
+		if errors.Is(err, ErrRunActive) {
+			writeJSON(w, http.StatusConflict, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	s.logger.Info("run triggered via api")
+	writeJSON(w, http.StatusAccepted, map[string]string{"status": "started"})
+}
+
+func (s *Server) handlePause(w http.ResponseWriter, r *http.Request) {
+	s.controller.Pause()
+	s.logger.Info("scheduled runs paused via api")
+	writeJSON(w, http.StatusOK, map[string]string{"status": "paused"})
+}
+
+func (s *Server) handleResume(w http.ResponseWriter, r *http.Request) {
+	s.controller.Resume()
+	s.logger.Info("scheduled runs resumed via api")
+	writeJSON(w, http.StatusOK, map[string]string{"status": "resumed"})
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.controller.Status())
+}
+
+// writeJSON writes v as a JSON response with the given status code
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/processor"
+)
+
+// mockController records calls from the API
+type mockController struct {
+	running bool
+	paused  bool
+	runs    int
+}
+
+func (m *mockController) TriggerRun() error {
+	if m.running {
+		return ErrRunActive
+	}
+	m.running = true
+	m.runs++
+	return nil
+}
+
+func (m *mockController) Pause()  { m.paused = true }
+func (m *mockController) Resume() { m.paused = false }
+
+func (m *mockController) Status() Status {
+	return Status{
+		Status:  processor.Status{Phase: processor.PhaseSearching, DenylistCount: 3},
+		Running: m.running,
+		Paused:  m.paused,
+	}
+}
+
+func doRequest(t *testing.T, s *Server, method, path, token string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServer_RequiresToken(t *testing.T) {
+	s := NewServer("127.0.0.1:0", "secret", &mockController{}, nil)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"missing token", ""},
+		{"wrong token", "nope"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := doRequest(t, s, "GET", "/api/status", tt.token)
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("expected 401, got %d", rec.Code)
+			}
+		})
+	}
+}
+
+func TestServer_Run(t *testing.T) {
+	ctrl := &mockController{}
+	s := NewServer("127.0.0.1:0", "secret", ctrl, nil)
+
+	rec := doRequest(t, s, "POST", "/api/run", "secret")
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", rec.Code)
+	}
+	if ctrl.runs != 1 {
+		t.Errorf("expected 1 run, got %d", ctrl.runs)
+	}
+
+	// Second trigger while the first is still active
+	rec = doRequest(t, s, "POST", "/api/run", "secret")
+	if rec.Code != http.StatusConflict {
+		t.Errorf("expected 409 while a run is active, got %d", rec.Code)
+	}
+}
+
+func TestServer_PauseResume(t *testing.T) {
+	ctrl := &mockController{}
+	s := NewServer("127.0.0.1:0", "secret", ctrl, nil)
+
+	if rec := doRequest(t, s, "POST", "/api/pause", "secret"); rec.Code != http.StatusOK {
+		t.Fatalf("pause: expected 200, got %d", rec.Code)
+	}
+	if !ctrl.paused {
+		t.Error("controller should be paused")
+	}
+
+	if rec := doRequest(t, s, "POST", "/api/resume", "secret"); rec.Code != http.StatusOK {
+		t.Fatalf("resume: expected 200, got %d", rec.Code)
+	}
+	if ctrl.paused {
+		t.Error("controller should be resumed")
+	}
+}
+
+func TestServer_Status(t *testing.T) {
+	s := NewServer("127.0.0.1:0", "secret", &mockController{paused: true}, nil)
+
+	rec := doRequest(t, s, "GET", "/api/status", "secret")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	var body map[string]interface{}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode status: %v", err)
+	}
+
+	if body["phase"] != processor.PhaseSearching {
+		t.Errorf("expected phase %q, got %v", processor.PhaseSearching, body["phase"])
+	}
+	if body["paused"] != true {
+		t.Errorf("expected paused=true, got %v", body["paused"])
+	}
+	if body["denylist_count"] != float64(3) {
+		t.Errorf("expected denylist_count=3, got %v", body["denylist_count"])
+	}
+}
+
+func TestServer_WrongMethod(t *testing.T) {
+	s := NewServer("127.0.0.1:0", "secret", &mockController{}, nil)
+
+	rec := doRequest(t, s, "GET", "/api/run", "secret")
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", rec.Code)
+	}
+}
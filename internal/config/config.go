@@ -5,86 +5,382 @@ import (
 	"net/url"
 	"os"
 	"regexp"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/yuritomanek/seekarr/internal/pathmap"
 	"gopkg.in/yaml.v3"
 )
 
 // Config holds all application configuration
 type Config struct {
-	Lidarr   LidarrConfig     `yaml:"lidarr"`
-	Slskd    SlskdConfig      `yaml:"slskd"`
-	Release  ReleaseSettings  `yaml:"release"`
-	Search   SearchSettings   `yaml:"search"`
-	Download DownloadSettings `yaml:"download"`
-	Timing   TimingSettings   `yaml:"timing"`
-	Logging  LoggingConfig    `yaml:"logging"`
-	Daemon   DaemonSettings   `yaml:"daemon"`
+	DryRun       bool                `yaml:"dry_run"` // search and match, but never download
+	Lidarr       LidarrConfig        `yaml:"lidarr"`
+	Slskd        SlskdConfig         `yaml:"slskd"`
+	Release      ReleaseSettings     `yaml:"release"`
+	Search       SearchSettings      `yaml:"search"`
+	Download     DownloadSettings    `yaml:"download"`
+	Timing       TimingSettings      `yaml:"timing"`
+	Logging      LoggingConfig       `yaml:"logging"`
+	Daemon       DaemonSettings      `yaml:"daemon"`
+	API          APISettings         `yaml:"api"`
+	Telemetry    TelemetrySettings   `yaml:"telemetry"`
+	Report       ReportSettings      `yaml:"report"`
+	PathMappings PathMappingSettings `yaml:"path_mappings"`
+	Hooks        HookSettings        `yaml:"hooks"`
+	MusicBrainz  MusicBrainzSettings `yaml:"musicbrainz"`
 }
 
 type LidarrConfig struct {
 	APIKey      string `yaml:"api_key"`
 	HostURL     string `yaml:"host_url"`
+	URLBase     string `yaml:"url_base"`
 	DownloadDir string `yaml:"download_dir"`
 	DisableSync bool   `yaml:"disable_sync"`
+	// UseArtistPath organizes albums into the artist's folder name in Lidarr
+	UseArtistPath bool `yaml:"use_artist_path"`
+	// ImportMode is how organized albums are imported: scan runs Lidarr's
+	// DownloadedAlbumsScan on the folder, manual imports the files Lidarr
+	// accepts for the album and reports why the others were rejected
+	ImportMode string `yaml:"import_mode"`
+	MaxRetries *int   `yaml:"max_retries,omitempty"` // per API request after the first attempt
+	// ClearStaleQueueItemsHours removes queue entries of wanted albums whose
+	// download completed or failed this many hours ago; 0 leaves the queue alone
+	ClearStaleQueueItemsHours int `yaml:"clear_stale_queue_items_hours"`
+	// RefreshArtistAfterImport refreshes each imported album's artist once
+	// the imports finish, updating Lidarr's statistics and wanted list
+	RefreshArtistAfterImport bool `yaml:"refresh_artist_after_import"`
+	// RequestTimeoutSecs bounds read requests; commands such as import scans
+	// may always take up to five minutes
+	RequestTimeoutSecs *int `yaml:"request_timeout_seconds,omitempty"`
+	ConnectionSettings `yaml:",inline"`
+	TLSSettings        `yaml:",inline"`
 }
 
 type SlskdConfig struct {
-	APIKey         string `yaml:"api_key"`
-	HostURL        string `yaml:"host_url"`
-	URLBase        string `yaml:"url_base"`
-	DownloadDir    string `yaml:"download_dir"`
-	DeleteSearches bool   `yaml:"delete_searches"`
-	StalledTimeout int    `yaml:"stalled_timeout"` // seconds
+	APIKey             string `yaml:"api_key"`
+	Username           string `yaml:"username,omitempty"` // log in with a password instead of api_key
+	Password           string `yaml:"password,omitempty"`
+	HostURL            string `yaml:"host_url"`
+	URLBase            string `yaml:"url_base"`
+	DownloadDir        string `yaml:"download_dir"`
+	DeleteSearches     bool   `yaml:"delete_searches"`
+	StalledTimeout     int    `yaml:"stalled_timeout"`                   // seconds
+	MaxRequestAttempts *int   `yaml:"max_request_attempts,omitempty"`    // per API request, including the first
+	RetryPosts         bool   `yaml:"retry_posts"`                       // also retry searches, enqueues and other POSTs
+	MaxSearchAgeHours  int    `yaml:"max_search_age_hours"`              // delete seekarr's searches older than this, 0 keeps them
+	RequestTimeoutSecs *int   `yaml:"request_timeout_seconds,omitempty"` // per API request, 0 for no limit
+	ConnectionSettings `yaml:",inline"`
+	TLSSettings        `yaml:",inline"`
 }
 
+// validateAuth checks that exactly one way of authenticating with slskd is set
+func (s SlskdConfig) validateAuth() error {
+	credentials := s.Username != "" || s.Password != ""
+	switch {
+	case s.APIKey != "" && credentials:
+		return fmt.Errorf("slskd api_key and username/password are mutually exclusive")
+	case s.APIKey != "":
+		return nil
+	case !credentials:
+		return fmt.Errorf("slskd api_key or username and password are required")
+	case s.Username == "" || s.Password == "":
+		return fmt.Errorf("slskd username and password must be set together")
+	}
+	return nil
+}
+
+// RequestTimeout returns how long an slskd API request may take, 30 seconds when unset
+// Zero leaves requests bounded only by seekarr's own deadlines
+func (s SlskdConfig) RequestTimeout() time.Duration {
+	if s.RequestTimeoutSecs == nil {
+		return 30 * time.Second
+	}
+	return time.Duration(*s.RequestTimeoutSecs) * time.Second
+}
+
+// RequestTimeout returns how long a Lidarr API read may take, 30 seconds when unset
+// Zero leaves reads bounded only by seekarr's own deadlines
+func (l LidarrConfig) RequestTimeout() time.Duration {
+	if l.RequestTimeoutSecs == nil {
+		return 30 * time.Second
+	}
+	return time.Duration(*l.RequestTimeoutSecs) * time.Second
+}
+
+// RequestRetries returns how often a failed Lidarr API request is retried, 3 when unset
+// Zero disables retries
+func (l LidarrConfig) RequestRetries() int {
+	if l.MaxRetries == nil {
+		return 3
+	}
+	return *l.MaxRetries
+}
+
+// RequestAttempts returns how often a failed slskd API request is sent, 3 when unset
+// One disables retries
+func (s SlskdConfig) RequestAttempts() int {
+	if s.MaxRequestAttempts == nil {
+		return 3
+	}
+	return *s.MaxRequestAttempts
+}
+
+// ReleaseSettings constrain which Lidarr release of an album is searched for
+// The two pointer fields default to true when unset
 type ReleaseSettings struct {
-	UseMostCommonTrackNum bool     `yaml:"use_most_common_tracknum"`
-	AllowMultiDisc        bool     `yaml:"allow_multi_disc"`
+	UseMostCommonTrackNum *bool    `yaml:"use_most_common_tracknum,omitempty"`
+	AllowMultiDisc        *bool    `yaml:"allow_multi_disc,omitempty"`
 	AcceptedCountries     []string `yaml:"accepted_countries"`
 	SkipRegionCheck       bool     `yaml:"skip_region_check"`
 	AcceptedFormats       []string `yaml:"accepted_formats"`
 }
 
+// MostCommonTrackNum reports whether to prefer releases with the most common track count
+func (r ReleaseSettings) MostCommonTrackNum() bool {
+	return r.UseMostCommonTrackNum == nil || *r.UseMostCommonTrackNum
+}
+
+// MultiDiscAllowed reports whether releases with more than one medium may be chosen
+func (r ReleaseSettings) MultiDiscAllowed() bool {
+	return r.AllowMultiDisc == nil || *r.AllowMultiDisc
+}
+
 type SearchSettings struct {
 	SearchTimeout             int      `yaml:"search_timeout"`
 	MaximumPeerQueue          int      `yaml:"maximum_peer_queue"`
+	SkipBusyUsers             bool     `yaml:"skip_busy_users"` // skip users without a free slot or with a queue over maximum_peer_queue
 	MinimumPeerUploadSpeed    int      `yaml:"minimum_peer_upload_speed"`
 	MinimumFilenameMatchRatio float64  `yaml:"minimum_filename_match_ratio"`
 	AllowedFiletypes          []string `yaml:"allowed_filetypes"`
+	UseLidarrQualityProfile   bool     `yaml:"use_lidarr_quality_profile"`
 	IgnoredUsers              []string `yaml:"ignored_users"`
 	SearchForTracks           bool     `yaml:"search_for_tracks"`
+	AllowMultiSource          bool     `yaml:"allow_multi_source"`     // assemble albums from several users' directories
+	MinimumTrackFraction      float64  `yaml:"minimum_track_fraction"` // of an album's tracks a track search must find
+	MaxExtraFiles             *int     `yaml:"max_extra_files,omitempty"`
 	AlbumPrependArtist        bool     `yaml:"album_prepend_artist"`
 	TrackPrependArtist        bool     `yaml:"track_prepend_artist"`
 	SearchType                string   `yaml:"search_type"` // first_page, incrementing_page, all
 	NumberOfAlbumsToGrab      int      `yaml:"number_of_albums_to_grab"`
 	RemoveWantedOnFailure     bool     `yaml:"remove_wanted_on_failure"`
 	TitleBlacklist            []string `yaml:"title_blacklist"`
-	SearchSource              string   `yaml:"search_source"` // missing, cutoff_unmet, all
+	StrictArtistMatch         bool     `yaml:"strict_artist_match"`      // require the artist's name in a matched directory's path
+	AllowedAlbumTypes         []string `yaml:"allowed_album_types"`      // Lidarr primary types searched, empty for all
+	ExcludedSecondaryTypes    []string `yaml:"excluded_secondary_types"` // Lidarr secondary types skipped
+	StripEditionKeywords      []string `yaml:"strip_edition_keywords"`   // bracketed qualifiers dropped from queries
+	SearchSource              string   `yaml:"search_source"`            // missing, cutoff_unmet, all
 	EnableSearchDenylist      bool     `yaml:"enable_search_denylist"`
 	MaxSearchFailures         int      `yaml:"max_search_failures"`
-	SortKey                   string   `yaml:"sort_key"` // artist.sortName, albumTitle, releaseDate, etc.
+	WishlistOnDenylist        bool     `yaml:"wishlist_on_denylist"`
+	SortKey                   string   `yaml:"sort_key"` // releaseDate, artistName, albumTitle, id or random
 	SortDir                   string   `yaml:"sort_dir"` // ascending, descending
+	VerifyTracklistWithMB     bool     `yaml:"verify_tracklist_with_musicbrainz"`
+	ConcurrentSearches        int      `yaml:"concurrent_searches"`        // albums searched in parallel
+	MaxUserFailures           int      `yaml:"max_user_failures"`          // failed downloads in a row before a user is ignored for the run, 0 for never
+	AutoIgnoreAfterFailures   int      `yaml:"auto_ignore_after_failures"` // errored files before a user is ignored across runs, 0 for never
+	AutoIgnoreDays            int      `yaml:"auto_ignore_days"`
+	SkipActiveSlskdDownloads  *bool    `yaml:"skip_active_slskd_downloads,omitempty"`
+	SkipUnmonitoredArtists    *bool    `yaml:"skip_unmonitored_artists,omitempty"`
+	MaxResultsToConsider      int      `yaml:"max_results_to_consider"`     // user responses read per search, 0 for all
+	MinimumResponseFileCount  int      `yaml:"minimum_response_file_count"` // audio files a response needs before it is matched, 0 for no minimum
+	RecentHistoryHours        int      `yaml:"recent_history_hours"`        // skip albums Lidarr grabbed or imported this recently, 0 to not check
+}
+
+// SortRandom is the sort_key that shuffles wanted albums instead of having
+// Lidarr sort them
+const SortRandom = "random"
+
+// wantedSortKeys maps each accepted sort_key to the key Lidarr sorts by
+// Lidarr's own names are accepted as well
+var wantedSortKeys = map[string]string{
+	"releaseDate":        "albums.releaseDate",
+	"albumTitle":         "albums.title",
+	"artistName":         "artists.sortName",
+	"id":                 "id",
+	"albums.releaseDate": "albums.releaseDate",
+	"albums.title":       "albums.title",
+	"artists.sortName":   "artists.sortName",
+}
+
+// LidarrSortKey returns the key Lidarr sorts wanted albums by, empty for
+// Lidarr's default order, including with sort_key random
+func (s SearchSettings) LidarrSortKey() string {
+	return wantedSortKeys[s.SortKey]
+}
+
+// SkipActiveDownloads reports whether albums slskd is already downloading are
+// left out of the search, true when unset
+func (s SearchSettings) SkipActiveDownloads() bool {
+	return s.SkipActiveSlskdDownloads == nil || *s.SkipActiveSlskdDownloads
+}
+
+// SkipUnmonitoredArtist reports whether wanted albums of artists unmonitored
+// in Lidarr are skipped, true when unset
+func (s SearchSettings) SkipUnmonitoredArtist() bool {
+	return s.SkipUnmonitoredArtists == nil || *s.SkipUnmonitoredArtists
+}
+
+// ExtraFilesLimit returns how many files beyond the release's track count a
+// matched directory may hold before only its matched files are downloaded
+// ok is false when max_extra_files is unset, so whole directories are downloaded
+func (s SearchSettings) ExtraFilesLimit() (limit int, ok bool) {
+	if s.MaxExtraFiles == nil {
+		return 0, false
+	}
+	return *s.MaxExtraFiles, true
+}
+
+// Lidarr's primary and secondary album types
+var (
+	albumTypes     = []string{"Album", "EP", "Single", "Broadcast", "Other"}
+	secondaryTypes = []string{"Studio", "Compilation", "Soundtrack", "Spokenword", "Interview", "Audiobook",
+		"Live", "Remix", "DJ-mix", "Mixtape/Street", "Demo", "Audio drama"}
+)
+
+// validAlbumTypes checks each entry of a setting is one of types, ignoring case
+func validAlbumTypes(setting string, entries, types []string) error {
+	for _, entry := range entries {
+		if !slices.ContainsFunc(types, func(t string) bool { return strings.EqualFold(t, entry) }) {
+			return fmt.Errorf("%s entry %q must be one of: %s", setting, entry, strings.Join(types, ", "))
+		}
+	}
+	return nil
+}
+
+// TitleBlacklistRegexPrefix marks a title_blacklist entry as a regular expression
+const TitleBlacklistRegexPrefix = "re:"
+
+// TitleBlacklistPatterns compiles the title_blacklist entries prefixed with "re:"
+// Other entries are plain case-insensitive substrings and are not included
+func (s SearchSettings) TitleBlacklistPatterns() ([]*regexp.Regexp, error) {
+	var patterns []*regexp.Regexp
+	for _, entry := range s.TitleBlacklist {
+		expr, ok := strings.CutPrefix(entry, TitleBlacklistRegexPrefix)
+		if !ok {
+			continue
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("title_blacklist entry %q is not a valid regular expression: %w", entry, err)
+		}
+		patterns = append(patterns, re)
+	}
+	return patterns, nil
 }
 
 type DownloadSettings struct {
-	DownloadFiltering     bool     `yaml:"download_filtering"`
-	UseExtensionWhitelist bool     `yaml:"use_extension_whitelist"`
-	ExtensionsWhitelist   []string `yaml:"extensions_whitelist"`
+	DownloadFiltering     bool                    `yaml:"download_filtering"`
+	UseExtensionWhitelist bool                    `yaml:"use_extension_whitelist"`
+	ExtensionsWhitelist   []string                `yaml:"extensions_whitelist"`   // companion files enqueued with a matched album
+	MaxCompanionFileMB    int                     `yaml:"max_companion_file_mb"`  // largest companion file to download, 0 for no limit
+	MinFreeSpaceMB        int                     `yaml:"min_free_space_mb"`      // space to leave free when enqueueing
+	MinFileSizeKB         map[string]int          `yaml:"min_file_size_kb"`       // smallest plausible file per extension
+	PlausibleBitrateKbps  map[string]BitrateRange `yaml:"plausible_bitrate_kbps"` // average bitrate per extension a real share falls within
+	MaxFileRetries        *int                    `yaml:"max_file_retries,omitempty"`
+	MinPartialImportRatio float64                 `yaml:"min_partial_import_ratio"` // share of files that must finish to import a partial album
+	RetryDelaySeconds     int                     `yaml:"retry_delay_seconds"`
+	MaxQueuePosition      int                     `yaml:"max_queue_position"` // furthest back in an uploader's queue to wait, 0 for no limit
+	CancelOnShutdown      bool                    `yaml:"cancel_on_shutdown"`
+	MaxAlbumsPerRun       int                     `yaml:"max_albums_per_run"`      // 0 for no limit
+	MaxTotalBytesPerRun   int64                   `yaml:"max_total_bytes_per_run"` // 0 for no limit
+	SequentialPhases      bool                    `yaml:"sequential_phases"`       // finish every search before monitoring downloads
+}
+
+// BitrateRange bounds an average bitrate in kbps; a zero bound is not checked
+type BitrateRange struct {
+	Min int `yaml:"min"`
+	Max int `yaml:"max"`
+}
+
+// CompanionExtension reports whether files with the extension, e.g. ".jpg",
+// are downloaded alongside a matched album's audio
+func (d DownloadSettings) CompanionExtension(ext string) bool {
+	if !d.UseExtensionWhitelist {
+		return false
+	}
+	ext = strings.TrimPrefix(ext, ".")
+	for _, allowed := range d.ExtensionsWhitelist {
+		if ext != "" && strings.EqualFold(strings.TrimPrefix(allowed, "."), ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// FileRetries returns how often failed files are re-enqueued, 3 when unset
+// Zero disables retries
+func (d DownloadSettings) FileRetries() int {
+	if d.MaxFileRetries == nil {
+		return 3
+	}
+	return *d.MaxFileRetries
 }
 
 type TimingSettings struct {
-	SearchWaitSeconds     int `yaml:"search_wait_seconds"`
-	DownloadPollSeconds   int `yaml:"download_poll_seconds"`
-	ImportPollSeconds     int `yaml:"import_poll_seconds"`
-	StallCheckIntervalSec int `yaml:"stall_check_interval_seconds"`
+	SearchWaitSeconds      int     `yaml:"search_wait_seconds"`
+	SearchDelaySeconds     int     `yaml:"search_delay_seconds"`      // minimum gap between slskd searches
+	SearchDelayJitter      float64 `yaml:"search_delay_jitter"`       // fraction of the gap, up to 0.5
+	PerAlbumTimeoutSeconds int     `yaml:"per_album_timeout_seconds"` // 0 for no limit
+	DownloadPollSeconds    int     `yaml:"download_poll_seconds"`
+	ImportPollSeconds      int     `yaml:"import_poll_seconds"`
+	ImportTimeoutSeconds   int     `yaml:"import_timeout_seconds"` // how long import commands are polled, 600 when unset or 0
+	StallCheckIntervalSec  int     `yaml:"stall_check_interval_seconds"`
+	StallChecks            int     `yaml:"stall_checks"`               // intervals without progress before a transfer is cancelled
+	StallMinBytesPerSecond int     `yaml:"stall_min_bytes_per_second"` // slower progress counts as none, 0 to count any
+}
+
+// ImportTimeout returns how long Lidarr's import commands are waited for,
+// 10 minutes when unset or 0
+func (t TimingSettings) ImportTimeout() time.Duration {
+	if t.ImportTimeoutSeconds == 0 {
+		return 10 * time.Minute
+	}
+	return time.Duration(t.ImportTimeoutSeconds) * time.Second
 }
 
 type DaemonSettings struct {
-	Enabled             bool `yaml:"enabled"`
-	IntervalMinutes     int  `yaml:"interval_minutes"`
-	DeleteAfterImport   bool `yaml:"delete_after_import"`
-	CleanupDelaySeconds int  `yaml:"cleanup_delay_seconds"`
+	Enabled                bool `yaml:"enabled"`
+	IntervalMinutes        int  `yaml:"interval_minutes"`
+	DeleteAfterImport      bool `yaml:"delete_after_import"`
+	CleanupDelaySeconds    int  `yaml:"cleanup_delay_seconds"`
+	DeleteImportedFiles    bool `yaml:"delete_imported_files"`    // also delete what's left of imported album folders
+	ShutdownTimeoutSeconds int  `yaml:"shutdown_timeout_seconds"` // how long shutdown waits for the run to clean up
+}
+
+type APISettings struct {
+	Enabled bool   `yaml:"enabled"`
+	Listen  string `yaml:"listen"`
+	Token   string `yaml:"token"`
+}
+
+type TelemetrySettings struct {
+	OTLPEndpoint string `yaml:"otlp_endpoint"`
+}
+
+type ReportSettings struct {
+	Dir           string   `yaml:"dir"`
+	Formats       []string `yaml:"formats"`
+	RetentionDays int      `yaml:"retention_days"`
+}
+
+// PathMappingSettings translates paths between how slskd, seekarr, and Lidarr
+// each see the shared download volume
+type PathMappingSettings struct {
+	SlskdToLocal  []pathmap.Mapping `yaml:"slskd_to_local"`
+	LocalToLidarr []pathmap.Mapping `yaml:"local_to_lidarr"`
+}
+
+type HookSettings struct {
+	PostImport     []string `yaml:"post_import"`
+	PostRun        []string `yaml:"post_run"`
+	TimeoutSeconds int      `yaml:"timeout_seconds"`
+}
+
+type MusicBrainzSettings struct {
+	Enabled bool   `yaml:"enabled"`
+	BaseURL string `yaml:"base_url"`
 }
 
 type LoggingConfig struct {
@@ -118,6 +414,32 @@ func Load(path string) (*Config, error) {
 	return &config, nil
 }
 
+// LocalDownloadDir returns slskd's download directory as seekarr sees it,
+// applying any slskd_to_local path mapping
+func (c *Config) LocalDownloadDir() string {
+	mapper, err := pathmap.New(c.PathMappings.SlskdToLocal)
+	if err != nil {
+		return c.Slskd.DownloadDir
+	}
+	dir, _ := mapper.Map(c.Slskd.DownloadDir)
+	return dir
+}
+
+// Save writes the configuration as YAML
+// The file is created with owner-only permissions because it contains API keys
+func (c *Config) Save(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("marshal config: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("write config file: %w", err)
+	}
+
+	return nil
+}
+
 // expandEnvVars expands environment variables in ${VAR} or $VAR format
 func expandEnvVars(s string) string {
 	re := regexp.MustCompile(`\$\{([^}]+)\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
@@ -137,6 +459,13 @@ func expandEnvVars(s string) string {
 
 // setDefaults applies default values for optional configuration fields
 func (c *Config) setDefaults() {
+	if c.Lidarr.URLBase == "" {
+		c.Lidarr.URLBase = "/"
+	}
+	if c.Lidarr.ImportMode == "" {
+		c.Lidarr.ImportMode = "scan"
+	}
+
 	// Slskd defaults
 	if c.Slskd.URLBase == "" {
 		c.Slskd.URLBase = "/"
@@ -155,6 +484,12 @@ func (c *Config) setDefaults() {
 	if c.Search.MinimumFilenameMatchRatio == 0 {
 		c.Search.MinimumFilenameMatchRatio = 0.8
 	}
+	if c.Search.MinimumTrackFraction == 0 {
+		c.Search.MinimumTrackFraction = 0.8
+	}
+	if c.Search.AutoIgnoreDays == 0 {
+		c.Search.AutoIgnoreDays = 30
+	}
 	if c.Search.SearchType == "" {
 		c.Search.SearchType = "incrementing_page"
 	}
@@ -167,6 +502,9 @@ func (c *Config) setDefaults() {
 	if c.Search.MaxSearchFailures == 0 {
 		c.Search.MaxSearchFailures = 3
 	}
+	if c.Search.ConcurrentSearches == 0 {
+		c.Search.ConcurrentSearches = 1
+	}
 	// Sort parameters are optional - if not set, Lidarr uses its default sorting
 	// Don't set defaults here to allow users to explicitly opt-in
 
@@ -183,6 +521,9 @@ func (c *Config) setDefaults() {
 	if c.Timing.StallCheckIntervalSec == 0 {
 		c.Timing.StallCheckIntervalSec = 60 // Check for stalls every minute
 	}
+	if c.Timing.StallChecks == 0 {
+		c.Timing.StallChecks = 5
+	}
 
 	// Logging defaults
 	if c.Logging.Level == "" {
@@ -199,6 +540,32 @@ func (c *Config) setDefaults() {
 	if c.Daemon.CleanupDelaySeconds == 0 {
 		c.Daemon.CleanupDelaySeconds = 10 // Wait 10 seconds after import before cleanup
 	}
+	if c.Daemon.ShutdownTimeoutSeconds == 0 {
+		c.Daemon.ShutdownTimeoutSeconds = 30
+	}
+
+	// API defaults
+	if c.API.Listen == "" {
+		c.API.Listen = ":8687"
+	}
+
+	// Report defaults
+	if len(c.Report.Formats) == 0 {
+		c.Report.Formats = []string{"csv"}
+	}
+	if c.Report.RetentionDays == 0 {
+		c.Report.RetentionDays = 30
+	}
+
+	// Hook defaults
+	if c.Hooks.TimeoutSeconds == 0 {
+		c.Hooks.TimeoutSeconds = 300
+	}
+
+	// MusicBrainz defaults
+	if c.MusicBrainz.BaseURL == "" {
+		c.MusicBrainz.BaseURL = "https://musicbrainz.org"
+	}
 }
 
 // Validate checks required fields and value ranges
@@ -216,10 +583,13 @@ func (c *Config) Validate() error {
 	if c.Lidarr.DownloadDir == "" {
 		return fmt.Errorf("lidarr download_dir is required")
 	}
+	if _, err := c.Lidarr.ClientConfig(); err != nil {
+		return fmt.Errorf("lidarr %w", err)
+	}
 
 	// Required Slskd fields
-	if c.Slskd.APIKey == "" {
-		return fmt.Errorf("slskd api_key is required")
+	if err := c.Slskd.validateAuth(); err != nil {
+		return err
 	}
 	if c.Slskd.HostURL == "" {
 		return fmt.Errorf("slskd host_url is required")
@@ -230,11 +600,71 @@ func (c *Config) Validate() error {
 	if c.Slskd.DownloadDir == "" {
 		return fmt.Errorf("slskd download_dir is required")
 	}
+	if _, err := c.Slskd.ClientConfig(); err != nil {
+		return fmt.Errorf("slskd %w", err)
+	}
+	if c.Slskd.RequestTimeout() < 0 {
+		return fmt.Errorf("slskd request_timeout_seconds must be non-negative, got %d", *c.Slskd.RequestTimeoutSecs)
+	}
+	if err := c.Slskd.ConnectionSettings.validate("slskd"); err != nil {
+		return err
+	}
+	if c.Slskd.MaxSearchAgeHours < 0 {
+		return fmt.Errorf("slskd max_search_age_hours must be non-negative, got %d", c.Slskd.MaxSearchAgeHours)
+	}
+	if c.Lidarr.RequestRetries() < 0 {
+		return fmt.Errorf("lidarr max_retries must be non-negative, got %d", c.Lidarr.RequestRetries())
+	}
+	if c.Lidarr.RequestTimeout() < 0 {
+		return fmt.Errorf("lidarr request_timeout_seconds must be non-negative, got %d", *c.Lidarr.RequestTimeoutSecs)
+	}
+	if err := c.Lidarr.ConnectionSettings.validate("lidarr"); err != nil {
+		return err
+	}
+	if c.Lidarr.ClearStaleQueueItemsHours < 0 {
+		return fmt.Errorf("lidarr clear_stale_queue_items_hours must be non-negative, got %d", c.Lidarr.ClearStaleQueueItemsHours)
+	}
+	if c.Slskd.RequestAttempts() < 1 {
+		return fmt.Errorf("slskd max_request_attempts must be at least 1, got %d", c.Slskd.RequestAttempts())
+	}
 
 	// Validate search settings
 	if c.Search.MinimumFilenameMatchRatio < 0 || c.Search.MinimumFilenameMatchRatio > 1 {
 		return fmt.Errorf("minimum_filename_match_ratio must be between 0 and 1, got %f", c.Search.MinimumFilenameMatchRatio)
 	}
+	if c.Search.MinimumTrackFraction < 0 || c.Search.MinimumTrackFraction > 1 {
+		return fmt.Errorf("minimum_track_fraction must be between 0 and 1, got %f", c.Search.MinimumTrackFraction)
+	}
+	if c.Search.RecentHistoryHours < 0 {
+		return fmt.Errorf("recent_history_hours must be non-negative, got %d", c.Search.RecentHistoryHours)
+	}
+	if c.Search.MinimumResponseFileCount < 0 {
+		return fmt.Errorf("minimum_response_file_count must be non-negative, got %d", c.Search.MinimumResponseFileCount)
+	}
+	if c.Search.MaxResultsToConsider < 0 {
+		return fmt.Errorf("max_results_to_consider must be non-negative, got %d", c.Search.MaxResultsToConsider)
+	}
+	if limit, ok := c.Search.ExtraFilesLimit(); ok && limit < 0 {
+		return fmt.Errorf("max_extra_files must be non-negative, got %d", limit)
+	}
+	if c.Timing.StallCheckIntervalSec < 1 || c.Timing.StallChecks < 1 {
+		return fmt.Errorf("stall_check_interval_seconds and stall_checks must be at least 1")
+	}
+	if c.Search.ConcurrentSearches < 1 {
+		return fmt.Errorf("concurrent_searches must be at least 1, got %d", c.Search.ConcurrentSearches)
+	}
+	if c.Search.MaxUserFailures < 0 {
+		return fmt.Errorf("max_user_failures must be non-negative, got %d", c.Search.MaxUserFailures)
+	}
+	if c.Search.AutoIgnoreAfterFailures < 0 {
+		return fmt.Errorf("auto_ignore_after_failures must be non-negative, got %d", c.Search.AutoIgnoreAfterFailures)
+	}
+	if c.Search.AutoIgnoreDays < 0 {
+		return fmt.Errorf("auto_ignore_days must be non-negative, got %d", c.Search.AutoIgnoreDays)
+	}
+	if c.Lidarr.ImportMode != "scan" && c.Lidarr.ImportMode != "manual" {
+		return fmt.Errorf("import_mode must be one of: scan, manual (got %q)", c.Lidarr.ImportMode)
+	}
 	if c.Search.SearchType != "first_page" && c.Search.SearchType != "incrementing_page" && c.Search.SearchType != "all" {
 		return fmt.Errorf("search_type must be one of: first_page, incrementing_page, all (got %q)", c.Search.SearchType)
 	}
@@ -244,20 +674,132 @@ func (c *Config) Validate() error {
 	if c.Search.NumberOfAlbumsToGrab < 1 {
 		return fmt.Errorf("number_of_albums_to_grab must be at least 1, got %d", c.Search.NumberOfAlbumsToGrab)
 	}
+	if _, ok := wantedSortKeys[c.Search.SortKey]; !ok && c.Search.SortKey != "" && c.Search.SortKey != SortRandom {
+		return fmt.Errorf("sort_key must be one of: releaseDate, artistName, albumTitle, id, random (got %q)", c.Search.SortKey)
+	}
 	if c.Search.SortDir != "" && c.Search.SortDir != "ascending" && c.Search.SortDir != "descending" {
 		return fmt.Errorf("sort_dir must be one of: ascending, descending (got %q)", c.Search.SortDir)
 	}
+	if _, err := c.Search.TitleBlacklistPatterns(); err != nil {
+		return err
+	}
+	if err := validAlbumTypes("allowed_album_types", c.Search.AllowedAlbumTypes, albumTypes); err != nil {
+		return err
+	}
+	if err := validAlbumTypes("excluded_secondary_types", c.Search.ExcludedSecondaryTypes, secondaryTypes); err != nil {
+		return err
+	}
+
+	if c.Search.VerifyTracklistWithMB && !c.MusicBrainz.Enabled {
+		return fmt.Errorf("verify_tracklist_with_musicbrainz requires musicbrainz.enabled")
+	}
 
 	// Validate timing settings
 	if c.Timing.SearchWaitSeconds < 0 {
 		return fmt.Errorf("search_wait_seconds must be non-negative, got %d", c.Timing.SearchWaitSeconds)
 	}
+	if c.Timing.SearchDelaySeconds < 0 {
+		return fmt.Errorf("search_delay_seconds must be non-negative, got %d", c.Timing.SearchDelaySeconds)
+	}
+	if c.Timing.PerAlbumTimeoutSeconds < 0 {
+		return fmt.Errorf("per_album_timeout_seconds must be non-negative, got %d", c.Timing.PerAlbumTimeoutSeconds)
+	}
+	if c.Timing.SearchDelayJitter < 0 || c.Timing.SearchDelayJitter > 0.5 {
+		return fmt.Errorf("search_delay_jitter must be between 0 and 0.5, got %f", c.Timing.SearchDelayJitter)
+	}
 	if c.Timing.DownloadPollSeconds < 1 {
 		return fmt.Errorf("download_poll_seconds must be at least 1, got %d", c.Timing.DownloadPollSeconds)
 	}
 	if c.Timing.ImportPollSeconds < 1 {
 		return fmt.Errorf("import_poll_seconds must be at least 1, got %d", c.Timing.ImportPollSeconds)
 	}
+	if c.Timing.ImportTimeoutSeconds < 0 {
+		return fmt.Errorf("import_timeout_seconds must be non-negative, got %d", c.Timing.ImportTimeoutSeconds)
+	}
+	if c.Timing.StallMinBytesPerSecond < 0 {
+		return fmt.Errorf("stall_min_bytes_per_second must be non-negative, got %d", c.Timing.StallMinBytesPerSecond)
+	}
+
+	// Validate download settings
+	if c.Download.MinFreeSpaceMB < 0 {
+		return fmt.Errorf("min_free_space_mb must be non-negative, got %d", c.Download.MinFreeSpaceMB)
+	}
+	for ext, size := range c.Download.MinFileSizeKB {
+		if size < 0 {
+			return fmt.Errorf("min_file_size_kb for %s must be non-negative, got %d", ext, size)
+		}
+	}
+	for ext, window := range c.Download.PlausibleBitrateKbps {
+		if window.Min < 0 || window.Max < 0 {
+			return fmt.Errorf("plausible_bitrate_kbps for %s must be non-negative, got %d-%d", ext, window.Min, window.Max)
+		}
+		if window.Max > 0 && window.Max < window.Min {
+			return fmt.Errorf("plausible_bitrate_kbps for %s must have max at least min, got %d-%d", ext, window.Min, window.Max)
+		}
+	}
+	if c.Download.FileRetries() < 0 {
+		return fmt.Errorf("max_file_retries must be non-negative, got %d", c.Download.FileRetries())
+	}
+	if c.Download.MinPartialImportRatio < 0 || c.Download.MinPartialImportRatio > 1 {
+		return fmt.Errorf("min_partial_import_ratio must be between 0 and 1, got %f", c.Download.MinPartialImportRatio)
+	}
+	if c.Download.RetryDelaySeconds < 0 {
+		return fmt.Errorf("retry_delay_seconds must be non-negative, got %d", c.Download.RetryDelaySeconds)
+	}
+	if c.Download.MaxCompanionFileMB < 0 {
+		return fmt.Errorf("max_companion_file_mb must be non-negative, got %d", c.Download.MaxCompanionFileMB)
+	}
+	if c.Download.MaxQueuePosition < 0 {
+		return fmt.Errorf("max_queue_position must be non-negative, got %d", c.Download.MaxQueuePosition)
+	}
+	if c.Download.MaxAlbumsPerRun < 0 || c.Download.MaxTotalBytesPerRun < 0 {
+		return fmt.Errorf("max_albums_per_run and max_total_bytes_per_run must be non-negative")
+	}
+
+	// Validate daemon settings
+	if c.Daemon.Enabled && c.Daemon.IntervalMinutes < 1 {
+		return fmt.Errorf("daemon interval_minutes must be at least 1, got %d", c.Daemon.IntervalMinutes)
+	}
+
+	// Validate API settings
+	if c.API.Enabled && c.API.Token == "" {
+		return fmt.Errorf("api token is required when the api is enabled")
+	}
+
+	// Validate report settings
+	for _, format := range c.Report.Formats {
+		if format != "csv" && format != "json" {
+			return fmt.Errorf("report formats must be csv or json, got %q", format)
+		}
+	}
+	if c.Report.RetentionDays < 0 {
+		return fmt.Errorf("report retention_days must be at least 1, got %d", c.Report.RetentionDays)
+	}
+
+	// Validate hook settings
+	if c.Hooks.TimeoutSeconds < 1 {
+		return fmt.Errorf("hooks timeout_seconds must be at least 1, got %d", c.Hooks.TimeoutSeconds)
+	}
+
+	// Validate path mappings
+	if _, err := pathmap.New(c.PathMappings.SlskdToLocal); err != nil {
+		return fmt.Errorf("path_mappings.slskd_to_local: %w", err)
+	}
+	if _, err := pathmap.New(c.PathMappings.LocalToLidarr); err != nil {
+		return fmt.Errorf("path_mappings.local_to_lidarr: %w", err)
+	}
+
+	// Validate MusicBrainz settings
+	if u, err := url.Parse(c.MusicBrainz.BaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("musicbrainz base_url must be an http or https URL, got %q", c.MusicBrainz.BaseURL)
+	}
+
+	// Validate telemetry settings
+	if ep := c.Telemetry.OTLPEndpoint; ep != "" {
+		if u, err := url.Parse(ep); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("telemetry otlp_endpoint must be an http or https URL, got %q", ep)
+		}
+	}
 
 	return nil
 }
@@ -266,19 +808,45 @@ func (c *Config) Validate() error {
 func Example() string {
 	return `# Seekarr Configuration
 
+dry_run: false
+
 lidarr:
   api_key: ${LIDARR_API_KEY}
   host_url: http://lidarr:8686
+  url_base: /
   download_dir: /downloads
   disable_sync: false
+  use_artist_path: false
+  import_mode: scan
+  max_retries: 3
+  clear_stale_queue_items_hours: 0
+  refresh_artist_after_import: false
+  request_timeout_seconds: 30
+  max_idle_connections: 4
+  idle_connection_timeout_seconds: 90
+  disable_http2: false
+  tls_skip_verify: false
+  tls_ca_file: ""
 
 slskd:
   api_key: ${SLSKD_API_KEY}
+  # Or log in instead, for slskd without API keys:
+  # username: ${SLSKD_USERNAME}
+  # password: ${SLSKD_PASSWORD}
   host_url: http://slskd:5030
   url_base: /
   download_dir: /downloads
   delete_searches: false
   stalled_timeout: 3600
+  max_request_attempts: 3
+  retry_posts: false
+  max_search_age_hours: 0
+  request_timeout_seconds: 30
+  max_idle_connections: 4
+  idle_connection_timeout_seconds: 90
+  disable_http2: false
+  tls_skip_verify: false
+  tls_ca_file: ""
 
 release:
   use_most_common_tracknum: true
@@ -297,6 +865,9 @@ release:
 search:
   search_timeout: 5000
   maximum_peer_queue: 50
+  skip_busy_users: false
+  max_results_to_consider: 0
+  minimum_response_file_count: 0  # Skip responses with fewer audio files than this or the album's track count (0 = no minimum)
   minimum_peer_upload_speed: 0
   minimum_filename_match_ratio: 0.8
   allowed_filetypes:
@@ -305,35 +876,105 @@ search:
     - flac
     - mp3 320
     - mp3
+  use_lidarr_quality_profile: false
   ignored_users: []
   search_for_tracks: true
+  allow_multi_source: false
+  minimum_track_fraction: 0.8
+  # max_extra_files: 0  # Download only the matched files from directories with more files than the release
   album_prepend_artist: false
   track_prepend_artist: true
   search_type: incrementing_page  # first_page, incrementing_page, all
   number_of_albums_to_grab: 10
   remove_wanted_on_failure: false
+  strict_artist_match: false
+  allowed_album_types: []
+  excluded_secondary_types: []
   title_blacklist: []
+  strip_edition_keywords: []
   search_source: missing  # missing, cutoff_unmet, all
   enable_search_denylist: false
   max_search_failures: 3
+  wishlist_on_denylist: false
+  verify_tracklist_with_musicbrainz: false
+  concurrent_searches: 1
+  max_user_failures: 0
+  auto_ignore_after_failures: 0  # Errored files before a user is ignored for auto_ignore_days (0 = never)
+  auto_ignore_days: 30
+  skip_active_slskd_downloads: true  # Skip albums that match a directory slskd is still downloading
+  skip_unmonitored_artists: true  # Skip albums whose artist is unmonitored in Lidarr
+  recent_history_hours: 0  # Skip albums Lidarr grabbed or imported since the last run, within this many hours (0 = don't check)
 
 download:
   download_filtering: true
-  use_extension_whitelist: false
+  use_extension_whitelist: false  # Also download companion files with these extensions from a matched directory
   extensions_whitelist:
     - lrc
     - nfo
     - txt
+  max_companion_file_mb: 0  # Skip larger companion files (0 = no limit)
+  min_free_space_mb: 0  # Free space to keep when enqueueing; albums that don't fit are deferred
+  min_file_size_kb: {}  # Smallest believable file per extension, e.g. {flac: 1000, mp3: 500}
+  plausible_bitrate_kbps: {}  # Average bitrate a directory's size and Lidarr's track durations must imply, e.g. {flac: {min: 500}, mp3: {min: 96, max: 330}}
+  max_file_retries: 3  # Times failed files are re-enqueued; 0 accepts a partial album or fails right away
+  min_partial_import_ratio: 0  # Share of an album's files that must finish to import it as a partial album
+  retry_delay_seconds: 0  # Wait before re-enqueueing failed files
+  max_queue_position: 0  # Search again when queued further back than this in an uploader's queue (0 waits)
+  cancel_on_shutdown: false  # Cancel unfinished slskd downloads when seekarr is stopped mid-run
+  max_albums_per_run: 0  # Stop queueing after this many albums per run (0 = no limit)
+  max_total_bytes_per_run: 0  # Stop queueing once this many bytes are queued in a run (0 = no limit)
+  sequential_phases: false  # Finish every search before monitoring downloads, for debugging
 
 timing:
   search_wait_seconds: 5
+  search_delay_seconds: 0
+  search_delay_jitter: 0
+  per_album_timeout_seconds: 0
   download_poll_seconds: 10
   import_poll_seconds: 2
+  import_timeout_seconds: 600  # Stop waiting for Lidarr's import commands after this long (0 = 600)
   stall_check_interval_seconds: 60
+  stall_checks: 5
+  stall_min_bytes_per_second: 0
 
 logging:
   level: INFO
   format: ""
   datefmt: ""
+
+daemon:
+  enabled: false  # Run continuously instead of once
+  interval_minutes: 15
+  delete_after_import: true
+  cleanup_delay_seconds: 10
+  delete_imported_files: false
+  shutdown_timeout_seconds: 30
+
+api:
+  enabled: false
+  listen: ":8687"
+  token: ${SEEKARR_API_TOKEN}
+
+telemetry:
+  otlp_endpoint: ""
+
+report:
+  dir: ""
+  formats:
+    - csv
+  retention_days: 30
+
+path_mappings:
+  slskd_to_local: []
+  local_to_lidarr: []
+
+hooks:
+  post_import: []
+  post_run: []
+  timeout_seconds: 300
+
+musicbrainz:
+  enabled: false
+  base_url: https://musicbrainz.org
 `
 }
@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/pathmap"
 )
 
 func TestLoad_ValidConfig(t *testing.T) {
@@ -86,7 +88,36 @@ slskd:
 	}
 }
 
+func TestLoad_SlskdCredentials(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	configContent := `
+lidarr:
+  api_key: test
+  host_url: http://localhost:8686
+  download_dir: /downloads
+
+slskd:
+  username: admin
+  password: secret
+  host_url: http://localhost:5030
+  download_dir: /downloads
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Slskd.APIKey != "" || cfg.Slskd.Username != "admin" || cfg.Slskd.Password != "secret" {
+		t.Errorf("expected username and password without an api_key, got %+v", cfg.Slskd)
+	}
+}
+
 func TestValidate_MissingRequiredFields(t *testing.T) {
+	negative := -1
+	zero := 0
 	tests := []struct {
 		name        string
 		config      Config
@@ -107,6 +138,73 @@ func TestValidate_MissingRequiredFields(t *testing.T) {
 			},
 			expectError: "lidarr api_key is required",
 		},
+		{
+			name: "missing slskd credentials",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:8686",
+					DownloadDir: "/downloads",
+				},
+				Slskd: SlskdConfig{
+
+					HostURL:     "http://localhost:5030",
+					DownloadDir: "/downloads",
+				},
+			},
+			expectError: "slskd api_key or username and password are required",
+		},
+		{
+			name: "slskd api_key with credentials",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:8686",
+					DownloadDir: "/downloads",
+				},
+				Slskd: SlskdConfig{
+					APIKey:      "test",
+					Username:    "admin",
+					Password:    "secret",
+					HostURL:     "http://localhost:5030",
+					DownloadDir: "/downloads",
+				},
+			},
+			expectError: "slskd api_key and username/password are mutually exclusive",
+		},
+		{
+			name: "slskd username without password",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:8686",
+					DownloadDir: "/downloads",
+				},
+				Slskd: SlskdConfig{
+					Username:    "admin",
+					HostURL:     "http://localhost:5030",
+					DownloadDir: "/downloads",
+				},
+			},
+			expectError: "slskd username and password must be set together",
+		},
+		{
+			name: "negative slskd idle connections",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:8686",
+					DownloadDir: "/downloads",
+				},
+				Slskd: SlskdConfig{
+					APIKey:             "test",
+					HostURL:            "http://localhost:5030",
+					DownloadDir:        "/downloads",
+					ConnectionSettings: ConnectionSettings{MaxIdleConns: negative},
+				},
+			},
+			expectError: "slskd max_idle_connections must be non-negative",
+		},
 		{
 			name: "invalid host url",
 			config: Config{
@@ -161,51 +259,664 @@ func TestValidate_MissingRequiredFields(t *testing.T) {
 			},
 			expectError: "search_type must be one of: first_page, incrementing_page, all",
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			tt.config.setDefaults()
-			err := tt.config.Validate()
-			if err == nil {
-				t.Fatal("expected validation error, got nil")
-			}
-			// Check that error message starts with expected prefix
-			if tt.expectError != "" {
-				errMsg := err.Error()
-				hasPrefix := len(errMsg) >= len(tt.expectError) && errMsg[:len(tt.expectError)] == tt.expectError
-				if !hasPrefix {
-					t.Errorf("expected error starting with %q, got %q", tt.expectError, errMsg)
-				}
-			}
-		})
-	}
-}
-
-func TestSetDefaults(t *testing.T) {
-	cfg := &Config{}
-	cfg.setDefaults()
-
-	tests := []struct {
-		name     string
-		got      interface{}
-		expected interface{}
-	}{
-		{"URLBase", cfg.Slskd.URLBase, "/"},
-		{"StalledTimeout", cfg.Slskd.StalledTimeout, 3600},
-		{"SearchTimeout", cfg.Search.SearchTimeout, 5000},
-		{"MinimumFilenameMatchRatio", cfg.Search.MinimumFilenameMatchRatio, 0.8},
-		{"SearchType", cfg.Search.SearchType, "incrementing_page"},
-		{"SearchWaitSeconds", cfg.Timing.SearchWaitSeconds, 5},
-		{"DownloadPollSeconds", cfg.Timing.DownloadPollSeconds, 10},
-		{"ImportPollSeconds", cfg.Timing.ImportPollSeconds, 2},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if tt.got != tt.expected {
-				t.Errorf("default %s = %v, expected %v", tt.name, tt.got, tt.expected)
-			}
-		})
+		{
+			name: "api enabled without token",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:8686",
+					DownloadDir: "/downloads",
+				},
+				Slskd: SlskdConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:5030",
+					DownloadDir: "/downloads",
+				},
+				API: APISettings{
+					Enabled: true,
+				},
+			},
+			expectError: "api token is required when the api is enabled",
+		},
+		{
+			name: "telemetry endpoint without scheme",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:8686",
+					DownloadDir: "/downloads",
+				},
+				Slskd: SlskdConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:5030",
+					DownloadDir: "/downloads",
+				},
+				Telemetry: TelemetrySettings{
+					OTLPEndpoint: "otel-collector:4318",
+				},
+			},
+			expectError: "telemetry otlp_endpoint must be an http or https URL",
+		},
+		{
+			name: "unknown report format",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:8686",
+					DownloadDir: "/downloads",
+				},
+				Slskd: SlskdConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:5030",
+					DownloadDir: "/downloads",
+				},
+				Report: ReportSettings{
+					Dir:     "/reports",
+					Formats: []string{"xml"},
+				},
+			},
+			expectError: "report formats must be csv or json",
+		},
+		{
+			name: "musicbrainz verification without musicbrainz",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:8686",
+					DownloadDir: "/downloads",
+				},
+				Slskd: SlskdConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:5030",
+					DownloadDir: "/downloads",
+				},
+				Search: SearchSettings{
+					VerifyTracklistWithMB: true,
+				},
+			},
+			expectError: "verify_tracklist_with_musicbrainz requires musicbrainz.enabled",
+		},
+		{
+			name: "negative concurrent searches",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:8686",
+					DownloadDir: "/downloads",
+				},
+				Slskd: SlskdConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:5030",
+					DownloadDir: "/downloads",
+				},
+				Search: SearchSettings{
+					ConcurrentSearches: -1,
+				},
+			},
+			expectError: "concurrent_searches must be at least 1",
+		},
+		{
+			name: "negative import timeout",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:8686",
+					DownloadDir: "/downloads",
+				},
+				Slskd: SlskdConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:5030",
+					DownloadDir: "/downloads",
+				},
+				Timing: TimingSettings{
+					DownloadPollSeconds:  10,
+					ImportPollSeconds:    2,
+					ImportTimeoutSeconds: negative,
+				},
+			},
+			expectError: "import_timeout_seconds must be non-negative",
+		},
+		{
+			name: "negative free space reserve",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:8686",
+					DownloadDir: "/downloads",
+				},
+				Slskd: SlskdConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:5030",
+					DownloadDir: "/downloads",
+				},
+				Download: DownloadSettings{
+					MinFreeSpaceMB: -1,
+				},
+			},
+			expectError: "min_free_space_mb must be non-negative",
+		},
+		{
+			name: "unknown import mode",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:8686",
+					DownloadDir: "/downloads",
+					ImportMode:  "move",
+				},
+				Slskd: SlskdConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:5030",
+					DownloadDir: "/downloads",
+				},
+			},
+			expectError: "import_mode must be one of",
+		},
+		{
+			name: "unknown album type",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:8686",
+					DownloadDir: "/downloads",
+				},
+				Slskd: SlskdConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:5030",
+					DownloadDir: "/downloads",
+				},
+				Search: SearchSettings{
+					ExcludedSecondaryTypes: []string{"Bootleg"},
+				},
+			},
+			expectError: "excluded_secondary_types entry \"Bootleg\" must be one of",
+		},
+		{
+			name: "unknown sort key",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:8686",
+					DownloadDir: "/downloads",
+				},
+				Slskd: SlskdConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:5030",
+					DownloadDir: "/downloads",
+				},
+				Search: SearchSettings{
+					SortKey: "artist",
+				},
+			},
+			expectError: "sort_key must be one of",
+		},
+		{
+			name: "negative minimum response file count",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:8686",
+					DownloadDir: "/downloads",
+				},
+				Slskd: SlskdConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:5030",
+					DownloadDir: "/downloads",
+				},
+				Search: SearchSettings{
+					MinimumResponseFileCount: -1,
+				},
+			},
+			expectError: "minimum_response_file_count must be non-negative",
+		},
+		{
+			name: "negative companion file limit",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:8686",
+					DownloadDir: "/downloads",
+				},
+				Slskd: SlskdConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:5030",
+					DownloadDir: "/downloads",
+				},
+				Download: DownloadSettings{
+					MaxCompanionFileMB: -1,
+				},
+			},
+			expectError: "max_companion_file_mb must be non-negative",
+		},
+		{
+			name: "negative retry delay",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:8686",
+					DownloadDir: "/downloads",
+				},
+				Slskd: SlskdConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:5030",
+					DownloadDir: "/downloads",
+				},
+				Download: DownloadSettings{
+					RetryDelaySeconds: -5,
+				},
+			},
+			expectError: "retry_delay_seconds must be non-negative",
+		},
+		{
+			name: "negative run budget",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:8686",
+					DownloadDir: "/downloads",
+				},
+				Slskd: SlskdConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:5030",
+					DownloadDir: "/downloads",
+				},
+				Download: DownloadSettings{
+					MaxTotalBytesPerRun: -1,
+				},
+			},
+			expectError: "max_albums_per_run and max_total_bytes_per_run must be non-negative",
+		},
+		{
+			name: "daemon interval below one minute",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:8686",
+					DownloadDir: "/downloads",
+				},
+				Slskd: SlskdConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:5030",
+					DownloadDir: "/downloads",
+				},
+				Daemon: DaemonSettings{
+					Enabled:         true,
+					IntervalMinutes: -5,
+				},
+			},
+			expectError: "daemon interval_minutes must be at least 1",
+		},
+		{
+			name: "negative lidarr retries",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:8686",
+					DownloadDir: "/downloads",
+					MaxRetries:  &negative,
+				},
+				Slskd: SlskdConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:5030",
+					DownloadDir: "/downloads",
+				},
+			},
+			expectError: "lidarr max_retries must be non-negative",
+		},
+		{
+			name: "negative stale queue item hours",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:                    "test",
+					HostURL:                   "http://localhost:8686",
+					DownloadDir:               "/downloads",
+					ClearStaleQueueItemsHours: -1,
+				},
+				Slskd: SlskdConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:5030",
+					DownloadDir: "/downloads",
+				},
+			},
+			expectError: "lidarr clear_stale_queue_items_hours must be non-negative",
+		},
+		{
+			name: "negative lidarr request timeout",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:             "test",
+					HostURL:            "http://localhost:8686",
+					DownloadDir:        "/downloads",
+					RequestTimeoutSecs: &negative,
+				},
+				Slskd: SlskdConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:5030",
+					DownloadDir: "/downloads",
+				},
+			},
+			expectError: "lidarr request_timeout_seconds must be non-negative",
+		},
+		{
+			name: "negative lidarr idle connection timeout",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:             "test",
+					HostURL:            "http://localhost:8686",
+					DownloadDir:        "/downloads",
+					ConnectionSettings: ConnectionSettings{IdleConnTimeoutSec: negative},
+				},
+				Slskd: SlskdConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:5030",
+					DownloadDir: "/downloads",
+				},
+			},
+			expectError: "lidarr idle_connection_timeout_seconds must be non-negative",
+		},
+		{
+			name: "zero slskd request attempts",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:8686",
+					DownloadDir: "/downloads",
+				},
+				Slskd: SlskdConfig{
+					APIKey:             "test",
+					HostURL:            "http://localhost:5030",
+					DownloadDir:        "/downloads",
+					MaxRequestAttempts: &zero,
+				},
+			},
+			expectError: "slskd max_request_attempts must be at least 1",
+		},
+		{
+			name: "negative slskd request timeout",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:8686",
+					DownloadDir: "/downloads",
+				},
+				Slskd: SlskdConfig{
+					APIKey:             "test",
+					HostURL:            "http://localhost:5030",
+					DownloadDir:        "/downloads",
+					RequestTimeoutSecs: &negative,
+				},
+			},
+			expectError: "slskd request_timeout_seconds must be non-negative",
+		},
+		{
+			name: "negative slskd max_search_age_hours",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:8686",
+					DownloadDir: "/downloads",
+				},
+				Slskd: SlskdConfig{
+					APIKey:            "test",
+					HostURL:           "http://localhost:5030",
+					DownloadDir:       "/downloads",
+					MaxSearchAgeHours: -1,
+				},
+			},
+			expectError: "slskd max_search_age_hours must be non-negative",
+		},
+		{
+			name: "missing slskd tls_ca_file",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:8686",
+					DownloadDir: "/downloads",
+				},
+				Slskd: SlskdConfig{
+					APIKey:      "test",
+					HostURL:     "https://localhost:5030",
+					DownloadDir: "/downloads",
+					TLSSettings: TLSSettings{CAFile: "/nonexistent/ca.pem"},
+				},
+			},
+			expectError: "slskd read tls_ca_file",
+		},
+		{
+			name: "negative max extra files",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:8686",
+					DownloadDir: "/downloads",
+				},
+				Slskd: SlskdConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:5030",
+					DownloadDir: "/downloads",
+				},
+				Search: SearchSettings{
+					MaxExtraFiles: &negative,
+				},
+			},
+			expectError: "max_extra_files must be non-negative",
+		},
+		{
+			name: "negative max results to consider",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:8686",
+					DownloadDir: "/downloads",
+				},
+				Slskd: SlskdConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:5030",
+					DownloadDir: "/downloads",
+				},
+				Search: SearchSettings{
+					MaxResultsToConsider: -1,
+				},
+			},
+			expectError: "max_results_to_consider must be non-negative",
+		},
+		{
+			name: "negative min file size",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:8686",
+					DownloadDir: "/downloads",
+				},
+				Slskd: SlskdConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:5030",
+					DownloadDir: "/downloads",
+				},
+				Download: DownloadSettings{
+					MinFileSizeKB: map[string]int{"flac": -1},
+				},
+			},
+			expectError: "min_file_size_kb for flac must be non-negative",
+		},
+		{
+			name: "inverted plausible bitrate range",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:8686",
+					DownloadDir: "/downloads",
+				},
+				Slskd: SlskdConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:5030",
+					DownloadDir: "/downloads",
+				},
+				Download: DownloadSettings{
+					PlausibleBitrateKbps: map[string]BitrateRange{"mp3": {Min: 320, Max: 128}},
+				},
+			},
+			expectError: "plausible_bitrate_kbps for mp3 must have max at least min",
+		},
+		{
+			name: "invalid title blacklist regex",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:8686",
+					DownloadDir: "/downloads",
+				},
+				Slskd: SlskdConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:5030",
+					DownloadDir: "/downloads",
+				},
+				Search: SearchSettings{
+					TitleBlacklist: []string{"karaoke", "re:live (at"},
+				},
+			},
+			expectError: `title_blacklist entry "re:live (at" is not a valid regular expression`,
+		},
+		{
+			name: "negative max user failures",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:8686",
+					DownloadDir: "/downloads",
+				},
+				Slskd: SlskdConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:5030",
+					DownloadDir: "/downloads",
+				},
+				Search: SearchSettings{
+					MaxUserFailures: -1,
+				},
+			},
+			expectError: "max_user_failures must be non-negative",
+		},
+		{
+			name: "ambiguous path mappings",
+			config: Config{
+				Lidarr: LidarrConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:8686",
+					DownloadDir: "/downloads",
+				},
+				Slskd: SlskdConfig{
+					APIKey:      "test",
+					HostURL:     "http://localhost:5030",
+					DownloadDir: "/downloads",
+				},
+				PathMappings: PathMappingSettings{
+					SlskdToLocal: []pathmap.Mapping{
+						{FromPrefix: "/app/downloads", ToPrefix: "/data/a"},
+						{FromPrefix: "/app/downloads/", ToPrefix: "/data/b"},
+					},
+				},
+			},
+			expectError: "path_mappings.slskd_to_local: mapping 2: from_prefix",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.setDefaults()
+			err := tt.config.Validate()
+			if err == nil {
+				t.Fatal("expected validation error, got nil")
+			}
+			// Check that error message starts with expected prefix
+			if tt.expectError != "" {
+				errMsg := err.Error()
+				hasPrefix := len(errMsg) >= len(tt.expectError) && errMsg[:len(tt.expectError)] == tt.expectError
+				if !hasPrefix {
+					t.Errorf("expected error starting with %q, got %q", tt.expectError, errMsg)
+				}
+			}
+		})
+	}
+}
+
+func TestSetDefaults(t *testing.T) {
+	cfg := &Config{}
+	cfg.setDefaults()
+
+	tests := []struct {
+		name     string
+		got      interface{}
+		expected interface{}
+	}{
+		{"URLBase", cfg.Slskd.URLBase, "/"},
+		{"LidarrURLBase", cfg.Lidarr.URLBase, "/"},
+		{"StalledTimeout", cfg.Slskd.StalledTimeout, 3600},
+		{"SearchTimeout", cfg.Search.SearchTimeout, 5000},
+		{"MinimumFilenameMatchRatio", cfg.Search.MinimumFilenameMatchRatio, 0.8},
+		{"MinimumTrackFraction", cfg.Search.MinimumTrackFraction, 0.8},
+		{"ConcurrentSearches", cfg.Search.ConcurrentSearches, 1},
+		{"SearchType", cfg.Search.SearchType, "incrementing_page"},
+		{"SearchWaitSeconds", cfg.Timing.SearchWaitSeconds, 5},
+		{"DownloadPollSeconds", cfg.Timing.DownloadPollSeconds, 10},
+		{"ImportPollSeconds", cfg.Timing.ImportPollSeconds, 2},
+		{"StallChecks", cfg.Timing.StallChecks, 5},
+		{"FileRetries", cfg.Download.FileRetries(), 3},
+		{"ShutdownTimeoutSeconds", cfg.Daemon.ShutdownTimeoutSeconds, 30},
+		{"APIListen", cfg.API.Listen, ":8687"},
+		{"ReportRetentionDays", cfg.Report.RetentionDays, 30},
+		{"HookTimeoutSeconds", cfg.Hooks.TimeoutSeconds, 300},
+		{"MusicBrainzBaseURL", cfg.MusicBrainz.BaseURL, "https://musicbrainz.org"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.expected {
+				t.Errorf("default %s = %v, expected %v", tt.name, tt.got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSaveRoundTrip(t *testing.T) {
+	cfg := &Config{
+		Lidarr: LidarrConfig{APIKey: "lidarr-key", HostURL: "http://lidarr:8686", DownloadDir: "/downloads"},
+		Slskd:  SlskdConfig{APIKey: "slskd-key", HostURL: "http://slskd:5030", DownloadDir: "/downloads"},
+		Search: SearchSettings{AllowedFiletypes: []string{"flac", "mp3 320"}},
+	}
+	cfg.setDefaults()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := cfg.Save(path); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	if loaded.Lidarr.APIKey != "lidarr-key" || loaded.Slskd.HostURL != "http://slskd:5030" {
+		t.Errorf("connection settings not preserved: %+v %+v", loaded.Lidarr, loaded.Slskd)
+	}
+	if len(loaded.Search.AllowedFiletypes) != 2 {
+		t.Errorf("allowed_filetypes not preserved: %v", loaded.Search.AllowedFiletypes)
+	}
+}
+
+func TestLocalDownloadDir(t *testing.T) {
+	cfg := &Config{
+		Slskd: SlskdConfig{DownloadDir: `C:\slskd\downloads`},
+		PathMappings: PathMappingSettings{
+			SlskdToLocal: []pathmap.Mapping{{FromPrefix: `C:\slskd`, ToPrefix: "/data/slskd"}},
+		},
+	}
+
+	if got := cfg.LocalDownloadDir(); got != "/data/slskd/downloads" {
+		t.Errorf("LocalDownloadDir() = %q, want %q", got, "/data/slskd/downloads")
+	}
+
+	cfg.PathMappings.SlskdToLocal = nil
+	if got := cfg.LocalDownloadDir(); got != `C:\slskd\downloads` {
+		t.Errorf("LocalDownloadDir() without mappings = %q, want unchanged", got)
 	}
 }
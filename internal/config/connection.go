@@ -0,0 +1,30 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// ConnectionSettings configures how connections to a service are kept open
+// between requests
+type ConnectionSettings struct {
+	MaxIdleConns       int  `yaml:"max_idle_connections"`            // kept open between requests, 0 for the default
+	IdleConnTimeoutSec int  `yaml:"idle_connection_timeout_seconds"` // 0 for the default
+	DisableHTTP2       bool `yaml:"disable_http2"`
+}
+
+// IdleConnTimeout returns how long an unused connection is kept, zero for the default
+func (c ConnectionSettings) IdleConnTimeout() time.Duration {
+	return time.Duration(c.IdleConnTimeoutSec) * time.Second
+}
+
+// validate checks the settings of the named service
+func (c ConnectionSettings) validate(service string) error {
+	if c.MaxIdleConns < 0 {
+		return fmt.Errorf("%s max_idle_connections must be non-negative, got %d", service, c.MaxIdleConns)
+	}
+	if c.IdleConnTimeoutSec < 0 {
+		return fmt.Errorf("%s idle_connection_timeout_seconds must be non-negative, got %d", service, c.IdleConnTimeoutSec)
+	}
+	return nil
+}
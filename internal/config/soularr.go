@@ -0,0 +1,265 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// soularrSetter applies a single Soularr option value to a Config
+type soularrSetter func(c *Config, value string) error
+
+// soularrOptions maps normalised "section.key" names from Soularr's config.ini
+// onto seekarr config fields
+var soularrOptions = map[string]soularrSetter{
+	"lidarr.api_key":      setString(func(c *Config) *string { return &c.Lidarr.APIKey }),
+	"lidarr.host_url":     setString(func(c *Config) *string { return &c.Lidarr.HostURL }),
+	"lidarr.download_dir": setString(func(c *Config) *string { return &c.Lidarr.DownloadDir }),
+	"lidarr.disable_sync": setBool(func(c *Config) *bool { return &c.Lidarr.DisableSync }),
+
+	"slskd.api_key":         setString(func(c *Config) *string { return &c.Slskd.APIKey }),
+	"slskd.host_url":        setString(func(c *Config) *string { return &c.Slskd.HostURL }),
+	"slskd.url_base":        setString(func(c *Config) *string { return &c.Slskd.URLBase }),
+	"slskd.download_dir":    setString(func(c *Config) *string { return &c.Slskd.DownloadDir }),
+	"slskd.delete_searches": setBool(func(c *Config) *bool { return &c.Slskd.DeleteSearches }),
+	"slskd.stalled_timeout": setInt(func(c *Config) *int { return &c.Slskd.StalledTimeout }),
+
+	"release_settings.use_most_common_tracknum": setBoolPtr(func(c *Config) **bool { return &c.Release.UseMostCommonTrackNum }),
+	"release_settings.allow_multi_disc":         setBoolPtr(func(c *Config) **bool { return &c.Release.AllowMultiDisc }),
+	"release_settings.accepted_countries":       setList(func(c *Config) *[]string { return &c.Release.AcceptedCountries }),
+	"release_settings.skip_region_check":        setBool(func(c *Config) *bool { return &c.Release.SkipRegionCheck }),
+	"release_settings.accepted_formats":         setList(func(c *Config) *[]string { return &c.Release.AcceptedFormats }),
+
+	"search_settings.search_timeout":               setInt(func(c *Config) *int { return &c.Search.SearchTimeout }),
+	"search_settings.maximum_peer_queue":           setInt(func(c *Config) *int { return &c.Search.MaximumPeerQueue }),
+	"search_settings.minimum_peer_upload_speed":    setInt(func(c *Config) *int { return &c.Search.MinimumPeerUploadSpeed }),
+	"search_settings.minimum_filename_match_ratio": setFloat(func(c *Config) *float64 { return &c.Search.MinimumFilenameMatchRatio }),
+	"search_settings.allowed_filetypes":            setList(func(c *Config) *[]string { return &c.Search.AllowedFiletypes }),
+	"search_settings.ignored_users":                setList(func(c *Config) *[]string { return &c.Search.IgnoredUsers }),
+	"search_settings.search_for_tracks":            setBool(func(c *Config) *bool { return &c.Search.SearchForTracks }),
+	"search_settings.album_prepend_artist":         setBool(func(c *Config) *bool { return &c.Search.AlbumPrependArtist }),
+	"search_settings.track_prepend_artist":         setBool(func(c *Config) *bool { return &c.Search.TrackPrependArtist }),
+	"search_settings.search_type":                  setString(func(c *Config) *string { return &c.Search.SearchType }),
+	"search_settings.number_of_albums_to_grab":     setInt(func(c *Config) *int { return &c.Search.NumberOfAlbumsToGrab }),
+	"search_settings.remove_wanted_on_failure":     setBool(func(c *Config) *bool { return &c.Search.RemoveWantedOnFailure }),
+	"search_settings.title_blacklist":              setList(func(c *Config) *[]string { return &c.Search.TitleBlacklist }),
+	"search_settings.search_source":                setString(func(c *Config) *string { return &c.Search.SearchSource }),
+	"search_settings.enable_search_denylist":       setBool(func(c *Config) *bool { return &c.Search.EnableSearchDenylist }),
+	"search_settings.max_search_failures":          setInt(func(c *Config) *int { return &c.Search.MaxSearchFailures }),
+
+	"download_settings.download_filtering":      setBool(func(c *Config) *bool { return &c.Download.DownloadFiltering }),
+	"download_settings.use_extension_whitelist": setBool(func(c *Config) *bool { return &c.Download.UseExtensionWhitelist }),
+	"download_settings.extensions_whitelist":    setList(func(c *Config) *[]string { return &c.Download.ExtensionsWhitelist }),
+
+	"logging.level": setString(func(c *Config) *string { return &c.Logging.Level }),
+}
+
+// soularrAliases maps option names used by older Soularr releases onto the current names
+var soularrAliases = map[string]string{
+	"search_settings.allowed_filetype": "search_settings.allowed_filetypes",
+	"search_settings.ignored_user":     "search_settings.ignored_users",
+}
+
+// soularrNotImplemented lists options that are carried over but currently have no effect in seekarr
+var soularrNotImplemented = map[string]bool{
+	"download_settings.download_filtering": true,
+}
+
+// soularrChanged explains options whose meaning differs in seekarr; they are not copied
+var soularrChanged = map[string]string{
+	"logging.format":  "Python logging format strings are not supported; set LOG_FORMAT=json or structured instead",
+	"logging.datefmt": "Python strftime formats are not supported; timestamps use RFC 3339",
+}
+
+// FromSoularr converts a Soularr config.ini into a seekarr Config
+// It returns human-readable warnings for every option that could not be carried
+// over unchanged. The returned config has defaults applied and has been validated
+func FromSoularr(r io.Reader) (*Config, []string, error) {
+	values, err := parseINI(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var cfg Config
+	var warnings []string
+
+	for _, opt := range values {
+		name := opt.name
+		if current, ok := soularrAliases[name]; ok {
+			warnings = append(warnings, fmt.Sprintf("%s: renamed to %s in newer Soularr releases, converted", opt.display, current))
+			name = current
+		}
+
+		if reason, ok := soularrChanged[name]; ok {
+			warnings = append(warnings, fmt.Sprintf("%s: not converted (%s)", opt.display, reason))
+			continue
+		}
+
+		setter, ok := soularrOptions[name]
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("%s: no seekarr equivalent, ignored", opt.display))
+			continue
+		}
+
+		if err := setter(&cfg, opt.value); err != nil {
+			return nil, warnings, fmt.Errorf("line %d: %s: %w", opt.line, opt.display, err)
+		}
+
+		if soularrNotImplemented[name] {
+			warnings = append(warnings, fmt.Sprintf("%s: converted, but not yet implemented by seekarr", opt.display))
+		}
+	}
+
+	// Behaviour seekarr does not make optional
+	if !cfg.Search.EnableSearchDenylist && hasOption(values, "search_settings.enable_search_denylist") {
+		warnings = append(warnings, "search_settings.enable_search_denylist: seekarr always tracks search failures; use max_search_failures to tune it")
+	}
+
+	cfg.setDefaults()
+	if err := cfg.Validate(); err != nil {
+		return nil, warnings, fmt.Errorf("converted config is invalid: %w", err)
+	}
+
+	return &cfg, warnings, nil
+}
+
+// iniOption is a single key/value pair from an INI file
+type iniOption struct {
+	name    string // normalised "section.key"
+	display string // as written in the file, for messages
+	value   string
+	line    int
+}
+
+// parseINI reads the subset of Python configparser syntax Soularr configs use:
+// [sections], "key = value" or "key: value", and # or ; comment lines
+func parseINI(r io.Reader) ([]iniOption, error) {
+	var options []iniOption
+	section := ""
+	sectionDisplay := ""
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			sectionDisplay = strings.TrimSpace(line[1 : len(line)-1])
+			section = normaliseININame(sectionDisplay)
+			continue
+		}
+
+		sep := strings.IndexAny(line, "=:")
+		if sep < 0 {
+			return nil, fmt.Errorf("line %d: expected key = value, got %q", lineNo, line)
+		}
+		if section == "" {
+			return nil, fmt.Errorf("line %d: option outside of a section", lineNo)
+		}
+
+		key := strings.TrimSpace(line[:sep])
+		options = append(options, iniOption{
+			name:    section + "." + normaliseININame(key),
+			display: sectionDisplay + "." + key,
+			value:   strings.TrimSpace(line[sep+1:]),
+			line:    lineNo,
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read ini: %w", err)
+	}
+
+	return options, nil
+}
+
+// normaliseININame lowercases a name and joins words with underscores so
+// "Search Settings" and "search_settings" compare equal
+func normaliseININame(name string) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(strings.ToLower(name), "_", " ")), "_")
+}
+
+// hasOption reports whether the named option appeared in the file
+func hasOption(options []iniOption, name string) bool {
+	for _, opt := range options {
+		if opt.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func setString(field func(*Config) *string) soularrSetter {
+	return func(c *Config, value string) error {
+		*field(c) = value
+		return nil
+	}
+}
+
+func setBool(field func(*Config) *bool) soularrSetter {
+	return func(c *Config, value string) error {
+		// configparser's getboolean accepts these spellings
+		switch strings.ToLower(value) {
+		case "1", "yes", "true", "on":
+			*field(c) = true
+		case "0", "no", "false", "off":
+			*field(c) = false
+		default:
+			return fmt.Errorf("invalid boolean %q", value)
+		}
+		return nil
+	}
+}
+
+func setBoolPtr(field func(*Config) **bool) soularrSetter {
+	return func(c *Config, value string) error {
+		var b bool
+		if err := setBool(func(*Config) *bool { return &b })(c, value); err != nil {
+			return err
+		}
+		*field(c) = &b
+		return nil
+	}
+}
+
+func setInt(field func(*Config) *int) soularrSetter {
+	return func(c *Config, value string) error {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", value)
+		}
+		*field(c) = n
+		return nil
+	}
+}
+
+func setFloat(field func(*Config) *float64) soularrSetter {
+	return func(c *Config, value string) error {
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", value)
+		}
+		*field(c) = f
+		return nil
+	}
+}
+
+func setList(field func(*Config) *[]string) soularrSetter {
+	return func(c *Config, value string) error {
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		*field(c) = items
+		return nil
+	}
+}
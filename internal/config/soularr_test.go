@@ -0,0 +1,173 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func loadSoularrFixture(t *testing.T, name string) (*Config, []string) {
+	t.Helper()
+
+	f, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatalf("open fixture: %v", err)
+	}
+	defer f.Close()
+
+	cfg, warnings, err := FromSoularr(f)
+	if err != nil {
+		t.Fatalf("FromSoularr() error: %v", err)
+	}
+	return cfg, warnings
+}
+
+// hasWarning reports whether any warning starts with prefix
+func hasWarning(warnings []string, prefix string) bool {
+	for _, w := range warnings {
+		if strings.HasPrefix(w, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestFromSoularr_Current(t *testing.T) {
+	cfg, warnings := loadSoularrFixture(t, "soularr_current.ini")
+
+	tests := []struct {
+		name     string
+		got      interface{}
+		expected interface{}
+	}{
+		{"LidarrAPIKey", cfg.Lidarr.APIKey, "0123456789abcdef0123456789abcdef"},
+		{"LidarrHostURL", cfg.Lidarr.HostURL, "http://lidarr:8686"},
+		{"LidarrDownloadDir", cfg.Lidarr.DownloadDir, "/lidarr/downloads"},
+		{"SlskdDownloadDir", cfg.Slskd.DownloadDir, "/slskd/downloads"},
+		{"StalledTimeout", cfg.Slskd.StalledTimeout, 3600},
+		{"AcceptedCountries", len(cfg.Release.AcceptedCountries), 7},
+		{"AllowMultiDisc", cfg.Release.AllowMultiDisc != nil && *cfg.Release.AllowMultiDisc, true},
+		{"AllowedFiletypes", cfg.Search.AllowedFiletypes, []string{"flac 24/192", "flac 16/44.1", "flac", "mp3 320", "mp3"}},
+		{"IgnoredUsers", cfg.Search.IgnoredUsers, []string{"User1", "User2", "Fred", "Bob"}},
+		{"MatchRatio", cfg.Search.MinimumFilenameMatchRatio, 0.8},
+		{"SearchType", cfg.Search.SearchType, "incrementing_page"},
+		{"TitleBlacklist", cfg.Search.TitleBlacklist, []string{"Word1", "word2"}},
+		{"TrackPrependArtist", cfg.Search.TrackPrependArtist, true},
+		{"ExtensionsWhitelist", cfg.Download.ExtensionsWhitelist, []string{"lrc", "nfo", "txt"}},
+		{"LoggingLevel", cfg.Logging.Level, "INFO"},
+		{"LoggingFormat", cfg.Logging.Format, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.expected) {
+				t.Errorf("got %v, want %v", tt.got, tt.expected)
+			}
+		})
+	}
+
+	for _, prefix := range []string{
+		"Logging.format: not converted",
+		"Logging.datefmt: not converted",
+		"Download Settings.download_filtering: converted, but not yet implemented",
+	} {
+		if !hasWarning(warnings, prefix) {
+			t.Errorf("expected warning starting with %q, got %v", prefix, warnings)
+		}
+	}
+}
+
+func TestFromSoularr_Legacy(t *testing.T) {
+	cfg, warnings := loadSoularrFixture(t, "soularr_legacy.ini")
+
+	if !reflect.DeepEqual(cfg.Search.AllowedFiletypes, []string{"flac"}) {
+		t.Errorf("allowed_filetype not converted: %v", cfg.Search.AllowedFiletypes)
+	}
+	if !reflect.DeepEqual(cfg.Search.IgnoredUsers, []string{"leecher42"}) {
+		t.Errorf("ignored_user not converted: %v", cfg.Search.IgnoredUsers)
+	}
+	if !reflect.DeepEqual(cfg.Search.TitleBlacklist, []string{"live", "remaster"}) {
+		t.Errorf("search_blacklist not converted: %v", cfg.Search.TitleBlacklist)
+	}
+	if !cfg.Slskd.DeleteSearches {
+		t.Error("delete_searches: yes should convert to true")
+	}
+	if cfg.Search.SearchSource != "all" {
+		t.Errorf("expected search_source all, got %q", cfg.Search.SearchSource)
+	}
+	if cfg.Search.SearchTimeout != 10000 {
+		t.Errorf("expected search_timeout 10000, got %d", cfg.Search.SearchTimeout)
+	}
+
+	// Defaults are applied for options the old config didn't have
+	if cfg.Slskd.URLBase != "/" {
+		t.Errorf("expected default url_base, got %q", cfg.Slskd.URLBase)
+	}
+
+	for _, prefix := range []string{
+		"search_settings.allowed_filetype: renamed",
+		"search_settings.enable_search_denylist: seekarr always tracks search failures",
+	} {
+		if !hasWarning(warnings, prefix) {
+			t.Errorf("expected warning starting with %q, got %v", prefix, warnings)
+		}
+	}
+}
+
+func TestFromSoularr_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		ini         string
+		expectError string
+	}{
+		{
+			name:        "option outside section",
+			ini:         "api_key = abc\n",
+			expectError: "line 1: option outside of a section",
+		},
+		{
+			name:        "invalid boolean",
+			ini:         "[Lidarr]\ndisable_sync = maybe\n",
+			expectError: "line 2: Lidarr.disable_sync: invalid boolean",
+		},
+		{
+			name:        "missing required fields",
+			ini:         "[Lidarr]\nhost_url = http://lidarr:8686\n",
+			expectError: "converted config is invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := FromSoularr(strings.NewReader(tt.ini))
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.HasPrefix(err.Error(), tt.expectError) {
+				t.Errorf("expected error starting with %q, got %q", tt.expectError, err.Error())
+			}
+		})
+	}
+}
+
+func TestFromSoularr_UnknownOption(t *testing.T) {
+	ini := `[Lidarr]
+api_key = abc
+host_url = http://lidarr:8686
+download_dir = /downloads
+
+[Slskd]
+api_key = def
+host_url = http://slskd:5030
+download_dir = /downloads
+some_future_option = 1
+`
+	_, warnings, err := FromSoularr(strings.NewReader(ini))
+	if err != nil {
+		t.Fatalf("FromSoularr() error: %v", err)
+	}
+	if !hasWarning(warnings, "Slskd.some_future_option: no seekarr equivalent") {
+		t.Errorf("expected unknown option warning, got %v", warnings)
+	}
+}
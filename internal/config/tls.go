@@ -0,0 +1,39 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// TLSSettings configures how seekarr verifies a service reached over HTTPS
+type TLSSettings struct {
+	SkipVerify bool   `yaml:"tls_skip_verify,omitempty"` // accept any certificate
+	CAFile     string `yaml:"tls_ca_file,omitempty"`     // PEM bundle trusted alongside the system roots
+}
+
+// ClientConfig builds the TLS configuration for the service, nil when the
+// defaults apply
+func (t TLSSettings) ClientConfig() (*tls.Config, error) {
+	if !t.SkipVerify && t.CAFile == "" {
+		return nil, nil
+	}
+
+	config := &tls.Config{InsecureSkipVerify: t.SkipVerify}
+	if t.CAFile != "" {
+		pem, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read tls_ca_file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("tls_ca_file %s contains no PEM certificates", t.CAFile)
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTLSSettings_ClientConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, certPEM, 0644); err != nil {
+		t.Fatal(err)
+	}
+	notPEM := filepath.Join(dir, "not.pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		settings   TLSSettings
+		wantConfig bool
+		wantErr    string
+	}{
+		{name: "defaults", settings: TLSSettings{}},
+		{name: "skip verify", settings: TLSSettings{SkipVerify: true}, wantConfig: true},
+		{name: "ca file", settings: TLSSettings{CAFile: caFile}, wantConfig: true},
+		{name: "missing ca file", settings: TLSSettings{CAFile: filepath.Join(dir, "missing.pem")}, wantErr: "read tls_ca_file"},
+		{name: "ca file without certificates", settings: TLSSettings{CAFile: notPEM}, wantErr: "contains no PEM certificates"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := tt.settings.ClientConfig()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ClientConfig() error: %v", err)
+			}
+			if (config != nil) != tt.wantConfig {
+				t.Fatalf("expected config %v, got %+v", tt.wantConfig, config)
+			}
+			if config == nil {
+				return
+			}
+			if config.InsecureSkipVerify != tt.settings.SkipVerify {
+				t.Errorf("expected InsecureSkipVerify %v", tt.settings.SkipVerify)
+			}
+			if tt.settings.CAFile != "" {
+				client := &http.Client{Transport: &http.Transport{TLSClientConfig: config}}
+				resp, err := client.Get(server.URL)
+				if err != nil {
+					t.Fatalf("expected the CA to be trusted: %v", err)
+				}
+				resp.Body.Close()
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package filter
 
 import (
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -41,11 +42,58 @@ func (f *Filter) FileMatches(file slskd.SearchFile) bool {
 	return false
 }
 
+// MatchedFiletype returns the first allowed filetype pattern a file satisfies
+// (e.g. "flac 24/192"), or the bare extension when no filter is configured
+func (f *Filter) MatchedFiletype(file slskd.SearchFile) string {
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	if ext == "" {
+		return ""
+	}
+	ext = ext[1:] // Remove leading dot
+
+	for _, allowedType := range f.allowedFiletypes {
+		if f.matchesFiletype(file, ext, allowedType) {
+			return allowedType
+		}
+	}
+
+	return ext
+}
+
+// Rank returns the position of the first allowed filetype a file satisfies,
+// where 0 is the most preferred. Files matching none rank after every pattern
+func (f *Filter) Rank(file slskd.SearchFile) int {
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	if ext == "" {
+		return len(f.allowedFiletypes)
+	}
+	ext = ext[1:] // Remove leading dot
+
+	for i, allowedType := range f.allowedFiletypes {
+		if f.matchesFiletype(file, ext, allowedType) {
+			return i
+		}
+	}
+
+	return len(f.allowedFiletypes)
+}
+
+// AllowedCount returns the number of allowed filetype patterns
+func (f *Filter) AllowedCount() int {
+	return len(f.allowedFiletypes)
+}
+
+// AllowedFiletypes returns the allowed filetype patterns, most preferred first
+func (f *Filter) AllowedFiletypes() []string {
+	return slices.Clone(f.allowedFiletypes)
+}
+
 // matchesFiletype checks if a file matches a specific filetype pattern
 // Patterns can be:
 // - "flac" (any FLAC file)
 // - "flac 24/192" (FLAC with 24-bit depth and 192kHz sample rate)
 // - "flac 16/44.1" (FLAC with 16-bit depth and 44.1kHz sample rate)
+// - "flac 24" (FLAC with 24-bit depth at any sample rate)
 // - "mp3" (any MP3 file)
 // - "mp3 320" (MP3 with 320kbps bitrate)
 func (f *Filter) matchesFiletype(file slskd.SearchFile, ext, pattern string) bool {
@@ -67,8 +115,15 @@ func (f *Filter) matchesFiletype(file slskd.SearchFile, ext, pattern string) boo
 
 	// Check bitrate/quality specifications
 	if ext == "flac" && len(parts) == 2 {
-		// Format: "flac 24/192" or "flac 16/44.1"
+		// Format: "flac 24/192", "flac 16/44.1" or "flac 24"
 		qualityParts := strings.Split(parts[1], "/")
+		if len(qualityParts) == 1 {
+			wantedDepth, err := strconv.Atoi(qualityParts[0])
+			if err != nil {
+				return false
+			}
+			return file.BitDepth != nil && *file.BitDepth == wantedDepth
+		}
 		if len(qualityParts) == 2 {
 			wantedDepth, err1 := strconv.Atoi(qualityParts[0])
 			wantedRate, err2 := parseFloatRate(qualityParts[1])
@@ -158,6 +213,7 @@ func (f *Filter) FilterFilesDebug(files []slskd.SearchFile) ([]slskd.SearchFile,
 			BitRate:    file.BitRate,
 			SampleRate: file.SampleRate,
 			BitDepth:   file.BitDepth,
+			Length:     file.Length,
 			Matched:    matched,
 		}
 		filterInfo = append(filterInfo, info)
@@ -173,5 +229,6 @@ type FileFilterInfo struct {
 	BitRate    *int
 	SampleRate *int
 	BitDepth   *int
+	Length     *int // seconds
 	Matched    bool
 }
@@ -50,6 +50,22 @@ func TestFileMatches(t *testing.T) {
 			file:             slskd.SearchFile{Filename: "test"},
 			want:             false,
 		},
+		{
+			name:             "flac 24 matches any 24-bit sample rate",
+			allowedFiletypes: []string{"flac 24"},
+			file: slskd.SearchFile{
+				Filename:   "test.flac",
+				BitDepth:   intPtr(24),
+				SampleRate: intPtr(96000),
+			},
+			want: true,
+		},
+		{
+			name:             "flac 24 rejects 16-bit and unknown depth",
+			allowedFiletypes: []string{"flac 24"},
+			file:             slskd.SearchFile{Filename: "test.flac"},
+			want:             false,
+		},
 		{
 			name:             "flac 24/192 matches exact spec",
 			allowedFiletypes: []string{"flac 24/192"},
@@ -266,6 +282,7 @@ func TestFilterFilesDebug(t *testing.T) {
 		files            []slskd.SearchFile
 		wantFiltered     int
 		wantInfo         int
+		wantLength       *int
 	}{
 		{
 			name:             "no filter returns all with no info",
@@ -296,10 +313,12 @@ func TestFilterFilesDebug(t *testing.T) {
 					BitDepth:   intPtr(24),
 					SampleRate: intPtr(192000),
 					BitRate:    intPtr(1200),
+					Length:     intPtr(245),
 				},
 			},
 			wantFiltered: 1,
 			wantInfo:     1,
+			wantLength:   intPtr(245),
 		},
 	}
 
@@ -322,6 +341,9 @@ func TestFilterFilesDebug(t *testing.T) {
 					t.Error("FileFilterInfo has empty Filename")
 				}
 			}
+			if tt.wantLength != nil && (info[0].Length == nil || *info[0].Length != *tt.wantLength) {
+				t.Errorf("FileFilterInfo Length = %v, want %d", info[0].Length, *tt.wantLength)
+			}
 		})
 	}
 }
@@ -329,3 +351,71 @@ func TestFilterFilesDebug(t *testing.T) {
 func intPtr(i int) *int {
 	return &i
 }
+
+func TestMatchedFiletype(t *testing.T) {
+	f := NewFilter([]string{"flac 24/192", "flac", "mp3 320"})
+
+	tests := []struct {
+		name string
+		file slskd.SearchFile
+		want string
+	}{
+		{
+			name: "most specific pattern listed first",
+			file: slskd.SearchFile{Filename: "a.flac", BitDepth: intPtr(24), SampleRate: intPtr(192000)},
+			want: "flac 24/192",
+		},
+		{
+			name: "falls back to generic pattern",
+			file: slskd.SearchFile{Filename: "a.FLAC", BitDepth: intPtr(16), SampleRate: intPtr(44100)},
+			want: "flac",
+		},
+		{
+			name: "mp3 bitrate",
+			file: slskd.SearchFile{Filename: "a.mp3", BitRate: intPtr(320)},
+			want: "mp3 320",
+		},
+		{
+			name: "unmatched file reports its extension",
+			file: slskd.SearchFile{Filename: "a.ogg"},
+			want: "ogg",
+		},
+		{
+			name: "no extension",
+			file: slskd.SearchFile{Filename: "a"},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := f.MatchedFiletype(tt.file); got != tt.want {
+				t.Errorf("MatchedFiletype() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRank(t *testing.T) {
+	f := NewFilter([]string{"flac 24/192", "flac", "mp3 320"})
+
+	tests := []struct {
+		name string
+		file slskd.SearchFile
+		want int
+	}{
+		{"hi-res flac", slskd.SearchFile{Filename: "a.flac", BitDepth: intPtr(24), SampleRate: intPtr(192000)}, 0},
+		{"cd flac", slskd.SearchFile{Filename: "a.flac", BitDepth: intPtr(16), SampleRate: intPtr(44100)}, 1},
+		{"mp3 320", slskd.SearchFile{Filename: "a.mp3", BitRate: intPtr(320)}, 2},
+		{"unmatched", slskd.SearchFile{Filename: "a.mp3", BitRate: intPtr(128)}, 3},
+		{"no extension", slskd.SearchFile{Filename: "a"}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := f.Rank(tt.file); got != tt.want {
+				t.Errorf("Rank() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
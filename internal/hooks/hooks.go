@@ -0,0 +1,122 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Album describes an imported album passed to post-import hooks
+type Album struct {
+	Artist  string
+	Album   string
+	AlbumID int
+	Path    string
+	Quality string
+}
+
+// Runner executes user-configured hook commands
+// Commands are run directly (not through a shell) one after another
+type Runner struct {
+	postImport []string
+	postRun    []string
+	timeout    time.Duration
+	logger     *slog.Logger
+}
+
+// NewRunner creates a hook runner
+func NewRunner(postImport, postRun []string, timeout time.Duration, logger *slog.Logger) *Runner {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Runner{
+		postImport: postImport,
+		postRun:    postRun,
+		timeout:    timeout,
+		logger:     logger,
+	}
+}
+
+// PostImport runs the post-import hooks for one imported album
+// Failures are logged and never returned
+func (r *Runner) PostImport(ctx context.Context, album Album) {
+	env := []string{
+		"SEEKARR_ARTIST=" + album.Artist,
+		"SEEKARR_ALBUM=" + album.Album,
+		"SEEKARR_ALBUM_ID=" + strconv.Itoa(album.AlbumID),
+		"SEEKARR_PATH=" + album.Path,
+		"SEEKARR_QUALITY=" + album.Quality,
+	}
+
+	for _, command := range r.postImport {
+		if err := r.run(ctx, command, env, nil); err != nil {
+			r.logger.Warn("post-import hook failed",
+				"command", command,
+				"album", album.Album,
+				"artist", album.Artist,
+				"error", err)
+		}
+	}
+}
+
+// PostRun runs the post-run hooks with the run summary JSON on stdin
+// Failures are logged and never returned
+func (r *Runner) PostRun(ctx context.Context, summary []byte) {
+	for _, command := range r.postRun {
+		if err := r.run(ctx, command, nil, bytes.NewReader(summary)); err != nil {
+			r.logger.Warn("post-run hook failed", "command", command, "error", err)
+		}
+	}
+}
+
+// HasPostImport reports whether any post-import hooks are configured
+func (r *Runner) HasPostImport() bool {
+	return len(r.postImport) > 0
+}
+
+// HasPostRun reports whether any post-run hooks are configured
+func (r *Runner) HasPostRun() bool {
+	return len(r.postRun) > 0
+}
+
+// run executes a single hook command with a timeout
+func (r *Runner) run(ctx context.Context, command string, env []string, stdin io.Reader) error {
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, command)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin = stdin
+
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	// Don't hang on grandchildren still holding the output pipe after a kill
+	cmd.WaitDelay = time.Second
+
+	start := time.Now()
+	err := cmd.Run()
+
+	r.logger.Debug("hook finished",
+		"command", command,
+		"elapsed", time.Since(start),
+		"output", strings.TrimSpace(output.String()))
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timed out after %s", r.timeout)
+	}
+	if err != nil {
+		return fmt.Errorf("run %s: %w", command, err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeScript creates an executable shell script for a test
+func writeScript(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "hook.sh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestPostImport_Environment(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "env.txt")
+	script := writeScript(t, `echo "$SEEKARR_ARTIST|$SEEKARR_ALBUM|$SEEKARR_ALBUM_ID|$SEEKARR_PATH|$SEEKARR_QUALITY" > `+out+"\n")
+
+	r := NewRunner([]string{script}, nil, 5*time.Second, nil)
+	r.PostImport(context.Background(), Album{
+		Artist:  "Artist",
+		Album:   "Album",
+		AlbumID: 42,
+		Path:    "/downloads/Artist/Album",
+		Quality: "flac 16/44.1",
+	})
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("hook did not run: %v", err)
+	}
+	want := "Artist|Album|42|/downloads/Artist/Album|flac 16/44.1"
+	if got := strings.TrimSpace(string(data)); got != want {
+		t.Errorf("hook env = %q, want %q", got, want)
+	}
+}
+
+func TestPostRun_Stdin(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "stdin.json")
+	script := writeScript(t, "cat > "+out+"\n")
+
+	r := NewRunner(nil, []string{script}, 5*time.Second, nil)
+	r.PostRun(context.Background(), []byte(`{"wanted":3}`))
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("hook did not run: %v", err)
+	}
+	if string(data) != `{"wanted":3}` {
+		t.Errorf("hook stdin = %q", data)
+	}
+}
+
+func TestHookFailuresAreLogged(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		timeout time.Duration
+		want    string
+	}{
+		{"non-zero exit", "exit 3\n", 5 * time.Second, "exit status 3"},
+		{"timeout", "sleep 5\n", 100 * time.Millisecond, "timed out"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+			r := NewRunner([]string{writeScript(t, tt.body)}, nil, tt.timeout, logger)
+			r.PostImport(context.Background(), Album{Artist: "A", Album: "B"})
+
+			if !strings.Contains(logs.String(), "post-import hook failed") || !strings.Contains(logs.String(), tt.want) {
+				t.Errorf("expected warning containing %q, got %q", tt.want, logs.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+)
+
+// Connection pool defaults, shared by the Lidarr and slskd clients so both
+// behave alike behind a proxy; sized for monitoring polls running alongside
+// concurrent searches
+const (
+	DefaultMaxIdleConns    = 4
+	DefaultIdleConnTimeout = 90 * time.Second
+)
+
+// ConnectionPool controls how connections to a service are kept open between
+// requests, so polling doesn't set up a new TLS session each time
+type ConnectionPool struct {
+	MaxIdleConns    int           // Idle connections kept open (default: 4)
+	IdleConnTimeout time.Duration // How long an idle connection is kept (default: 90s)
+	DisableHTTP2    bool          // Only speak HTTP/1.1, e.g. behind a proxy mishandling HTTP/2
+}
+
+// Apply configures transport to pool connections as described, filling in defaults
+func (pool ConnectionPool) Apply(transport *http.Transport) {
+	if pool.MaxIdleConns <= 0 {
+		pool.MaxIdleConns = DefaultMaxIdleConns
+	}
+	if pool.IdleConnTimeout <= 0 {
+		pool.IdleConnTimeout = DefaultIdleConnTimeout
+	}
+
+	transport.MaxIdleConnsPerHost = pool.MaxIdleConns
+	transport.MaxIdleConns = max(transport.MaxIdleConns, pool.MaxIdleConns)
+	transport.IdleConnTimeout = pool.IdleConnTimeout
+	transport.ForceAttemptHTTP2 = !pool.DisableHTTP2
+	if pool.DisableHTTP2 {
+		// A non-nil empty map stops HTTPS connections from negotiating HTTP/2
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+}
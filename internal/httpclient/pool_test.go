@@ -0,0 +1,37 @@
+package httpclient
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestConnectionPoolApply(t *testing.T) {
+	tests := []struct {
+		name        string
+		pool        ConnectionPool
+		maxIdle     int
+		idleTimeout time.Duration
+		http2       bool
+	}{
+		{"defaults", ConnectionPool{}, DefaultMaxIdleConns, DefaultIdleConnTimeout, true},
+		{"tuned", ConnectionPool{MaxIdleConns: 8, IdleConnTimeout: 30 * time.Second, DisableHTTP2: true}, 8, 30 * time.Second, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := http.DefaultTransport.(*http.Transport).Clone()
+			tt.pool.Apply(transport)
+
+			if transport.MaxIdleConnsPerHost != tt.maxIdle || transport.MaxIdleConns < tt.maxIdle {
+				t.Errorf("expected %d idle connections per host, got %d (total %d)", tt.maxIdle, transport.MaxIdleConnsPerHost, transport.MaxIdleConns)
+			}
+			if transport.IdleConnTimeout != tt.idleTimeout {
+				t.Errorf("expected a %s idle timeout, got %s", tt.idleTimeout, transport.IdleConnTimeout)
+			}
+			if transport.ForceAttemptHTTP2 != tt.http2 || (!tt.http2 && transport.TLSNextProto == nil) {
+				t.Errorf("expected HTTP/2 %v, got %v", tt.http2, transport.ForceAttemptHTTP2)
+			}
+		})
+	}
+}
@@ -1,41 +1,140 @@
 package lidarr
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/yuritomanek/seekarr/internal/httpclient"
 )
 
 // Client defines the interface for interacting with Lidarr API
 type Client interface {
 	GetWanted(ctx context.Context, opts GetWantedOptions) (*WantedResponse, error)
+	GetAllWanted(ctx context.Context, opts GetWantedOptions) ([]Album, error)
 	GetAlbum(ctx context.Context, id int) (*Album, error)
+	GetArtist(ctx context.Context, id int) (*Artist, error)
 	GetTracks(ctx context.Context, albumID int, releaseID *int) ([]Track, error)
+	GetTrackFiles(ctx context.Context, albumID int) ([]TrackFile, error)
 	UpdateAlbum(ctx context.Context, album *Album) (*Album, error)
 	GetQueue(ctx context.Context, page int, pageSize int) (*QueueResponse, error)
+	GetAllQueue(ctx context.Context, pageSize int) ([]QueueItem, error)
+	DeleteQueueItem(ctx context.Context, id int, removeFromClient, blocklist bool) error
 	PostCommand(ctx context.Context, cmd Command) (*CommandResponse, error)
 	GetCommand(ctx context.Context, id int) (*CommandResponse, error)
+	GetQualityProfiles(ctx context.Context) ([]QualityProfile, error)
+	GetSystemStatus(ctx context.Context) (*SystemStatus, error)
+	GetHistorySince(ctx context.Context, since time.Time, eventType string) ([]HistoryRecord, error)
+	GetManualImport(ctx context.Context, folder string, albumID int) ([]ManualImportItem, error)
+	PostManualImport(ctx context.Context, items []ManualImportItem) (*CommandResponse, error)
 }
 
 // client implements the Lidarr API client
 type client struct {
 	baseURL    string
+	urlBase    string
 	apiKey     string
 	httpClient *http.Client
+	transport  *http.Transport // Underlying transport, even when wrapped
+	retry      RetryPolicy
+	logger     *slog.Logger
+
+	// timeout bounds each GET; commandTimeout other requests, such as
+	// commands that start import scans, and manual import listings
+	timeout        time.Duration
+	commandTimeout time.Duration
+}
+
+// Request timeouts unless WithTimeout says otherwise
+const (
+	DefaultTimeout        = 30 * time.Second
+	DefaultCommandTimeout = 5 * time.Minute
+)
+
+// Option configures optional client behaviour
+type Option func(*client)
+
+// WithTimeout limits how long a GET may take, including reading the response.
+// Zero leaves GETs bounded only by their context's deadline. Other requests
+// and manual import listings keep DefaultCommandTimeout, or timeout if it is
+// longer
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *client) {
+		c.timeout = timeout
+		if timeout > c.commandTimeout {
+			c.commandTimeout = timeout
+		}
+	}
+}
+
+// WithTransport sets the HTTP transport used for requests (e.g. for tracing)
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *client) {
+		c.httpClient.Transport = rt
+	}
+}
+
+// WrapTransport wraps the HTTP transport used for requests (e.g. for tracing),
+// keeping its TLS and proxy settings
+func WrapTransport(wrap func(http.RoundTripper) http.RoundTripper) Option {
+	return func(c *client) {
+		c.httpClient.Transport = wrap(c.httpClient.Transport)
+	}
+}
+
+// WithTLSConfig sets how HTTPS connections are verified, e.g. to trust a
+// private certificate authority
+func WithTLSConfig(config *tls.Config) Option {
+	return func(c *client) {
+		c.transport.TLSClientConfig = config
+	}
+}
+
+// WithConnectionPool tunes how connections to Lidarr are reused
+func WithConnectionPool(pool httpclient.ConnectionPool) Option {
+	return func(c *client) {
+		pool.Apply(c.transport)
+	}
+}
+
+// newTransport returns the default transport, taking proxies from the
+// environment (HTTPS_PROXY, HTTP_PROXY and NO_PROXY) and keeping connections
+// open as the default connection pool describes
+func newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	httpclient.ConnectionPool{}.Apply(transport)
+	return transport
 }
 
 // NewClient creates a new Lidarr API client
-func NewClient(baseURL, apiKey string) Client {
-	return &client{
-		baseURL:    strings.TrimSuffix(baseURL, "/"),
-		apiKey:     apiKey,
-		httpClient: &http.Client{Timeout: 5 * time.Minute}, // Longer timeout for import scans
+// urlBase is the path Lidarr is served under, e.g. "/lidarr" behind a reverse
+// proxy; a path in baseURL works as well
+func NewClient(baseURL, apiKey, urlBase string, opts ...Option) Client {
+	transport := newTransport()
+	c := &client{
+		baseURL:        strings.TrimRight(baseURL, "/"),
+		urlBase:        strings.Trim(urlBase, "/"),
+		apiKey:         apiKey,
+		httpClient:     &http.Client{Transport: transport}, // Requests are bounded by send
+		transport:      transport,
+		logger:         slog.New(slog.DiscardHandler),
+		timeout:        DefaultTimeout,
+		commandTimeout: DefaultCommandTimeout,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // GetWantedOptions configures a GetWanted request
@@ -45,6 +144,9 @@ type GetWantedOptions struct {
 	SortKey  string
 	SortDir  string
 	Missing  bool // true for missing, false for cutoff_unmet
+
+	// Monitored asks Lidarr for monitored albums only
+	Monitored bool
 }
 
 // GetWanted fetches wanted albums (missing or cutoff unmet)
@@ -67,6 +169,10 @@ func (c *client) GetWanted(ctx context.Context, opts GetWantedOptions) (*WantedR
 	if opts.SortDir != "" {
 		params.Set("sortDir", opts.SortDir)
 	}
+	if opts.Monitored {
+		params.Set("monitored", "true")
+	}
+	params.Set("includeArtist", "true") // Lidarr leaves the artist out otherwise
 
 	var response WantedResponse
 	if err := c.doRequest(ctx, "GET", endpoint, params, nil, &response); err != nil {
@@ -76,6 +182,46 @@ func (c *client) GetWanted(ctx context.Context, opts GetWantedOptions) (*WantedR
 	return &response, nil
 }
 
+// maxWantedPages bounds GetAllWanted, in case Lidarr keeps reporting more records
+const maxWantedPages = 1000
+
+// GetAllWanted fetches every page of wanted albums, starting from the first
+// whatever opts.Page is. Records shift between pages when Lidarr imports or
+// adds albums meanwhile, so albums are deduplicated by ID, each page's total
+// is taken as current, and paging stops at a page that adds nothing new
+func (c *client) GetAllWanted(ctx context.Context, opts GetWantedOptions) ([]Album, error) {
+	if opts.PageSize <= 0 {
+		opts.PageSize = 50
+	}
+
+	seen := make(map[int]bool)
+	var albums []Album
+	for page := 1; page <= maxWantedPages; page++ {
+		opts.Page = page
+		resp, err := c.GetWanted(ctx, opts)
+		if err != nil {
+			return nil, fmt.Errorf("page %d: %w", page, err)
+		}
+
+		added := 0
+		for _, album := range resp.Records {
+			if seen[album.ID] {
+				continue
+			}
+			seen[album.ID] = true
+			albums = append(albums, album)
+			added++
+		}
+
+		if added == 0 || len(resp.Records) < opts.PageSize || page*opts.PageSize >= resp.TotalRecords {
+			return albums, nil
+		}
+	}
+
+	c.logger.Warn("stopped fetching wanted albums at the page limit", "pages", maxWantedPages, "albums", len(albums))
+	return albums, nil
+}
+
 // GetAlbum fetches a specific album by ID
 func (c *client) GetAlbum(ctx context.Context, id int) (*Album, error) {
 	endpoint := fmt.Sprintf("/api/v1/album/%d", id)
@@ -88,6 +234,18 @@ func (c *client) GetAlbum(ctx context.Context, id int) (*Album, error) {
 	return &album, nil
 }
 
+// GetArtist fetches a specific artist by ID
+func (c *client) GetArtist(ctx context.Context, id int) (*Artist, error) {
+	endpoint := fmt.Sprintf("/api/v1/artist/%d", id)
+
+	var artist Artist
+	if err := c.doRequest(ctx, "GET", endpoint, nil, nil, &artist); err != nil {
+		return nil, fmt.Errorf("get artist %d: %w", id, err)
+	}
+
+	return &artist, nil
+}
+
 // GetTracks fetches tracks for an album, optionally filtered by release
 func (c *client) GetTracks(ctx context.Context, albumID int, releaseID *int) ([]Track, error) {
 	endpoint := "/api/v1/track"
@@ -106,6 +264,19 @@ func (c *client) GetTracks(ctx context.Context, albumID int, releaseID *int) ([]
 	return tracks, nil
 }
 
+// GetTrackFiles fetches the files Lidarr has imported for an album's tracks
+func (c *client) GetTrackFiles(ctx context.Context, albumID int) ([]TrackFile, error) {
+	params := url.Values{}
+	params.Set("albumId", fmt.Sprintf("%d", albumID))
+
+	var files []TrackFile
+	if err := c.doRequest(ctx, "GET", "/api/v1/trackfile", params, nil, &files); err != nil {
+		return nil, fmt.Errorf("get track files for album %d: %w", albumID, err)
+	}
+
+	return files, nil
+}
+
 // UpdateAlbum updates an album (e.g., to set monitored status)
 func (c *client) UpdateAlbum(ctx context.Context, album *Album) (*Album, error) {
 	endpoint := fmt.Sprintf("/api/v1/album/%d", album.ID)
@@ -138,6 +309,77 @@ func (c *client) GetQueue(ctx context.Context, page int, pageSize int) (*QueueRe
 	return &response, nil
 }
 
+// maxQueuePages bounds GetAllQueue, in case Lidarr keeps reporting more records
+const maxQueuePages = 100
+
+// GetAllQueue fetches every page of Lidarr's queue. Like GetAllWanted it
+// deduplicates entries by ID and stops at a page that adds nothing new, since
+// entries move between pages as downloads finish
+func (c *client) GetAllQueue(ctx context.Context, pageSize int) ([]QueueItem, error) {
+	if pageSize <= 0 {
+		pageSize = 1000
+	}
+
+	seen := make(map[int]bool)
+	var items []QueueItem
+	for page := 1; page <= maxQueuePages; page++ {
+		resp, err := c.GetQueue(ctx, page, pageSize)
+		if err != nil {
+			return nil, fmt.Errorf("page %d: %w", page, err)
+		}
+
+		added := 0
+		for _, item := range resp.Records {
+			if seen[item.ID] {
+				continue
+			}
+			seen[item.ID] = true
+			items = append(items, item)
+			added++
+		}
+
+		if added == 0 || len(resp.Records) < pageSize || page*pageSize >= resp.TotalRecords {
+			return items, nil
+		}
+	}
+
+	c.logger.Warn("stopped fetching the queue at the page limit", "pages", maxQueuePages, "items", len(items))
+	return items, nil
+}
+
+// DeleteQueueItem removes an entry from Lidarr's queue, optionally removing
+// the download from its client and adding the release to the blocklist
+func (c *client) DeleteQueueItem(ctx context.Context, id int, removeFromClient, blocklist bool) error {
+	endpoint := fmt.Sprintf("/api/v1/queue/%d", id)
+
+	params := url.Values{}
+	params.Set("removeFromClient", strconv.FormatBool(removeFromClient))
+	params.Set("blocklist", strconv.FormatBool(blocklist))
+
+	if err := c.doRequest(ctx, "DELETE", endpoint, params, nil, nil); err != nil {
+		return fmt.Errorf("delete queue item %d: %w", id, err)
+	}
+
+	return nil
+}
+
+// GetHistorySince fetches the history events since a time, only those of
+// eventType (e.g. HistoryGrabbed) unless it is empty
+func (c *client) GetHistorySince(ctx context.Context, since time.Time, eventType string) ([]HistoryRecord, error) {
+	params := url.Values{}
+	params.Set("date", since.UTC().Format(time.RFC3339))
+	if eventType != "" {
+		params.Set("eventType", eventType)
+	}
+
+	var records []HistoryRecord
+	if err := c.doRequest(ctx, "GET", "/api/v1/history/since", params, nil, &records); err != nil {
+		return nil, fmt.Errorf("get history since %s: %w", since.Format(time.RFC3339), err)
+	}
+
+	return records, nil
+}
+
 // PostCommand sends a command to Lidarr (e.g., DownloadedAlbumsScan)
 func (c *client) PostCommand(ctx context.Context, cmd Command) (*CommandResponse, error) {
 	endpoint := "/api/v1/command"
@@ -150,6 +392,53 @@ func (c *client) PostCommand(ctx context.Context, cmd Command) (*CommandResponse
 	return &response, nil
 }
 
+// GetManualImport lists the files in folder Lidarr would import for an album,
+// each with the tracks it matched and any rejections
+func (c *client) GetManualImport(ctx context.Context, folder string, albumID int) ([]ManualImportItem, error) {
+	params := url.Values{}
+	params.Set("folder", folder)
+	params.Set("albumId", fmt.Sprintf("%d", albumID))
+	params.Set("filterExistingFiles", "false")
+	params.Set("replaceExistingFiles", "false")
+
+	// Lidarr parses every file in the folder before answering, which can take
+	// as long as a command
+	var items []ManualImportItem
+	if err := c.doRequest(withCommandTimeout(ctx), "GET", "/api/v1/manualimport", params, nil, &items); err != nil {
+		return nil, fmt.Errorf("get manual import %s: %w", folder, err)
+	}
+
+	return items, nil
+}
+
+// PostManualImport imports files proposed by GetManualImport through a
+// ManualImport command, whose progress can be followed with GetCommand
+func (c *client) PostManualImport(ctx context.Context, items []ManualImportItem) (*CommandResponse, error) {
+	files := make([]ManualImportFile, 0, len(items))
+	for _, item := range items {
+		file := ManualImportFile{
+			Path:                    item.Path,
+			AlbumReleaseID:          item.AlbumReleaseID,
+			Quality:                 item.Quality,
+			IndexerFlags:            item.IndexerFlags,
+			DownloadID:              item.DownloadID,
+			DisableReleaseSwitching: item.DisableReleaseSwitching,
+		}
+		if item.Artist != nil {
+			file.ArtistID = item.Artist.ID
+		}
+		if item.Album != nil {
+			file.AlbumID = item.Album.ID
+		}
+		for _, track := range item.Tracks {
+			file.TrackIDs = append(file.TrackIDs, track.ID)
+		}
+		files = append(files, file)
+	}
+
+	return c.PostCommand(ctx, Command{Name: "ManualImport", Files: files, ImportMode: "auto"})
+}
+
 // GetCommand fetches the status of a command by ID
 func (c *client) GetCommand(ctx context.Context, id int) (*CommandResponse, error) {
 	endpoint := fmt.Sprintf("/api/v1/command/%d", id)
@@ -162,9 +451,38 @@ func (c *client) GetCommand(ctx context.Context, id int) (*CommandResponse, erro
 	return &response, nil
 }
 
-// doRequest executes an HTTP request to the Lidarr API
+// GetQualityProfiles fetches every quality profile
+func (c *client) GetQualityProfiles(ctx context.Context) ([]QualityProfile, error) {
+	var profiles []QualityProfile
+	if err := c.doRequest(ctx, "GET", "/api/v1/qualityprofile", nil, nil, &profiles); err != nil {
+		return nil, fmt.Errorf("get quality profiles: %w", err)
+	}
+
+	return profiles, nil
+}
+
+// GetSystemStatus fetches Lidarr's version and runtime details
+func (c *client) GetSystemStatus(ctx context.Context) (*SystemStatus, error) {
+	var status SystemStatus
+	if err := c.doRequest(ctx, "GET", "/api/v1/system/status", nil, nil, &status); err != nil {
+		return nil, fmt.Errorf("get system status: %w", err)
+	}
+
+	return &status, nil
+}
+
+// resolve returns the URL of an API endpoint, under the URL base if one is set
+func (c *client) resolve(endpoint string) string {
+	if c.urlBase != "" {
+		return c.baseURL + "/" + c.urlBase + endpoint
+	}
+	return c.baseURL + endpoint
+}
+
+// doRequest executes an HTTP request to the Lidarr API, retrying it as the
+// retry policy allows
 func (c *client) doRequest(ctx context.Context, method, endpoint string, params url.Values, body, result interface{}) error {
-	u, err := url.Parse(c.baseURL + endpoint)
+	u, err := url.Parse(c.resolve(endpoint))
 	if err != nil {
 		return fmt.Errorf("parse url: %w", err)
 	}
@@ -173,16 +491,69 @@ func (c *client) doRequest(ctx context.Context, method, endpoint string, params
 		u.RawQuery = params.Encode()
 	}
 
-	var bodyReader io.Reader
+	var bodyBytes []byte
 	if body != nil {
-		bodyBytes, err := json.Marshal(body)
+		bodyBytes, err = json.Marshal(body)
 		if err != nil {
 			return fmt.Errorf("marshal request body: %w", err)
 		}
-		bodyReader = strings.NewReader(string(bodyBytes))
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, u.String(), bodyReader)
+	for attempt := 1; ; attempt++ {
+		err := c.send(ctx, method, u.String(), bodyBytes, result)
+		if err == nil && attempt > 1 {
+			c.logger.Debug("lidarr request succeeded after retrying",
+				"method", method,
+				"endpoint", endpoint,
+				"retries", attempt-1)
+		}
+		if err == nil || attempt > c.retry.MaxRetries || !retryable(method, err) || ctx.Err() != nil {
+			return err
+		}
+
+		delay := c.retry.delay(attempt)
+		c.logger.Debug("retrying lidarr request",
+			"method", method,
+			"endpoint", endpoint,
+			"retry", attempt,
+			"maxRetries", c.retry.MaxRetries,
+			"retryIn", delay,
+			"error", err)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w (retry cancelled: %w)", err, ctx.Err())
+		case <-time.After(delay):
+		}
+	}
+}
+
+type commandTimeoutKey struct{}
+
+// withCommandTimeout gives the requests made with ctx the command timeout,
+// even if they are GETs
+func withCommandTimeout(ctx context.Context) context.Context {
+	return context.WithValue(ctx, commandTimeoutKey{}, true)
+}
+
+// send makes a single attempt at a request
+func (c *client) send(ctx context.Context, method, rawURL string, body []byte, result interface{}) error {
+	timeout := c.commandTimeout
+	if method == http.MethodGet && ctx.Value(commandTimeoutKey{}) == nil {
+		timeout = c.timeout
+	}
+	if timeout > 0 {
+		// The deadline also covers decoding the response below
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, bodyReader)
 	if err != nil {
 		return fmt.Errorf("create request: %w", err)
 	}
@@ -198,7 +569,7 @@ func (c *client) doRequest(ctx context.Context, method, endpoint string, params
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(bodyBytes))
+		return &StatusError{Code: resp.StatusCode, Body: string(bodyBytes)}
 	}
 
 	if result != nil {
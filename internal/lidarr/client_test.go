@@ -2,11 +2,17 @@ package lidarr
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestGetWanted(t *testing.T) {
@@ -26,6 +32,12 @@ func TestGetWanted(t *testing.T) {
 		if r.URL.Query().Get("page") != "1" {
 			t.Errorf("expected page=1, got %s", r.URL.Query().Get("page"))
 		}
+		if r.URL.Query().Get("monitored") != "true" {
+			t.Errorf("expected monitored=true, got %q", r.URL.Query().Get("monitored"))
+		}
+		if r.URL.Query().Get("includeArtist") != "true" {
+			t.Errorf("expected includeArtist=true, got %q", r.URL.Query().Get("includeArtist"))
+		}
 
 		// Return mock response
 		w.Header().Set("Content-Type", "application/json")
@@ -38,8 +50,11 @@ func TestGetWanted(t *testing.T) {
 					ID:    123,
 					Title: "Test Album",
 					Artist: Artist{
-						ID:         456,
-						ArtistName: "Test Artist",
+						ID:                456,
+						ArtistName:        "Test Artist",
+						Monitored:         true,
+						MonitorNewItems:   "none",
+						MetadataProfileID: 2,
 					},
 				},
 			},
@@ -47,12 +62,13 @@ func TestGetWanted(t *testing.T) {
 	}))
 	defer server.Close()
 
-	client := NewClient(server.URL, "test-key")
+	client := NewClient(server.URL, "test-key", "")
 
 	resp, err := client.GetWanted(context.Background(), GetWantedOptions{
-		Page:     1,
-		PageSize: 10,
-		Missing:  true,
+		Page:      1,
+		PageSize:  10,
+		Missing:   true,
+		Monitored: true,
 	})
 
 	if err != nil {
@@ -70,6 +86,13 @@ func TestGetWanted(t *testing.T) {
 	if resp.Records[0].Title != "Test Album" {
 		t.Errorf("expected title 'Test Album', got %q", resp.Records[0].Title)
 	}
+
+	if !resp.Records[0].Artist.Monitored {
+		t.Error("expected the artist to be monitored")
+	}
+	if artist := resp.Records[0].Artist; artist.MonitorNewItems != "none" || artist.MetadataProfileID != 2 {
+		t.Errorf("unexpected artist monitoring fields: %+v", artist)
+	}
 }
 
 func TestGetAlbum(t *testing.T) {
@@ -101,7 +124,7 @@ func TestGetAlbum(t *testing.T) {
 	}))
 	defer server.Close()
 
-	client := NewClient(server.URL, "test-key")
+	client := NewClient(server.URL, "test-key", "")
 
 	album, err := client.GetAlbum(context.Background(), 123)
 	if err != nil {
@@ -121,6 +144,70 @@ func TestGetAlbum(t *testing.T) {
 	}
 }
 
+func TestGetArtist(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/artist/456" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": 456, "artistName": "The Test Artist", "sortName": "test artist", "path": "/music/Test Artist, The", "foreignArtistId": "mbid-1", "aliases": ["Artiste Test", "Test"]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", "")
+
+	artist, err := client.GetArtist(context.Background(), 456)
+	if err != nil {
+		t.Fatalf("GetArtist() error: %v", err)
+	}
+
+	if artist.ArtistName != "The Test Artist" {
+		t.Errorf("expected name 'The Test Artist', got %q", artist.ArtistName)
+	}
+	if artist.Path != "/music/Test Artist, The" || artist.SortName != "test artist" || artist.ForeignArtistID != "mbid-1" {
+		t.Errorf("unexpected path, sort name or MusicBrainz ID: %+v", artist)
+	}
+	if len(artist.Aliases) != 2 || artist.Aliases[0] != "Artiste Test" {
+		t.Errorf("unexpected aliases: %v", artist.Aliases)
+	}
+}
+
+func TestGetQualityProfiles(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/qualityprofile" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id": 1, "name": "Lossless", "cutoff": 6, "items": [
+			{"quality": {"id": 2, "name": "MP3-320"}, "items": [], "allowed": false},
+			{"name": "Lossless", "id": 1000, "items": [
+				{"quality": {"id": 6, "name": "FLAC"}, "items": [], "allowed": true}
+			], "allowed": true}
+		]}]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", "")
+
+	profiles, err := client.GetQualityProfiles(context.Background())
+	if err != nil {
+		t.Fatalf("GetQualityProfiles() error: %v", err)
+	}
+
+	if len(profiles) != 1 || profiles[0].Name != "Lossless" {
+		t.Fatalf("unexpected profiles: %+v", profiles)
+	}
+	items := profiles[0].Items
+	if len(items) != 2 || items[0].Quality == nil || items[0].Quality.Name != "MP3-320" {
+		t.Fatalf("unexpected items: %+v", items)
+	}
+	if items[1].Quality != nil || len(items[1].Items) != 1 || items[1].Items[0].Quality.Name != "FLAC" {
+		t.Errorf("expected a group holding FLAC, got %+v", items[1])
+	}
+}
+
 func TestGetTracks(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/api/v1/track" {
@@ -152,7 +239,7 @@ func TestGetTracks(t *testing.T) {
 	}))
 	defer server.Close()
 
-	client := NewClient(server.URL, "test-key")
+	client := NewClient(server.URL, "test-key", "")
 
 	tracks, err := client.GetTracks(context.Background(), 123, nil)
 	if err != nil {
@@ -168,6 +255,40 @@ func TestGetTracks(t *testing.T) {
 	}
 }
 
+func TestGetTrackFiles(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/trackfile" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if albumID := r.URL.Query().Get("albumId"); albumID != "123" {
+			t.Errorf("expected albumId=123, got %s", albumID)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id":9,"albumId":123,"path":"/music/Artist/Album/01 - Song.mp3","size":4096,` +
+			`"quality":{"quality":{"id":4,"name":"MP3-320"},"revision":{"version":1}},"qualityCutoffNotMet":true}]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", "")
+
+	files, err := client.GetTrackFiles(context.Background(), 123)
+	if err != nil {
+		t.Fatalf("GetTrackFiles() error: %v", err)
+	}
+
+	if len(files) != 1 {
+		t.Fatalf("expected 1 file, got %d", len(files))
+	}
+	file := files[0]
+	if file.ID != 9 || file.AlbumID != 123 || file.Size != 4096 || !file.QualityCutoffNotMet {
+		t.Errorf("unexpected file: %+v", file)
+	}
+	if file.Quality.Quality.Name != "MP3-320" {
+		t.Errorf("expected quality MP3-320, got %q", file.Quality.Quality.Name)
+	}
+}
+
 func TestPostCommand(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "POST" {
@@ -199,7 +320,7 @@ func TestPostCommand(t *testing.T) {
 	}))
 	defer server.Close()
 
-	client := NewClient(server.URL, "test-key")
+	client := NewClient(server.URL, "test-key", "")
 
 	resp, err := client.PostCommand(context.Background(), Command{
 		Name: "DownloadedAlbumsScan",
@@ -226,7 +347,7 @@ func TestClientErrorHandling(t *testing.T) {
 	}))
 	defer server.Close()
 
-	client := NewClient(server.URL, "test-key")
+	client := NewClient(server.URL, "test-key", "")
 
 	_, err := client.GetAlbum(context.Background(), 999)
 	if err == nil {
@@ -257,7 +378,7 @@ func TestUpdateAlbum(t *testing.T) {
 	}))
 	defer server.Close()
 
-	client := NewClient(server.URL, "test-key")
+	client := NewClient(server.URL, "test-key", "")
 
 	album := &Album{
 		ID:    123,
@@ -295,7 +416,7 @@ func TestGetQueue(t *testing.T) {
 	}))
 	defer server.Close()
 
-	client := NewClient(server.URL, "test-key")
+	client := NewClient(server.URL, "test-key", "")
 
 	result, err := client.GetQueue(context.Background(), 1, 50)
 	if err != nil {
@@ -315,6 +436,95 @@ func TestGetQueue(t *testing.T) {
 	}
 }
 
+func TestDeleteQueueItem(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("expected DELETE, got %s", r.Method)
+		}
+		if r.URL.Path != "/api/v1/queue/42" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		query := r.URL.Query()
+		if query.Get("removeFromClient") != "false" || query.Get("blocklist") != "true" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", "")
+
+	if err := client.DeleteQueueItem(context.Background(), 42, false, true); err != nil {
+		t.Fatalf("DeleteQueueItem() error: %v", err)
+	}
+}
+
+func TestManualImport(t *testing.T) {
+	quality := json.RawMessage(`{"quality":{"id":6,"name":"FLAC"}}`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case "GET":
+			if r.URL.Path != "/api/v1/manualimport" {
+				t.Errorf("unexpected path: %s", r.URL.Path)
+			}
+			query := r.URL.Query()
+			if query.Get("folder") != "/downloads/Artist/Album" || query.Get("albumId") != "7" {
+				t.Errorf("unexpected query: %s", r.URL.RawQuery)
+			}
+			json.NewEncoder(w).Encode([]ManualImportItem{{
+				ID:             1,
+				Path:           "/downloads/Artist/Album/01 - Song.flac",
+				Name:           "01 - Song",
+				Artist:         &Artist{ID: 3},
+				Album:          &Album{ID: 7},
+				AlbumReleaseID: 11,
+				Tracks:         []Track{{ID: 21}, {ID: 22}},
+				Quality:        quality,
+				Rejections:     []Rejection{{Reason: "Not an upgrade", Type: "permanent"}},
+			}})
+		case "POST":
+			if r.URL.Path != "/api/v1/command" {
+				t.Errorf("unexpected path: %s", r.URL.Path)
+			}
+			var cmd Command
+			if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+				t.Fatalf("failed to decode command: %v", err)
+			}
+			if cmd.Name != "ManualImport" || cmd.ImportMode != "auto" || len(cmd.Files) != 1 {
+				t.Fatalf("unexpected command: %+v", cmd)
+			}
+			file := cmd.Files[0]
+			if file.ArtistID != 3 || file.AlbumID != 7 || file.AlbumReleaseID != 11 || !slices.Equal(file.TrackIDs, []int{21, 22}) {
+				t.Errorf("unexpected file: %+v", file)
+			}
+			if string(file.Quality) != string(quality) {
+				t.Errorf("expected quality passed back unchanged, got %s", file.Quality)
+			}
+			json.NewEncoder(w).Encode(CommandResponse{ID: 5, Name: cmd.Name, Status: "queued"})
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", "")
+
+	items, err := client.GetManualImport(context.Background(), "/downloads/Artist/Album", 7)
+	if err != nil {
+		t.Fatalf("GetManualImport() error: %v", err)
+	}
+	if len(items) != 1 || len(items[0].Rejections) != 1 || items[0].Rejections[0].Reason != "Not an upgrade" {
+		t.Fatalf("unexpected items: %+v", items)
+	}
+
+	resp, err := client.PostManualImport(context.Background(), items)
+	if err != nil {
+		t.Fatalf("PostManualImport() error: %v", err)
+	}
+	if resp.ID != 5 {
+		t.Errorf("expected command ID 5, got %d", resp.ID)
+	}
+}
+
 func TestGetCommand(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if !strings.Contains(r.URL.Path, "/api/v1/command/123") {
@@ -332,7 +542,7 @@ func TestGetCommand(t *testing.T) {
 	}))
 	defer server.Close()
 
-	client := NewClient(server.URL, "test-key")
+	client := NewClient(server.URL, "test-key", "")
 
 	result, err := client.GetCommand(context.Background(), 123)
 	if err != nil {
@@ -355,3 +565,417 @@ func TestGetCommand(t *testing.T) {
 func intPtr(i int) *int {
 	return &i
 }
+
+// countingTransport counts requests passing through it
+type countingTransport struct {
+	requests int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestWithTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"records":[]}`))
+	}))
+	defer server.Close()
+
+	rt := &countingTransport{}
+	client := NewClient(server.URL, "test-key", "", WithTransport(rt))
+
+	if _, err := client.GetQueue(context.Background(), 1, 10); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+
+	if rt.requests != 1 {
+		t.Errorf("expected request to use custom transport, got %d requests", rt.requests)
+	}
+}
+
+// wrappingTransport counts the requests passed to the transport it wraps
+type wrappingTransport struct {
+	base     http.RoundTripper
+	requests int
+}
+
+func (t *wrappingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+	return t.base.RoundTrip(req)
+}
+
+func TestWithTLSConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"records":[]}`))
+	}))
+	defer server.Close()
+
+	trusted := x509.NewCertPool()
+	trusted.AddCert(server.Certificate())
+
+	tests := []struct {
+		name    string
+		config  *tls.Config
+		wrap    bool
+		wantErr bool
+	}{
+		{name: "untrusted certificate", wantErr: true},
+		{name: "trusted ca", config: &tls.Config{RootCAs: trusted}},
+		{name: "skip verify", config: &tls.Config{InsecureSkipVerify: true}},
+		{name: "trusted ca with wrapped transport", config: &tls.Config{RootCAs: trusted}, wrap: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wrapper := &wrappingTransport{}
+			opts := []Option{WithTLSConfig(tt.config)}
+			if tt.wrap {
+				opts = append(opts, WrapTransport(func(base http.RoundTripper) http.RoundTripper {
+					wrapper.base = base
+					return wrapper
+				}))
+			}
+			client := NewClient(server.URL, "test-key", "", opts...)
+
+			_, err := client.GetQueue(context.Background(), 1, 10)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("request error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wrap && wrapper.requests != 1 {
+				t.Errorf("expected the request to use the wrapped transport, got %d requests", wrapper.requests)
+			}
+		})
+	}
+}
+
+func TestClientURLBase(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string // Appended to the server's URL for host_url
+		urlBase string
+	}{
+		{"url_base", "", "/lidarr"},
+		{"url_base with slashes", "", "/lidarr/"},
+		{"path in host_url", "/lidarr", ""},
+		{"path in host_url with trailing slash", "/lidarr/", "/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/lidarr/api/v1/wanted/missing" {
+					t.Errorf("expected path under /lidarr, got %s", r.URL.Path)
+				}
+				if r.URL.Query().Get("page") != "2" || r.URL.Query().Get("sortKey") != "releaseDate" {
+					t.Errorf("query parameters lost: %s", r.URL.RawQuery)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(WantedResponse{})
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL+tt.path, "test-key", tt.urlBase)
+			if _, err := client.GetWanted(context.Background(), GetWantedOptions{Page: 2, SortKey: "releaseDate", Missing: true}); err != nil {
+				t.Fatalf("GetWanted() error: %v", err)
+			}
+		})
+	}
+}
+
+func TestGetSystemStatus(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		wantVersion  string
+		unauthorized bool
+	}{
+		{"ok", http.StatusOK, "2.9.6.4552", false},
+		{"wrong api key", http.StatusUnauthorized, "", true},
+		{"server error", http.StatusInternalServerError, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v1/system/status" {
+					t.Errorf("unexpected path: %s", r.URL.Path)
+				}
+				if tt.status != http.StatusOK {
+					w.WriteHeader(tt.status)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"appName": "Lidarr", "version": "2.9.6.4552", "urlBase": ""}`))
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "test-key", "")
+			status, err := client.GetSystemStatus(context.Background())
+
+			if errors.Is(err, ErrUnauthorized) != tt.unauthorized {
+				t.Errorf("errors.Is(err, ErrUnauthorized) = %v, want %v (err: %v)", !tt.unauthorized, tt.unauthorized, err)
+			}
+			if tt.wantVersion == "" {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetSystemStatus() error: %v", err)
+			}
+			if status.Version != tt.wantVersion {
+				t.Errorf("expected version %q, got %q", tt.wantVersion, status.Version)
+			}
+		})
+	}
+}
+
+func TestGetHistorySince(t *testing.T) {
+	since := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/history/since" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("date"); got != "2026-03-01T12:00:00Z" {
+			t.Errorf("unexpected date: %s", got)
+		}
+		if got := r.URL.Query().Get("eventType"); got != HistoryGrabbed {
+			t.Errorf("unexpected event type: %s", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id": 1, "albumId": 123, "artistId": 456, "eventType": "grabbed", "date": "2026-03-01T13:00:00Z"}]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", "")
+	records, err := client.GetHistorySince(context.Background(), since, HistoryGrabbed)
+	if err != nil {
+		t.Fatalf("GetHistorySince() error: %v", err)
+	}
+
+	if len(records) != 1 || records[0].AlbumID != 123 || records[0].EventType != HistoryGrabbed {
+		t.Errorf("unexpected records: %+v", records)
+	}
+}
+
+func TestStatusErrors(t *testing.T) {
+	tests := []struct {
+		status       int
+		notFound     bool
+		unauthorized bool
+	}{
+		{http.StatusNotFound, true, false},
+		{http.StatusUnauthorized, false, true},
+		{http.StatusForbidden, false, true},
+		{http.StatusInternalServerError, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "test-key", "")
+			_, err := client.GetAlbum(context.Background(), 7)
+
+			var statusErr *StatusError
+			if !errors.As(err, &statusErr) || statusErr.Code != tt.status {
+				t.Fatalf("expected a StatusError with code %d, got %v", tt.status, err)
+			}
+			if errors.Is(err, ErrNotFound) != tt.notFound {
+				t.Errorf("errors.Is(err, ErrNotFound) = %v, want %v", !tt.notFound, tt.notFound)
+			}
+			if errors.Is(err, ErrUnauthorized) != tt.unauthorized {
+				t.Errorf("errors.Is(err, ErrUnauthorized) = %v, want %v", !tt.unauthorized, tt.unauthorized)
+			}
+		})
+	}
+}
+
+func TestGetAllWanted(t *testing.T) {
+	ids := func(from, to int) []int {
+		var list []int
+		for id := from; id <= to; id++ {
+			list = append(list, id)
+		}
+		return list
+	}
+
+	tests := []struct {
+		name      string
+		wanted    []int
+		change    func(wanted []int, page int) []int // Applied after each page is served
+		ignore    bool                               // Serve the first page whatever is asked
+		wantIDs   []int
+		wantPages int
+	}{
+		{
+			name:      "every page",
+			wanted:    ids(1, 5),
+			wantIDs:   ids(1, 5),
+			wantPages: 3,
+		},
+		{
+			name:   "album added while paging",
+			wanted: ids(1, 5),
+			change: func(wanted []int, page int) []int {
+				if page == 1 {
+					return append([]int{0}, wanted...) // Shifts album 2 onto page 2
+				}
+				return wanted
+			},
+			wantIDs:   ids(1, 5),
+			wantPages: 3,
+		},
+		{
+			name:   "album imported while paging",
+			wanted: ids(1, 6),
+			change: func(wanted []int, page int) []int {
+				if page == 1 {
+					return wanted[1:] // Total drops to 5
+				}
+				return wanted
+			},
+			wantIDs:   []int{1, 2, 4, 5, 6},
+			wantPages: 3,
+		},
+		{
+			name:      "page adds nothing new",
+			wanted:    ids(1, 6),
+			ignore:    true,
+			wantIDs:   ids(1, 2),
+			wantPages: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wanted := tt.wanted
+			pages := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				pages++
+				page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+				pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+				if tt.ignore {
+					page = 1
+				}
+
+				start := min((page-1)*pageSize, len(wanted))
+				end := min(start+pageSize, len(wanted))
+				var records []Album
+				for _, id := range wanted[start:end] {
+					records = append(records, Album{ID: id})
+				}
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(WantedResponse{Page: page, PageSize: pageSize, TotalRecords: len(wanted), Records: records})
+
+				if tt.change != nil {
+					wanted = tt.change(wanted, page)
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "test-key", "")
+			albums, err := client.GetAllWanted(context.Background(), GetWantedOptions{Page: 3, PageSize: 2, Missing: true})
+			if err != nil {
+				t.Fatalf("GetAllWanted() error: %v", err)
+			}
+
+			var got []int
+			for _, album := range albums {
+				got = append(got, album.ID)
+			}
+			if !slices.Equal(got, tt.wantIDs) {
+				t.Errorf("expected albums %v, got %v", tt.wantIDs, got)
+			}
+			if pages != tt.wantPages {
+				t.Errorf("expected %d pages fetched, got %d", tt.wantPages, pages)
+			}
+		})
+	}
+}
+
+func TestGetAllQueue(t *testing.T) {
+	const total = 2500
+	var pages []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+		pages = append(pages, page)
+
+		start := min((page-1)*pageSize, total)
+		end := min(start+pageSize, total)
+		var records []QueueItem
+		for id := start + 1; id <= end; id++ {
+			records = append(records, QueueItem{ID: id, AlbumID: intPtr(id * 10)})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(QueueResponse{Page: page, PageSize: pageSize, TotalRecords: total, Records: records})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", "")
+
+	items, err := client.GetAllQueue(context.Background(), 1000)
+	if err != nil {
+		t.Fatalf("GetAllQueue() error: %v", err)
+	}
+
+	if !slices.Equal(pages, []int{1, 2, 3}) {
+		t.Errorf("expected pages 1-3 fetched, got %v", pages)
+	}
+	if len(items) != total {
+		t.Fatalf("expected %d items, got %d", total, len(items))
+	}
+	if items[total-1].ID != total {
+		t.Errorf("expected last item %d, got %d", total, items[total-1].ID)
+	}
+}
+
+func TestRequestTimeouts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(200 * time.Millisecond):
+		case <-r.Context().Done():
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/v1/manualimport":
+			json.NewEncoder(w).Encode([]ManualImportItem{})
+		case r.Method == "GET":
+			json.NewEncoder(w).Encode(SystemStatus{Version: "2.9.6"})
+		case r.Method == "POST":
+			json.NewEncoder(w).Encode(CommandResponse{ID: 1})
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", "", WithTimeout(50*time.Millisecond))
+
+	start := time.Now()
+	_, err := client.GetSystemStatus(context.Background())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the read to time out, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("expected the read cut off after 50ms, took %s", elapsed)
+	}
+
+	// Commands keep the longer timeout
+	if _, err := client.PostCommand(context.Background(), Command{Name: "DownloadedAlbumsScan"}); err != nil {
+		t.Errorf("PostCommand() error: %v", err)
+	}
+
+	// So do manual import listings, which parse every file in the folder
+	if _, err := client.GetManualImport(context.Background(), "/downloads/Artist/Album", 7); err != nil {
+		t.Errorf("GetManualImport() error: %v", err)
+	}
+}
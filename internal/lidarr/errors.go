@@ -0,0 +1,34 @@
+package lidarr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Errors matched by a StatusError with the corresponding HTTP status
+var (
+	ErrNotFound     = errors.New("not found")    // 404, e.g. an album deleted from Lidarr since it was listed
+	ErrUnauthorized = errors.New("unauthorized") // 401 or 403, usually a wrong API key
+)
+
+// StatusError is an unexpected HTTP status returned by Lidarr
+type StatusError struct {
+	Code int
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status %d: %s", e.Code, e.Body)
+}
+
+// Is matches the sentinel error for the status, so callers can use errors.Is
+func (e *StatusError) Is(target error) bool {
+	switch e.Code {
+	case http.StatusNotFound:
+		return target == ErrNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return target == ErrUnauthorized
+	}
+	return false
+}
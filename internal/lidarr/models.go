@@ -1,33 +1,71 @@
 package lidarr
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // Album represents a Lidarr album
 type Album struct {
-	ID        int       `json:"id"`
-	Title     string    `json:"title"`
-	ArtistID  int       `json:"artistId"`
-	Artist    Artist    `json:"artist"`
-	Releases  []Release `json:"releases"`
-	Monitored bool      `json:"monitored"`
+	ID             int       `json:"id"`
+	Title          string    `json:"title"`
+	ForeignAlbumID string    `json:"foreignAlbumId"` // MusicBrainz release group ID
+	ArtistID       int       `json:"artistId"`
+	Artist         Artist    `json:"artist"`
+	Releases       []Release `json:"releases"`
+	Monitored      bool      `json:"monitored"`
+	AlbumType      string    `json:"albumType,omitempty"`      // Album, EP, Single, Broadcast or Other
+	SecondaryTypes []string  `json:"secondaryTypes,omitempty"` // e.g. Live, Compilation, Remix
 }
 
 // Artist represents a Lidarr artist
 type Artist struct {
-	ID         int    `json:"id"`
-	ArtistName string `json:"artistName"`
+	ID                int      `json:"id"`
+	ArtistName        string   `json:"artistName"`
+	SortName          string   `json:"sortName,omitempty"`
+	Path              string   `json:"path,omitempty"`            // Artist folder in Lidarr's root folder
+	ForeignArtistID   string   `json:"foreignArtistId,omitempty"` // MusicBrainz artist ID
+	Aliases           []string `json:"aliases,omitempty"`         // Alternate and foreign names from MusicBrainz
+	QualityProfileID  int      `json:"qualityProfileId,omitempty"`
+	MetadataProfileID int      `json:"metadataProfileId,omitempty"`
+	Monitored         bool     `json:"monitored"`
+	MonitorNewItems   string   `json:"monitorNewItems,omitempty"` // all, new or none
+}
+
+// QualityProfile is a Lidarr quality profile
+type QualityProfile struct {
+	ID     int                  `json:"id"`
+	Name   string               `json:"name"`
+	Cutoff int                  `json:"cutoff"` // Quality or group ID upgrades stop at
+	Items  []QualityProfileItem `json:"items"`  // Lowest quality first
+}
+
+// QualityProfileItem is a single quality or a named group of qualities in a profile
+type QualityProfileItem struct {
+	ID      int                  `json:"id,omitempty"` // Set on groups
+	Name    string               `json:"name,omitempty"`
+	Quality *Quality             `json:"quality,omitempty"` // Nil for groups
+	Items   []QualityProfileItem `json:"items,omitempty"`
+	Allowed bool                 `json:"allowed"`
+}
+
+// Quality is a Lidarr audio quality such as "FLAC" or "MP3-320"
+type Quality struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
 }
 
 // Release represents an album release variant
 type Release struct {
-	ID          int      `json:"id"`
-	AlbumID     int      `json:"albumId"`
-	TrackCount  int      `json:"trackCount"`
-	MediumCount int      `json:"mediumCount"`
-	Country     []string `json:"country"`
-	Format      string   `json:"format"`
-	Status      string   `json:"status"`
-	Media       []Medium `json:"media"`
+	ID               int      `json:"id"`
+	AlbumID          int      `json:"albumId"`
+	ForeignReleaseID string   `json:"foreignReleaseId"` // MusicBrainz release ID
+	TrackCount       int      `json:"trackCount"`
+	MediumCount      int      `json:"mediumCount"`
+	Country          []string `json:"country"`
+	Format           string   `json:"format"`
+	Status           string   `json:"status"`
+	Media            []Medium `json:"media"`
 }
 
 // Medium represents a disc/medium in a release
@@ -43,6 +81,24 @@ type Track struct {
 	AlbumID             int    `json:"albumId"`
 	MediumNumber        int    `json:"mediumNumber"`
 	AbsoluteTrackNumber int    `json:"absoluteTrackNumber"`
+	Duration            int    `json:"duration"` // milliseconds
+	HasFile             bool   `json:"hasFile"`
+	TrackFileID         int    `json:"trackFileId"` // 0 without a file
+}
+
+// TrackFile is an audio file Lidarr has imported for one of an album's tracks
+type TrackFile struct {
+	ID                  int         `json:"id"`
+	AlbumID             int         `json:"albumId"`
+	Path                string      `json:"path"`
+	Size                int64       `json:"size"`
+	Quality             FileQuality `json:"quality"`
+	QualityCutoffNotMet bool        `json:"qualityCutoffNotMet"` // Below the quality profile's cutoff
+}
+
+// FileQuality is the quality Lidarr detected for a file
+type FileQuality struct {
+	Quality Quality `json:"quality"`
 }
 
 // WantedResponse represents paginated wanted albums response
@@ -67,10 +123,33 @@ type QueueResponse struct {
 
 // QueueItem represents an item in the download queue
 type QueueItem struct {
-	ID      int    `json:"id"`
-	AlbumID *int   `json:"albumId,omitempty"` // Can be nil for some entries
-	Title   string `json:"title"`
-	Status  string `json:"status"`
+	ID             int        `json:"id"`
+	AlbumID        *int       `json:"albumId,omitempty"` // Can be nil for some entries
+	Title          string     `json:"title"`
+	Status         string     `json:"status"`                   // e.g. queued, downloading, completed, failed
+	Added          *time.Time `json:"added,omitempty"`          // When Lidarr grabbed the release
+	DownloadClient string     `json:"downloadClient,omitempty"` // Name of the download client
+}
+
+// Queue item statuses of downloads that have stopped
+const (
+	QueueStatusCompleted = "completed"
+	QueueStatusFailed    = "failed"
+)
+
+// History event types, as Lidarr names them
+const (
+	HistoryGrabbed          = "grabbed"
+	HistoryDownloadImported = "downloadImported" // A download client's files were imported
+)
+
+// HistoryRecord is an event in Lidarr's history
+type HistoryRecord struct {
+	ID        int       `json:"id"`
+	AlbumID   int       `json:"albumId"`
+	ArtistID  int       `json:"artistId"`
+	EventType string    `json:"eventType"`
+	Date      time.Time `json:"date"`
 }
 
 // Command represents a Lidarr command request
@@ -79,9 +158,52 @@ type Command struct {
 	Name string `json:"name"`
 	// Path is used for DownloadedAlbumsScan command
 	Path string `json:"path,omitempty"`
+	// Files and ImportMode are used for ManualImport command
+	Files      []ManualImportFile `json:"files,omitempty"`
+	ImportMode string             `json:"importMode,omitempty"` // auto, move or copy
+	// ArtistID is used for RefreshArtist command
+	ArtistID int `json:"artistId,omitempty"`
 	// Additional parameters can be added as needed
 }
 
+// ManualImportItem is a file Lidarr proposes to import from a folder, with the
+// album and tracks it matched and the reasons it would be rejected
+type ManualImportItem struct {
+	ID                      int             `json:"id"`
+	Path                    string          `json:"path"`
+	Name                    string          `json:"name"`
+	Size                    int64           `json:"size"`
+	Artist                  *Artist         `json:"artist,omitempty"`
+	Album                   *Album          `json:"album,omitempty"`
+	AlbumReleaseID          int             `json:"albumReleaseId"`
+	Tracks                  []Track         `json:"tracks,omitempty"`
+	Quality                 json.RawMessage `json:"quality,omitempty"` // Passed back to Lidarr unchanged
+	ReleaseGroup            string          `json:"releaseGroup,omitempty"`
+	IndexerFlags            int             `json:"indexerFlags"`
+	DownloadID              string          `json:"downloadId,omitempty"`
+	DisableReleaseSwitching bool            `json:"disableReleaseSwitching"`
+	Rejections              []Rejection     `json:"rejections,omitempty"`
+}
+
+// Rejection is a reason Lidarr won't import a file
+type Rejection struct {
+	Reason string `json:"reason"`
+	Type   string `json:"type"` // permanent or temporary
+}
+
+// ManualImportFile is a file in a ManualImport command
+type ManualImportFile struct {
+	Path                    string          `json:"path"`
+	ArtistID                int             `json:"artistId"`
+	AlbumID                 int             `json:"albumId"`
+	AlbumReleaseID          int             `json:"albumReleaseId"`
+	TrackIDs                []int           `json:"trackIds"`
+	Quality                 json.RawMessage `json:"quality,omitempty"`
+	IndexerFlags            int             `json:"indexerFlags"`
+	DownloadID              string          `json:"downloadId,omitempty"`
+	DisableReleaseSwitching bool            `json:"disableReleaseSwitching"`
+}
+
 // CommandResponse represents a command status response
 type CommandResponse struct {
 	ID          int                    `json:"id"`
@@ -93,3 +215,10 @@ type CommandResponse struct {
 	Ended       *time.Time             `json:"ended,omitempty"`
 	Body        map[string]interface{} `json:"body,omitempty"`
 }
+
+// SystemStatus is Lidarr's version and runtime details
+type SystemStatus struct {
+	AppName string `json:"appName"`
+	Version string `json:"version"`
+	URLBase string `json:"urlBase"`
+}
@@ -0,0 +1,30 @@
+package lidarr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yuritomanek/seekarr/internal/httpclient"
+)
+
+func TestWithConnectionPool(t *testing.T) {
+	c := NewClient("http://localhost:8686", "test-key", "",
+		WithConnectionPool(httpclient.ConnectionPool{MaxIdleConns: 8, IdleConnTimeout: 30 * time.Second, DisableHTTP2: true}),
+	).(*client)
+
+	if c.transport.MaxIdleConnsPerHost != 8 {
+		t.Errorf("expected 8 idle connections per host, got %d", c.transport.MaxIdleConnsPerHost)
+	}
+	if c.transport.IdleConnTimeout != 30*time.Second {
+		t.Errorf("expected a 30s idle timeout, got %s", c.transport.IdleConnTimeout)
+	}
+	if c.transport.ForceAttemptHTTP2 || c.transport.TLSNextProto == nil {
+		t.Error("expected HTTP/2 to be disabled")
+	}
+
+	defaults := NewClient("http://localhost:8686", "test-key", "").(*client)
+	if defaults.transport.MaxIdleConnsPerHost != httpclient.DefaultMaxIdleConns || !defaults.transport.ForceAttemptHTTP2 {
+		t.Errorf("expected the default pool, got %d idle connections, HTTP/2 %v",
+			defaults.transport.MaxIdleConnsPerHost, defaults.transport.ForceAttemptHTTP2)
+	}
+}
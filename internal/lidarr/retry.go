@@ -0,0 +1,65 @@
+package lidarr
+
+import (
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// RetryPolicy controls how requests failing with a connection error or a 5xx
+// response, such as SQLite's "database is locked", are retried
+type RetryPolicy struct {
+	MaxRetries int           // Retries after the first attempt; 0 disables them
+	BaseDelay  time.Duration // Wait before the first retry, doubled for each one after (default: 1s)
+	MaxDelay   time.Duration // Longest wait between attempts (default: 30s)
+}
+
+// WithRetry retries failed requests. GET, PUT and DELETE are retried on
+// connection errors and 5xx responses; POSTs, which Lidarr may have acted on,
+// only when the connection couldn't be made
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *client) {
+		if policy.BaseDelay <= 0 {
+			policy.BaseDelay = time.Second
+		}
+		if policy.MaxDelay <= 0 {
+			policy.MaxDelay = 30 * time.Second
+		}
+		c.retry = policy
+	}
+}
+
+// WithLogger sets the logger retries are reported to
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *client) {
+		c.logger = logger
+	}
+}
+
+// delay returns the wait before retrying after the given attempt failed
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	return min(p.BaseDelay<<min(attempt-1, 16), p.MaxDelay)
+}
+
+// retryable reports whether a request with method that failed with err may
+// succeed if sent again without side effects
+func retryable(method string, err error) bool {
+	if method == http.MethodPost {
+		return dialFailed(err)
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Code >= 500
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// dialFailed reports whether a request failed before reaching Lidarr
+func dialFailed(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
@@ -0,0 +1,121 @@
+package lidarr
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDoRequest_Retry(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		status    int // Returned by every attempt but the last
+		wantCalls int32
+		wantErr   bool
+	}{
+		{name: "GET retried on 503", method: "GET", status: http.StatusServiceUnavailable, wantCalls: 3},
+		{name: "GET retried on database is locked", method: "GET", status: http.StatusInternalServerError, wantCalls: 3},
+		{name: "PUT retried on 502", method: "PUT", status: http.StatusBadGateway, wantCalls: 3},
+		{name: "GET not retried on 404", method: "GET", status: http.StatusNotFound, wantCalls: 1, wantErr: true},
+		{name: "POST not retried on 5xx", method: "POST", status: http.StatusServiceUnavailable, wantCalls: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if calls.Add(1) < 3 {
+					w.WriteHeader(tt.status)
+					w.Write([]byte("database is locked"))
+					return
+				}
+				w.Write([]byte("{}"))
+			}))
+			defer server.Close()
+
+			c := NewClient(server.URL, "key", "", WithRetry(RetryPolicy{
+				MaxRetries: 2,
+				BaseDelay:  time.Millisecond,
+			})).(*client)
+
+			err := c.doRequest(context.Background(), tt.method, "/api/v1/test", nil, map[string]string{"name": "test"}, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("doRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("expected %d calls, got %d", tt.wantCalls, got)
+			}
+		})
+	}
+}
+
+func TestDoRequest_RetryDisabled(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "key", "")
+	if _, err := client.GetAlbum(context.Background(), 1); err == nil {
+		t.Fatal("expected an error")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected 1 call without a retry policy, got %d", got)
+	}
+}
+
+func TestDoRequest_RetryConnectionError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	c := NewClient(url, "key", "", WithRetry(RetryPolicy{MaxRetries: 1, BaseDelay: time.Millisecond})).(*client)
+	for _, method := range []string{"GET", "POST"} {
+		err := c.doRequest(context.Background(), method, "/api/v1/command", nil, nil, nil)
+		if err == nil {
+			t.Fatalf("%s: expected an error once every attempt failed", method)
+		}
+		if !retryable(method, err) {
+			t.Errorf("%s: expected a refused connection to be retryable, got %v", method, err)
+		}
+	}
+}
+
+func TestDoRequest_RetryStopsOnCancel(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "key", "", WithRetry(RetryPolicy{MaxRetries: 3, BaseDelay: time.Minute, MaxDelay: time.Minute}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := client.GetAlbum(ctx, 1); err == nil {
+		t.Fatal("expected an error")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the wait to end with the context, took %v", elapsed)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected 1 call, got %d", got)
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 40: 5 * time.Second} {
+		if got := policy.delay(attempt); got != want {
+			t.Errorf("delay(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+}
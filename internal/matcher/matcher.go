@@ -13,7 +13,8 @@ import (
 
 // Matcher handles fuzzy string matching for track names
 type Matcher struct {
-	minRatio float64
+	minRatio    float64
+	compilation bool
 }
 
 // NewMatcher creates a new matcher with the given minimum match ratio
@@ -21,6 +22,13 @@ func NewMatcher(minRatio float64) *Matcher {
 	return &Matcher{minRatio: minRatio}
 }
 
+// ForCompilation returns a matcher that also compares each track title with the
+// part of a filename after its last " - ", since compilation files are usually
+// named "Artist - Title"
+func (m *Matcher) ForCompilation() *Matcher {
+	return &Matcher{minRatio: m.minRatio, compilation: true}
+}
+
 // MatchTracks checks if all expected tracks match files in the directory
 // Returns true if all tracks matched and the average match ratio
 func (m *Matcher) MatchTracks(expectedTracks []string, actualFiles []string) (bool, float64) {
@@ -82,21 +90,24 @@ func (m *Matcher) MatchTracksDebug(expectedTracks []string, actualFiles []string
 	for _, expected := range expectedTracks {
 		bestRatio := 0.0
 		bestMatch := ""
+		bestIndex := -1
 		// Strip file extension from expected for consistent comparison
 		expectedNoExt := ExtractFilename(expected)
 
-		for _, actual := range actualFiles {
+		for i, actual := range actualFiles {
 			actualNoExt := ExtractFilename(actual)
 			ratio := m.calculateBestRatio(expectedNoExt, actualNoExt)
 			if ratio > bestRatio {
 				bestRatio = ratio
 				bestMatch = actual
+				bestIndex = i
 			}
 		}
 
 		info := TrackMatchInfo{
 			ExpectedTrack: expected,
 			BestMatch:     bestMatch,
+			BestIndex:     bestIndex,
 			BestRatio:     bestRatio,
 			Matched:       bestRatio >= m.minRatio,
 		}
@@ -116,10 +127,27 @@ func (m *Matcher) MatchTracksDebug(expectedTracks []string, actualFiles []string
 	return false, 0.0, matchInfo
 }
 
+// MatchedTracks counts the expected tracks that match one of the files, however
+// few files there are
+func (m *Matcher) MatchedTracks(expectedTracks []string, actualFiles []string) int {
+	matched := 0
+	for _, expected := range expectedTracks {
+		expectedNoExt := ExtractFilename(expected)
+		for _, actual := range actualFiles {
+			if m.calculateBestRatio(expectedNoExt, ExtractFilename(actual)) >= m.minRatio {
+				matched++
+				break
+			}
+		}
+	}
+	return matched
+}
+
 // TrackMatchInfo contains debug information about track matching
 type TrackMatchInfo struct {
 	ExpectedTrack string
 	BestMatch     string
+	BestIndex     int // Index of BestMatch in the files matched against, -1 when none
 	BestRatio     float64
 	Matched       bool
 }
@@ -144,6 +172,13 @@ func (m *Matcher) calculateBestRatio(expected, actual string) float64 {
 		m.ratioWithTruncation(expectedNorm, actualNorm, "-"),
 	}
 
+	// Per-track artist truncation (handles "01 - Artist - Track.flac" on compilations)
+	if m.compilation {
+		if i := strings.LastIndex(actualNorm, " - "); i >= 0 {
+			ratios = append(ratios, m.ratio(expectedNorm, strings.TrimSpace(actualNorm[i+len(" - "):])))
+		}
+	}
+
 	max := 0.0
 	for _, r := range ratios {
 		if r > max {
@@ -154,6 +189,18 @@ func (m *Matcher) calculateBestRatio(expected, actual string) float64 {
 	return max
 }
 
+// Normalize applies the matcher's preprocessing, so callers can compare
+// names the way track matching does
+func (m *Matcher) Normalize(s string) string {
+	return m.preprocess(s)
+}
+
+// Similarity compares two names after the matcher's preprocessing
+// Returns a value between 0.0 (completely different) and 1.0 (identical)
+func (m *Matcher) Similarity(a, b string) float64 {
+	return m.ratio(m.preprocess(a), m.preprocess(b))
+}
+
 // preprocess normalizes a string for better matching
 // - Unicode NFKD decomposition
 // - Strip accents/diacritics
@@ -224,6 +271,34 @@ func (m *Matcher) ratioWithTruncation(expected, actual, separator string) float6
 	return m.ratio(expected, truncated)
 }
 
+// durationToleranceSec is the allowed difference in total length per track
+const durationToleranceSec = 3
+
+// DurationsMatch checks that a directory's total running time is close to the
+// expected track list's. expectedMs are track lengths in milliseconds and
+// actualSec file lengths in seconds. It returns true when the comparison can't
+// be made: unknown lengths or a different number of files and tracks
+func DurationsMatch(expectedMs []int, actualSec []int) bool {
+	if len(expectedMs) == 0 || len(expectedMs) != len(actualSec) {
+		return true
+	}
+
+	expectedTotal, actualTotal := 0, 0
+	for i := range expectedMs {
+		if expectedMs[i] <= 0 || actualSec[i] <= 0 {
+			return true
+		}
+		expectedTotal += expectedMs[i]
+		actualTotal += actualSec[i]
+	}
+
+	diff := expectedTotal/1000 - actualTotal
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= durationToleranceSec*len(expectedMs)
+}
+
 // ExtractFilename removes the file extension from a filename
 func ExtractFilename(filename string) string {
 	lastDot := strings.LastIndex(filename, ".")
@@ -58,6 +58,28 @@ func TestRatio(t *testing.T) {
 	}
 }
 
+func TestSimilarity(t *testing.T) {
+	m := NewMatcher(0.8)
+
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		expected float64
+	}{
+		{"case and accents ignored", "Beyoncé  Lemonade", "beyonce lemonade", 1.0},
+		{"one letter changed", "Hello", "hallo", 0.6}, // Substitutions cost two edits
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if ratio := m.Similarity(tt.a, tt.b); ratio != tt.expected {
+				t.Errorf("Similarity(%q, %q) = %f, want %f", tt.a, tt.b, ratio, tt.expected)
+			}
+		})
+	}
+}
+
 func TestRatioWithTruncation(t *testing.T) {
 	m := NewMatcher(0.8)
 
@@ -227,6 +249,52 @@ func TestMatchTracks(t *testing.T) {
 	}
 }
 
+func TestForCompilation(t *testing.T) {
+	m := NewMatcher(0.8)
+	compilation := m.ForCompilation()
+
+	tests := []struct {
+		name               string
+		expected           []string
+		actual             []string
+		matches            bool
+		compilationMatches bool
+	}{
+		{
+			name:               "per-track artist",
+			expected:           []string{"Rock 'n' Roll", "Hey Ya!"},
+			actual:             []string{"01 - Motörhead - Rock'n'Roll.flac", "02 - OutKast - Hey Ya!.flac"},
+			matches:            false,
+			compilationMatches: true,
+		},
+		{
+			name:               "plain titles still match",
+			expected:           []string{"Track 1", "Track 2"},
+			actual:             []string{"Track 1.flac", "Track 2.flac"},
+			matches:            true,
+			compilationMatches: true,
+		},
+		{
+			name:               "wrong title after the artist",
+			expected:           []string{"Rock 'n' Roll"},
+			actual:             []string{"01 - Motörhead - Ace of Spades.flac"},
+			matches:            false,
+			compilationMatches: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if matched, _ := m.MatchTracks(tt.expected, tt.actual); matched != tt.matches {
+				t.Errorf("MatchTracks() matched = %v, want %v", matched, tt.matches)
+			}
+			if matched, _ := compilation.MatchTracks(tt.expected, tt.actual); matched != tt.compilationMatches {
+				t.Errorf("compilation MatchTracks() matched = %v, want %v", matched, tt.compilationMatches)
+			}
+		})
+	}
+}
+
 func TestMatchTracksDebug(t *testing.T) {
 	m := NewMatcher(0.8)
 
@@ -321,6 +389,55 @@ func TestMatchTracksDebug(t *testing.T) {
 	}
 }
 
+func TestMatchedTracks(t *testing.T) {
+	m := NewMatcher(0.8)
+	expected := []string{"Intro", "Sunrise", "Long Road Home", "Outro"}
+
+	tests := []struct {
+		name   string
+		actual []string
+		want   int
+	}{
+		{name: "fewer files than tracks", actual: []string{"01 - Intro.flac", "03 - Long Road Home.flac"}, want: 2},
+		{name: "every track", actual: []string{"01 Intro.flac", "02 Sunrise.flac", "03 Long Road Home.flac", "04 Outro.flac"}, want: 4},
+		{name: "unrelated files", actual: []string{"cover.jpg", "Something Else.flac"}, want: 0},
+		{name: "no files", actual: nil, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.MatchedTracks(expected, tt.actual); got != tt.want {
+				t.Errorf("MatchedTracks() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDurationsMatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		expected  []int
+		actual    []int
+		wantMatch bool
+	}{
+		{"exact", []int{180000, 240000}, []int{180, 240}, true},
+		{"within tolerance", []int{180000, 240000}, []int{182, 243}, true},
+		{"different edition", []int{180000, 240000}, []int{180, 300}, false},
+		{"unknown expected length", []int{0, 240000}, []int{180, 300}, true},
+		{"unknown file length", []int{180000, 240000}, []int{0, 300}, true},
+		{"track count differs", []int{180000, 240000}, []int{180, 240, 200}, true},
+		{"no expected tracks", nil, []int{180}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DurationsMatch(tt.expected, tt.actual); got != tt.wantMatch {
+				t.Errorf("DurationsMatch(%v, %v) = %v, want %v", tt.expected, tt.actual, got, tt.wantMatch)
+			}
+		})
+	}
+}
+
 func TestExtractFilename(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -0,0 +1,170 @@
+package musicbrainz
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// cacheTTL is how long a cached lookup is trusted before it is fetched again
+const cacheTTL = 30 * 24 * time.Hour
+
+// Cache persists MusicBrainz lookups between runs so repeated searches for the
+// same album don't spend the one-request-per-second budget again
+type Cache struct {
+	mu       sync.RWMutex
+	data     cacheData
+	filePath string
+}
+
+type cacheData struct {
+	Releases      map[string]cachedRelease      `json:"releases"`
+	ReleaseGroups map[string]cachedReleaseGroup `json:"release_groups"`
+}
+
+type cachedRelease struct {
+	Tracks    []Track   `json:"tracks"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+type cachedReleaseGroup struct {
+	ReleaseID string    `json:"release_id"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// NewCache creates a cache backed by filePath, loading it if it exists
+func NewCache(filePath string) (*Cache, error) {
+	c := &Cache{
+		data: cacheData{
+			Releases:      make(map[string]cachedRelease),
+			ReleaseGroups: make(map[string]cachedReleaseGroup),
+		},
+		filePath: filePath,
+	}
+
+	if err := c.Load(); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("load musicbrainz cache: %w", err)
+	}
+
+	return c, nil
+}
+
+// Load reads the cache from file
+func (c *Cache) Load() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := os.ReadFile(c.filePath)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, &c.data); err != nil {
+		return fmt.Errorf("unmarshal musicbrainz cache: %w", err)
+	}
+	if c.data.Releases == nil {
+		c.data.Releases = make(map[string]cachedRelease)
+	}
+	if c.data.ReleaseGroups == nil {
+		c.data.ReleaseGroups = make(map[string]cachedReleaseGroup)
+	}
+
+	return nil
+}
+
+// Save writes the cache to file atomically
+func (c *Cache) Save() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	dir := filepath.Dir(c.filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(c.data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal musicbrainz cache: %w", err)
+	}
+
+	tmpFile, err := os.CreateTemp(dir, ".musicbrainz_cache.*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("write musicbrainz cache: %w", err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("close temp file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, c.filePath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("rename temp file: %w", err)
+	}
+
+	return nil
+}
+
+// Tracks returns the cached tracks of a release if present and fresh
+func (c *Cache) Tracks(releaseID string) ([]Track, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.data.Releases[releaseID]
+	if !ok || time.Since(entry.FetchedAt) > cacheTTL {
+		return nil, false
+	}
+	return entry.Tracks, true
+}
+
+// SetTracks caches the tracks of a release
+func (c *Cache) SetTracks(releaseID string, tracks []Track) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.data.Releases[releaseID] = cachedRelease{Tracks: tracks, FetchedAt: time.Now()}
+}
+
+// GroupRelease returns the cached release chosen for a release group
+func (c *Cache) GroupRelease(releaseGroupID string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.data.ReleaseGroups[releaseGroupID]
+	if !ok || time.Since(entry.FetchedAt) > cacheTTL {
+		return "", false
+	}
+	return entry.ReleaseID, true
+}
+
+// SetGroupRelease caches the release chosen for a release group
+func (c *Cache) SetGroupRelease(releaseGroupID, releaseID string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.data.ReleaseGroups[releaseGroupID] = cachedReleaseGroup{ReleaseID: releaseID, FetchedAt: time.Now()}
+}
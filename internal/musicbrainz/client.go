@@ -0,0 +1,198 @@
+package musicbrainz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBaseURL is the public MusicBrainz web service
+const DefaultBaseURL = "https://musicbrainz.org"
+
+// UserAgent identifies seekarr to MusicBrainz as their API policy requires
+const UserAgent = "seekarr ( https://github.com/yuritomanek/seekarr )"
+
+// minRequestInterval enforces MusicBrainz's one-request-per-second limit
+const minRequestInterval = time.Second
+
+// ErrNoRelease is returned when neither a release nor a usable release group ID is known
+var ErrNoRelease = errors.New("no musicbrainz release id available")
+
+// Client defines the interface for looking up track lists on MusicBrainz
+type Client interface {
+	// ReleaseTracks returns the tracks of a release. When releaseID is empty the
+	// first official release of the release group is used instead
+	ReleaseTracks(ctx context.Context, releaseID, releaseGroupID string) ([]Track, error)
+}
+
+// client implements the MusicBrainz API client
+type client struct {
+	baseURL    string
+	userAgent  string
+	httpClient *http.Client
+	cache      *Cache
+
+	mu          sync.Mutex
+	lastRequest time.Time
+}
+
+// Option configures optional client behaviour
+type Option func(*client)
+
+// WithTransport sets the HTTP transport used for requests (e.g. for tracing)
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *client) {
+		c.httpClient.Transport = rt
+	}
+}
+
+// NewClient creates a new MusicBrainz API client backed by an on-disk cache
+func NewClient(baseURL string, cache *Cache, opts ...Option) Client {
+	c := &client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		userAgent:  UserAgent,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		cache:      cache,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// ReleaseTracks resolves the release and returns its tracks, using the cache when possible
+func (c *client) ReleaseTracks(ctx context.Context, releaseID, releaseGroupID string) ([]Track, error) {
+	if releaseID == "" {
+		if releaseGroupID == "" {
+			return nil, ErrNoRelease
+		}
+
+		id, err := c.releaseForGroup(ctx, releaseGroupID)
+		if err != nil {
+			return nil, err
+		}
+		releaseID = id
+	}
+
+	if tracks, ok := c.cache.Tracks(releaseID); ok {
+		return tracks, nil
+	}
+
+	var resp releaseResponse
+	params := url.Values{"inc": {"recordings"}}
+	if err := c.doRequest(ctx, "/ws/2/release/"+url.PathEscape(releaseID), params, &resp); err != nil {
+		return nil, fmt.Errorf("get release %s: %w", releaseID, err)
+	}
+
+	var tracks []Track
+	for _, medium := range resp.Media {
+		for _, t := range medium.Tracks {
+			track := Track{
+				Title:        t.Title,
+				MediumNumber: medium.Position,
+				Position:     t.Position,
+			}
+			if t.Length != nil {
+				track.Length = *t.Length
+			}
+			tracks = append(tracks, track)
+		}
+	}
+
+	c.cache.SetTracks(releaseID, tracks)
+	return tracks, nil
+}
+
+// releaseForGroup picks the first official release of a release group
+func (c *client) releaseForGroup(ctx context.Context, releaseGroupID string) (string, error) {
+	if id, ok := c.cache.GroupRelease(releaseGroupID); ok {
+		return id, nil
+	}
+
+	var resp releaseGroupResponse
+	params := url.Values{"inc": {"releases"}}
+	if err := c.doRequest(ctx, "/ws/2/release-group/"+url.PathEscape(releaseGroupID), params, &resp); err != nil {
+		return "", fmt.Errorf("get release group %s: %w", releaseGroupID, err)
+	}
+
+	if len(resp.Releases) == 0 {
+		return "", fmt.Errorf("release group %s has no releases", releaseGroupID)
+	}
+
+	id := resp.Releases[0].ID
+	for _, r := range resp.Releases {
+		if strings.EqualFold(r.Status, "Official") {
+			id = r.ID
+			break
+		}
+	}
+
+	c.cache.SetGroupRelease(releaseGroupID, id)
+	return id, nil
+}
+
+// wait blocks until another request is allowed by the rate limit
+func (c *client) wait(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if delay := minRequestInterval - time.Since(c.lastRequest); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	c.lastRequest = time.Now()
+	return nil
+}
+
+// doRequest executes a rate-limited GET request against the MusicBrainz API
+func (c *client) doRequest(ctx context.Context, endpoint string, params url.Values, result interface{}) error {
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+
+	u, err := url.Parse(c.baseURL + endpoint)
+	if err != nil {
+		return fmt.Errorf("parse url: %w", err)
+	}
+
+	params.Set("fmt", "json")
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+package musicbrainz
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+const releaseJSON = `{
+	"id": "rel-1",
+	"media": [
+		{"position": 1, "tracks": [
+			{"title": "Intro", "length": 61000, "position": 1},
+			{"title": "Song", "length": 240000, "position": 2}
+		]},
+		{"position": 2, "tracks": [
+			{"title": "Bonus", "length": null, "position": 1}
+		]}
+	]
+}`
+
+const releaseGroupJSON = `{
+	"id": "rg-1",
+	"releases": [
+		{"id": "rel-bootleg", "status": "Bootleg"},
+		{"id": "rel-1", "status": "Official"}
+	]
+}`
+
+func newTestServer(t *testing.T, requests *[]string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r.URL.Path)
+
+		if r.Header.Get("User-Agent") != UserAgent {
+			t.Errorf("expected User-Agent %q, got %q", UserAgent, r.Header.Get("User-Agent"))
+		}
+		if r.URL.Query().Get("fmt") != "json" {
+			t.Errorf("expected fmt=json, got %q", r.URL.RawQuery)
+		}
+
+		switch r.URL.Path {
+		case "/ws/2/release/rel-1":
+			w.Write([]byte(releaseJSON))
+		case "/ws/2/release-group/rg-1":
+			w.Write([]byte(releaseGroupJSON))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func newTestCache(t *testing.T) *Cache {
+	t.Helper()
+	cache, err := NewCache(filepath.Join(t.TempDir(), "musicbrainz_cache.json"))
+	if err != nil {
+		t.Fatalf("NewCache() error: %v", err)
+	}
+	return cache
+}
+
+func TestReleaseTracks(t *testing.T) {
+	var requests []string
+	server := newTestServer(t, &requests)
+	defer server.Close()
+
+	client := NewClient(server.URL, newTestCache(t))
+	tracks, err := client.ReleaseTracks(context.Background(), "rel-1", "")
+	if err != nil {
+		t.Fatalf("ReleaseTracks() error: %v", err)
+	}
+
+	if len(tracks) != 3 {
+		t.Fatalf("expected 3 tracks, got %d", len(tracks))
+	}
+	if tracks[1].Title != "Song" || tracks[1].Length != 240000 {
+		t.Errorf("unexpected track: %+v", tracks[1])
+	}
+	if tracks[2].MediumNumber != 2 || tracks[2].Length != 0 {
+		t.Errorf("expected bonus track on medium 2 with unknown length, got %+v", tracks[2])
+	}
+}
+
+func TestReleaseTracks_FromReleaseGroup(t *testing.T) {
+	var requests []string
+	server := newTestServer(t, &requests)
+	defer server.Close()
+
+	client := NewClient(server.URL, newTestCache(t))
+	tracks, err := client.ReleaseTracks(context.Background(), "", "rg-1")
+	if err != nil {
+		t.Fatalf("ReleaseTracks() error: %v", err)
+	}
+
+	if len(tracks) != 3 {
+		t.Errorf("expected tracks of the official release, got %d", len(tracks))
+	}
+	if len(requests) != 2 || requests[0] != "/ws/2/release-group/rg-1" || requests[1] != "/ws/2/release/rel-1" {
+		t.Errorf("unexpected requests: %v", requests)
+	}
+}
+
+func TestReleaseTracks_NoIDs(t *testing.T) {
+	client := NewClient("http://unused", newTestCache(t))
+	if _, err := client.ReleaseTracks(context.Background(), "", ""); !errors.Is(err, ErrNoRelease) {
+		t.Errorf("expected ErrNoRelease, got %v", err)
+	}
+}
+
+func TestReleaseTracks_UsesCacheAcrossClients(t *testing.T) {
+	var requests []string
+	server := newTestServer(t, &requests)
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "musicbrainz_cache.json")
+	cache, err := NewCache(path)
+	if err != nil {
+		t.Fatalf("NewCache() error: %v", err)
+	}
+
+	if _, err := NewClient(server.URL, cache).ReleaseTracks(context.Background(), "", "rg-1"); err != nil {
+		t.Fatalf("ReleaseTracks() error: %v", err)
+	}
+	if err := cache.Save(); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	// A new client with a reloaded cache, as on the next daemon run
+	reloaded, err := NewCache(path)
+	if err != nil {
+		t.Fatalf("NewCache() reload error: %v", err)
+	}
+	tracks, err := NewClient(server.URL, reloaded).ReleaseTracks(context.Background(), "", "rg-1")
+	if err != nil {
+		t.Fatalf("ReleaseTracks() from cache error: %v", err)
+	}
+
+	if len(tracks) != 3 {
+		t.Errorf("expected 3 cached tracks, got %d", len(tracks))
+	}
+	if len(requests) != 2 {
+		t.Errorf("expected cached lookup to make no requests, got %v", requests)
+	}
+}
+
+func TestReleaseTracks_RespectsContextWhileRateLimited(t *testing.T) {
+	var requests []string
+	server := newTestServer(t, &requests)
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	if _, err := client.ReleaseTracks(context.Background(), "rel-1", ""); err != nil {
+		t.Fatalf("ReleaseTracks() error: %v", err)
+	}
+
+	// Without a cache the second lookup must wait for the rate limit
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.ReleaseTracks(ctx, "rel-1", ""); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestReleaseTracks_ErrorStatus(t *testing.T) {
+	var requests []string
+	server := newTestServer(t, &requests)
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	if _, err := client.ReleaseTracks(context.Background(), "missing", ""); err == nil {
+		t.Error("expected error for 404 response")
+	}
+}
@@ -0,0 +1,31 @@
+package musicbrainz
+
+// Track is a single track of a MusicBrainz release
+type Track struct {
+	Title        string `json:"title"`
+	Length       int    `json:"length"` // milliseconds, 0 if unknown
+	MediumNumber int    `json:"medium_number"`
+	Position     int    `json:"position"`
+}
+
+// releaseResponse is the subset of /ws/2/release/{mbid}?inc=recordings we use
+type releaseResponse struct {
+	ID    string `json:"id"`
+	Media []struct {
+		Position int `json:"position"`
+		Tracks   []struct {
+			Title    string `json:"title"`
+			Length   *int   `json:"length"`
+			Position int    `json:"position"`
+		} `json:"tracks"`
+	} `json:"media"`
+}
+
+// releaseGroupResponse is the subset of /ws/2/release-group/{mbid}?inc=releases we use
+type releaseGroupResponse struct {
+	ID       string `json:"id"`
+	Releases []struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	} `json:"releases"`
+}
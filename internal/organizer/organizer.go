@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/yuritomanek/seekarr/internal/matcher"
@@ -13,17 +14,20 @@ import (
 
 // DownloadedAlbum represents an album that has been downloaded and needs organization
 type DownloadedAlbum struct {
-	ArtistName  string
-	AlbumName   string
-	FolderPath  string // Current folder path in download directory
-	MediumCount int    // Number of discs
-	Tracks      []DownloadedTrack
+	ArtistName   string
+	ArtistFolder string // Folder to organize into, e.g. the artist's folder in Lidarr; ArtistName when empty
+	AlbumName    string
+	FolderPath   string // Current folder path in download directory
+	MediumCount  int    // Number of discs
+	Tracks       []DownloadedTrack
+	Companions   []DownloadedTrack // Artwork, cue sheets and the like; moved with the tracks but not tagged
 }
 
 // DownloadedTrack represents a track with its disc number
 type DownloadedTrack struct {
 	Filename     string
-	MediumNumber int // Disc number
+	MediumNumber int    // Disc number
+	Folder       string // Download folder holding the file, if different from the album's FolderPath
 }
 
 // Organizer handles file organization and metadata tagging
@@ -46,26 +50,45 @@ func NewOrganizer(downloadDir string, logger *slog.Logger) *Organizer {
 // OrganizeAlbums processes a list of downloaded albums
 // For single-disc: Renames folder to sanitized artist name
 // For multi-disc: Tags files with metadata and reorganizes into Artist/Album structure
-func (o *Organizer) OrganizeAlbums(albums []DownloadedAlbum) error {
+// Returns the folder each album was organized into, in the order given
+func (o *Organizer) OrganizeAlbums(albums []DownloadedAlbum) ([]string, error) {
 	// Sort by artist name for better organization
 	// (In Go, we could use sort.Slice here, but for simplicity keeping order as-is)
 
+	dirs := make([]string, 0, len(albums))
 	for _, album := range albums {
-		if err := o.organizeAlbum(album); err != nil {
+		dir, err := o.organizeAlbum(album)
+		if err != nil {
 			o.logger.Error("failed to organize album",
 				"artist", album.ArtistName,
 				"album", album.AlbumName,
 				"error", err)
-			return fmt.Errorf("organize album %s - %s: %w", album.ArtistName, album.AlbumName, err)
+			return dirs, fmt.Errorf("organize album %s - %s: %w", album.ArtistName, album.AlbumName, err)
 		}
+		dirs = append(dirs, dir)
 	}
 
-	return nil
+	return dirs, nil
+}
+
+// AlbumDir returns the Artist/Album folder an album is organized into
+// artist is the album's ArtistFolder, or its ArtistName when that is empty
+func (o *Organizer) AlbumDir(artist, album string) string {
+	return filepath.Join(o.downloadDir, matcher.SanitizeFolderName(artist), matcher.SanitizeFolderName(album))
 }
 
-// organizeAlbum organizes a single album
-func (o *Organizer) organizeAlbum(album DownloadedAlbum) error {
-	sanitizedArtist := matcher.SanitizeFolderName(album.ArtistName)
+// organizeAlbum organizes a single album, returning the folder it ends up in
+func (o *Organizer) organizeAlbum(album DownloadedAlbum) (string, error) {
+	artistFolder := album.ArtistFolder
+	if artistFolder == "" {
+		artistFolder = album.ArtistName
+	}
+	sanitizedArtist := matcher.SanitizeFolderName(artistFolder)
+
+	// Albums assembled from several sources arrive in several folders
+	if err := o.gatherTracks(album); err != nil {
+		return "", err
+	}
 
 	if album.MediumCount > 1 {
 		// Multi-disc: Tag files and reorganize
@@ -76,19 +99,71 @@ func (o *Organizer) organizeAlbum(album DownloadedAlbum) error {
 	return o.organizeSingleDisc(album, sanitizedArtist)
 }
 
+// trackPath returns where a track is inside the album's FolderPath once
+// gathered. Multi-disc albums keep each file under its download folder, such
+// as CD1 and CD2, so same-named tracks on different discs don't collide
+func (album DownloadedAlbum) trackPath(track DownloadedTrack) string {
+	if track.Folder == "" || track.Folder == album.FolderPath || album.MediumCount <= 1 {
+		return track.Filename
+	}
+	return filepath.Join(track.Folder, track.Filename)
+}
+
+// gatherTracks moves tracks and companion files downloaded into other folders
+// into the album's FolderPath
+// Source folders left empty are removed
+func (o *Organizer) gatherTracks(album DownloadedAlbum) error {
+	folderPath := filepath.Join(o.downloadDir, album.FolderPath)
+
+	for _, track := range slices.Concat(album.Tracks, album.Companions) {
+		if track.Folder == "" || track.Folder == album.FolderPath {
+			continue
+		}
+
+		srcDir := filepath.Join(o.downloadDir, track.Folder)
+		srcPath := filepath.Join(srcDir, track.Filename)
+		if _, err := os.Stat(srcPath); os.IsNotExist(err) {
+			o.logger.Debug("skipping gather for non-existent file", "file", srcPath)
+			continue
+		}
+
+		dstPath := filepath.Join(folderPath, album.trackPath(track))
+		if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
+			return fmt.Errorf("create album folder: %w", err)
+		}
+
+		if _, err := os.Stat(dstPath); err == nil {
+			o.logger.Warn("file already exists in album folder, keeping existing",
+				"file", track.Filename,
+				"folder", folderPath)
+			continue
+		}
+
+		if err := os.Rename(srcPath, dstPath); err != nil {
+			return fmt.Errorf("gather %s: %w", track.Filename, err)
+		}
+
+		// Only succeeds once the folder is empty
+		os.Remove(srcDir)
+	}
+
+	return nil
+}
+
 // organizeSingleDisc organizes single-disc album into Artist/Album structure
-func (o *Organizer) organizeSingleDisc(album DownloadedAlbum, sanitizedArtist string) error {
+// An existing album folder is left alone and the album moved beside it
+func (o *Organizer) organizeSingleDisc(album DownloadedAlbum, sanitizedArtist string) (string, error) {
 	folderPath := filepath.Join(o.downloadDir, album.FolderPath)
 	sanitizedAlbum := matcher.SanitizeFolderName(album.AlbumName)
 
 	// Check if source exists
 	if _, err := os.Stat(folderPath); os.IsNotExist(err) {
-		return fmt.Errorf("source folder does not exist: %s", folderPath)
+		return "", fmt.Errorf("source folder does not exist: %s", folderPath)
 	}
 
 	// Step 1: Tag all files with metadata (important for Lidarr matching)
 	for _, track := range album.Tracks {
-		filePath := filepath.Join(folderPath, track.Filename)
+		filePath := filepath.Join(folderPath, album.trackPath(track))
 
 		// Check if file exists before trying to tag (some files may have failed to download)
 		if _, err := os.Stat(filePath); os.IsNotExist(err) {
@@ -111,12 +186,12 @@ func (o *Organizer) organizeSingleDisc(album DownloadedAlbum, sanitizedArtist st
 	// If already at correct path, skip move
 	if folderPath == albumDir {
 		o.logger.Info("folder already correctly organized", "path", albumDir)
-		return nil
+		return albumDir, nil
 	}
 
 	// Create artist directory if needed
 	if err := os.MkdirAll(artistDir, 0755); err != nil {
-		return fmt.Errorf("create artist directory: %w", err)
+		return "", fmt.Errorf("create artist directory: %w", err)
 	}
 
 	// Handle collision
@@ -130,20 +205,20 @@ func (o *Organizer) organizeSingleDisc(album DownloadedAlbum, sanitizedArtist st
 		"to", targetPath)
 
 	if err := os.Rename(folderPath, targetPath); err != nil {
-		return fmt.Errorf("move to album directory: %w", err)
+		return "", fmt.Errorf("move to album directory: %w", err)
 	}
 
-	return nil
+	return targetPath, nil
 }
 
 // organizeMultiDisc tags files with metadata and reorganizes into Artist/Album structure
-func (o *Organizer) organizeMultiDisc(album DownloadedAlbum, sanitizedArtist string) error {
+func (o *Organizer) organizeMultiDisc(album DownloadedAlbum, sanitizedArtist string) (string, error) {
 	folderPath := filepath.Join(o.downloadDir, album.FolderPath)
 	sanitizedAlbum := matcher.SanitizeFolderName(album.AlbumName)
 
 	// Step 1: Tag all files with metadata
 	for _, track := range album.Tracks {
-		filePath := filepath.Join(folderPath, track.Filename)
+		filePath := filepath.Join(folderPath, album.trackPath(track))
 
 		// Check if file exists before trying to tag (some files may have failed to download)
 		if _, err := os.Stat(filePath); os.IsNotExist(err) {
@@ -164,22 +239,42 @@ func (o *Organizer) organizeMultiDisc(album DownloadedAlbum, sanitizedArtist str
 	albumDir := filepath.Join(artistDir, sanitizedAlbum)
 
 	if err := os.MkdirAll(albumDir, 0755); err != nil {
-		return fmt.Errorf("create album directory: %w", err)
+		return "", fmt.Errorf("create album directory: %w", err)
 	}
 
-	// Step 3: Move all files to target directory
-	files, err := os.ReadDir(folderPath)
-	if err != nil {
-		return fmt.Errorf("read folder: %w", err)
+	// Step 3: Move all files to target directory, keeping the disc folders
+	// gatherTracks moved files into
+	gathered := make(map[string]bool)
+	for _, track := range slices.Concat(album.Tracks, album.Companions) {
+		if dir := filepath.Dir(album.trackPath(track)); dir != "." {
+			gathered[dir] = true
+		}
 	}
 
-	for _, file := range files {
-		if file.IsDir() {
-			continue
+	var discDirs []string
+	err := filepath.WalkDir(folderPath, func(srcPath string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(folderPath, srcPath)
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if rel == "." {
+				return nil
+			}
+			if !gathered[rel] {
+				return filepath.SkipDir
+			}
+			discDirs = append(discDirs, srcPath)
+			return nil
 		}
 
-		srcPath := filepath.Join(folderPath, file.Name())
-		dstPath := filepath.Join(albumDir, file.Name())
+		dstPath := filepath.Join(albumDir, rel)
+		if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
+			return fmt.Errorf("create disc directory: %w", err)
+		}
 
 		// Handle collision
 		if _, err := os.Stat(dstPath); err == nil {
@@ -192,9 +287,16 @@ func (o *Organizer) organizeMultiDisc(album DownloadedAlbum, sanitizedArtist str
 				"to", dstPath,
 				"error", err)
 		}
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("read folder: %w", err)
 	}
 
 	// Step 4: Remove original folder if empty
+	for _, dir := range discDirs {
+		os.Remove(dir)
+	}
 	if err := os.Remove(folderPath); err != nil {
 		o.logger.Warn("failed to remove original folder",
 			"path", folderPath,
@@ -206,7 +308,7 @@ func (o *Organizer) organizeMultiDisc(album DownloadedAlbum, sanitizedArtist str
 		"album", album.AlbumName,
 		"discs", album.MediumCount)
 
-	return nil
+	return albumDir, nil
 }
 
 // tagFile writes metadata to an audio file
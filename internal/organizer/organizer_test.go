@@ -35,7 +35,7 @@ func TestOrganizeSingleDisc(t *testing.T) {
 		MediumCount: 1,
 	}
 
-	if err := org.OrganizeAlbums([]DownloadedAlbum{album}); err != nil {
+	if _, err := org.OrganizeAlbums([]DownloadedAlbum{album}); err != nil {
 		t.Fatalf("OrganizeAlbums() error: %v", err)
 	}
 
@@ -58,6 +58,32 @@ func TestOrganizeSingleDisc(t *testing.T) {
 	}
 }
 
+func TestOrganizeSingleDisc_ArtistFolder(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(tmpDir, "Download"), 0755); err != nil {
+		t.Fatalf("failed to create test folder: %v", err)
+	}
+
+	org := NewOrganizer(tmpDir, slog.Default())
+	album := DownloadedAlbum{
+		ArtistName:   "The Beatles",
+		ArtistFolder: "Beatles, The",
+		AlbumName:    "Abbey Road",
+		FolderPath:   "Download",
+		MediumCount:  1,
+	}
+
+	dirs, err := org.OrganizeAlbums([]DownloadedAlbum{album})
+	if err != nil {
+		t.Fatalf("OrganizeAlbums() error: %v", err)
+	}
+
+	want := filepath.Join(tmpDir, "Beatles, The", "Abbey Road")
+	if len(dirs) != 1 || dirs[0] != want {
+		t.Errorf("expected album in %s, got %v", want, dirs)
+	}
+}
+
 func TestOrganizeSingleDisc_Collision(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -84,7 +110,8 @@ func TestOrganizeSingleDisc_Collision(t *testing.T) {
 		MediumCount: 1,
 	}
 
-	if err := org.OrganizeAlbums([]DownloadedAlbum{album}); err != nil {
+	dirs, err := org.OrganizeAlbums([]DownloadedAlbum{album})
+	if err != nil {
 		t.Fatalf("OrganizeAlbums() error: %v", err)
 	}
 
@@ -93,6 +120,9 @@ func TestOrganizeSingleDisc_Collision(t *testing.T) {
 	if _, err := os.Stat(expectedPath); os.IsNotExist(err) {
 		t.Errorf("expected album folder with collision suffix not found: %s", expectedPath)
 	}
+	if len(dirs) != 1 || dirs[0] != expectedPath {
+		t.Errorf("expected OrganizeAlbums() to return %s, got %v", expectedPath, dirs)
+	}
 
 	// Verify original album folder still exists
 	if _, err := os.Stat(existingAlbumPath); os.IsNotExist(err) {
@@ -100,6 +130,81 @@ func TestOrganizeSingleDisc_Collision(t *testing.T) {
 	}
 }
 
+func TestOrganizeSingleDisc_GathersTracksFromSeveralFolders(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Tracks found by separate searches land in separate download folders
+	for folder, file := range map[string]string{"Album": "01 - One.flac", "Singles": "02 - Two.flac"} {
+		if err := os.MkdirAll(filepath.Join(tmpDir, folder), 0755); err != nil {
+			t.Fatalf("failed to create test folder: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(tmpDir, folder, file), []byte("dummy"), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+	}
+
+	org := NewOrganizer(tmpDir, slog.Default())
+	album := DownloadedAlbum{
+		ArtistName:  "Test Artist",
+		AlbumName:   "Test Album",
+		FolderPath:  "Album",
+		MediumCount: 1,
+		Tracks: []DownloadedTrack{
+			{Filename: "01 - One.flac", MediumNumber: 1, Folder: "Album"},
+			{Filename: "02 - Two.flac", MediumNumber: 1, Folder: "Singles"},
+		},
+	}
+
+	if _, err := org.OrganizeAlbums([]DownloadedAlbum{album}); err != nil {
+		t.Fatalf("OrganizeAlbums() error: %v", err)
+	}
+
+	albumDir := filepath.Join(tmpDir, "Test Artist", "Test Album")
+	for _, file := range []string{"01 - One.flac", "02 - Two.flac"} {
+		if _, err := os.Stat(filepath.Join(albumDir, file)); err != nil {
+			t.Errorf("expected %s in album folder: %v", file, err)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, "Singles")); !os.IsNotExist(err) {
+		t.Error("emptied source folder should be removed")
+	}
+}
+
+func TestOrganizeSingleDisc_GathersCompanionFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for folder, file := range map[string]string{"Album": "01 - One.flac", "Scans": "folder.jpg"} {
+		if err := os.MkdirAll(filepath.Join(tmpDir, folder), 0755); err != nil {
+			t.Fatalf("failed to create test folder: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(tmpDir, folder, file), []byte("dummy"), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+	}
+
+	org := NewOrganizer(tmpDir, slog.Default())
+	album := DownloadedAlbum{
+		ArtistName:  "Test Artist",
+		AlbumName:   "Test Album",
+		FolderPath:  "Album",
+		MediumCount: 1,
+		Tracks:      []DownloadedTrack{{Filename: "01 - One.flac", MediumNumber: 1}},
+		Companions:  []DownloadedTrack{{Filename: "folder.jpg", Folder: "Scans"}},
+	}
+
+	if _, err := org.OrganizeAlbums([]DownloadedAlbum{album}); err != nil {
+		t.Fatalf("OrganizeAlbums() error: %v", err)
+	}
+
+	albumDir := filepath.Join(tmpDir, "Test Artist", "Test Album")
+	for _, file := range []string{"01 - One.flac", "folder.jpg"} {
+		if _, err := os.Stat(filepath.Join(albumDir, file)); err != nil {
+			t.Errorf("expected %s in album folder: %v", file, err)
+		}
+	}
+}
+
 func TestOrganizeMultiDisc(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -133,7 +238,8 @@ func TestOrganizeMultiDisc(t *testing.T) {
 		},
 	}
 
-	if err := org.OrganizeAlbums([]DownloadedAlbum{album}); err != nil {
+	dirs, err := org.OrganizeAlbums([]DownloadedAlbum{album})
+	if err != nil {
 		t.Fatalf("OrganizeAlbums() error: %v", err)
 	}
 
@@ -142,6 +248,9 @@ func TestOrganizeMultiDisc(t *testing.T) {
 	if _, err := os.Stat(expectedDir); os.IsNotExist(err) {
 		t.Errorf("expected directory not found: %s", expectedDir)
 	}
+	if len(dirs) != 1 || dirs[0] != expectedDir {
+		t.Errorf("expected OrganizeAlbums() to return %s, got %v", expectedDir, dirs)
+	}
 
 	// Verify all files were moved
 	for _, file := range files {
@@ -195,7 +304,7 @@ func TestOrganizeMultiDisc_WithSubdirectories(t *testing.T) {
 		},
 	}
 
-	if err := org.OrganizeAlbums([]DownloadedAlbum{album}); err != nil {
+	if _, err := org.OrganizeAlbums([]DownloadedAlbum{album}); err != nil {
 		t.Fatalf("OrganizeAlbums() error: %v", err)
 	}
 
@@ -212,6 +321,49 @@ func TestOrganizeMultiDisc_WithSubdirectories(t *testing.T) {
 	// The original folder won't be deleted if it's not empty
 }
 
+func TestOrganizeMultiDisc_GathersDiscFolders(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// slskd saves each disc folder of the remote album beside the other
+	for _, disc := range []string{"CD1", "CD2"} {
+		if err := os.MkdirAll(filepath.Join(tmpDir, disc), 0755); err != nil {
+			t.Fatalf("failed to create test folder: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(tmpDir, disc, "01.flac"), []byte(disc), 0644); err != nil {
+			t.Fatalf("failed to create test file: %v", err)
+		}
+	}
+
+	org := NewOrganizer(tmpDir, slog.Default())
+	album := DownloadedAlbum{
+		ArtistName:  "Test Artist",
+		AlbumName:   "Test Album",
+		FolderPath:  "Album",
+		MediumCount: 2,
+		Tracks: []DownloadedTrack{
+			{Filename: "01.flac", MediumNumber: 1, Folder: "CD1"},
+			{Filename: "01.flac", MediumNumber: 2, Folder: "CD2"},
+		},
+	}
+
+	if _, err := org.OrganizeAlbums([]DownloadedAlbum{album}); err != nil {
+		t.Fatalf("OrganizeAlbums() error: %v", err)
+	}
+
+	albumDir := filepath.Join(tmpDir, "Test Artist", "Test Album")
+	for _, disc := range []string{"CD1", "CD2"} {
+		data, err := os.ReadFile(filepath.Join(albumDir, disc, "01.flac"))
+		if err != nil || string(data) != disc {
+			t.Errorf("expected %s's track in its own folder, got %q: %v", disc, data, err)
+		}
+	}
+	for _, folder := range []string{"Album", "CD1", "CD2"} {
+		if _, err := os.Stat(filepath.Join(tmpDir, folder)); !os.IsNotExist(err) {
+			t.Errorf("expected emptied folder %s removed", folder)
+		}
+	}
+}
+
 func TestSanitizeFolderName(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -231,7 +383,7 @@ func TestSanitizeFolderName(t *testing.T) {
 		MediumCount: 1,
 	}
 
-	if err := org.OrganizeAlbums([]DownloadedAlbum{album}); err != nil {
+	if _, err := org.OrganizeAlbums([]DownloadedAlbum{album}); err != nil {
 		t.Fatalf("OrganizeAlbums() error: %v", err)
 	}
 
@@ -399,7 +551,7 @@ func TestOrganizeAlbums_Error(t *testing.T) {
 		MediumCount: 1,
 	}
 
-	err := org.OrganizeAlbums([]DownloadedAlbum{album})
+	_, err := org.OrganizeAlbums([]DownloadedAlbum{album})
 	if err == nil {
 		t.Error("expected error for non-existent folder")
 	}
@@ -431,7 +583,7 @@ func TestOrganizeSingleDisc_AlreadyOrganized(t *testing.T) {
 	}
 
 	// Should succeed without error
-	if err := org.OrganizeAlbums([]DownloadedAlbum{album}); err != nil {
+	if _, err := org.OrganizeAlbums([]DownloadedAlbum{album}); err != nil {
 		t.Fatalf("OrganizeAlbums() error: %v", err)
 	}
 
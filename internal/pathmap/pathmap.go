@@ -0,0 +1,109 @@
+package pathmap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Mapping rewrites paths starting with FromPrefix to start with ToPrefix
+type Mapping struct {
+	FromPrefix string `yaml:"from_prefix"`
+	ToPrefix   string `yaml:"to_prefix"`
+}
+
+// Mapper applies a set of prefix mappings using longest-prefix match
+// Prefixes may use either forward or back slashes; Windows-style prefixes
+// (drive letters or backslashes) are matched case-insensitively
+type Mapper struct {
+	mappings []Mapping
+}
+
+// New validates mappings and returns a Mapper
+// Each from_prefix must be non-empty and unique once normalised; nested
+// prefixes are allowed and resolved by the longest match
+func New(mappings []Mapping) (*Mapper, error) {
+	seen := make(map[string]string)
+	sorted := make([]Mapping, 0, len(mappings))
+
+	for i, m := range mappings {
+		if strings.TrimSpace(m.FromPrefix) == "" {
+			return nil, fmt.Errorf("mapping %d: from_prefix is required", i+1)
+		}
+		if strings.TrimSpace(m.ToPrefix) == "" {
+			return nil, fmt.Errorf("mapping %d: to_prefix is required", i+1)
+		}
+
+		key := matchKey(m.FromPrefix)
+		if prev, ok := seen[key]; ok {
+			return nil, fmt.Errorf("mapping %d: from_prefix %q overlaps with %q", i+1, m.FromPrefix, prev)
+		}
+		seen[key] = m.FromPrefix
+		sorted = append(sorted, m)
+	}
+
+	// Longest prefix first so the most specific mapping wins
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return len(normalise(sorted[i].FromPrefix)) > len(normalise(sorted[j].FromPrefix))
+	})
+
+	return &Mapper{mappings: sorted}, nil
+}
+
+// Map rewrites path using the longest matching prefix
+// Returns the path unchanged and false when no mapping applies
+func (m *Mapper) Map(path string) (string, bool) {
+	if m == nil {
+		return path, false
+	}
+
+	normalised := normalise(path)
+	for _, mapping := range m.mappings {
+		from := normalise(mapping.FromPrefix)
+
+		candidate := normalised
+		if isWindows(mapping.FromPrefix) {
+			candidate = strings.ToLower(candidate)
+			from = strings.ToLower(from)
+		}
+
+		if candidate != from && !strings.HasPrefix(candidate, from+"/") {
+			continue
+		}
+
+		rest := normalised[len(from):]
+		to := strings.TrimRight(mapping.ToPrefix, `/\`)
+		if strings.Contains(mapping.ToPrefix, `\`) {
+			rest = strings.ReplaceAll(rest, "/", `\`)
+		}
+		return to + rest, true
+	}
+
+	return path, false
+}
+
+// normalise converts separators to forward slashes and drops any trailing slash
+func normalise(path string) string {
+	path = strings.ReplaceAll(path, `\`, "/")
+	if len(path) > 1 {
+		path = strings.TrimRight(path, "/")
+	}
+	return path
+}
+
+// matchKey is the form used to detect duplicate prefixes
+func matchKey(prefix string) string {
+	if isWindows(prefix) {
+		return strings.ToLower(normalise(prefix))
+	}
+	return normalise(prefix)
+}
+
+// isWindows reports whether a prefix looks like a Windows path
+func isWindows(prefix string) bool {
+	if strings.Contains(prefix, `\`) {
+		return true
+	}
+	return len(prefix) >= 2 && prefix[1] == ':' &&
+		((prefix[0] >= 'a' && prefix[0] <= 'z') || (prefix[0] >= 'A' && prefix[0] <= 'Z'))
+}
@@ -0,0 +1,74 @@
+package pathmap
+
+import "testing"
+
+func TestMap(t *testing.T) {
+	mapper, err := New([]Mapping{
+		{FromPrefix: "/app/downloads", ToPrefix: "/data/slskd"},
+		{FromPrefix: "/app/downloads/complete", ToPrefix: "/data/complete"},
+		{FromPrefix: `C:\slskd\downloads`, ToPrefix: "/mnt/windows/downloads"},
+		{FromPrefix: "/data/music", ToPrefix: `D:\Music\incoming`},
+	})
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		want       string
+		wantMapped bool
+	}{
+		{"exact prefix", "/app/downloads", "/data/slskd", true},
+		{"nested path", "/app/downloads/Artist/Album", "/data/slskd/Artist/Album", true},
+		{"longest prefix wins", "/app/downloads/complete/Album", "/data/complete/Album", true},
+		{"prefix must end at separator", "/app/downloads2/Album", "/app/downloads2/Album", false},
+		{"trailing slash", "/app/downloads/", "/data/slskd", true},
+		{"windows backslash source", `C:\slskd\downloads\Artist\Album`, "/mnt/windows/downloads/Artist/Album", true},
+		{"windows case insensitive", `c:\SLSKD\Downloads\Album`, "/mnt/windows/downloads/Album", true},
+		{"windows forward slash source", "C:/slskd/downloads/Album", "/mnt/windows/downloads/Album", true},
+		{"windows target uses backslashes", "/data/music/Artist", `D:\Music\incoming\Artist`, true},
+		{"unix prefixes are case sensitive", "/APP/downloads/Album", "/APP/downloads/Album", false},
+		{"no match", "/elsewhere/Album", "/elsewhere/Album", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, mapped := mapper.Map(tt.path)
+			if got != tt.want || mapped != tt.wantMapped {
+				t.Errorf("Map(%q) = %q, %v; want %q, %v", tt.path, got, mapped, tt.want, tt.wantMapped)
+			}
+		})
+	}
+}
+
+func TestNew_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		mappings []Mapping
+		wantErr  bool
+	}{
+		{"empty", nil, false},
+		{"missing from", []Mapping{{ToPrefix: "/b"}}, true},
+		{"missing to", []Mapping{{FromPrefix: "/a"}}, true},
+		{"duplicate prefix", []Mapping{{FromPrefix: "/a", ToPrefix: "/b"}, {FromPrefix: "/a/", ToPrefix: "/c"}}, true},
+		{"duplicate windows prefix", []Mapping{{FromPrefix: `C:\a`, ToPrefix: "/b"}, {FromPrefix: "c:/A", ToPrefix: "/c"}}, true},
+		{"nested prefixes", []Mapping{{FromPrefix: "/a", ToPrefix: "/b"}, {FromPrefix: "/a/b", ToPrefix: "/c"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.mappings)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMap_NilMapper(t *testing.T) {
+	var m *Mapper
+	if got, mapped := m.Map("/a/b"); got != "/a/b" || mapped {
+		t.Errorf("nil mapper should leave paths unchanged, got %q %v", got, mapped)
+	}
+}
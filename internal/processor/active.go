@@ -0,0 +1,109 @@
+package processor
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// activeDirectory is a directory slskd is still downloading or has queued
+type activeDirectory struct {
+	username string
+	path     string
+	name     string // Album folder without bracketed qualifiers or a disc subfolder
+	parent   string // Folder above it, often the artist
+}
+
+// activeDirectories lists the directories with a file slskd hasn't finished
+func activeDirectories(downloads slskd.DownloadsResponse) []activeDirectory {
+	var dirs []activeDirectory
+	for _, userDownload := range downloads {
+		for _, dirDownload := range userDownload.Directories {
+			active := slices.ContainsFunc(dirDownload.Files, func(file slskd.DownloadFile) bool {
+				return !file.IsCompleted()
+			})
+			if !active {
+				continue
+			}
+
+			path := strings.ReplaceAll(dirDownload.Directory, "\\", "/")
+			parts := strings.FieldsFunc(path, func(r rune) bool { return r == '/' })
+			if len(parts) > 1 {
+				if _, ok := discNumber(parts[len(parts)-1]); ok {
+					parts = parts[:len(parts)-1]
+				}
+			}
+			if len(parts) == 0 {
+				continue
+			}
+
+			dir := activeDirectory{
+				username: userDownload.Username,
+				path:     path,
+				name:     strings.TrimSpace(bracketedPattern.ReplaceAllString(parts[len(parts)-1], "")),
+			}
+			if len(parts) > 1 {
+				dir.parent = strings.TrimSpace(bracketedPattern.ReplaceAllString(parts[len(parts)-2], ""))
+			}
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// activeDownloadFor finds an active directory whose name matches an album,
+// either as "Artist/Album" folders or a single "Artist - Album" folder
+func (p *Processor) activeDownloadFor(album lidarr.Album, dirs []activeDirectory) (activeDirectory, bool) {
+	m := p.matcherFor(album)
+	threshold := p.cfg.Search.MinimumFilenameMatchRatio
+	artist, title := album.Artist.ArtistName, album.Title
+
+	for _, dir := range dirs {
+		// Compilation folders rarely name the artist
+		if isVariousArtists(album) {
+			if m.Similarity(title, dir.name) >= threshold {
+				return dir, true
+			}
+			continue
+		}
+		if m.Similarity(artist+" "+title, dir.parent+" "+dir.name) >= threshold ||
+			m.Similarity(artist+" - "+title, dir.name) >= threshold {
+			return dir, true
+		}
+	}
+	return activeDirectory{}, false
+}
+
+// filterActiveDownloads removes albums slskd is already downloading, so a
+// restarted run doesn't queue them again from another user
+func (p *Processor) filterActiveDownloads(ctx context.Context, albums []lidarr.Album) []lidarr.Album {
+	downloads, err := p.slskd.GetDownloads(ctx)
+	if err != nil {
+		p.logger.Warn("failed to fetch slskd downloads, skipping active download filtering", "error", err)
+		return albums
+	}
+
+	dirs := activeDirectories(downloads)
+	if len(dirs) == 0 {
+		return albums
+	}
+
+	var filtered []lidarr.Album
+	for _, album := range albums {
+		dir, ok := p.activeDownloadFor(album, dirs)
+		if !ok {
+			filtered = append(filtered, album)
+			continue
+		}
+		p.logger.Info("skipping album already downloading in slskd",
+			"album", album.Title,
+			"artist", album.Artist.ArtistName,
+			"username", dir.username,
+			"directory", dir.path)
+		p.recordDecision(album, OutcomeSkipped, ReasonQueued, "")
+	}
+	return filtered
+}
@@ -0,0 +1,57 @@
+package processor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// mockSlskdClientWithDownloads reports a fixed set of downloads
+type mockSlskdClientWithDownloads struct {
+	mockSlskdClient
+	downloads slskd.DownloadsResponse
+}
+
+func (m *mockSlskdClientWithDownloads) GetDownloads(ctx context.Context) (slskd.DownloadsResponse, error) {
+	return m.downloads, nil
+}
+
+func TestFilterActiveDownloads(t *testing.T) {
+	download := func(dir, state string) slskd.UserDownloads {
+		return slskd.UserDownloads{Username: "user", Directories: []slskd.DirectoryDownloads{{
+			Directory: dir,
+			Files:     []slskd.DownloadFile{{ID: "1", Filename: dir + `\01.flac`, State: state}},
+		}}}
+	}
+	album := lidarr.Album{ID: 1, Title: "Homogenic", Artist: lidarr.Artist{ArtistName: "Björk"}}
+	compilation := lidarr.Album{ID: 2, Title: "Now 50", Artist: lidarr.Artist{ArtistName: "Various Artists"}}
+
+	tests := []struct {
+		name     string
+		album    lidarr.Album
+		download slskd.UserDownloads
+		skipped  bool
+	}{
+		{"artist and album folders", album, download(`Music\Bjork\Homogenic (1997) [FLAC]`, "InProgress"), true},
+		{"single folder", album, download(`Music\Björk - Homogenic`, "Queued, Remotely"), true},
+		{"disc subfolder", album, download(`Music\Bjork\Homogenic\CD1`, "Initializing"), true},
+		{"compilation by title", compilation, download(`Music\Now 50 [2001]`, "InProgress"), true},
+		{"finished download", album, download(`Music\Bjork\Homogenic`, "Completed, Succeeded"), false},
+		{"other album", album, download(`Music\Bjork\Vespertine`, "InProgress"), false},
+		{"same title by another artist", album, download(`Music\Someone Else\Homogenic`, "InProgress"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slskdClient := &mockSlskdClientWithDownloads{downloads: slskd.DownloadsResponse{tt.download}}
+			p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+
+			filtered := p.filterActiveDownloads(context.Background(), []lidarr.Album{tt.album})
+			if skipped := len(filtered) == 0; skipped != tt.skipped {
+				t.Errorf("expected skipped=%v, got %v", tt.skipped, skipped)
+			}
+		})
+	}
+}
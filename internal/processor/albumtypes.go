@@ -0,0 +1,31 @@
+package processor
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+)
+
+// excludedAlbumType returns the type an album is skipped for, either a primary
+// type missing from allowed_album_types or one of excluded_secondary_types.
+// Albums Lidarr gives no type for are searched
+func (p *Processor) excludedAlbumType(album lidarr.Album) (string, bool) {
+	allowed := p.cfg.Search.AllowedAlbumTypes
+	if len(allowed) > 0 && album.AlbumType != "" && !containsFold(allowed, album.AlbumType) {
+		return album.AlbumType, true
+	}
+	for _, secondary := range album.SecondaryTypes {
+		if containsFold(p.cfg.Search.ExcludedSecondaryTypes, secondary) {
+			return secondary, true
+		}
+	}
+	return "", false
+}
+
+// containsFold reports whether list contains s, ignoring case
+func containsFold(list []string, s string) bool {
+	return slices.ContainsFunc(list, func(entry string) bool {
+		return strings.EqualFold(entry, s)
+	})
+}
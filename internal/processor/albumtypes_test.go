@@ -0,0 +1,89 @@
+package processor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+)
+
+func TestExcludedAlbumType(t *testing.T) {
+	tests := []struct {
+		name     string
+		allowed  []string
+		excluded []string
+		album    lidarr.Album
+		wantType string
+		wantSkip bool
+	}{
+		{
+			name:  "no filtering",
+			album: lidarr.Album{AlbumType: "Single", SecondaryTypes: []string{"Live"}},
+		},
+		{
+			name:    "allowed type",
+			allowed: []string{"Album", "EP"},
+			album:   lidarr.Album{AlbumType: "EP"},
+		},
+		{
+			name:     "type not allowed",
+			allowed:  []string{"Album", "EP"},
+			album:    lidarr.Album{AlbumType: "Broadcast"},
+			wantType: "Broadcast",
+			wantSkip: true,
+		},
+		{
+			name:    "allowed ignoring case",
+			allowed: []string{"album"},
+			album:   lidarr.Album{AlbumType: "Album"},
+		},
+		{
+			name:    "no type reported",
+			allowed: []string{"Album"},
+			album:   lidarr.Album{},
+		},
+		{
+			name:     "excluded secondary type",
+			excluded: []string{"live"},
+			album:    lidarr.Album{AlbumType: "Album", SecondaryTypes: []string{"Compilation", "Live"}},
+			wantType: "Live",
+			wantSkip: true,
+		},
+		{
+			name:     "other secondary type",
+			excluded: []string{"Live"},
+			album:    lidarr.Album{AlbumType: "Album", SecondaryTypes: []string{"Compilation"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestProcessor(t, &mockLidarrClient{}, &mockSlskdClient{})
+			p.cfg.Search.AllowedAlbumTypes = tt.allowed
+			p.cfg.Search.ExcludedSecondaryTypes = tt.excluded
+
+			albumType, skip := p.excludedAlbumType(tt.album)
+			if skip != tt.wantSkip || albumType != tt.wantType {
+				t.Errorf("excludedAlbumType() = %q, %v, want %q, %v", albumType, skip, tt.wantType, tt.wantSkip)
+			}
+		})
+	}
+}
+
+func TestQueueAlbum_SkipsExcludedAlbumType(t *testing.T) {
+	p := newTestProcessor(t, &mockLidarrClient{}, &mockSlskdClient{})
+	p.cfg.Search.AllowedAlbumTypes = []string{"Album"}
+	p.current = &RunSummary{}
+
+	album, _ := candidateAlbum()
+	album.AlbumType = "Single"
+	if _, outcome := p.queueAlbum(context.Background(), album); outcome != OutcomeSkipped {
+		t.Fatalf("expected the single skipped, got %s", outcome)
+	}
+	if got := p.current.Decisions; len(got) != 1 || got[0].Reason != ReasonAlbumType {
+		t.Errorf("expected an album_type decision, got %+v", got)
+	}
+	if entry := p.denylist.GetEntry(album.ID); entry != nil {
+		t.Errorf("expected no denylist entry, got %+v", entry)
+	}
+}
@@ -0,0 +1,163 @@
+package processor
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// mockLidarrClientWithAliases returns an artist with aliases and a fixed track list
+type mockLidarrClientWithAliases struct {
+	mockLidarrClient
+	aliases []string
+	tracks  []lidarr.Track
+}
+
+func (m *mockLidarrClientWithAliases) GetTracks(ctx context.Context, albumID int, releaseID *int) ([]lidarr.Track, error) {
+	return m.tracks, nil
+}
+
+func (m *mockLidarrClientWithAliases) GetArtist(ctx context.Context, id int) (*lidarr.Artist, error) {
+	return &lidarr.Artist{ID: id, Aliases: m.aliases}, nil
+}
+
+// mockSlskdClientWithQueries only returns results for searches containing matchText
+type mockSlskdClientWithQueries struct {
+	mockSlskdClient
+	matchText string
+	queries   []string
+}
+
+func (m *mockSlskdClientWithQueries) Search(ctx context.Context, req slskd.SearchRequest) (*slskd.SearchResponse, error) {
+	m.queries = append(m.queries, req.SearchText)
+	return &slskd.SearchResponse{ID: req.SearchText}, nil
+}
+
+func (m *mockSlskdClientWithQueries) GetSearchResults(ctx context.Context, searchID string) ([]slskd.SearchResult, error) {
+	if !strings.Contains(searchID, m.matchText) {
+		return nil, nil
+	}
+	return []slskd.SearchResult{{
+		Username: "peer",
+		Files:    []slskd.SearchFile{{Filename: `Music\Album\01 - Opening.flac`, Size: 100}},
+	}}, nil
+}
+
+func (m *mockSlskdClientWithQueries) StreamSearchResults(ctx context.Context, searchID string, fn func(slskd.SearchResult) bool) error {
+	results, err := m.GetSearchResults(ctx, searchID)
+	return yieldResults(results, err, fn)
+}
+
+func TestArtistAliases(t *testing.T) {
+	got := artistAliases("The Band", []string{"Band", " the band ", "", "Old Name", "band", "Другое Имя", "Fourth"})
+	want := []string{"Band", "Old Name", "Другое Имя"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("artistAliases() = %v, want %v", got, want)
+	}
+}
+
+func TestQueueAlbum_RetriesWithArtistAlias(t *testing.T) {
+	tests := []struct {
+		name        string
+		aliases     []string
+		matchText   string
+		wantOutcome string
+		wantQueries int
+	}{
+		{"primary query matches", []string{"Old Name"}, "The Band", OutcomeQueued, 1},
+		{"alias matches", []string{"Wrong", "Old Name"}, "Old Name", OutcomeQueued, 4},
+		{"no alias matches", []string{"A", "B", "C", "D"}, "nothing", OutcomeFailed, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lidarrClient := &mockLidarrClientWithAliases{
+				aliases: tt.aliases,
+				tracks:  []lidarr.Track{{Title: "Opening"}},
+			}
+			slskdClient := &mockSlskdClientWithQueries{matchText: tt.matchText}
+			p := newTestProcessor(t, lidarrClient, slskdClient)
+			p.cfg.Search.AlbumPrependArtist = true
+			p.current = &RunSummary{}
+
+			album := lidarr.Album{
+				ID:       1,
+				Title:    "Album",
+				ArtistID: 2,
+				Artist:   lidarr.Artist{ID: 2, ArtistName: "The Band"},
+				Releases: []lidarr.Release{{Status: "Official", TrackCount: 1}},
+			}
+
+			_, outcome := p.queueAlbum(context.Background(), album)
+			if outcome != tt.wantOutcome {
+				t.Errorf("expected outcome %q, got %q", tt.wantOutcome, outcome)
+			}
+			if len(slskdClient.queries) != tt.wantQueries {
+				t.Errorf("expected %d searches, got %v", tt.wantQueries, slskdClient.queries)
+			}
+			if tt.wantOutcome == OutcomeQueued {
+				decision := p.current.Decisions[0]
+				if !strings.HasPrefix(decision.Query, tt.matchText) {
+					t.Errorf("expected recorded query to use %q, got %q", tt.matchText, decision.Query)
+				}
+			}
+		})
+	}
+}
+
+func TestAlbumQueries(t *testing.T) {
+	album := lidarr.Album{Title: "Album", Artist: lidarr.Artist{ArtistName: "Artist"}}
+
+	tests := []struct {
+		name          string
+		prependArtist bool
+		album         lidarr.Album
+		want          []string
+	}{
+		{"title first", false, album, []string{"Album", "Artist Album"}},
+		{"artist first", true, album, []string{"Artist Album", "Album"}},
+		{"no artist name", false, lidarr.Album{Title: "Album"}, []string{"Album"}},
+		{"various artists", true, lidarr.Album{Title: "Now 45", Artist: lidarr.Artist{ArtistName: "Various Artists"}}, []string{"Now 45"}},
+		{"various artists by MusicBrainz ID", false, lidarr.Album{Title: "Now 45", Artist: lidarr.Artist{ArtistName: "Verschiedene Interpreten", ForeignArtistID: variousArtistsMBID}}, []string{"Now 45"}},
+		{"punctuation", false, lidarr.Album{Title: "Don't Stop", Artist: lidarr.Artist{ArtistName: "AC/DC"}}, []string{"Don t Stop", "AC DC Don t Stop", "Don't Stop"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestProcessor(t, &mockLidarrClient{}, &mockSlskdClient{})
+			p.cfg.Search.AlbumPrependArtist = tt.prependArtist
+
+			if got := p.albumQueries(tt.album); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("albumQueries() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueueAlbum_RetriesWithOtherQueryForm(t *testing.T) {
+	slskdClient := &mockSlskdClientWithQueries{matchText: "The Band Album"}
+	lidarrClient := &mockLidarrClientWithAliases{tracks: []lidarr.Track{{Title: "Opening"}}}
+	p := newTestProcessor(t, lidarrClient, slskdClient)
+	p.current = &RunSummary{}
+
+	album := lidarr.Album{
+		ID:       1,
+		Title:    "Album",
+		Artist:   lidarr.Artist{ArtistName: "The Band"},
+		Releases: []lidarr.Release{{Status: "Official", TrackCount: 1}},
+	}
+
+	if _, outcome := p.queueAlbum(context.Background(), album); outcome != OutcomeQueued {
+		t.Fatalf("expected album to be queued, got %q", outcome)
+	}
+	if want := []string{"Album", "The Band Album"}; !reflect.DeepEqual(slskdClient.queries, want) {
+		t.Errorf("expected searches %v, got %v", want, slskdClient.queries)
+	}
+	if got := p.current.Decisions[0].Query; got != "The Band Album" {
+		t.Errorf("expected the matching query to be recorded, got %q", got)
+	}
+}
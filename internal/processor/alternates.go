@@ -0,0 +1,75 @@
+package processor
+
+import (
+	"context"
+	"slices"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// maxAlternateReleases bounds how many other releases' track lists are matched
+// against search results once the chosen release's track list matched nothing
+const maxAlternateReleases = 2
+
+// alternateReleases returns other official releases of an album to match against,
+// preferring those that pass the release: constraints. Releases with the same
+// track count as the chosen one are skipped, as are repeated track counts
+func (p *Processor) alternateReleases(ctx context.Context, album lidarr.Album, chosen *lidarr.Release) []lidarr.Release {
+	releases := album.Releases
+	if len(releases) == 0 {
+		fullAlbum, err := p.lidarrAlbum(ctx, album.ID)
+		if err != nil {
+			p.logger.Debug("failed to fetch releases for alternate track lists", "album", album.Title, "error", err)
+			return nil
+		}
+		releases = fullAlbum.Releases
+	}
+
+	ordered := slices.Concat(p.filterReleases(album, releases), releases)
+	seen := map[int]bool{chosen.TrackCount: true}
+	var alternates []lidarr.Release
+	for _, release := range ordered {
+		if len(alternates) == maxAlternateReleases {
+			break
+		}
+		if release.Status != "Official" || release.ID == chosen.ID || seen[release.TrackCount] {
+			continue
+		}
+		seen[release.TrackCount] = true
+		alternates = append(alternates, release)
+	}
+	return alternates
+}
+
+// matchAlternateReleases matches search results against the track lists of
+// other releases of an album, for shares holding a different edition
+func (p *Processor) matchAlternateReleases(ctx context.Context, results []slskd.SearchResult, album lidarr.Album, chosen *lidarr.Release) []albumCandidate {
+	var candidates []albumCandidate
+	for _, release := range p.alternateReleases(ctx, album, chosen) {
+		tracks, err := p.lidarrTracks(ctx, album.ID, release.ID)
+		if err != nil {
+			p.logger.Debug("failed to fetch alternate release tracks",
+				"album", album.Title,
+				"releaseID", release.ID,
+				"error", err)
+			continue
+		}
+		if len(tracks) == 0 {
+			continue
+		}
+
+		matched := p.matchCandidates(ctx, results, tracks, album, &release)
+		if len(matched) > 0 {
+			p.logger.Info("matched an alternate release",
+				"album", album.Title,
+				"releaseID", release.ID,
+				"format", release.Format,
+				"tracks", len(tracks),
+				"chosenTracks", chosen.TrackCount,
+				"directories", len(matched))
+		}
+		candidates = append(candidates, matched...)
+	}
+	return candidates
+}
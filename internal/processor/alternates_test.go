@@ -0,0 +1,98 @@
+package processor
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// mockLidarrClientWithReleases serves a track list per release and counts the requests
+// Requests without a release get the default release's tracks
+type mockLidarrClientWithReleases struct {
+	mockLidarrClient
+	defaultTracks []lidarr.Track
+	releaseTracks map[int][]lidarr.Track
+	requests      map[int]int
+}
+
+func (m *mockLidarrClientWithReleases) GetTracks(ctx context.Context, albumID int, releaseID *int) ([]lidarr.Track, error) {
+	if releaseID == nil {
+		return m.defaultTracks, nil
+	}
+	m.requests[*releaseID]++
+	return m.releaseTracks[*releaseID], nil
+}
+
+func TestSearchForAlbum_MatchesAlternateRelease(t *testing.T) {
+	album, standard := candidateAlbum()
+	deluxe := append(standard, lidarr.Track{Title: "Bonus Track", MediumNumber: 1})
+	album.Releases = []lidarr.Release{
+		{ID: 1, Status: "Official", TrackCount: 3, MediumCount: 1},
+		{ID: 2, Status: "Official", TrackCount: 3, MediumCount: 1},
+		{ID: 3, Status: "Bootleg", TrackCount: 2, MediumCount: 1},
+		{ID: 4, Status: "Official", TrackCount: 2, MediumCount: 1},
+		{ID: 5, Status: "Official", TrackCount: 4, MediumCount: 1},
+		{ID: 6, Status: "Official", TrackCount: 5, MediumCount: 1},
+	}
+
+	lidarrClient := &mockLidarrClientWithReleases{
+		releaseTracks: map[int][]lidarr.Track{4: standard, 5: nil},
+		requests:      make(map[int]int),
+	}
+	slskdClient := &mockSlskdClientWithResults{results: map[string][]slskd.SearchResult{
+		"Album": {albumResult("user", "flac", 900, 30_000_000)},
+	}}
+	p := newTestProcessor(t, lidarrClient, slskdClient)
+
+	// The share holds the two-track edition, not the chosen deluxe release
+	chosen := &album.Releases[0]
+	for range 2 {
+		item, err := p.searchForAlbum(context.Background(), "Album", deluxe, album, chosen)
+		if err != nil {
+			t.Fatalf("searchForAlbum() error: %v", err)
+		}
+		if item.ReleaseID != 4 {
+			t.Errorf("expected the item to record release 4, got %d", item.ReleaseID)
+		}
+		if len(item.Tracks) != 2 {
+			t.Errorf("expected 2 tracks, got %d", len(item.Tracks))
+		}
+		p.resetQueuedDirs()
+	}
+
+	// Only two fallback releases are fetched, each once per run
+	if lidarrClient.requests[4] != 1 || lidarrClient.requests[5] != 1 || len(lidarrClient.requests) != 2 {
+		t.Errorf("unexpected track list requests: %v", lidarrClient.requests)
+	}
+}
+
+func TestQueueAlbum_FetchesChosenReleaseTracks(t *testing.T) {
+	album, standard := candidateAlbum()
+	deluxe := append(slices.Clone(standard), lidarr.Track{Title: "Bonus Track", MediumNumber: 1})
+	album.Releases = []lidarr.Release{{ID: 7, Status: "Official", TrackCount: 2, MediumCount: 1}}
+
+	// Lidarr's default release is the deluxe edition, the chosen one is not
+	lidarrClient := &mockLidarrClientWithReleases{
+		defaultTracks: deluxe,
+		releaseTracks: map[int][]lidarr.Track{7: standard},
+		requests:      make(map[int]int),
+	}
+	slskdClient := &mockSlskdClientWithResults{results: map[string][]slskd.SearchResult{
+		"Album": {albumResult("user", "flac", 900, 30_000_000)},
+	}}
+	p := newTestProcessor(t, lidarrClient, slskdClient)
+
+	item, outcome := p.queueAlbum(context.Background(), album)
+	if outcome != OutcomeQueued {
+		t.Fatalf("expected outcome %q, got %q", OutcomeQueued, outcome)
+	}
+	if item.ReleaseID != 7 || len(item.Tracks) != 2 {
+		t.Errorf("expected the 2 tracks of release 7, got %d tracks of release %d", len(item.Tracks), item.ReleaseID)
+	}
+	if lidarrClient.requests[7] != 1 {
+		t.Errorf("expected the chosen release's tracks to be fetched once, got %v", lidarrClient.requests)
+	}
+}
@@ -0,0 +1,140 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// errDeclined is returned when a match is turned down at the approval prompt
+// It is not a failed search, so the album is not denylisted
+var errDeclined = errors.New("download declined")
+
+// Approval is the answer to an approval prompt
+type Approval int
+
+const (
+	Approve    Approval = iota
+	Decline             // Skip this album
+	DeclineAll          // Skip this album and every album after it
+)
+
+// Match describes a download waiting for approval
+type Match struct {
+	Artist    string
+	Album     string
+	Tracks    int // Tracks on the release the files were matched against
+	Files     []MatchFile
+	TotalSize int64
+	Ratio     float64 // Filename match ratio, averaged over the tracks for track searches
+}
+
+// MatchFile is one file of a match
+type MatchFile struct {
+	Username  string
+	Directory string
+	Filename  string // Without the directory
+	Size      int64
+	Quality   string // e.g. "flac 24/96" or "mp3 320"
+}
+
+// Approver decides whether a match may be enqueued
+type Approver interface {
+	Approve(ctx context.Context, match Match) (Approval, error)
+}
+
+// SetApprover makes the processor ask approver before enqueueing each match
+// Dry runs never ask, since nothing is enqueued
+func (p *Processor) SetApprover(approver Approver) {
+	p.approver = approver
+}
+
+// approve asks the approver whether a match may be enqueued, returning
+// errDeclined when it may not. The prompt waits under the run's context with
+// the album's timer stopped, so a slow answer doesn't time the album out
+func (p *Processor) approve(ctx context.Context, match Match) error {
+	if p.approver == nil || p.cfg.DryRun {
+		return nil
+	}
+	if p.declinedAll.Load() {
+		return errDeclined
+	}
+
+	resume := pauseAlbumTimer(ctx)
+	approval, err := p.approver.Approve(runContextFor(ctx), match)
+	resume()
+	if err != nil {
+		// Nothing is enqueued without an answer
+		p.logger.Warn("failed to ask for approval, declining remaining albums", "album", match.Album, "error", err)
+		approval = DeclineAll
+	}
+	switch approval {
+	case Approve:
+		return nil
+	case DeclineAll:
+		p.declinedAll.Store(true)
+		p.logger.Info("declined all remaining albums", "album", match.Album)
+	default:
+		p.logger.Info("declined download", "album", match.Album, "artist", match.Artist)
+	}
+	return errDeclined
+}
+
+// candidateMatch describes an album candidate for the approval prompt
+func (p *Processor) candidateMatch(ctx context.Context, album lidarr.Album, candidate albumCandidate) Match {
+	match := Match{
+		Artist:    album.Artist.ArtistName,
+		Album:     album.Title,
+		Tracks:    len(candidate.tracks),
+		TotalSize: candidate.totalSize,
+		Ratio:     candidate.ratio,
+	}
+	for _, file := range candidate.files {
+		match.Files = append(match.Files, p.matchFile(ctx, candidate.username, file))
+	}
+	return match
+}
+
+// trackMatch describes the files chosen by a track search for the approval prompt
+func (p *Processor) trackMatch(ctx context.Context, album lidarr.Album, release *lidarr.Release, found []trackCandidate) Match {
+	match := Match{
+		Artist: album.Artist.ArtistName,
+		Album:  album.Title,
+		Tracks: release.TrackCount,
+	}
+	for _, c := range found {
+		match.Files = append(match.Files, p.matchFile(ctx, c.username, c.file))
+		match.TotalSize += c.file.Size
+		match.Ratio += c.ratio / float64(len(found))
+	}
+	return match
+}
+
+// matchFile describes one search result file for the approval prompt
+func (p *Processor) matchFile(ctx context.Context, username string, file slskd.SearchFile) MatchFile {
+	path := strings.ReplaceAll(file.Filename, "\\", "/")
+	return MatchFile{
+		Username:  username,
+		Directory: filepath.Dir(path),
+		Filename:  filepath.Base(path),
+		Size:      file.Size,
+		Quality:   fileQuality(file),
+	}
+}
+
+// fileQuality describes a file's format and the quality slskd reported for it
+func fileQuality(file slskd.SearchFile) string {
+	quality := fileExtension(file.Filename)
+	switch {
+	case file.BitDepth != nil && file.SampleRate != nil:
+		quality += fmt.Sprintf(" %d/%g", *file.BitDepth, float64(*file.SampleRate)/1000)
+	case file.BitRate != nil:
+		quality += fmt.Sprintf(" %d", *file.BitRate)
+	}
+	return quality
+}
@@ -0,0 +1,120 @@
+package processor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// mockApprover answers every prompt the same way and records the matches it saw
+type mockApprover struct {
+	answer  Approval
+	matches []Match
+}
+
+func (m *mockApprover) Approve(ctx context.Context, match Match) (Approval, error) {
+	m.matches = append(m.matches, match)
+	return m.answer, nil
+}
+
+func TestQueueAlbum_Approval(t *testing.T) {
+	tests := []struct {
+		name         string
+		answer       Approval
+		outcome      string
+		enqueued     int
+		nextSearched bool
+	}{
+		{name: "approved", answer: Approve, outcome: OutcomeQueued, enqueued: 2, nextSearched: true},
+		{name: "declined", answer: Decline, outcome: OutcomeSkipped, enqueued: 0, nextSearched: true},
+		{name: "declined all", answer: DeclineAll, outcome: OutcomeSkipped, enqueued: 0, nextSearched: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			album, tracks := candidateAlbum()
+			album.Releases = []lidarr.Release{{Status: "Official", TrackCount: 2, MediumCount: 1}}
+			next := album
+			next.ID = 10
+
+			lidarrClient := &mockLidarrClientDryRun{tracks: tracks}
+			slskdClient := &mockSlskdClientWithResults{results: map[string][]slskd.SearchResult{
+				"Album": {albumResult("user", "flac", 900, 30_000_000)},
+			}}
+			p := newTestProcessor(t, lidarrClient, slskdClient)
+			p.current = &RunSummary{}
+			approver := &mockApprover{answer: tt.answer}
+			p.SetApprover(approver)
+
+			_, outcome := p.queueAlbum(context.Background(), album)
+			if outcome != tt.outcome {
+				t.Fatalf("expected outcome %q, got %q", tt.outcome, outcome)
+			}
+			if got := len(slskdClient.enqueued["user"]); got != tt.enqueued {
+				t.Errorf("expected %d files enqueued, got %d", tt.enqueued, got)
+			}
+			if tt.outcome == OutcomeSkipped {
+				if entry := p.denylist.GetEntry(album.ID); entry != nil && entry.Failures > 0 {
+					t.Errorf("expected no denylist failure for a declined album, got %d", entry.Failures)
+				}
+				if reason := p.current.Decisions[0].Reason; reason != ReasonDeclined {
+					t.Errorf("expected reason %q, got %q", ReasonDeclined, reason)
+				}
+			}
+
+			match := approver.matches[0]
+			if match.Album != "Album" || len(match.Files) != 2 || match.TotalSize != 60_000_000 {
+				t.Errorf("unexpected match: %+v", match)
+			}
+			if match.Files[0].Username != "user" || match.Files[0].Quality != "flac 900" {
+				t.Errorf("unexpected match file: %+v", match.Files[0])
+			}
+
+			// The next album matches the same directory, so forget this run's claims
+			p.resetQueuedDirs()
+			p.queueAlbum(context.Background(), next)
+			if searched := len(approver.matches) == 2; searched != tt.nextSearched {
+				t.Errorf("expected next album asked about: %v, got %v", tt.nextSearched, searched)
+			}
+		})
+	}
+}
+
+// slowApprover approves after keeping the prompt open for delay
+type slowApprover struct {
+	delay time.Duration
+}
+
+func (s *slowApprover) Approve(ctx context.Context, match Match) (Approval, error) {
+	select {
+	case <-time.After(s.delay):
+		return Approve, nil
+	case <-ctx.Done():
+		return DeclineAll, ctx.Err()
+	}
+}
+
+func TestQueueAlbum_ApprovalOutsideAlbumTimeout(t *testing.T) {
+	album, tracks := candidateAlbum()
+	album.Releases = []lidarr.Release{{Status: "Official", TrackCount: 2, MediumCount: 1}}
+	slskdClient := &mockSlskdClientWithResults{results: map[string][]slskd.SearchResult{
+		"Album": {albumResult("user", "flac", 900, 30_000_000)},
+	}}
+	p := newTestProcessor(t, &mockLidarrClientDryRun{tracks: tracks}, slskdClient)
+	p.cfg.Timing.PerAlbumTimeoutSeconds = 1
+	p.current = &RunSummary{}
+	p.SetApprover(&slowApprover{delay: 1500 * time.Millisecond})
+
+	if _, outcome := p.queueAlbum(context.Background(), album); outcome != OutcomeQueued {
+		t.Fatalf("expected a slowly approved album to be queued, got %q", outcome)
+	}
+	if got := len(slskdClient.enqueued["user"]); got != 2 {
+		t.Errorf("expected 2 files enqueued, got %d", got)
+	}
+	if entry := p.denylist.GetEntry(album.ID); entry != nil && entry.Failures > 0 {
+		t.Errorf("expected no denylist failure, got %d", entry.Failures)
+	}
+}
@@ -0,0 +1,65 @@
+package processor
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+)
+
+// artistPathDepth is how many of a directory's last folders may name the
+// artist, e.g. "Music/Queen/Greatest Hits/CD1"
+const artistPathDepth = 3
+
+// pathNamesArtist reports whether one of a matched directory's last folders
+// names the album's artist, or one of its aliases, closely enough for
+// strict_artist_match. Compilations and artists without a comparable name pass
+func (p *Processor) pathNamesArtist(album lidarr.Album, dir string) bool {
+	if isVariousArtists(album) {
+		return true
+	}
+
+	var names [][]string
+	for _, name := range append([]string{album.Artist.ArtistName}, album.Artist.Aliases...) {
+		if words := nameWords(p.matcher.Normalize(name)); len(words) > 0 {
+			names = append(names, words)
+		}
+	}
+	if len(names) == 0 {
+		return true
+	}
+
+	folders := strings.Split(strings.Trim(strings.ReplaceAll(dir, "\\", "/"), "/"), "/")
+	for _, folder := range folders[max(len(folders)-artistPathDepth, 0):] {
+		words := nameWords(p.matcher.Normalize(folder))
+		for _, name := range names {
+			if p.containsName(words, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// containsName reports whether a run of words fuzzy-matches a name
+func (p *Processor) containsName(words, name []string) bool {
+	want := strings.Join(name, " ")
+	for i := 0; i+len(name) <= len(words); i++ {
+		if p.matcher.Similarity(strings.Join(words[i:i+len(name)], " "), want) >= p.cfg.Search.MinimumFilenameMatchRatio {
+			return true
+		}
+	}
+	return false
+}
+
+// nameWords splits a normalized name into words, dropping punctuation and a
+// leading "the" so "The Beatles" is found in "Beatles, The - 1"
+func nameWords(s string) []string {
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > 1 && words[0] == "the" {
+		words = words[1:]
+	}
+	return words
+}
@@ -0,0 +1,108 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+func TestPathNamesArtist(t *testing.T) {
+	tests := []struct {
+		name     string
+		artist   lidarr.Artist
+		dir      string
+		expected bool
+	}{
+		{"artist and title in one folder", lidarr.Artist{ArtistName: "Queen"}, "Music/Queen - Greatest Hits", true},
+		{"another artist's share", lidarr.Artist{ArtistName: "Queen"}, "Music/ABBA - Greatest Hits", false},
+		{"artist folder above the album", lidarr.Artist{ArtistName: "Queen"}, `Music\Queen\1981 - Greatest Hits\CD1`, true},
+		{"artist too far up the path", lidarr.Artist{ArtistName: "Queen"}, "Queen/Shares/Compilations/Greatest Hits/CD1", false},
+		{"no artist in the path", lidarr.Artist{ArtistName: "Queen"}, "Music/Greatest Hits", false},
+		{"small spelling difference", lidarr.Artist{ArtistName: "Motörhead"}, "Music/Motorhead - Ace of Spades", true},
+		{"leading the", lidarr.Artist{ArtistName: "The Beatles"}, "Music/Beatles, The - 1", true},
+		{"alias", lidarr.Artist{ArtistName: "Prince", Aliases: []string{"The Artist Formerly Known as Prince"}}, "Music/TAFKAP/Artist Formerly Known As Prince - Emancipation", true},
+		{"name within a word", lidarr.Artist{ArtistName: "Abba"}, "Music/Abbatoir - Live", false},
+		{"various artists", lidarr.Artist{ArtistName: "Various Artists"}, "Music/Greatest Hits", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestProcessor(t, &mockLidarrClient{}, &mockSlskdClient{})
+			album := lidarr.Album{Title: "Greatest Hits", Artist: tt.artist}
+
+			if got := p.pathNamesArtist(album, tt.dir); got != tt.expected {
+				t.Errorf("pathNamesArtist(%q) = %v, expected %v", tt.dir, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSearchForAlbum_StrictArtistMatch(t *testing.T) {
+	album := lidarr.Album{ID: 9, Title: "Greatest Hits", Monitored: true, Artist: lidarr.Artist{ArtistName: "Queen"}}
+	tracks := []lidarr.Track{
+		{Title: "Bohemian Rhapsody", MediumNumber: 1},
+		{Title: "Another One Bites the Dust", MediumNumber: 1},
+	}
+
+	// A better-ranked share of another artist's album with the same track titles
+	share := func(username, dir string, bitRate int) slskd.SearchResult {
+		return slskd.SearchResult{
+			Username: username,
+			Files: []slskd.SearchFile{
+				{Filename: dir + `\01 - Bohemian Rhapsody.flac`, Size: 30_000_000, BitRate: intPtr(bitRate)},
+				{Filename: dir + `\02 - Another One Bites the Dust.flac`, Size: 30_000_000, BitRate: intPtr(bitRate)},
+			},
+		}
+	}
+	results := []slskd.SearchResult{
+		share("abba", `Music\ABBA - Greatest Hits`, 1000),
+		share("queen", `Music\Queen - Greatest Hits`, 900),
+	}
+
+	tests := []struct {
+		strict   bool
+		expected string
+	}{
+		{false, "abba"},
+		{true, "queen"},
+	}
+
+	for _, tt := range tests {
+		slskdClient := &mockSlskdClientWithResults{results: map[string][]slskd.SearchResult{"Greatest Hits": results}}
+		p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+		p.cfg.Search.StrictArtistMatch = tt.strict
+
+		item, err := p.searchForAlbum(context.Background(), "Greatest Hits", tracks, album, &lidarr.Release{MediumCount: 1})
+		if err != nil {
+			t.Fatalf("strict=%v: searchForAlbum() error: %v", tt.strict, err)
+		}
+		if len(item.Sources) != 1 || item.Sources[0].Username != tt.expected {
+			t.Errorf("strict=%v: expected download from %q, got %+v", tt.strict, tt.expected, item.Sources)
+		}
+	}
+}
+
+func TestAlbumLogger(t *testing.T) {
+	var buf bytes.Buffer
+	p := &Processor{logger: slog.New(slog.NewTextHandler(&buf, nil))}
+	album := lidarr.Album{Title: "Greatest Hits", ForeignAlbumID: "rg-1"}
+
+	p.albumLogger(album, nil).Info("before release")
+	p.albumLogger(album, &lidarr.Release{ForeignReleaseID: "rel-1"}).Info("after release")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", buf.String())
+	}
+	if !strings.Contains(lines[0], "releaseGroupMBID=rg-1") || strings.Contains(lines[0], "releaseMBID") {
+		t.Errorf("unexpected line before release: %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "releaseGroupMBID=rg-1") || !strings.Contains(lines[1], "releaseMBID=rel-1") {
+		t.Errorf("unexpected line after release: %q", lines[1])
+	}
+}
@@ -0,0 +1,39 @@
+package processor
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+)
+
+// artistFolder returns the name of the artist's folder in Lidarr with
+// use_artist_path, such as "Beatles, The", so albums are organized where
+// Lidarr's importer expects them. It is empty otherwise, or when Lidarr
+// doesn't report a path, and the folder is named after the artist
+func (p *Processor) artistFolder(ctx context.Context, album lidarr.Album) string {
+	if !p.cfg.Lidarr.UseArtistPath {
+		return ""
+	}
+
+	artistPath := album.Artist.Path
+	if artistPath == "" {
+		artist, err := p.lidarr.GetArtist(ctx, album.ArtistID)
+		if err != nil {
+			p.logger.Warn("failed to fetch artist path, naming folder after the artist",
+				"album", album.Title,
+				"artist", album.Artist.ArtistName,
+				"error", err)
+			return ""
+		}
+		artistPath = artist.Path
+	}
+
+	// Lidarr may run on Windows
+	artistPath = strings.TrimRight(strings.ReplaceAll(artistPath, "\\", "/"), "/")
+	if artistPath == "" {
+		return ""
+	}
+	return path.Base(artistPath)
+}
@@ -0,0 +1,74 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+)
+
+// mockLidarrClientWithArtist returns an artist at a fixed path
+type mockLidarrClientWithArtist struct {
+	mockLidarrClient
+	path    string
+	err     error
+	fetched int
+}
+
+func (m *mockLidarrClientWithArtist) GetArtist(ctx context.Context, id int) (*lidarr.Artist, error) {
+	m.fetched++
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &lidarr.Artist{ID: id, Path: m.path}, nil
+}
+
+func TestArtistFolder(t *testing.T) {
+	tests := []struct {
+		name        string
+		enabled     bool
+		albumPath   string // Artist path included with the wanted album
+		artistPath  string // Artist path from GetArtist
+		err         error
+		want        string
+		wantFetched int
+	}{
+		{name: "disabled", enabled: false, artistPath: "/music/Beatles, The", want: ""},
+		{name: "path included with the album", enabled: true, albumPath: "/music/Beatles, The", want: "Beatles, The"},
+		{name: "path fetched", enabled: true, artistPath: "/music/Genesis (band)/", want: "Genesis (band)", wantFetched: 1},
+		{name: "windows path", enabled: true, artistPath: `D:\Music\Beatles, The`, want: "Beatles, The", wantFetched: 1},
+		{name: "no path", enabled: true, want: "", wantFetched: 1},
+		{name: "fetch fails", enabled: true, err: errors.New("boom"), want: "", wantFetched: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lidarrClient := &mockLidarrClientWithArtist{path: tt.artistPath, err: tt.err}
+			p := newTestProcessor(t, lidarrClient, &mockSlskdClient{})
+			p.cfg.Lidarr.UseArtistPath = tt.enabled
+
+			album := lidarr.Album{ID: 1, Title: "Abbey Road", ArtistID: 7, Artist: lidarr.Artist{ArtistName: "The Beatles", Path: tt.albumPath}}
+			if got := p.artistFolder(context.Background(), album); got != tt.want {
+				t.Errorf("artistFolder() = %q, want %q", got, tt.want)
+			}
+			if lidarrClient.fetched != tt.wantFetched {
+				t.Errorf("expected %d artist fetches, got %d", tt.wantFetched, lidarrClient.fetched)
+			}
+		})
+	}
+}
+
+func TestAlbumDir_ArtistFolder(t *testing.T) {
+	p := newTestProcessor(t, &mockLidarrClient{}, &mockSlskdClient{})
+
+	named := p.albumDir(DownloadedItem{ArtistName: "The Beatles", AlbumName: "Abbey Road"})
+	inArtistFolder := p.albumDir(DownloadedItem{ArtistName: "The Beatles", ArtistFolder: "Beatles, The", AlbumName: "Abbey Road"})
+
+	if named != p.organizer.AlbumDir("The Beatles", "Abbey Road") {
+		t.Errorf("unexpected album folder without an artist folder: %s", named)
+	}
+	if inArtistFolder != p.organizer.AlbumDir("Beatles, The", "Abbey Road") {
+		t.Errorf("album folder should use the artist folder, got %s", inArtistFolder)
+	}
+}
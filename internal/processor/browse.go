@@ -0,0 +1,140 @@
+package processor
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// browseMatchRatio is the share of an album's tracks a directory's search hits
+// must match before the rest of the directory is browsed
+const browseMatchRatio = 0.5
+
+// completePartialDirectories replaces the files of directories that match part
+// of an album with their full listing, since search responses only hold the
+// files whose names matched the query
+func (p *Processor) completePartialDirectories(ctx context.Context, album lidarr.Album, expectedTracks []string, username string, files []slskd.SearchFile) []slskd.SearchFile {
+	var dirs []string
+	byDir := make(map[string][]slskd.SearchFile)
+	for _, file := range files {
+		dir := remoteDirectory(file.Filename)
+		if _, ok := byDir[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+		byDir[dir] = append(byDir[dir], file)
+	}
+
+	m := p.matcherFor(album)
+	albumFilter := p.filterFor(ctx)
+	completed := make([]slskd.SearchFile, 0, len(files))
+	for _, dir := range dirs {
+		dirFiles := byDir[dir]
+		if len(dirFiles) >= len(expectedTracks) || dir == "" {
+			completed = append(completed, dirFiles...)
+			continue
+		}
+
+		names := make([]string, len(dirFiles))
+		for i, file := range dirFiles {
+			names[i] = filepath.Base(strings.ReplaceAll(file.Filename, "\\", "/"))
+		}
+		matched := m.MatchedTracks(expectedTracks, names)
+		if matched == 0 || float64(matched) < browseMatchRatio*float64(len(expectedTracks)) {
+			completed = append(completed, dirFiles...)
+			continue
+		}
+
+		listing, ok := p.browseDirectory(ctx, username, dir)
+		browsed := p.dropImplausibleFiles(album, username, albumFilter.FilterFiles(listing))
+		if !ok || len(browsed) <= len(dirFiles) {
+			completed = append(completed, dirFiles...)
+			continue
+		}
+
+		p.logger.Debug("browsed directory for files the search left out",
+			"album", album.Title,
+			"username", username,
+			"directory", dir,
+			"matchedTracks", matched,
+			"searchFiles", len(dirFiles),
+			"files", len(browsed))
+		completed = append(completed, browsed...)
+	}
+	return completed
+}
+
+// browseDirectory lists a user's directory as search files, browsing each
+// directory once per run. It reports false if the directory couldn't be browsed
+func (p *Processor) browseDirectory(ctx context.Context, username, dir string) ([]slskd.SearchFile, bool) {
+	key := username + "\x00" + dir
+	p.browsedMu.Lock()
+	files, ok := p.browsed[key]
+	p.browsedMu.Unlock()
+	if ok {
+		return files, files != nil
+	}
+
+	directory, err := p.slskd.GetDirectory(ctx, username, dir)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, false // Not cached, the directory may be browsed next run
+		}
+		p.logger.Debug("failed to browse directory", "username", username, "directory", dir, "error", err)
+	} else {
+		files = directoryFiles(dir, directory.Files)
+	}
+
+	// A failed browse is cached too, so an offline user isn't asked again
+	p.browsedMu.Lock()
+	defer p.browsedMu.Unlock()
+	if p.browsed == nil {
+		p.browsed = make(map[string][]slskd.SearchFile)
+	}
+	p.browsed[key] = files
+	return files, files != nil
+}
+
+// resetBrowsed makes the next run browse directories again
+func (p *Processor) resetBrowsed() {
+	p.browsedMu.Lock()
+	defer p.browsedMu.Unlock()
+	p.browsed = nil
+}
+
+// directoryFiles converts a directory listing into search files with the full
+// remote path, as search results name them
+func directoryFiles(dir string, listing []slskd.DirectoryFile) []slskd.SearchFile {
+	separator := "\\"
+	if !strings.Contains(dir, "\\") && strings.Contains(dir, "/") {
+		separator = "/"
+	}
+
+	files := make([]slskd.SearchFile, 0, len(listing))
+	for _, file := range listing {
+		filename := file.Filename
+		if !strings.ContainsAny(filename, `\/`) {
+			filename = dir + separator + filename
+		}
+		files = append(files, slskd.SearchFile{
+			Filename:   filename,
+			Size:       file.Size,
+			BitRate:    file.BitRate,
+			SampleRate: file.SampleRate,
+			BitDepth:   file.BitDepth,
+			Length:     file.Length,
+		})
+	}
+	return files
+}
+
+// remoteDirectory returns the directory part of a remote filename as slskd
+// reports it
+func remoteDirectory(filename string) string {
+	if i := strings.LastIndexAny(filename, `\/`); i >= 0 {
+		return filename[:i]
+	}
+	return ""
+}
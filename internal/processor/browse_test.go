@@ -0,0 +1,159 @@
+package processor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/config"
+	"github.com/yuritomanek/seekarr/internal/filter"
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// mockSlskdClientBrowsing lists a directory holding the whole album
+type mockSlskdClientBrowsing struct {
+	mockSlskdClient
+	browsed []string
+}
+
+func (m *mockSlskdClientBrowsing) GetDirectory(ctx context.Context, username, directory string) (*slskd.Directory, error) {
+	m.browsed = append(m.browsed, username+":"+directory)
+	return &slskd.Directory{Name: directory, Files: []slskd.DirectoryFile{
+		{Filename: "01 - Intro.flac", Size: 20_000_000},
+		{Filename: "02 - Sunrise.flac", Size: 20_000_000},
+		{Filename: "03 - Long Road Home.flac", Size: 20_000_000},
+		{Filename: "04 - Outro.flac", Size: 20_000_000},
+		{Filename: "cover.jpg", Size: 100_000},
+	}}, nil
+}
+
+func TestMatchCandidates_BrowsesPartialDirectories(t *testing.T) {
+	album := lidarr.Album{ID: 9, Title: "Album", Artist: lidarr.Artist{ArtistName: "Artist"}}
+	tracks := []lidarr.Track{{Title: "Intro"}, {Title: "Sunrise"}, {Title: "Long Road Home"}, {Title: "Outro"}}
+
+	tests := []struct {
+		name           string
+		files          []string
+		wantBrowsed    int
+		wantCandidates int
+	}{
+		{
+			name:           "half the tracks matched",
+			files:          []string{`Music\Album\01 - Intro.flac`, `Music\Album\03 - Long Road Home.flac`},
+			wantBrowsed:    1,
+			wantCandidates: 1,
+		},
+		{
+			name:           "too few tracks matched",
+			files:          []string{`Music\Album\01 - Intro.flac`},
+			wantBrowsed:    0,
+			wantCandidates: 0,
+		},
+		{
+			name:           "unrelated directory",
+			files:          []string{`Music\Other\01 - Something.flac`, `Music\Other\02 - Else.flac`},
+			wantBrowsed:    0,
+			wantCandidates: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slskdClient := &mockSlskdClientBrowsing{}
+			p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+			p.filter = filter.NewFilter([]string{"flac"})
+
+			result := slskd.SearchResult{Username: "user"}
+			for _, name := range tt.files {
+				result.Files = append(result.Files, slskd.SearchFile{Filename: name, Size: 20_000_000})
+			}
+
+			// Matching again, e.g. for an alternate release, reuses the listing
+			for range 2 {
+				candidates := p.matchCandidates(context.Background(), []slskd.SearchResult{result}, tracks, album, &lidarr.Release{MediumCount: 1})
+				if len(candidates) != tt.wantCandidates {
+					t.Fatalf("expected %d candidates, got %d", tt.wantCandidates, len(candidates))
+				}
+				if len(candidates) == 1 && len(candidates[0].files) != len(tracks) {
+					t.Errorf("expected the browsed directory's %d tracks, got %d files", len(tracks), len(candidates[0].files))
+				}
+			}
+
+			if len(slskdClient.browsed) != tt.wantBrowsed {
+				t.Errorf("expected %d directory browses, got %v", tt.wantBrowsed, slskdClient.browsed)
+			}
+			if tt.wantBrowsed > 0 && slskdClient.browsed[0] != `user:Music\Album` {
+				t.Errorf("unexpected directory browsed: %s", slskdClient.browsed[0])
+			}
+		})
+	}
+}
+
+func TestDirectoryFiles(t *testing.T) {
+	files := directoryFiles(`Music\Album`, []slskd.DirectoryFile{
+		{Filename: "01 - Intro.flac", Size: 10, Length: intPtr(61)},
+		{Filename: `Music\Album\02 - Sunrise.flac`, Size: 20},
+	})
+
+	want := []string{`Music\Album\01 - Intro.flac`, `Music\Album\02 - Sunrise.flac`}
+	for i, file := range files {
+		if file.Filename != want[i] {
+			t.Errorf("file %d: expected %q, got %q", i, want[i], file.Filename)
+		}
+	}
+	if files[0].Length == nil || *files[0].Length != 61 {
+		t.Errorf("expected the length to be kept, got %v", files[0].Length)
+	}
+}
+
+func TestMatchCandidates_MinimumResponseFileCount(t *testing.T) {
+	album := lidarr.Album{ID: 9, Title: "Album", Artist: lidarr.Artist{ArtistName: "Artist"}}
+	tracks := []lidarr.Track{{Title: "Intro"}, {Title: "Sunrise"}, {Title: "Long Road Home"}, {Title: "Outro"}}
+	partial := slskd.SearchResult{Username: "user", Files: []slskd.SearchFile{
+		{Filename: `Music\Album\01 - Intro.flac`, Size: 20_000_000},
+		{Filename: `Music\Album\03 - Long Road Home.flac`, Size: 20_000_000},
+		{Filename: `Music\Album\cover.jpg`, Size: 100_000},
+	}}
+
+	tests := []struct {
+		name        string
+		minimum     int
+		wantBrowsed int
+	}{
+		{"no minimum", 0, 1},
+		{"enough files", 2, 1},
+		{"too few audio files", 3, 0},
+		{"capped at the track count", 10, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slskdClient := &mockSlskdClientBrowsing{}
+			p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+			p.filter = filter.NewFilter([]string{"flac"})
+			p.cfg.Search.MinimumResponseFileCount = tt.minimum
+
+			p.matchCandidates(context.Background(), []slskd.SearchResult{partial}, tracks, album, &lidarr.Release{MediumCount: 1})
+			if len(slskdClient.browsed) != tt.wantBrowsed {
+				t.Errorf("expected %d browses, got %d", tt.wantBrowsed, len(slskdClient.browsed))
+			}
+		})
+	}
+}
+
+func TestMinimumResponseFiles(t *testing.T) {
+	p := &Processor{cfg: &config.Config{}}
+	tests := []struct {
+		minimum, tracks, want int
+	}{
+		{0, 12, 0},
+		{5, 12, 5},
+		{20, 12, 12},
+	}
+	for _, tt := range tests {
+		p.cfg.Search.MinimumResponseFileCount = tt.minimum
+		if got := p.minimumResponseFiles(tt.tracks); got != tt.want {
+			t.Errorf("minimumResponseFiles(%d) with minimum %d = %d, want %d", tt.tracks, tt.minimum, got, tt.want)
+		}
+	}
+}
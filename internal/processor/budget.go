@@ -0,0 +1,74 @@
+package processor
+
+import "context"
+
+// claimBudget claims one of the run's album slots before the album is searched,
+// so concurrent workers can't queue past max_albums_per_run. When no slot is
+// left it reports which per-run download budget has been used up. Bytes are
+// those enqueued so far in the run, the same count the free space check uses
+// While other albums still hold claims it waits for them to be queued or
+// released, as a failed search gives its slot back
+func (p *Processor) claimBudget(ctx context.Context) (string, bool) {
+	for {
+		p.spaceMu.Lock()
+		limit := p.budgetLimit()
+		if limit == "" {
+			p.queuedAlbums++
+			p.budgetClaims++
+			p.spaceMu.Unlock()
+			return "", true
+		}
+		if p.budgetClaims == 0 {
+			p.spaceMu.Unlock()
+			return limit, false
+		}
+		if p.budgetResolved == nil {
+			p.budgetResolved = make(chan struct{})
+		}
+		resolved := p.budgetResolved
+		p.spaceMu.Unlock()
+
+		select {
+		case <-resolved:
+		case <-ctx.Done():
+			return limit, false
+		}
+	}
+}
+
+// budgetLimit returns the per-run budget that has been used up, if any
+// The caller must hold spaceMu
+func (p *Processor) budgetLimit() string {
+	if limit := p.cfg.Download.MaxAlbumsPerRun; limit > 0 && p.queuedAlbums >= limit {
+		return "max_albums_per_run"
+	}
+	if limit := p.cfg.Download.MaxTotalBytesPerRun; limit > 0 && p.reservedBytes >= limit {
+		return "max_total_bytes_per_run"
+	}
+	return ""
+}
+
+// confirmBudget keeps the slot claimed for an album that was queued
+func (p *Processor) confirmBudget() {
+	p.spaceMu.Lock()
+	defer p.spaceMu.Unlock()
+	p.resolveClaim()
+}
+
+// releaseBudget gives back a slot claimed for an album that wasn't queued
+func (p *Processor) releaseBudget() {
+	p.spaceMu.Lock()
+	defer p.spaceMu.Unlock()
+	p.queuedAlbums--
+	p.resolveClaim()
+}
+
+// resolveClaim wakes the workers waiting on outstanding claims
+// The caller must hold spaceMu
+func (p *Processor) resolveClaim() {
+	p.budgetClaims--
+	if p.budgetResolved != nil {
+		close(p.budgetResolved)
+		p.budgetResolved = nil
+	}
+}
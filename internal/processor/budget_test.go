@@ -0,0 +1,120 @@
+package processor
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+func TestSearchAndQueueDownloads_RunBudget(t *testing.T) {
+	tests := []struct {
+		name       string
+		workers    int
+		maxAlbums  int
+		maxBytes   int64
+		wantQueued int
+	}{
+		{name: "no limits", wantQueued: 4},
+		{name: "album limit", maxAlbums: 2, wantQueued: 2},
+		{name: "album limit with concurrent workers", workers: 4, maxAlbums: 2, wantQueued: 2},
+		{name: "byte limit reached", maxBytes: 400, wantQueued: 2},
+		{name: "album crossing byte limit still queued", maxBytes: 250, wantQueued: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracks := []lidarr.Track{{Title: "First Song"}, {Title: "Second Song"}}
+			p := newTestProcessor(t, &mockLidarrClientWithAliases{tracks: tracks}, &mockSlskdClientConcurrent{})
+			p.cfg.Search.ConcurrentSearches = max(tt.workers, 1)
+			p.cfg.Download.MaxAlbumsPerRun = tt.maxAlbums
+			p.cfg.Download.MaxTotalBytesPerRun = tt.maxBytes
+			p.current = &RunSummary{}
+
+			albums := concurrentAlbums(4)
+			downloadList, failed := p.searchAndQueueDownloads(context.Background(), albums, nil)
+
+			if failed != 0 {
+				t.Errorf("expected no failures, got %d", failed)
+			}
+			if len(downloadList) != tt.wantQueued {
+				t.Fatalf("expected %d queued, got %d", tt.wantQueued, len(downloadList))
+			}
+			if got := p.current.count(OutcomeDeferred); got != len(albums)-tt.wantQueued {
+				t.Errorf("expected %d deferred, got %d", len(albums)-tt.wantQueued, got)
+			}
+			for _, album := range albums[tt.wantQueued:] {
+				if entry := p.denylist.GetEntry(album.ID); entry != nil {
+					t.Errorf("album %d deferred by budget should not be denylisted", album.ID)
+				}
+			}
+		})
+	}
+}
+
+// mockSlskdClientFailingFirst finds nothing for the first albums searched,
+// whatever query they are searched with
+type mockSlskdClientFailingFirst struct {
+	mockSlskdClientConcurrent
+	fail   int
+	failed map[string]bool // By album title, the last word of each query
+}
+
+func (m *mockSlskdClientFailingFirst) Search(ctx context.Context, req slskd.SearchRequest) (*slskd.SearchResponse, error) {
+	title := albumTitleOf(req.SearchText)
+	m.mu.Lock()
+	if len(m.failed) < m.fail {
+		m.failed[title] = true
+	}
+	m.mu.Unlock()
+	return m.mockSlskdClientConcurrent.Search(ctx, req)
+}
+
+func (m *mockSlskdClientFailingFirst) GetSearchResults(ctx context.Context, searchID string) ([]slskd.SearchResult, error) {
+	m.mu.Lock()
+	failed := m.failed[albumTitleOf(searchID)]
+	m.mu.Unlock()
+	if failed {
+		return nil, nil
+	}
+	return m.mockSlskdClientConcurrent.GetSearchResults(ctx, searchID)
+}
+
+func (m *mockSlskdClientFailingFirst) StreamSearchResults(ctx context.Context, searchID string, fn func(slskd.SearchResult) bool) error {
+	results, err := m.GetSearchResults(ctx, searchID)
+	return yieldResults(results, err, fn)
+}
+
+func albumTitleOf(query string) string {
+	fields := strings.Fields(query)
+	return fields[len(fields)-1]
+}
+
+func TestSearchAndQueueDownloads_RunBudgetWaitsForInFlightClaims(t *testing.T) {
+	// Both slots are claimed by searches that fail while idle workers look for work
+	tracks := []lidarr.Track{{Title: "First Song"}, {Title: "Second Song"}}
+	slskdClient := &mockSlskdClientFailingFirst{
+		mockSlskdClientConcurrent: mockSlskdClientConcurrent{delay: 20 * time.Millisecond},
+		fail:                      2,
+		failed:                    make(map[string]bool),
+	}
+	p := newTestProcessor(t, &mockLidarrClientWithAliases{tracks: tracks}, slskdClient)
+	p.cfg.Search.ConcurrentSearches = 4
+	p.cfg.Download.MaxAlbumsPerRun = 2
+	p.current = &RunSummary{}
+
+	downloadList, failed := p.searchAndQueueDownloads(context.Background(), concurrentAlbums(4), nil)
+
+	if failed != 2 {
+		t.Errorf("expected 2 failed searches, got %d", failed)
+	}
+	if len(downloadList) != 2 {
+		t.Errorf("expected the freed slots used by the remaining albums, got %d queued", len(downloadList))
+	}
+	if got := p.current.count(OutcomeDeferred); got != 0 {
+		t.Errorf("expected nothing deferred, got %d", got)
+	}
+}
@@ -0,0 +1,196 @@
+package processor
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/yuritomanek/seekarr/internal/filter"
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/matcher"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// Weights for the parts of a candidate's score. Quality dominates so that a
+// lossless directory beats a lossy one that matched slightly better
+const (
+	qualityWeight     = 50.0
+	matchWeight       = 40.0
+	sizeWeight        = 5.0
+	uploadSpeedWeight = 5.0
+	freeSlotBonus     = 5.0
+	queuePenalty      = 5.0
+
+	fastUploadSpeed  = 1024 * 1024 // Bytes per second earning the full upload speed score
+	longQueue        = 50          // Queue length earning the full queue penalty
+	loggedCandidates = 5           // How many top candidates are logged
+)
+
+// albumCandidate is a remote directory that matched every expected track
+type albumCandidate struct {
+	release     *lidarr.Release // Release whose track list the directory matched
+	tracks      []lidarr.Track
+	username    string
+	dir         string         // Normalized to forward slashes
+	discs       map[string]int // Disc number of each disc folder when dir holds several
+	files       []slskd.SearchFile
+	companions  []slskd.SearchFile // Whitelisted non-audio files, never matched against the tracks
+	ratio       float64
+	qualityRank int // Worst allowed_filetypes position among the files, 0 is best
+	quality     string
+	totalSize   int64
+	uploadSpeed int
+	queueLength int
+	freeSlot    bool
+	unreliable  bool // The user's recent downloads keep failing
+	score       float64
+}
+
+// rankCandidates scores candidates and sorts them best first
+// f is the filter that ranked the candidates' files
+func (p *Processor) rankCandidates(candidates []albumCandidate, f *filter.Filter) {
+	var maxSize int64
+	for _, c := range candidates {
+		if c.totalSize > maxSize {
+			maxSize = c.totalSize
+		}
+	}
+
+	allowed := f.AllowedCount()
+	for i := range candidates {
+		c := &candidates[i]
+		c.score = matchWeight * c.ratio
+
+		if allowed > 0 {
+			c.score += qualityWeight * float64(allowed-c.qualityRank) / float64(allowed)
+		}
+		if maxSize > 0 {
+			c.score += sizeWeight * float64(c.totalSize) / float64(maxSize)
+		}
+
+		c.score += uploadSpeedWeight * float64(min(c.uploadSpeed, fastUploadSpeed)) / fastUploadSpeed
+		if c.freeSlot {
+			c.score += freeSlotBonus
+		}
+		c.score -= queuePenalty * float64(min(c.queueLength, longQueue)) / longQueue
+
+		reputation, unreliable := p.userReputation(c.username)
+		c.score += reputation
+		c.unreliable = unreliable
+	}
+
+	// Users whose downloads keep failing go last whatever they offer
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].unreliable != candidates[j].unreliable {
+			return candidates[j].unreliable
+		}
+		return candidates[i].score > candidates[j].score
+	})
+}
+
+// logCandidates logs the best candidates with their scores
+func (p *Processor) logCandidates(album lidarr.Album, candidates []albumCandidate) {
+	for i, c := range candidates {
+		if i == loggedCandidates {
+			break
+		}
+
+		p.logger.Debug("album candidate",
+			"album", album.Title,
+			"rank", i+1,
+			"score", fmt.Sprintf("%.1f", c.score),
+			"username", c.username,
+			"directory", c.dir,
+			"ratio", fmt.Sprintf("%.2f", c.ratio),
+			"quality", c.quality,
+			"bytes", c.totalSize,
+			"uploadSpeed", c.uploadSpeed,
+			"queueLength", c.queueLength,
+			"freeSlot", c.freeSlot,
+			"unreliable", c.unreliable)
+	}
+}
+
+// unlockedFiles drops files a user shares only with peers they chose, since
+// downloads of them never start
+func unlockedFiles(files []slskd.SearchFile) []slskd.SearchFile {
+	unlocked := make([]slskd.SearchFile, 0, len(files))
+	for _, file := range files {
+		if !file.IsLocked {
+			unlocked = append(unlocked, file)
+		}
+	}
+	return unlocked
+}
+
+// minimumResponseFiles returns how many allowed audio files a response needs
+// to be matched against an album with trackCount tracks, 0 for no minimum
+func (p *Processor) minimumResponseFiles(trackCount int) int {
+	return min(p.cfg.Search.MinimumResponseFileCount, trackCount)
+}
+
+// busyReason returns why a result's user is skipped under skip_busy_users
+// Users that didn't report a free slot are only judged by their queue
+func (p *Processor) busyReason(result slskd.SearchResult) (string, bool) {
+	if !p.cfg.Search.SkipBusyUsers {
+		return "", false
+	}
+	if result.HasFreeUploadSlot != nil && !*result.HasFreeUploadSlot {
+		return "no free upload slot", true
+	}
+	if result.QueueLength > p.cfg.Search.MaximumPeerQueue {
+		return "queue too long", true
+	}
+	return "", false
+}
+
+// claimDirectory reserves a remote directory for an album for the rest of the run
+// It returns the album that already claimed the directory when it is taken
+func (p *Processor) claimDirectory(username, dir, album string) (string, bool) {
+	p.queuedMu.Lock()
+	defer p.queuedMu.Unlock()
+
+	key := username + "\x00" + dir
+	if owner, ok := p.queuedDirs[key]; ok {
+		return owner, false
+	}
+	if p.queuedDirs == nil {
+		p.queuedDirs = make(map[string]string)
+	}
+	p.queuedDirs[key] = album
+	return album, true
+}
+
+// releaseDirectory gives up a claim on a directory that could not be enqueued
+func (p *Processor) releaseDirectory(username, dir string) {
+	p.queuedMu.Lock()
+	defer p.queuedMu.Unlock()
+	delete(p.queuedDirs, username+"\x00"+dir)
+}
+
+// resetQueuedDirs forgets the directories queued by an earlier run
+func (p *Processor) resetQueuedDirs() {
+	p.queuedMu.Lock()
+	defer p.queuedMu.Unlock()
+	p.queuedDirs = nil
+}
+
+// matchedFiles keeps the files that were the best match for an expected track
+// paths holds the full path of each file matched against, so same-named files
+// in different disc folders are told apart
+func matchedFiles(files []slskd.SearchFile, paths []string, matchInfo []matcher.TrackMatchInfo) []slskd.SearchFile {
+	best := make(map[string]bool, len(matchInfo))
+	for _, info := range matchInfo {
+		if info.BestIndex >= 0 {
+			best[paths[info.BestIndex]] = true
+		}
+	}
+
+	var kept []slskd.SearchFile
+	for _, file := range files {
+		if best[path.Clean(remotePath(file.Filename))] {
+			kept = append(kept, file)
+		}
+	}
+	return kept
+}
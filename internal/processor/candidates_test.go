@@ -0,0 +1,362 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/filter"
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/matcher"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// mockSlskdClientFailingEnqueue refuses enqueues for some users
+type mockSlskdClientFailingEnqueue struct {
+	mockSlskdClientWithResults
+	failUsers map[string]bool
+}
+
+func (m *mockSlskdClientFailingEnqueue) EnqueueDownloads(ctx context.Context, username string, files []slskd.EnqueueFile) (*slskd.EnqueueResult, error) {
+	if m.failUsers[username] {
+		return nil, errors.New("peer refused")
+	}
+	return m.mockSlskdClientWithResults.EnqueueDownloads(ctx, username, files)
+}
+
+func candidateAlbum() (lidarr.Album, []lidarr.Track) {
+	album := lidarr.Album{ID: 9, Title: "Album", Monitored: true, Artist: lidarr.Artist{ArtistName: "Artist"}}
+	tracks := []lidarr.Track{
+		{Title: "First Song", MediumNumber: 1},
+		{Title: "Second Song", MediumNumber: 1},
+	}
+	return album, tracks
+}
+
+func albumResult(username, ext string, bitRate int, size int64) slskd.SearchResult {
+	return slskd.SearchResult{
+		Username: username,
+		Files: []slskd.SearchFile{
+			{Filename: `Music\Album\01 - First Song.` + ext, Size: size, BitRate: intPtr(bitRate)},
+			{Filename: `Music\Album\02 - Second Song.` + ext, Size: size, BitRate: intPtr(bitRate)},
+		},
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestSearchForAlbum_PrefersHigherQualityCandidate(t *testing.T) {
+	album, tracks := candidateAlbum()
+	slskdClient := &mockSlskdClientWithResults{results: map[string][]slskd.SearchResult{
+		"Album": {
+			albumResult("lossy", "mp3", 128, 3_000_000),
+			albumResult("lossless", "flac", 900, 30_000_000),
+		},
+	}}
+	p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+	p.filter = filter.NewFilter([]string{"flac", "mp3 320", "mp3"})
+
+	item, err := p.searchForAlbum(context.Background(), "Album", tracks, album, &lidarr.Release{MediumCount: 1})
+	if err != nil {
+		t.Fatalf("searchForAlbum() error: %v", err)
+	}
+
+	if item.Sources[0].Username != "lossless" || item.Quality != "flac" {
+		t.Errorf("expected the flac directory, got %+v (quality %q)", item.Sources, item.Quality)
+	}
+	if len(slskdClient.enqueued) != 1 || len(slskdClient.enqueued["lossless"]) != 2 {
+		t.Errorf("expected only the best candidate enqueued, got %v", slskdClient.enqueued)
+	}
+}
+
+func TestSearchForAlbum_FallsBackToRunnerUpOnEnqueueFailure(t *testing.T) {
+	album, tracks := candidateAlbum()
+	slskdClient := &mockSlskdClientFailingEnqueue{
+		mockSlskdClientWithResults: mockSlskdClientWithResults{results: map[string][]slskd.SearchResult{
+			"Album": {
+				albumResult("lossy", "mp3", 320, 10_000_000),
+				albumResult("lossless", "flac", 900, 30_000_000),
+			},
+		}},
+		failUsers: map[string]bool{"lossless": true},
+	}
+	p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+	p.filter = filter.NewFilter([]string{"flac", "mp3 320"})
+
+	item, err := p.searchForAlbum(context.Background(), "Album", tracks, album, &lidarr.Release{MediumCount: 1})
+	if err != nil {
+		t.Fatalf("searchForAlbum() error: %v", err)
+	}
+
+	if item.Sources[0].Username != "lossy" || item.Quality != "mp3 320" {
+		t.Errorf("expected fallback to the mp3 directory, got %+v (quality %q)", item.Sources, item.Quality)
+	}
+	if len(slskdClient.searches) != 1 {
+		t.Errorf("expected no re-search, got %d searches", len(slskdClient.searches))
+	}
+}
+
+func TestRankCandidates(t *testing.T) {
+	p := newTestProcessor(t, &mockLidarrClient{}, &mockSlskdClient{})
+	p.filter = filter.NewFilter([]string{"flac", "mp3"})
+
+	tests := []struct {
+		name       string
+		candidates []albumCandidate
+		want       string
+	}{
+		{
+			name: "quality beats match ratio",
+			candidates: []albumCandidate{
+				{username: "lossy", ratio: 1.0, qualityRank: 1},
+				{username: "lossless", ratio: 0.85, qualityRank: 0},
+			},
+			want: "lossless",
+		},
+		{
+			name: "free slot and short queue break ties",
+			candidates: []albumCandidate{
+				{username: "busy", ratio: 0.9, queueLength: 40},
+				{username: "idle", ratio: 0.9, freeSlot: true},
+			},
+			want: "idle",
+		},
+		{
+			name: "larger directory wins at equal quality",
+			candidates: []albumCandidate{
+				{username: "small", ratio: 0.9, totalSize: 100},
+				{username: "large", ratio: 0.9, totalSize: 1000},
+			},
+			want: "large",
+		},
+		{
+			name: "faster uploader wins at equal quality",
+			candidates: []albumCandidate{
+				{username: "slow", ratio: 0.9, uploadSpeed: 10_000},
+				{username: "fast", ratio: 0.9, uploadSpeed: 2_000_000},
+			},
+			want: "fast",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p.rankCandidates(tt.candidates, p.filter)
+			if tt.candidates[0].username != tt.want {
+				t.Errorf("best candidate = %q, want %q", tt.candidates[0].username, tt.want)
+			}
+		})
+	}
+}
+
+// mockSlskdClientCountingEnqueue counts enqueue attempts per user on top of mockSlskdClientFailingEnqueue
+type mockSlskdClientCountingEnqueue struct {
+	mockSlskdClientFailingEnqueue
+	attempts map[string]int
+}
+
+func (m *mockSlskdClientCountingEnqueue) EnqueueDownloads(ctx context.Context, username string, files []slskd.EnqueueFile) (*slskd.EnqueueResult, error) {
+	if m.attempts == nil {
+		m.attempts = make(map[string]int)
+	}
+	m.attempts[username]++
+	return m.mockSlskdClientFailingEnqueue.EnqueueDownloads(ctx, username, files)
+}
+
+func TestSearchForAlbum_SkipsUsersThatRefused(t *testing.T) {
+	album, tracks := candidateAlbum()
+	offline := albumResult("offline", "flac", 900, 30_000_000)
+	// A second matching directory from the same user
+	offline.Files = append(offline.Files,
+		slskd.SearchFile{Filename: `Music\Album (Remaster)\01 - First Song.flac`, Size: 30_000_000},
+		slskd.SearchFile{Filename: `Music\Album (Remaster)\02 - Second Song.flac`, Size: 30_000_000},
+	)
+
+	tests := []struct {
+		name     string
+		results  []slskd.SearchResult
+		wantErr  error
+		wantUser string
+	}{
+		{
+			name:     "falls through to another user",
+			results:  []slskd.SearchResult{offline, albumResult("online", "mp3", 320, 10_000_000)},
+			wantUser: "online",
+		},
+		{
+			name:    "every user refused",
+			results: []slskd.SearchResult{offline},
+			wantErr: errEnqueueFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slskdClient := &mockSlskdClientCountingEnqueue{mockSlskdClientFailingEnqueue: mockSlskdClientFailingEnqueue{
+				mockSlskdClientWithResults: mockSlskdClientWithResults{results: map[string][]slskd.SearchResult{"Album": tt.results}},
+				failUsers:                  map[string]bool{"offline": true},
+			}}
+			p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+			p.filter = filter.NewFilter([]string{"flac", "mp3 320"})
+
+			item, err := p.searchForAlbum(context.Background(), "Album", tracks, album, &lidarr.Release{MediumCount: 1})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("searchForAlbum() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantUser != "" && item.Sources[0].Username != tt.wantUser {
+				t.Errorf("expected download from %q, got %+v", tt.wantUser, item.Sources)
+			}
+			if slskdClient.attempts["offline"] != 1 {
+				t.Errorf("expected one attempt with the offline user, got %d", slskdClient.attempts["offline"])
+			}
+			if !noCandidates(err) && tt.wantErr != nil {
+				t.Error("expected refused enqueues to allow fallback searches")
+			}
+		})
+	}
+}
+
+func TestSearchForAlbum_SkipsDirectoryQueuedForAnotherAlbum(t *testing.T) {
+	album, tracks := candidateAlbum()
+	slskdClient := &mockSlskdClientWithResults{results: map[string][]slskd.SearchResult{
+		"Album": {
+			albumResult("shared", "flac", 900, 30_000_000),
+			albumResult("other", "mp3", 320, 10_000_000),
+		},
+	}}
+	p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+	p.filter = filter.NewFilter([]string{"flac", "mp3 320"})
+
+	// The deluxe edition already took the flac directory this run
+	if _, ok := p.claimDirectory("shared", "Music/Album", "Album (Deluxe)"); !ok {
+		t.Fatal("claimDirectory() refused an unclaimed directory")
+	}
+
+	item, err := p.searchForAlbum(context.Background(), "Album", tracks, album, &lidarr.Release{MediumCount: 1})
+	if err != nil {
+		t.Fatalf("searchForAlbum() error: %v", err)
+	}
+	if item.Sources[0].Username != "other" {
+		t.Errorf("expected the unclaimed directory, got %+v", item.Sources)
+	}
+	if _, ok := slskdClient.enqueued["shared"]; ok {
+		t.Error("expected the shared directory not to be queued twice")
+	}
+	if owner, ok := p.claimDirectory("other", "Music/Album", "Another"); ok || owner != album.Title {
+		t.Errorf("expected the queued directory claimed by %q, got %q", album.Title, owner)
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestSearchForAlbum_SkipsLockedFilesAndBusyUsers(t *testing.T) {
+	locked := albumResult("locked", "flac", 900, 30_000_000)
+	for i := range locked.Files {
+		locked.Files[i].IsLocked = true
+	}
+	noSlot := albumResult("noslot", "flac", 900, 30_000_000)
+	noSlot.HasFreeUploadSlot = boolPtr(false)
+	longQueue := albumResult("queued", "flac", 900, 30_000_000)
+	longQueue.QueueLength = 500
+	unreported := albumResult("unreported", "flac", 900, 30_000_000)
+
+	tests := []struct {
+		name     string
+		skipBusy bool
+		results  []slskd.SearchResult
+		wantUser string
+		wantErr  error
+	}{
+		{name: "locked files ignored", results: []slskd.SearchResult{locked}, wantErr: errNoMatch},
+		{name: "busy users ranked by default", results: []slskd.SearchResult{noSlot}, wantUser: "noslot"},
+		{name: "no free slot skipped", skipBusy: true, results: []slskd.SearchResult{noSlot}, wantErr: errNoMatch},
+		{name: "long queue skipped", skipBusy: true, results: []slskd.SearchResult{longQueue}, wantErr: errNoMatch},
+		{name: "unreported slot kept", skipBusy: true, results: []slskd.SearchResult{unreported}, wantUser: "unreported"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			album, tracks := candidateAlbum()
+			slskdClient := &mockSlskdClientWithResults{results: map[string][]slskd.SearchResult{"Album": tt.results}}
+			p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+			p.cfg.Search.SkipBusyUsers = tt.skipBusy
+			p.cfg.Search.MaximumPeerQueue = 50
+
+			item, err := p.searchForAlbum(context.Background(), "Album", tracks, album, &lidarr.Release{MediumCount: 1})
+			if tt.wantErr != nil {
+				if err != tt.wantErr {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("searchForAlbum() error: %v", err)
+			}
+			if item.Sources[0].Username != tt.wantUser {
+				t.Errorf("expected %q, got %q", tt.wantUser, item.Sources[0].Username)
+			}
+		})
+	}
+}
+
+func TestMatchCandidates_RejectsWrongRunningTime(t *testing.T) {
+	album, tracks := candidateAlbum()
+	for i := range tracks {
+		tracks[i].Duration = 240_000
+	}
+	withLengths := func(username string, length int) slskd.SearchResult {
+		result := albumResult(username, "flac", 900, 20_000_000)
+		for i := range result.Files {
+			result.Files[i].Length = intPtr(length)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name   string
+		result slskd.SearchResult
+		want   int
+	}{
+		{name: "lengths match lidarr's", result: withLengths("user", 241), want: 1},
+		{name: "lengths far off lidarr's", result: withLengths("user", 60), want: 0},
+		{name: "unknown lengths", result: albumResult("user", "flac", 900, 20_000_000), want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestProcessor(t, &mockLidarrClient{}, &mockSlskdClientWithResults{})
+			p.filter = filter.NewFilter([]string{"flac"})
+
+			candidates := p.matchCandidates(context.Background(), []slskd.SearchResult{tt.result}, tracks, album, &lidarr.Release{MediumCount: 1})
+			if len(candidates) != tt.want {
+				t.Errorf("expected %d candidates, got %d", tt.want, len(candidates))
+			}
+		})
+	}
+}
+
+func TestMatchedFiles_TellsDiscFoldersApart(t *testing.T) {
+	dirFiles := map[string][]string{
+		"Music/Album/CD1": {"01.flac", "extra.flac"},
+		"Music/Album/CD2": {"01.flac"},
+	}
+	groups := groupDirectories(dirFiles, map[string][]int{})
+	discs := groups[len(groups)-1]
+
+	files := []slskd.SearchFile{
+		{Filename: `Music\Album\CD1\01.flac`},
+		{Filename: `Music\Album\CD1\extra.flac`},
+		{Filename: `Music\Album\CD2\01.flac`},
+	}
+	// Only the first disc's file matched an expected track
+	matchInfo := []matcher.TrackMatchInfo{{ExpectedTrack: "01", BestMatch: "01.flac", BestIndex: 0, Matched: true}}
+
+	kept := matchedFiles(files, discs.paths, matchInfo)
+	if len(kept) != 1 || kept[0].Filename != `Music\Album\CD1\01.flac` {
+		t.Errorf("expected only the matched disc's file kept, got %+v", kept)
+	}
+}
@@ -0,0 +1,94 @@
+package processor
+
+import (
+	"context"
+	"path"
+	"slices"
+
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// companionFiles picks the files in a matched directory, or its disc folders,
+// whose extensions are in extensions_whitelist. The album's own files and any
+// larger than max_companion_file_mb are left out
+func (p *Processor) companionFiles(files []slskd.SearchFile, dirs []string, albumFiles []slskd.SearchFile) []slskd.SearchFile {
+	if !p.cfg.Download.UseExtensionWhitelist {
+		return nil
+	}
+	maxSize := int64(p.cfg.Download.MaxCompanionFileMB) * 1024 * 1024
+
+	var companions []slskd.SearchFile
+	for _, file := range files {
+		filename := remotePath(file.Filename)
+		if !slices.Contains(dirs, path.Dir(filename)) || !p.cfg.Download.CompanionExtension(path.Ext(filename)) {
+			continue
+		}
+		if maxSize > 0 && file.Size > maxSize {
+			p.logger.Debug("skipping companion file over max_companion_file_mb", "file", file.Filename, "bytes", file.Size)
+			continue
+		}
+		if slices.ContainsFunc(albumFiles, func(f slskd.SearchFile) bool { return f.Filename == file.Filename }) {
+			continue
+		}
+		companions = append(companions, file)
+	}
+	return companions
+}
+
+// downloadSize returns the bytes a candidate's audio and companion files take up
+func (c albumCandidate) downloadSize() int64 {
+	size := c.totalSize
+	for _, file := range c.companions {
+		size += file.Size
+	}
+	return size
+}
+
+// enqueueFiles lists a candidate's audio and companion files for slskd
+func (c albumCandidate) enqueueFiles() []slskd.EnqueueFile {
+	files := make([]slskd.EnqueueFile, 0, len(c.files)+len(c.companions))
+	for _, file := range slices.Concat(c.files, c.companions) {
+		files = append(files, slskd.EnqueueFile{
+			Filename: file.Filename, // Keep original path for slskd
+			Size:     file.Size,
+		})
+	}
+	return files
+}
+
+// isCompanion reports whether a transfer is one of an item's companion files
+// rather than one of its tracks
+func (item DownloadedItem) isCompanion(file sourceFile) bool {
+	name := path.Base(remotePath(file.Filename))
+	for _, companion := range item.Companions {
+		if companion.Filename == name && (companion.Folder == "" || companion.Folder == localFolder(file.directory)) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitCompanions separates an item's companion files from its tracks
+func (item DownloadedItem) splitCompanions(files []sourceFile) (tracks, companions []sourceFile) {
+	for _, file := range files {
+		if item.isCompanion(file) {
+			companions = append(companions, file)
+		} else {
+			tracks = append(tracks, file)
+		}
+	}
+	return tracks, companions
+}
+
+// dropCompanions cancels companion files that failed, stalled or queued too far
+// back. They are extras, so the album goes ahead without them rather than
+// retrying them; dropped remembers them so later polls skip them
+func (p *Processor) dropCompanions(ctx context.Context, item DownloadedItem, files []sourceFile, dropped map[string]bool) {
+	for _, file := range files {
+		p.logger.Info("dropping companion file", "album", item.AlbumName, "username", file.username, "file", file.Filename, "state", file.State)
+		if err := p.slskd.CancelDownload(ctx, file.username, file.ID); err != nil {
+			p.logger.Debug("failed to cancel companion download", "file", file.Filename, "error", err)
+		}
+		dropped[transferKey(file.username, file.Filename)] = true
+	}
+}
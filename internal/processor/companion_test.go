@@ -0,0 +1,166 @@
+package processor
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/filter"
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/organizer"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+func TestSearchForAlbum_CompanionFiles(t *testing.T) {
+	album, tracks := candidateAlbum()
+
+	share := albumResult("user", "flac", 900, 30_000_000)
+	share.Files = append(share.Files,
+		slskd.SearchFile{Filename: `Music\Album\folder.jpg`, Size: 200_000},
+		slskd.SearchFile{Filename: `Music\Album\Album.CUE`, Size: 1_000},
+		slskd.SearchFile{Filename: `Music\Album\scan.png`, Size: 20_000_000},
+		slskd.SearchFile{Filename: `Music\Album\notes.txt`, Size: 1_000},
+		slskd.SearchFile{Filename: `Music\Other\cover.jpg`, Size: 1_000},
+		slskd.SearchFile{Filename: `Music\Album\locked.jpg`, Size: 1_000, IsLocked: true},
+	)
+
+	tests := []struct {
+		name       string
+		enabled    bool
+		companions []string
+	}{
+		{"disabled", false, nil},
+		{"whitelisted and small enough", true, []string{"folder.jpg", "Album.CUE"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slskdClient := &mockSlskdClientWithResults{results: map[string][]slskd.SearchResult{"Album": {share}}}
+			p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+			p.filter = filter.NewFilter([]string{"flac"})
+			p.cfg.Download.UseExtensionWhitelist = tt.enabled
+			p.cfg.Download.ExtensionsWhitelist = []string{"jpg", ".cue", "png"}
+			p.cfg.Download.MaxCompanionFileMB = 10
+
+			item, err := p.searchForAlbum(context.Background(), "Album", tracks, album, &lidarr.Release{MediumCount: 1})
+			if err != nil {
+				t.Fatalf("searchForAlbum() error: %v", err)
+			}
+
+			if len(item.Tracks) != len(tracks) {
+				t.Errorf("companion files should not be tracks, got %d tracks", len(item.Tracks))
+			}
+			var companions []string
+			for _, companion := range item.Companions {
+				companions = append(companions, companion.Filename)
+			}
+			if !slices.Equal(companions, tt.companions) {
+				t.Errorf("expected companions %v, got %v", tt.companions, companions)
+			}
+			if enqueued := slskdClient.enqueued["user"]; len(enqueued) != len(tracks)+len(tt.companions) {
+				t.Errorf("expected %d files enqueued, got %d", len(tracks)+len(tt.companions), len(enqueued))
+			}
+		})
+	}
+}
+
+func TestAlbumItem_CompanionFilesInDiscFolders(t *testing.T) {
+	album, tracks := candidateAlbum()
+	p := newTestProcessor(t, &mockLidarrClient{}, &mockSlskdClient{})
+
+	candidate := albumCandidate{
+		release:  &lidarr.Release{MediumCount: 2},
+		tracks:   tracks,
+		username: "user",
+		dir:      "Music/Album",
+		discs:    map[string]int{"Music/Album/CD1": 1, "Music/Album/CD2": 2},
+		files: []slskd.SearchFile{
+			{Filename: `Music\Album\CD1\01 - First Song.flac`},
+			{Filename: `Music\Album\CD2\01 - Second Song.flac`},
+		},
+		companions: []slskd.SearchFile{{Filename: `Music\Album\CD2\cover.jpg`}},
+	}
+
+	item := p.albumItem(candidate, album)
+	want := []organizer.DownloadedTrack{{Filename: "cover.jpg", Folder: "CD2"}}
+	if len(item.Companions) != 1 || item.Companions[0] != want[0] {
+		t.Errorf("expected companions %v, got %v", want, item.Companions)
+	}
+}
+
+// mockSlskdClientWithCompanion reports a track and a cover in the given states
+// and records enqueues and cancellations
+type mockSlskdClientWithCompanion struct {
+	mockSlskdClient
+	trackState, coverState string
+	enqueued               []string
+	cancelled              []string
+}
+
+func (m *mockSlskdClientWithCompanion) GetDownloads(ctx context.Context) (slskd.DownloadsResponse, error) {
+	return slskd.DownloadsResponse{{Username: "user", Directories: []slskd.DirectoryDownloads{{
+		Directory: `Music\Album`,
+		Files: []slskd.DownloadFile{
+			{ID: "1", Filename: `Music\Album\01.flac`, State: m.trackState},
+			{ID: "2", Filename: `Music\Album\cover.jpg`, State: m.coverState},
+		},
+	}}}}, nil
+}
+
+func (m *mockSlskdClientWithCompanion) EnqueueDownloads(ctx context.Context, username string, files []slskd.EnqueueFile) (*slskd.EnqueueResult, error) {
+	for _, file := range files {
+		m.enqueued = append(m.enqueued, file.Filename)
+	}
+	return &slskd.EnqueueResult{}, nil
+}
+
+func (m *mockSlskdClientWithCompanion) CancelDownload(ctx context.Context, username, downloadID string) error {
+	m.cancelled = append(m.cancelled, downloadID)
+	return nil
+}
+
+func TestMonitorDownloads_CompanionFiles(t *testing.T) {
+	tests := []struct {
+		name         string
+		trackState   string
+		coverState   string
+		wantImported bool
+	}{
+		{name: "failed cover dropped", trackState: "Completed, Succeeded", coverState: "Completed, Errored", wantImported: true},
+		{name: "only the cover finished", trackState: "Completed, Errored", coverState: "Completed, Succeeded", wantImported: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slskdClient := &mockSlskdClientWithCompanion{trackState: tt.trackState, coverState: tt.coverState}
+			p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+			p.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+			p.cfg.Slskd.StalledTimeout = 60
+			p.cfg.Search.WishlistOnDenylist = false
+			p.cfg.Download.MaxFileRetries = intPtr(1)
+			writeDownloadedFile(t, p, "Album", "01.flac", 0)
+			writeDownloadedFile(t, p, "Album", "cover.jpg", 0)
+
+			downloadList := []DownloadedItem{{
+				AlbumID:    3,
+				FolderName: "Album",
+				Sources:    []DownloadSource{{Username: "user", Directory: "Music/Album"}},
+				Tracks:     []organizer.DownloadedTrack{{Filename: "01.flac"}},
+				Companions: []organizer.DownloadedTrack{{Filename: "cover.jpg"}},
+			}}
+			succeeded, err := p.monitorDownloads(context.Background(), downloadList, nil, nil)
+			if err != nil {
+				t.Fatalf("monitorDownloads() error: %v", err)
+			}
+
+			if imported := len(succeeded) == 1; imported != tt.wantImported {
+				t.Fatalf("expected imported %v, got %v", tt.wantImported, imported)
+			}
+			if slices.Contains(slskdClient.enqueued, `Music\Album\cover.jpg`) {
+				t.Errorf("expected the cover never to be retried, enqueued %v", slskdClient.enqueued)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package processor
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/matcher"
+)
+
+// variousArtistsMBID is the MusicBrainz ID of the "Various Artists" artist
+const variousArtistsMBID = "89ad4ac3-39f7-470e-963a-56509c546377"
+
+// variousArtistsNames are artist names used for compilations, lowercased
+var variousArtistsNames = []string{"various artists", "various", "va"}
+
+// isVariousArtists reports whether an album is a compilation credited to Various Artists
+func isVariousArtists(album lidarr.Album) bool {
+	if album.Artist.ForeignArtistID == variousArtistsMBID {
+		return true
+	}
+	return slices.Contains(variousArtistsNames, strings.ToLower(strings.TrimSpace(album.Artist.ArtistName)))
+}
+
+// matcherFor returns the matcher for an album's files, which also accepts
+// "Artist - Title" filenames for compilations
+func (p *Processor) matcherFor(album lidarr.Album) *matcher.Matcher {
+	if isVariousArtists(album) {
+		return p.matcher.ForCompilation()
+	}
+	return p.matcher
+}
@@ -0,0 +1,130 @@
+package processor
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// mockSlskdClientConcurrent holds each search open briefly and records how many overlap
+type mockSlskdClientConcurrent struct {
+	mockSlskdClient
+	delay time.Duration
+
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+	searches    int
+}
+
+func (m *mockSlskdClientConcurrent) Search(ctx context.Context, req slskd.SearchRequest) (*slskd.SearchResponse, error) {
+	m.mu.Lock()
+	m.inFlight++
+	m.searches++
+	m.maxInFlight = max(m.maxInFlight, m.inFlight)
+	m.mu.Unlock()
+
+	defer func() {
+		m.mu.Lock()
+		m.inFlight--
+		m.mu.Unlock()
+	}()
+
+	select {
+	case <-time.After(m.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &slskd.SearchResponse{ID: req.SearchText}, nil
+}
+
+func (m *mockSlskdClientConcurrent) GetSearchResults(ctx context.Context, searchID string) ([]slskd.SearchResult, error) {
+	return []slskd.SearchResult{{
+		Username: "user",
+		Files: []slskd.SearchFile{
+			{Filename: `Music\` + searchID + `\01 - First Song.flac`, Size: 100},
+			{Filename: `Music\` + searchID + `\02 - Second Song.flac`, Size: 100},
+		},
+	}}, nil
+}
+
+func (m *mockSlskdClientConcurrent) StreamSearchResults(ctx context.Context, searchID string, fn func(slskd.SearchResult) bool) error {
+	results, err := m.GetSearchResults(ctx, searchID)
+	return yieldResults(results, err, fn)
+}
+
+func concurrentAlbums(n int) []lidarr.Album {
+	albums := make([]lidarr.Album, n)
+	for i := range albums {
+		albums[i] = lidarr.Album{
+			ID:        i + 1,
+			Title:     string(rune('A' + i)),
+			Artist:    lidarr.Artist{ArtistName: "Artist"},
+			Releases:  []lidarr.Release{{Status: "Official", TrackCount: 2, MediumCount: 1}},
+			Monitored: true,
+		}
+	}
+	return albums
+}
+
+func TestSearchAndQueueDownloads_Concurrent(t *testing.T) {
+	tracks := []lidarr.Track{{Title: "First Song"}, {Title: "Second Song"}}
+	slskdClient := &mockSlskdClientConcurrent{delay: 20 * time.Millisecond}
+	p := newTestProcessor(t, &mockLidarrClientWithAliases{tracks: tracks}, slskdClient)
+	p.cfg.Search.ConcurrentSearches = 4
+	p.current = &RunSummary{}
+
+	albums := concurrentAlbums(8)
+	downloadList, failed := p.searchAndQueueDownloads(context.Background(), albums, nil)
+
+	if failed != 0 || len(downloadList) != len(albums) {
+		t.Fatalf("expected %d queued and none failed, got %d queued, %d failed", len(albums), len(downloadList), failed)
+	}
+	for i, item := range downloadList {
+		if item.AlbumID != albums[i].ID {
+			t.Errorf("download list out of order at %d: got album %d, want %d", i, item.AlbumID, albums[i].ID)
+		}
+	}
+	if slskdClient.maxInFlight < 2 || slskdClient.maxInFlight > 4 {
+		t.Errorf("expected between 2 and 4 concurrent searches, got %d", slskdClient.maxInFlight)
+	}
+	if len(p.current.Decisions) != len(albums) {
+		t.Errorf("expected %d decisions, got %d", len(albums), len(p.current.Decisions))
+	}
+}
+
+func TestSearchAndQueueDownloads_StopsOnCancel(t *testing.T) {
+	tracks := []lidarr.Track{{Title: "First Song"}, {Title: "Second Song"}}
+	slskdClient := &mockSlskdClientConcurrent{delay: time.Minute}
+	p := newTestProcessor(t, &mockLidarrClientWithAliases{tracks: tracks}, slskdClient)
+	p.cfg.Search.ConcurrentSearches = 2
+	p.current = &RunSummary{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		p.searchAndQueueDownloads(ctx, concurrentAlbums(6), nil)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("workers did not stop after cancellation")
+	}
+
+	if slskdClient.searches > 2 {
+		t.Errorf("expected no searches after cancellation, got %d", slskdClient.searches)
+	}
+	for _, album := range concurrentAlbums(6) {
+		if entry := p.denylist.GetEntry(album.ID); entry != nil {
+			t.Errorf("interrupted search for album %d counted as a failure", album.ID)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package processor
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/yuritomanek/seekarr/internal/filter"
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// markCutoffUnmet records the albums listed by Lidarr's cutoff unmet endpoint
+// in the current run
+func (p *Processor) markCutoffUnmet(albums []lidarr.Album) {
+	p.cutoffMu.Lock()
+	defer p.cutoffMu.Unlock()
+	if p.cutoffUnmet == nil {
+		p.cutoffUnmet = make(map[int]bool)
+	}
+	for _, album := range albums {
+		p.cutoffUnmet[album.ID] = true
+	}
+}
+
+// isCutoffUnmet reports whether an album was listed as cutoff unmet this run
+func (p *Processor) isCutoffUnmet(albumID int) bool {
+	p.cutoffMu.Lock()
+	defer p.cutoffMu.Unlock()
+	return p.cutoffUnmet[albumID]
+}
+
+// resetCutoffUnmet forgets the cutoff-unmet albums of the previous run
+func (p *Processor) resetCutoffUnmet() {
+	p.cutoffMu.Lock()
+	defer p.cutoffMu.Unlock()
+	p.cutoffUnmet = nil
+}
+
+// cutoffSearch prepares the search for a cutoff-unmet album: the tracks are
+// narrowed to those being replaced and, when the quality of the files on disk
+// is known, only strictly better files are accepted. Without Lidarr's track
+// files every track is searched for as if the album were missing
+func (p *Processor) cutoffSearch(ctx context.Context, album lidarr.Album, tracks []lidarr.Track) (context.Context, []lidarr.Track) {
+	files, err := p.lidarr.GetTrackFiles(ctx, album.ID)
+	if err != nil {
+		p.logger.Debug("failed to fetch track files, searching for every track", "album", album.Title, "error", err)
+		return ctx, tracks
+	}
+
+	tracks = p.tracksToReplace(album, tracks, files)
+	if existing, ok := existingQuality(p.filterFor(ctx), files); ok {
+		p.logger.Debug("requiring files better than those on disk",
+			"album", album.Title,
+			"existing", existing.name)
+		ctx = withExistingQuality(ctx, existing)
+	}
+	return ctx, tracks
+}
+
+// tracksToReplace narrows a cutoff-unmet album's tracks to those without a
+// file or whose file is below the quality cutoff, so a directory only has to
+// hold the tracks being upgraded. All tracks are kept if none of them need
+// replacing
+func (p *Processor) tracksToReplace(album lidarr.Album, tracks []lidarr.Track, files []lidarr.TrackFile) []lidarr.Track {
+	met := make(map[int]bool, len(files))
+	for _, file := range files {
+		met[file.ID] = !file.QualityCutoffNotMet
+	}
+
+	var replace []lidarr.Track
+	for _, track := range tracks {
+		if !track.HasFile || !met[track.TrackFileID] {
+			replace = append(replace, track)
+		}
+	}
+	if len(replace) == 0 || len(replace) == len(tracks) {
+		return tracks
+	}
+
+	p.logger.Debug("searching for tracks below the quality cutoff",
+		"album", album.Title,
+		"replace", len(replace),
+		"tracks", len(tracks))
+	return replace
+}
+
+// onDiskQuality is the best quality among the files a cutoff search replaces,
+// with its position in the album's filter
+type onDiskQuality struct {
+	name string // Lidarr quality name, e.g. MP3-320
+	rank int
+}
+
+// existingQuality returns the best quality among the files below the cutoff,
+// ranked by f. It reports false if there are none, f has no filetypes to rank
+// by, or a file's quality can't be expressed as a filetype pattern
+func existingQuality(f *filter.Filter, files []lidarr.TrackFile) (onDiskQuality, bool) {
+	if f.AllowedCount() == 0 {
+		return onDiskQuality{}, false
+	}
+
+	var best onDiskQuality
+	found := false
+	for _, file := range files {
+		if !file.QualityCutoffNotMet {
+			continue
+		}
+		name := file.Quality.Quality.Name
+		sample, ok := qualityFile(name)
+		if !ok {
+			return onDiskQuality{}, false
+		}
+		if rank := f.Rank(sample); !found || rank < best.rank {
+			best = onDiskQuality{name: name, rank: rank}
+			found = true
+		}
+	}
+	return best, found
+}
+
+// qualityFile returns a search file with a Lidarr quality's extension and
+// bitrate or bit depth, for ranking files already on disk
+func qualityFile(quality string) (slskd.SearchFile, bool) {
+	pattern, ok := lidarrQualityPatterns[quality]
+	if !ok {
+		return slskd.SearchFile{}, false
+	}
+
+	parts := strings.Fields(pattern)
+	file := slskd.SearchFile{Filename: "track." + parts[0]}
+	if len(parts) == 2 {
+		value, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return slskd.SearchFile{}, false
+		}
+		if parts[0] == "mp3" {
+			file.BitRate = &value
+		} else {
+			file.BitDepth = &value
+		}
+	}
+	return file, true
+}
+
+// existingQualityKey is the context key for the quality a cutoff search upgrades
+type existingQualityKey struct{}
+
+// withExistingQuality attaches the quality on disk to a cutoff search's context
+func withExistingQuality(ctx context.Context, q onDiskQuality) context.Context {
+	return context.WithValue(ctx, existingQualityKey{}, q)
+}
+
+// existingQualityFor returns the quality on disk a search must improve on
+func existingQualityFor(ctx context.Context) (onDiskQuality, bool) {
+	q, ok := ctx.Value(existingQualityKey{}).(onDiskQuality)
+	return q, ok
+}
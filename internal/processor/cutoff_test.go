@@ -0,0 +1,246 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/filter"
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// mockLidarrClientWithTrackFiles returns the files Lidarr holds for an album
+type mockLidarrClientWithTrackFiles struct {
+	mockLidarrClient
+	files []lidarr.TrackFile
+	err   error
+}
+
+func (m *mockLidarrClientWithTrackFiles) GetTrackFiles(ctx context.Context, albumID int) ([]lidarr.TrackFile, error) {
+	return m.files, m.err
+}
+
+// halfImportedTracks returns four tracks, the first two with files
+func halfImportedTracks() []lidarr.Track {
+	return []lidarr.Track{
+		{ID: 1, Title: "One", HasFile: true, TrackFileID: 11},
+		{ID: 2, Title: "Two", HasFile: true, TrackFileID: 12},
+		{ID: 3, Title: "Three"},
+		{ID: 4, Title: "Four"},
+	}
+}
+
+func TestTracksToReplace(t *testing.T) {
+	tests := []struct {
+		name     string
+		tracks   []lidarr.Track
+		files    []lidarr.TrackFile
+		expected []int
+	}{
+		{
+			name:     "half the tracks have files meeting the cutoff",
+			tracks:   halfImportedTracks(),
+			files:    []lidarr.TrackFile{{ID: 11}, {ID: 12}},
+			expected: []int{3, 4},
+		},
+		{
+			name:     "a file below the cutoff is replaced",
+			tracks:   halfImportedTracks(),
+			files:    []lidarr.TrackFile{{ID: 11}, {ID: 12, QualityCutoffNotMet: true}},
+			expected: []int{2, 3, 4},
+		},
+		{
+			name:     "a file Lidarr no longer lists is replaced",
+			tracks:   halfImportedTracks(),
+			files:    []lidarr.TrackFile{{ID: 11}},
+			expected: []int{2, 3, 4},
+		},
+		{
+			name: "every file meets the cutoff keeps all tracks",
+			tracks: []lidarr.Track{
+				{ID: 1, HasFile: true, TrackFileID: 11},
+				{ID: 2, HasFile: true, TrackFileID: 12},
+			},
+			files:    []lidarr.TrackFile{{ID: 11}, {ID: 12}},
+			expected: []int{1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestProcessor(t, &mockLidarrClient{}, &mockSlskdClient{})
+
+			tracks := p.tracksToReplace(lidarr.Album{ID: 9, Title: "Album"}, tt.tracks, tt.files)
+
+			if len(tracks) != len(tt.expected) {
+				t.Fatalf("expected tracks %v, got %+v", tt.expected, tracks)
+			}
+			for i, track := range tracks {
+				if track.ID != tt.expected[i] {
+					t.Errorf("expected tracks %v, got %+v", tt.expected, tracks)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestTracksToReplace_MatchesPartialDirectory(t *testing.T) {
+	album, _ := candidateAlbum()
+	tracks := []lidarr.Track{
+		{ID: 1, Title: "First Song", MediumNumber: 1, HasFile: true, TrackFileID: 11},
+		{ID: 2, Title: "Second Song", MediumNumber: 1, HasFile: true, TrackFileID: 12},
+	}
+	lidarrClient := &mockLidarrClientWithTrackFiles{files: []lidarr.TrackFile{{ID: 11}, {ID: 12, QualityCutoffNotMet: true}}}
+
+	// A directory holding only the track being upgraded
+	result := slskd.SearchResult{
+		Username: "user",
+		Files:    []slskd.SearchFile{{Filename: `Music\Album\02 - Second Song.flac`, Size: 30_000_000, BitRate: intPtr(900)}},
+	}
+	slskdClient := &mockSlskdClientWithResults{results: map[string][]slskd.SearchResult{"Album": {result}}}
+	p := newTestProcessor(t, lidarrClient, slskdClient)
+
+	ctx, tracks := p.cutoffSearch(context.Background(), album, tracks)
+	item, err := p.searchForAlbum(ctx, "Album", tracks, album, &lidarr.Release{MediumCount: 1})
+	if err != nil {
+		t.Fatalf("searchForAlbum() error: %v", err)
+	}
+	if len(item.Tracks) != 1 {
+		t.Errorf("expected 1 track, got %d", len(item.Tracks))
+	}
+}
+
+func TestCutoffSearch_TrackFilesUnavailable(t *testing.T) {
+	lidarrClient := &mockLidarrClientWithTrackFiles{err: errors.New("connection refused")}
+	p := newTestProcessor(t, lidarrClient, &mockSlskdClient{})
+	p.filter = filter.NewFilter([]string{"flac", "mp3"})
+
+	ctx, tracks := p.cutoffSearch(context.Background(), lidarr.Album{ID: 9, Title: "Album"}, halfImportedTracks())
+	if len(tracks) != 4 {
+		t.Errorf("expected all 4 tracks kept, got %d", len(tracks))
+	}
+	if _, ok := existingQualityFor(ctx); ok {
+		t.Error("expected no quality requirement without track files")
+	}
+}
+
+// belowCutoff returns a track file of the given Lidarr quality below the cutoff
+func belowCutoff(id int, quality string) lidarr.TrackFile {
+	return lidarr.TrackFile{ID: id, QualityCutoffNotMet: true, Quality: lidarr.FileQuality{Quality: lidarr.Quality{Name: quality}}}
+}
+
+func TestExistingQuality(t *testing.T) {
+	tests := []struct {
+		name     string
+		allowed  []string
+		files    []lidarr.TrackFile
+		expected string
+		ok       bool
+	}{
+		{"mp3 on disk", []string{"flac", "mp3 320"}, []lidarr.TrackFile{belowCutoff(1, "MP3-320")}, "MP3-320", true},
+		{"best file below the cutoff", []string{"flac", "mp3 320", "mp3 192"}, []lidarr.TrackFile{belowCutoff(1, "MP3-192"), belowCutoff(2, "MP3-320")}, "MP3-320", true},
+		{"quality outside the allowed filetypes", []string{"flac"}, []lidarr.TrackFile{belowCutoff(1, "MP3-128")}, "MP3-128", true},
+		{"files meeting the cutoff ignored", []string{"flac", "mp3"}, []lidarr.TrackFile{{ID: 1, Quality: lidarr.FileQuality{Quality: lidarr.Quality{Name: "FLAC"}}}, belowCutoff(2, "MP3-256")}, "MP3-256", true},
+		{"unmapped quality", []string{"flac", "mp3"}, []lidarr.TrackFile{belowCutoff(1, "MP3-320"), belowCutoff(2, "AAC-256")}, "", false},
+		{"no files below the cutoff", []string{"flac", "mp3"}, []lidarr.TrackFile{{ID: 1}}, "", false},
+		{"no allowed filetypes", nil, []lidarr.TrackFile{belowCutoff(1, "MP3-320")}, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := existingQuality(filter.NewFilter(tt.allowed), tt.files)
+			if ok != tt.ok || got.name != tt.expected {
+				t.Errorf("existingQuality() = %q, %v, expected %q, %v", got.name, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSearchForAlbum_CutoffRequiresBetterQuality(t *testing.T) {
+	album, tracks := candidateAlbum()
+	tracks[0].HasFile, tracks[0].TrackFileID = true, 11
+	tracks[1].HasFile, tracks[1].TrackFileID = true, 12
+	onDisk := []lidarr.TrackFile{belowCutoff(11, "MP3-320"), belowCutoff(12, "MP3-320")}
+
+	tests := []struct {
+		name        string
+		multiSource bool
+		results     []slskd.SearchResult
+		expected    string
+	}{
+		{"flac accepted over the same mp3", false, []slskd.SearchResult{albumResult("mp3", "mp3", 320, 10_000_000), albumResult("flac", "flac", 900, 30_000_000)}, "flac"},
+		{"only the same mp3", false, []slskd.SearchResult{albumResult("mp3", "mp3", 320, 10_000_000)}, ""},
+		{"only the same mp3 with multi-source", true, []slskd.SearchResult{albumResult("mp3", "mp3", 320, 10_000_000)}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slskdClient := &mockSlskdClientWithResults{results: map[string][]slskd.SearchResult{"Album": tt.results}}
+			p := newTestProcessor(t, &mockLidarrClientWithTrackFiles{files: onDisk}, slskdClient)
+			p.filter = filter.NewFilter([]string{"flac", "mp3 320"})
+			p.cfg.Search.AllowMultiSource = tt.multiSource
+
+			ctx, tracks := p.cutoffSearch(context.Background(), album, tracks)
+			item, err := p.searchForAlbum(ctx, "Album", tracks, album, &lidarr.Release{MediumCount: 1})
+			if tt.expected == "" {
+				if !noCandidates(err) {
+					t.Errorf("expected no candidates, got %+v, %v", item.Sources, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("searchForAlbum() error: %v", err)
+			}
+			if len(item.Sources) != 1 || item.Sources[0].Username != tt.expected {
+				t.Errorf("expected download from %q, got %+v", tt.expected, item.Sources)
+			}
+		})
+	}
+}
+
+func TestBestTrackFile_CutoffRequiresBetterQuality(t *testing.T) {
+	album, tracks := candidateAlbum()
+	p := newTestProcessor(t, &mockLidarrClient{}, &mockSlskdClientWithResults{})
+	p.filter = filter.NewFilter([]string{"flac", "mp3 320"})
+	mp3 := albumResult("mp3", "mp3", 320, 10_000_000)
+	flac := albumResult("flac", "flac", 900, 30_000_000)
+
+	ctx := withExistingQuality(context.Background(), onDiskQuality{name: "MP3-320", rank: 1})
+	if candidate, ok := p.bestTrackFile(ctx, album, tracks[0], []slskd.SearchResult{mp3}); ok {
+		t.Errorf("expected the same quality to be skipped, got %q", candidate.file.Filename)
+	}
+	if candidate, ok := p.bestTrackFile(ctx, album, tracks[0], []slskd.SearchResult{mp3, flac}); !ok || candidate.username != "flac" {
+		t.Errorf("expected the better file, got %+v, %v", candidate, ok)
+	}
+	if _, ok := p.bestTrackFile(context.Background(), album, tracks[0], []slskd.SearchResult{mp3}); !ok {
+		t.Error("expected any allowed file outside a cutoff search")
+	}
+}
+
+func TestFetchWantedAlbums_MarksCutoffUnmet(t *testing.T) {
+	lidarrClient := &mockLidarrClientWithSources{
+		missing:     []lidarr.Album{{ID: 1, Title: "One"}},
+		cutoffUnmet: []lidarr.Album{{ID: 2, Title: "Two"}},
+	}
+	p := newTestProcessor(t, lidarrClient, &mockSlskdClient{})
+	p.cfg.Search.SearchSource = SourceAll
+	p.current = &RunSummary{}
+
+	if _, err := p.fetchWantedAlbums(context.Background()); err != nil {
+		t.Fatalf("fetchWantedAlbums() error: %v", err)
+	}
+
+	if p.isCutoffUnmet(1) {
+		t.Error("expected missing album not to be marked cutoff unmet")
+	}
+	if !p.isCutoffUnmet(2) {
+		t.Error("expected cutoff unmet album to be marked")
+	}
+
+	p.resetCutoffUnmet()
+	if p.isCutoffUnmet(2) {
+		t.Error("expected reset to forget cutoff unmet albums")
+	}
+}
@@ -0,0 +1,104 @@
+package processor
+
+import (
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// discFolderPattern matches disc subfolders such as "CD1", "Disc 2", "disk_03" or "D1 - Live"
+var discFolderPattern = regexp.MustCompile(`(?i)^(?:cd|disc|disk|d)[\s._-]*0*(\d{1,2})(?:\b.*)?$`)
+
+// discNumber returns the disc number of a disc subfolder name
+func discNumber(name string) (int, bool) {
+	m := discFolderPattern.FindStringSubmatch(name)
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil || n == 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// dirGroup is a set of remote directories matched against an album as one
+// Plain directories form a group of their own; sibling disc folders
+// (Album/CD1, Album/CD2) are also combined into a group keyed by their parent
+type dirGroup struct {
+	dir     string         // Normalized to forward slashes
+	members []string       // Directories whose files belong to the group
+	discs   map[string]int // Disc number of each member, nil for a plain directory
+	files   []string       // Base filenames across all members
+	paths   []string       // Full path of each file, aligned with files
+	lengths []int          // File lengths in seconds, aligned with files
+}
+
+// groupDirectories builds match groups from files grouped by directory
+func groupDirectories(dirFiles map[string][]string, dirLengths map[string][]int) []dirGroup {
+	dirs := make([]string, 0, len(dirFiles))
+	for dir := range dirFiles {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	var groups []dirGroup
+	discParents := make(map[string]map[string]int)
+	for _, dir := range dirs {
+		groups = append(groups, dirGroup{
+			dir:     dir,
+			members: []string{dir},
+			files:   dirFiles[dir],
+			paths:   joinPaths(dir, dirFiles[dir]),
+			lengths: dirLengths[dir],
+		})
+
+		if n, ok := discNumber(filepath.Base(dir)); ok {
+			parent := filepath.Dir(dir)
+			if discParents[parent] == nil {
+				discParents[parent] = make(map[string]int)
+			}
+			discParents[parent][dir] = n
+		}
+	}
+
+	parents := make([]string, 0, len(discParents))
+	for parent := range discParents {
+		parents = append(parents, parent)
+	}
+	sort.Strings(parents)
+
+	for _, parent := range parents {
+		discs := discParents[parent]
+		if len(discs) < 2 {
+			continue
+		}
+
+		members := make([]string, 0, len(discs))
+		for dir := range discs {
+			members = append(members, dir)
+		}
+		sort.Slice(members, func(i, j int) bool { return discs[members[i]] < discs[members[j]] })
+
+		group := dirGroup{dir: parent, members: members, discs: discs}
+		for _, dir := range members {
+			group.files = append(group.files, dirFiles[dir]...)
+			group.paths = append(group.paths, joinPaths(dir, dirFiles[dir])...)
+			group.lengths = append(group.lengths, dirLengths[dir]...)
+		}
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
+// joinPaths returns the full path of each file in dir
+func joinPaths(dir string, files []string) []string {
+	paths := make([]string, len(files))
+	for i, file := range files {
+		paths[i] = path.Join(dir, file)
+	}
+	return paths
+}
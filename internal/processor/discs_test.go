@@ -0,0 +1,110 @@
+package processor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/filter"
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+func TestDiscNumber(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   int
+		wantOK bool
+	}{
+		{name: "CD1", want: 1, wantOK: true},
+		{name: "Disc 2", want: 2, wantOK: true},
+		{name: "disk_03", want: 3, wantOK: true},
+		{name: "CD 2 - Live", want: 2, wantOK: true},
+		{name: "CD0", wantOK: false},
+		{name: "Discography", wantOK: false},
+		{name: "Album", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := discNumber(tt.name)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("discNumber(%q) = %d, %v, want %d, %v", tt.name, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGroupDirectories(t *testing.T) {
+	dirFiles := map[string][]string{
+		"Music/Album/CD2":  {"01 - Third Song.flac"},
+		"Music/Album/CD1":  {"01 - First Song.flac"},
+		"Music/Other":      {"01 - Intro.flac"},
+		"Music/Single/CD1": {"01 - Lonely.flac"},
+	}
+
+	groups := groupDirectories(dirFiles, map[string][]int{})
+	if len(groups) != 5 {
+		t.Fatalf("expected 4 plain groups and 1 disc set, got %d", len(groups))
+	}
+
+	discs := groups[4]
+	if discs.dir != "Music/Album" {
+		t.Errorf("expected the disc set keyed by its parent, got %q", discs.dir)
+	}
+	if len(discs.members) != 2 || discs.members[0] != "Music/Album/CD1" {
+		t.Errorf("expected members ordered by disc number, got %v", discs.members)
+	}
+	if len(discs.files) != 2 || discs.files[0] != "01 - First Song.flac" {
+		t.Errorf("expected files from both discs in disc order, got %v", discs.files)
+	}
+}
+
+func TestSearchForAlbum_MatchesDiscSubfolders(t *testing.T) {
+	album := lidarr.Album{ID: 9, Title: "Album", Artist: lidarr.Artist{ArtistName: "Artist"}}
+	tracks := []lidarr.Track{
+		{Title: "First Song", MediumNumber: 1},
+		{Title: "Second Song", MediumNumber: 1},
+		{Title: "Third Song", MediumNumber: 2},
+		{Title: "Fourth Song", MediumNumber: 2},
+	}
+	slskdClient := &mockSlskdClientWithResults{results: map[string][]slskd.SearchResult{
+		"Album": {{
+			Username: "user",
+			Files: []slskd.SearchFile{
+				{Filename: `Music\Album\CD1\01 - First Song.flac`, Size: 1000},
+				{Filename: `Music\Album\CD1\02 - Second Song.flac`, Size: 1000},
+				{Filename: `Music\Album\CD2\01 - Third Song.flac`, Size: 1000},
+				{Filename: `Music\Album\CD2\02 - Fourth Song.flac`, Size: 1000},
+			},
+		}},
+	}}
+	p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+	p.filter = filter.NewFilter([]string{"flac"})
+
+	item, err := p.searchForAlbum(context.Background(), "Album", tracks, album, &lidarr.Release{MediumCount: 2})
+	if err != nil {
+		t.Fatalf("searchForAlbum() error: %v", err)
+	}
+
+	if len(item.Sources) != 2 || item.Sources[0].Directory != "Music/Album/CD1" || item.Sources[1].Directory != "Music/Album/CD2" {
+		t.Errorf("expected one source per disc folder, got %+v", item.Sources)
+	}
+	if item.FolderName != "Album" {
+		t.Errorf("expected the album gathered into a folder named after the disc folders' parent, got %q", item.FolderName)
+	}
+	if len(item.Tracks) != 4 {
+		t.Fatalf("expected 4 tracks, got %d", len(item.Tracks))
+	}
+	for _, track := range item.Tracks {
+		wantDisc, wantFolder := 1, "CD1"
+		if track.Filename == "01 - Third Song.flac" || track.Filename == "02 - Fourth Song.flac" {
+			wantDisc, wantFolder = 2, "CD2"
+		}
+		if track.MediumNumber != wantDisc || track.Folder != wantFolder {
+			t.Errorf("%s: got disc %d in %q, want disc %d in %q", track.Filename, track.MediumNumber, track.Folder, wantDisc, wantFolder)
+		}
+	}
+	if len(slskdClient.enqueued["user"]) != 4 {
+		t.Errorf("expected all 4 files enqueued, got %v", slskdClient.enqueued)
+	}
+}
@@ -0,0 +1,83 @@
+package processor
+
+import (
+	"errors"
+	"syscall"
+)
+
+// errInsufficientSpace defers an album whose files would not fit in the download directory
+var errInsufficientSpace = errors.New("not enough free disk space")
+
+// reserveSpace claims room in the download directory for files about to be enqueued
+// Bytes claimed earlier in the run count as used until they have landed, when
+// free space accounts for them. When free space cannot be read the check is skipped
+func (p *Processor) reserveSpace(album string, size int64) error {
+	p.spaceMu.Lock()
+	defer p.spaceMu.Unlock()
+
+	free, err := p.diskFree(p.downloadDir)
+	if err != nil {
+		p.logger.Warn("failed to check free disk space", "path", p.downloadDir, "error", err)
+		return nil
+	}
+
+	reserve := int64(p.cfg.Download.MinFreeSpaceMB) * 1024 * 1024
+	var written int64
+	for _, bytes := range p.writtenBytes {
+		written += bytes
+	}
+	available := int64(free) - reserve - max(p.reservedBytes-written, 0)
+	if size > available {
+		p.logger.Warn("deferring album, not enough free disk space",
+			"album", album,
+			"neededMB", size/(1024*1024),
+			"availableMB", max(available, 0)/(1024*1024),
+			"reserveMB", p.cfg.Download.MinFreeSpaceMB)
+		return errInsufficientSpace
+	}
+
+	p.reservedBytes += size
+	return nil
+}
+
+// releaseSpace returns room claimed for files that could not be enqueued
+func (p *Processor) releaseSpace(size int64) {
+	p.spaceMu.Lock()
+	defer p.spaceMu.Unlock()
+	p.reservedBytes -= size
+}
+
+// recordWritten records how many bytes of an album's files slskd has written
+// so far, so they stop counting against the room still claimed for it
+func (p *Processor) recordWritten(albumID int, files []sourceFile) {
+	var written int64
+	for _, file := range files {
+		written += file.BytesTransferred
+	}
+
+	p.spaceMu.Lock()
+	defer p.spaceMu.Unlock()
+	if p.writtenBytes == nil {
+		p.writtenBytes = make(map[int]int64)
+	}
+	p.writtenBytes[albumID] = written
+}
+
+// freeSpace returns the bytes available to unprivileged users on the filesystem holding path
+func freeSpace(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
+
+// resetReservedSpace forgets the space claimed and albums queued by an earlier run
+func (p *Processor) resetReservedSpace() {
+	p.spaceMu.Lock()
+	defer p.spaceMu.Unlock()
+	p.reservedBytes = 0
+	p.writtenBytes = nil
+	p.queuedAlbums = 0
+	p.budgetClaims = 0
+}
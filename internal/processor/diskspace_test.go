@@ -0,0 +1,64 @@
+package processor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/filter"
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+func TestQueueAlbum_DefersWhenDiskIsFull(t *testing.T) {
+	const mb = 1024 * 1024
+
+	tests := []struct {
+		name        string
+		free        uint64
+		reserveMB   int
+		queuedBytes int64
+		writtenMB   int64 // Of the queued bytes, already on disk
+		wantOutcome string
+	}{
+		{name: "fits", free: 500 * mb, reserveMB: 100, wantOutcome: OutcomeQueued},
+		{name: "reserve leaves too little room", free: 150 * mb, reserveMB: 100, wantOutcome: OutcomeDeferred},
+		{name: "earlier albums this run count as used", free: 500 * mb, reserveMB: 100, queuedBytes: 350 * mb, wantOutcome: OutcomeDeferred},
+		{name: "landed bytes of earlier albums count once", free: 500 * mb, reserveMB: 100, queuedBytes: 350 * mb, writtenMB: 300, wantOutcome: OutcomeQueued},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			album, tracks := candidateAlbum()
+			album.Releases = []lidarr.Release{{Status: "Official", TrackCount: 2}}
+			slskdClient := &mockSlskdClientWithResults{results: map[string][]slskd.SearchResult{
+				"Album": {albumResult("user", "flac", 900, 30*mb)},
+			}}
+			p := newTestProcessor(t, &mockLidarrClientWithAliases{tracks: tracks}, slskdClient)
+			p.filter = filter.NewFilter([]string{"flac"})
+			p.cfg.Download.MinFreeSpaceMB = tt.reserveMB
+			p.diskFree = func(string) (uint64, error) { return tt.free, nil }
+			p.reservedBytes = tt.queuedBytes
+			p.recordWritten(1, []sourceFile{{DownloadFile: slskd.DownloadFile{BytesTransferred: tt.writtenMB * mb}}})
+			p.current = &RunSummary{}
+
+			_, outcome := p.queueAlbum(context.Background(), album)
+			if outcome != tt.wantOutcome {
+				t.Fatalf("expected outcome %q, got %q", tt.wantOutcome, outcome)
+			}
+
+			if tt.wantOutcome == OutcomeDeferred {
+				if len(slskdClient.enqueued) != 0 {
+					t.Errorf("expected nothing enqueued, got %v", slskdClient.enqueued)
+				}
+				if entry := p.denylist.GetEntry(album.ID); entry != nil {
+					t.Errorf("expected no denylist failure, got %+v", entry)
+				}
+				if p.current.count(OutcomeDeferred) != 1 || p.current.Decisions[0].Reason != ReasonDiskSpace {
+					t.Errorf("expected a disk_space decision, got %+v", p.current.Decisions)
+				}
+			} else if p.reservedBytes != tt.queuedBytes+60*mb {
+				t.Errorf("expected the album's 60 MB reserved, got %d", p.reservedBytes-tt.queuedBytes)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package processor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// mockLidarrClientDryRun returns a fixed wanted list with the same tracks for every album
+type mockLidarrClientDryRun struct {
+	mockLidarrClientWithWanted
+	tracks []lidarr.Track
+}
+
+func (m *mockLidarrClientDryRun) GetTracks(ctx context.Context, albumID int, releaseID *int) ([]lidarr.Track, error) {
+	return m.tracks, nil
+}
+
+func TestRun_DryRun(t *testing.T) {
+	album, tracks := candidateAlbum()
+	missing := lidarr.Album{ID: 10, Title: "Missing", Monitored: true, Artist: lidarr.Artist{ArtistName: "Artist"}}
+	for _, a := range []*lidarr.Album{&album, &missing} {
+		a.Releases = []lidarr.Release{{Status: "Official", TrackCount: 2, MediumCount: 1}}
+	}
+
+	lidarrClient := &mockLidarrClientDryRun{
+		mockLidarrClientWithWanted: mockLidarrClientWithWanted{wanted: []lidarr.Album{album, missing}},
+		tracks:                     tracks,
+	}
+	slskdClient := &mockSlskdClientWithResults{results: map[string][]slskd.SearchResult{
+		"Album": {albumResult("user", "flac", 900, 30_000_000)},
+	}}
+	p := newTestProcessor(t, lidarrClient, slskdClient)
+	p.cfg.DryRun = true
+	p.cfg.Search.MaxSearchFailures = 1
+
+	if err := p.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	if len(slskdClient.enqueued) != 0 {
+		t.Errorf("expected nothing enqueued, got %v", slskdClient.enqueued)
+	}
+	if entry := p.denylist.GetEntry(missing.ID); entry != nil {
+		t.Errorf("expected denylist untouched, got %+v", entry)
+	}
+	if p.wishlist.Get(missing.ID) != nil {
+		t.Error("expected no wishlist entry in a dry run")
+	}
+
+	summary := p.Status().LastRun
+	if summary == nil {
+		t.Fatal("expected a run summary")
+	}
+	if !summary.DryRun || summary.WouldDownload != 1 || summary.Queued != 0 || summary.Failed != 1 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+	if summary.Decisions[0].Outcome != OutcomeWouldDownload && summary.Decisions[1].Outcome != OutcomeWouldDownload {
+		t.Errorf("expected a would_download decision, got %+v", summary.Decisions)
+	}
+}
@@ -0,0 +1,38 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// errFilesRejected is returned when slskd refused every file it was asked to enqueue
+var errFilesRejected = errors.New("slskd rejected every file")
+
+// enqueue queues files from a user in slskd, logging any it rejected. It only
+// fails when none of the files were queued
+func (p *Processor) enqueue(ctx context.Context, username string, files []slskd.EnqueueFile) (*slskd.EnqueueResult, error) {
+	result, err := p.slskd.EnqueueDownloads(ctx, username, files)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		result = &slskd.EnqueueResult{}
+	}
+	if len(result.Failed) == 0 {
+		return result, nil
+	}
+
+	for _, failure := range result.Failed {
+		p.logger.Warn("slskd rejected file",
+			"username", username,
+			"file", failure.Filename,
+			"reason", failure.Reason)
+	}
+	if len(result.Failed) >= len(files) {
+		return nil, fmt.Errorf("enqueue downloads for %s: %w", username, errFilesRejected)
+	}
+	return result, nil
+}
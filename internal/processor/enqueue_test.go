@@ -0,0 +1,52 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// mockSlskdClientRejecting refuses to enqueue the given files
+type mockSlskdClientRejecting struct {
+	mockSlskdClient
+	rejected map[string]bool
+}
+
+func (m *mockSlskdClientRejecting) EnqueueDownloads(ctx context.Context, username string, files []slskd.EnqueueFile) (*slskd.EnqueueResult, error) {
+	result := &slskd.EnqueueResult{}
+	for _, file := range files {
+		if m.rejected[file.Filename] {
+			result.Failed = append(result.Failed, slskd.EnqueueFailure{Filename: file.Filename, Reason: "File not shared."})
+			continue
+		}
+		result.Enqueued = append(result.Enqueued, slskd.DownloadFile{Filename: file.Filename, Size: file.Size})
+	}
+	return result, nil
+}
+
+func TestEnqueue_RejectedFiles(t *testing.T) {
+	files := []slskd.EnqueueFile{{Filename: "Music\\Album\\01.flac"}, {Filename: "Music\\Album\\02.flac"}}
+
+	tests := []struct {
+		name     string
+		rejected map[string]bool
+		wantErr  bool
+	}{
+		{name: "all accepted"},
+		{name: "some rejected", rejected: map[string]bool{"Music\\Album\\02.flac": true}},
+		{name: "all rejected", rejected: map[string]bool{"Music\\Album\\01.flac": true, "Music\\Album\\02.flac": true}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestProcessor(t, &mockLidarrClient{}, &mockSlskdClientRejecting{rejected: tt.rejected})
+
+			_, err := p.enqueue(context.Background(), "user", files)
+			if tt.wantErr != errors.Is(err, errFilesRejected) {
+				t.Errorf("enqueue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
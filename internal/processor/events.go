@@ -0,0 +1,95 @@
+package processor
+
+import (
+	"context"
+	"time"
+
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// transferEventWindow is how long a burst of transfer events is collected
+// before the downloads are checked again
+const transferEventWindow = 500 * time.Millisecond
+
+// transferFeed keeps a snapshot of slskd's downloads current from its transfer
+// events, so monitoring doesn't fetch every download on each poll
+type transferFeed struct {
+	events    <-chan slskd.TransferEvent
+	downloads slskd.DownloadsResponse
+	seeded    bool // downloads holds a fetched snapshot the events apply to
+}
+
+// subscribeTransfers follows slskd's transfer events, returning nil when they
+// aren't available and downloads have to be polled
+func (p *Processor) subscribeTransfers(ctx context.Context) *transferFeed {
+	events, err := p.slskd.SubscribeTransfers(ctx)
+	if err != nil {
+		p.logger.Debug("transfer events unavailable, polling downloads", "error", err)
+		return nil
+	}
+	p.logger.Debug("following slskd transfer events")
+	return &transferFeed{events: events}
+}
+
+// seed replaces the snapshot with freshly fetched downloads
+func (f *transferFeed) seed(downloads slskd.DownloadsResponse) {
+	f.downloads = downloads
+	f.seeded = true
+}
+
+// apply records a transfer change in the snapshot
+func (f *transferFeed) apply(event slskd.TransferEvent) {
+	userIdx := -1
+	for i, user := range f.downloads {
+		if user.Username == event.Username {
+			userIdx = i
+			break
+		}
+	}
+	if userIdx < 0 {
+		f.downloads = append(f.downloads, slskd.UserDownloads{Username: event.Username})
+		userIdx = len(f.downloads) - 1
+	}
+	user := &f.downloads[userIdx]
+
+	dirIdx := -1
+	for i, dir := range user.Directories {
+		if dir.Directory == event.Directory {
+			dirIdx = i
+			break
+		}
+	}
+	if dirIdx < 0 {
+		user.Directories = append(user.Directories, slskd.DirectoryDownloads{Directory: event.Directory})
+		dirIdx = len(user.Directories) - 1
+	}
+	dir := &user.Directories[dirIdx]
+
+	for i, file := range dir.Files {
+		if file.ID == event.File.ID {
+			dir.Files[i] = event.File
+			return
+		}
+	}
+	dir.Files = append(dir.Files, event.File)
+}
+
+// collect applies the events arriving within window, so a burst of progress
+// updates is checked in one pass. It reports false once the events have ended
+func (f *transferFeed) collect(ctx context.Context, window time.Duration) bool {
+	timer := time.NewTimer(window)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return true
+		case <-timer.C:
+			return true
+		case event, ok := <-f.events:
+			if !ok {
+				return false
+			}
+			f.apply(event)
+		}
+	}
+}
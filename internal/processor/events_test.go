@@ -0,0 +1,113 @@
+package processor
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// mockSlskdClientWithEvents lists one download in progress and pushes its
+// changes as transfer events
+type mockSlskdClientWithEvents struct {
+	mockSlskdClient
+	events chan slskd.TransferEvent
+	calls  atomic.Int32
+}
+
+func (m *mockSlskdClientWithEvents) SubscribeTransfers(ctx context.Context) (<-chan slskd.TransferEvent, error) {
+	return m.events, nil
+}
+
+func (m *mockSlskdClientWithEvents) GetDownloads(ctx context.Context) (slskd.DownloadsResponse, error) {
+	state := "InProgress"
+	if m.calls.Add(1) > 1 {
+		state = "Completed, Succeeded" // Polling picks up what the events would have said
+	}
+	return slskd.DownloadsResponse{{Username: "user", Directories: []slskd.DirectoryDownloads{{
+		Directory: `Music\Album`,
+		Files:     []slskd.DownloadFile{{ID: "1", Filename: `Music\Album\01.flac`, State: state}},
+	}}}}, nil
+}
+
+func TestMonitorDownloads_TransferEvents(t *testing.T) {
+	tests := []struct {
+		name      string
+		complete  bool // Send an event completing the file rather than ending the events
+		wantCalls int32
+	}{
+		{name: "completed by an event", complete: true, wantCalls: 1},
+		{name: "falls back to polling when events end", complete: false, wantCalls: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slskdClient := &mockSlskdClientWithEvents{events: make(chan slskd.TransferEvent)}
+			p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+			p.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+			p.cfg.Slskd.StalledTimeout = 600
+			p.cfg.Timing.DownloadPollSeconds = 60 // Only the events can finish the test in time
+			p.cfg.Timing.StallCheckIntervalSec = 60
+			p.cfg.Timing.StallChecks = 5
+			writeDownloadedFile(t, p, "Album", "01.flac", 0)
+
+			go func() {
+				if !tt.complete {
+					close(slskdClient.events)
+					return
+				}
+				slskdClient.events <- slskd.TransferEvent{
+					Username:  "user",
+					Directory: `Music\Album`,
+					File:      slskd.DownloadFile{ID: "1", Filename: `Music\Album\01.flac`, State: "Completed, Succeeded"},
+				}
+			}()
+
+			downloadList := []DownloadedItem{{AlbumID: 3, FolderName: "Album", Sources: []DownloadSource{{Username: "user", Directory: "Music/Album"}}}}
+			done := make(chan []DownloadedItem)
+			go func() {
+				succeeded, err := p.monitorDownloads(context.Background(), downloadList, nil, nil)
+				if err != nil {
+					t.Errorf("monitorDownloads() error: %v", err)
+				}
+				done <- succeeded
+			}()
+
+			select {
+			case succeeded := <-done:
+				if len(succeeded) != 1 {
+					t.Errorf("expected the album to complete, got %d completed", len(succeeded))
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("monitoring did not react to the transfer events")
+			}
+			if got := slskdClient.calls.Load(); got != tt.wantCalls {
+				t.Errorf("expected %d downloads fetches, got %d", tt.wantCalls, got)
+			}
+		})
+	}
+}
+
+func TestTransferFeed_Apply(t *testing.T) {
+	feed := &transferFeed{}
+	feed.seed(slskd.DownloadsResponse{{Username: "user", Directories: []slskd.DirectoryDownloads{{
+		Directory: `Music\Album`,
+		Files:     []slskd.DownloadFile{{ID: "1", State: "InProgress"}},
+	}}}})
+
+	feed.apply(slskd.TransferEvent{Username: "user", Directory: `Music\Album`, File: slskd.DownloadFile{ID: "1", State: "Completed, Succeeded"}})
+	feed.apply(slskd.TransferEvent{Username: "user", Directory: `Music\Album`, File: slskd.DownloadFile{ID: "2", State: "Queued, Remotely"}})
+	feed.apply(slskd.TransferEvent{Username: "other", Directory: `Share\Other`, File: slskd.DownloadFile{ID: "3", State: "InProgress"}})
+
+	files := feed.downloads[0].Directories[0].Files
+	if len(files) != 2 || files[0].State != "Completed, Succeeded" || files[1].ID != "2" {
+		t.Errorf("unexpected files for user: %+v", files)
+	}
+	if len(feed.downloads) != 2 || feed.downloads[1].Directories[0].Files[0].ID != "3" {
+		t.Errorf("expected a new user for the third transfer, got %+v", feed.downloads)
+	}
+}
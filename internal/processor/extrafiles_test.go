@@ -0,0 +1,54 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+func TestSearchForAlbum_MaxExtraFiles(t *testing.T) {
+	album, tracks := candidateAlbum()
+
+	// An expanded edition: the two album tracks plus three bonus tracks
+	box := albumResult("user", "flac", 900, 30_000_000)
+	for i := 3; i <= 5; i++ {
+		box.Files = append(box.Files, slskd.SearchFile{
+			Filename: fmt.Sprintf(`Music\Album\%02d - Bonus %d.flac`, i, i),
+			Size:     30_000_000,
+			BitRate:  intPtr(900),
+		})
+	}
+
+	tests := []struct {
+		name     string
+		limit    *int
+		expected int
+	}{
+		{"unset downloads the whole directory", nil, 5},
+		{"within the limit", intPtr(3), 5},
+		{"over the limit keeps matched files", intPtr(2), 2},
+		{"exact", intPtr(0), 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slskdClient := &mockSlskdClientWithResults{results: map[string][]slskd.SearchResult{"Album": {box}}}
+			p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+			p.cfg.Search.MaxExtraFiles = tt.limit
+
+			item, err := p.searchForAlbum(context.Background(), "Album", tracks, album, &lidarr.Release{MediumCount: 1})
+			if err != nil {
+				t.Fatalf("searchForAlbum() error: %v", err)
+			}
+			if len(item.Tracks) != tt.expected {
+				t.Errorf("expected %d tracks, got %d", tt.expected, len(item.Tracks))
+			}
+			if enqueued := slskdClient.enqueued["user"]; len(enqueued) != tt.expected {
+				t.Errorf("expected %d files enqueued, got %d", tt.expected, len(enqueued))
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package processor
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/yuritomanek/seekarr/internal/config"
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/matcher"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// Shares of the advertised data rate a real file reaches. Lossless audio
+// rarely compresses below this much of its stereo PCM size, and lossy files
+// stay close to the bitrate they report
+const (
+	minLosslessFraction = 0.15
+	minLossyFraction    = 0.5
+)
+
+// losslessExtensions are sized from bit depth and sample rate rather than bitrate
+var losslessExtensions = []string{"flac", "wav", "aif", "aiff", "ape", "wv", "alac"}
+
+// plausibleSize estimates the smallest size a file can have for the quality and
+// length it advertises, or 0 when it doesn't report enough to tell
+func plausibleSize(file slskd.SearchFile) int64 {
+	if file.Length == nil || *file.Length <= 0 {
+		return 0
+	}
+	seconds := float64(*file.Length)
+
+	if slices.Contains(losslessExtensions, fileExtension(file.Filename)) {
+		if file.BitDepth == nil || file.SampleRate == nil {
+			return 0
+		}
+		pcmBytes := float64(*file.BitDepth) * float64(*file.SampleRate) * 2 / 8 * seconds
+		return int64(pcmBytes * minLosslessFraction)
+	}
+
+	if file.BitRate == nil || *file.BitRate <= 0 {
+		return 0
+	}
+	return int64(float64(*file.BitRate) * 1000 / 8 * seconds * minLossyFraction)
+}
+
+// minFileSize returns the size below which a file is taken to be fake, and
+// whether min_file_size_kb or the quality estimate set it
+func (p *Processor) minFileSize(file slskd.SearchFile) (int64, string) {
+	floor := int64(p.cfg.Download.MinFileSizeKB[fileExtension(file.Filename)]) * 1024
+	if estimate := plausibleSize(file); estimate > floor {
+		return estimate, "advertised quality"
+	}
+	if floor > 0 {
+		return floor, "min_file_size_kb"
+	}
+	return 0, ""
+}
+
+// dropImplausibleFiles leaves out files too small for what they claim to be
+func (p *Processor) dropImplausibleFiles(album lidarr.Album, username string, files []slskd.SearchFile) []slskd.SearchFile {
+	var kept []slskd.SearchFile
+	for _, file := range files {
+		if threshold, source := p.minFileSize(file); file.Size < threshold {
+			p.logger.Debug("dropping implausibly small file",
+				"album", album.Title,
+				"username", username,
+				"file", file.Filename,
+				"size", file.Size,
+				"threshold", threshold,
+				"thresholdFrom", source)
+			continue
+		}
+		kept = append(kept, file)
+	}
+	return kept
+}
+
+// impliedBitrate returns the average bitrate in kbps of the files matched to
+// tracks, taken from their sizes and the tracks' Lidarr durations. ok is false
+// when no file matched or a matched track has no duration
+func impliedBitrate(files []slskd.SearchFile, tracks []lidarr.Track, matchInfo []matcher.TrackMatchInfo) (kbps int, ok bool) {
+	matched := make(map[string]bool, len(matchInfo))
+	var durationMs int64
+	for i, info := range matchInfo {
+		if !info.Matched || i >= len(tracks) {
+			continue
+		}
+		if tracks[i].Duration <= 0 {
+			return 0, false
+		}
+		matched[info.BestMatch] = true
+		durationMs += int64(tracks[i].Duration)
+	}
+
+	var size int64
+	for _, file := range files {
+		if matched[filepath.Base(strings.ReplaceAll(file.Filename, "\\", "/"))] {
+			size += file.Size
+		}
+	}
+	if durationMs == 0 || size == 0 {
+		return 0, false
+	}
+	return int(size * 8 / durationMs), true // bits per millisecond are kbps
+}
+
+// implausibleBitrate reports the average bitrate of a matched directory when it
+// falls outside plausible_bitrate_kbps for the files' format
+func (p *Processor) implausibleBitrate(files []slskd.SearchFile, tracks []lidarr.Track, matchInfo []matcher.TrackMatchInfo) (kbps int, window config.BitrateRange, implausible bool) {
+	if len(files) == 0 {
+		return 0, window, false
+	}
+	window, ok := p.cfg.Download.PlausibleBitrateKbps[fileExtension(files[0].Filename)]
+	if !ok {
+		return 0, window, false
+	}
+	kbps, ok = impliedBitrate(files, tracks, matchInfo)
+	if !ok {
+		return 0, window, false
+	}
+	return kbps, window, (window.Min > 0 && kbps < window.Min) || (window.Max > 0 && kbps > window.Max)
+}
+
+// fileExtension returns a filename's extension, lowercased and without the dot
+func fileExtension(filename string) string {
+	return strings.TrimPrefix(strings.ToLower(filepath.Ext(filename)), ".")
+}
@@ -0,0 +1,125 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/config"
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+func TestPlausibleSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     slskd.SearchFile
+		expected int64
+	}{
+		{
+			name:     "lossless from depth and sample rate",
+			file:     slskd.SearchFile{Filename: "a.flac", BitDepth: intPtr(24), SampleRate: intPtr(96000), Length: intPtr(100)},
+			expected: 8_640_000, // 15% of 57.6MB of PCM
+		},
+		{
+			name:     "lossy from bitrate",
+			file:     slskd.SearchFile{Filename: "a.MP3", BitRate: intPtr(320), Length: intPtr(100)},
+			expected: 2_000_000,
+		},
+		{
+			name: "unknown length",
+			file: slskd.SearchFile{Filename: "a.flac", BitDepth: intPtr(24), SampleRate: intPtr(96000)},
+		},
+		{
+			name: "lossless without depth",
+			file: slskd.SearchFile{Filename: "a.flac", BitRate: intPtr(900), Length: intPtr(100)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := plausibleSize(tt.file); got != tt.expected {
+				t.Errorf("plausibleSize() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMinFileSize(t *testing.T) {
+	p := newTestProcessor(t, &mockLidarrClient{}, &mockSlskdClient{})
+	p.cfg.Download.MinFileSizeKB = map[string]int{"mp3": 1000}
+
+	// The larger of the floor and the estimate wins
+	small := slskd.SearchFile{Filename: "a.mp3", BitRate: intPtr(128), Length: intPtr(10)}
+	if threshold, source := p.minFileSize(small); threshold != 1000*1024 || source != "min_file_size_kb" {
+		t.Errorf("expected the extension floor, got %d from %q", threshold, source)
+	}
+	long := slskd.SearchFile{Filename: "a.mp3", BitRate: intPtr(320), Length: intPtr(600)}
+	if threshold, source := p.minFileSize(long); threshold != 12_000_000 || source != "advertised quality" {
+		t.Errorf("expected the estimate, got %d from %q", threshold, source)
+	}
+	if threshold, _ := p.minFileSize(slskd.SearchFile{Filename: "a.ogg"}); threshold != 0 {
+		t.Errorf("expected no threshold, got %d", threshold)
+	}
+}
+
+func TestSearchForAlbum_IgnoresFakeFiles(t *testing.T) {
+	album, tracks := candidateAlbum()
+
+	fake := slskd.SearchResult{Username: "faker", Files: []slskd.SearchFile{
+		{Filename: `Music\Album\01 - First Song.flac`, Size: 200_000, BitDepth: intPtr(24), SampleRate: intPtr(96000), Length: intPtr(240)},
+		{Filename: `Music\Album\02 - Second Song.flac`, Size: 200_000, BitDepth: intPtr(24), SampleRate: intPtr(96000), Length: intPtr(240)},
+	}}
+	slskdClient := &mockSlskdClientWithResults{results: map[string][]slskd.SearchResult{"Album": {fake}}}
+	p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+
+	_, err := p.searchForAlbum(context.Background(), "Album", tracks, album, &lidarr.Release{MediumCount: 1})
+	if !errors.Is(err, errNoMatch) {
+		t.Fatalf("expected errNoMatch, got %v", err)
+	}
+
+	// The same files at a believable size match
+	for i := range fake.Files {
+		fake.Files[i].Size = 30_000_000
+	}
+	if _, err := p.searchForAlbum(context.Background(), "Album", tracks, album, &lidarr.Release{MediumCount: 1}); err != nil {
+		t.Errorf("searchForAlbum() error: %v", err)
+	}
+}
+
+func TestSearchForAlbum_PlausibleBitrate(t *testing.T) {
+	album, tracks := candidateAlbum()
+	for i := range tracks {
+		tracks[i].Duration = 200_000 // 200 seconds
+	}
+
+	tests := []struct {
+		name    string
+		size    int64
+		window  config.BitrateRange
+		matched bool
+	}{
+		{"lossless sized", 20_000_000, config.BitrateRange{Min: 500}, true}, // 800 kbps
+		{"transcode", 5_000_000, config.BitrateRange{Min: 500}, false},      // 200 kbps
+		{"oversized", 300_000_000, config.BitrateRange{Max: 10_000}, false}, // 12000 kbps
+		{"unbounded", 5_000_000, config.BitrateRange{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slskdClient := &mockSlskdClientWithResults{results: map[string][]slskd.SearchResult{
+				"Album": {albumResult("user", "flac", 900, tt.size)},
+			}}
+			p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+			p.cfg.Download.PlausibleBitrateKbps = map[string]config.BitrateRange{"flac": tt.window}
+
+			_, err := p.searchForAlbum(context.Background(), "Album", tracks, album, &lidarr.Release{MediumCount: 1})
+			if tt.matched && err != nil {
+				t.Errorf("expected a match, got %v", err)
+			}
+			if !tt.matched && !errors.Is(err, errNoMatch) {
+				t.Errorf("expected errNoMatch, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package processor
+
+import (
+	"context"
+	"time"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+)
+
+// recentHistoryEvents are the Lidarr history events that mean an album was
+// taken care of by another download client
+var recentHistoryEvents = []string{lidarr.HistoryGrabbed, lidarr.HistoryDownloadImported}
+
+// historySince returns how far back Lidarr's history is checked: to the end of
+// the previous run, so the imports seekarr triggered itself aren't mistaken for
+// another client's, but no further than recent_history_hours
+func (p *Processor) historySince(now time.Time) time.Time {
+	since := now.Add(-time.Duration(p.cfg.Search.RecentHistoryHours) * time.Hour)
+	if last := p.runEnds.At(); last.After(since) {
+		return last
+	}
+	return since
+}
+
+// recentlyHandledAlbums returns the IDs of albums Lidarr grabbed or imported
+// since historySince, with the event that was seen
+// Nothing is returned with recent_history_hours unset or if the history
+// can't be fetched
+func (p *Processor) recentlyHandledAlbums(ctx context.Context) map[int]string {
+	if p.cfg.Search.RecentHistoryHours <= 0 {
+		return nil
+	}
+
+	since := p.historySince(time.Now())
+	handled := make(map[int]string)
+	for _, eventType := range recentHistoryEvents {
+		records, err := p.lidarr.GetHistorySince(ctx, since, eventType)
+		if err != nil {
+			p.logger.Warn("failed to fetch lidarr history, skipping history filtering", "eventType", eventType, "error", err)
+			continue
+		}
+		for _, record := range records {
+			if record.AlbumID > 0 {
+				handled[record.AlbumID] = record.EventType
+			}
+		}
+	}
+
+	p.logger.Debug("checked lidarr history", "since", since.Format(time.RFC3339), "albums", len(handled))
+	return handled
+}
@@ -0,0 +1,86 @@
+package processor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+)
+
+// mockLidarrClientWithHistory reports history events by type
+type mockLidarrClientWithHistory struct {
+	mockLidarrClient
+	events map[string][]lidarr.HistoryRecord
+	since  []time.Time
+}
+
+func (m *mockLidarrClientWithHistory) GetHistorySince(ctx context.Context, since time.Time, eventType string) ([]lidarr.HistoryRecord, error) {
+	m.since = append(m.since, since)
+	return m.events[eventType], nil
+}
+
+func TestFilterQueuedAlbums_RecentHistory(t *testing.T) {
+	albums := []lidarr.Album{{ID: 1, Title: "Grabbed"}, {ID: 2, Title: "Imported"}, {ID: 3, Title: "Wanted"}}
+	lidarrClient := &mockLidarrClientWithHistory{events: map[string][]lidarr.HistoryRecord{
+		lidarr.HistoryGrabbed:          {{AlbumID: 1, EventType: lidarr.HistoryGrabbed}},
+		lidarr.HistoryDownloadImported: {{AlbumID: 2, EventType: lidarr.HistoryDownloadImported}},
+	}}
+
+	tests := []struct {
+		name  string
+		hours int
+		want  []int
+	}{
+		{"history not checked", 0, []int{1, 2, 3}},
+		{"grabbed and imported albums skipped", 24, []int{3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestProcessor(t, lidarrClient, &mockSlskdClient{})
+			p.cfg.Search.RecentHistoryHours = tt.hours
+
+			filtered, err := p.filterQueuedAlbums(context.Background(), albums)
+			if err != nil {
+				t.Fatalf("filterQueuedAlbums() error: %v", err)
+			}
+			var ids []int
+			for _, album := range filtered {
+				ids = append(ids, album.ID)
+			}
+			if len(ids) != len(tt.want) || (len(ids) > 0 && ids[0] != tt.want[0]) {
+				t.Errorf("expected albums %v, got %v", tt.want, ids)
+			}
+		})
+	}
+}
+
+func TestHistorySince(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		lastRun time.Time
+		want    time.Time
+	}{
+		{"no previous run", time.Time{}, now.Add(-24 * time.Hour)},
+		{"previous run within the window", now.Add(-time.Hour), now.Add(-time.Hour)},
+		{"previous run before the window", now.Add(-72 * time.Hour), now.Add(-24 * time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestProcessor(t, &mockLidarrClient{}, &mockSlskdClient{})
+			p.cfg.Search.RecentHistoryHours = 24
+			if !tt.lastRun.IsZero() {
+				if err := p.runEnds.Record(tt.lastRun); err != nil {
+					t.Fatalf("Record() error: %v", err)
+				}
+			}
+
+			if got := p.historySince(now); !got.Equal(tt.want) {
+				t.Errorf("historySince() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
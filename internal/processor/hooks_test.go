@@ -0,0 +1,54 @@
+package processor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yuritomanek/seekarr/internal/hooks"
+)
+
+func TestTriggerImport_RunsPostImportHook(t *testing.T) {
+	p := newTestProcessor(t, &mockLidarrClientRecordingCommands{}, &mockSlskdClient{})
+	p.cfg.Timing.ImportPollSeconds = 1
+	p.cfg.Daemon.DeleteAfterImport = true
+	p.cfg.Daemon.DeleteImportedFiles = true
+	albumDir := filepath.Join(p.cfg.Slskd.DownloadDir, "Artist", "Album")
+	if err := os.MkdirAll(albumDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(t.TempDir(), "hook.txt")
+	script := filepath.Join(t.TempDir(), "hook.sh")
+	body := "#!/bin/sh\n[ -d \"$SEEKARR_PATH\" ] && found=present || found=missing\necho \"$SEEKARR_ALBUM_ID $SEEKARR_QUALITY $SEEKARR_PATH $found\" >> " + out + "\n"
+	if err := os.WriteFile(script, []byte(body), 0755); err != nil {
+		t.Fatal(err)
+	}
+	p.hooks = hooks.NewRunner([]string{script}, nil, 5*time.Second, p.logger)
+
+	downloads := []DownloadedItem{{
+		ArtistName: "Artist",
+		AlbumName:  "Album",
+		AlbumID:    9,
+		Sources:    []DownloadSource{{Username: "user", Directory: "Music/Album"}},
+		Quality:    "flac",
+	}}
+	if err := p.triggerImport(context.Background(), downloads); err != nil {
+		t.Fatalf("triggerImport() error: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("post-import hook did not run: %v", err)
+	}
+	want := "9 flac " + albumDir + " present"
+	if got := strings.TrimSpace(string(data)); got != want {
+		t.Errorf("hook saw %q, want %q", got, want)
+	}
+	if _, err := os.Stat(albumDir); !os.IsNotExist(err) {
+		t.Errorf("expected the album folder deleted after the hook, got %v", err)
+	}
+}
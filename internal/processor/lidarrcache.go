@@ -0,0 +1,85 @@
+package processor
+
+import (
+	"context"
+	"slices"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+)
+
+// maxCachedLidarrEntries bounds how many albums, and separately how many
+// track lists, are kept per run
+const maxCachedLidarrEntries = 500
+
+// trackListKey identifies a track list: an album's, or one of its releases'
+type trackListKey struct {
+	albumID   int
+	releaseID int // 0 for Lidarr's default release
+}
+
+// lidarrAlbum returns an album with its releases, fetching it once per run
+func (p *Processor) lidarrAlbum(ctx context.Context, id int) (*lidarr.Album, error) {
+	p.lidarrCacheMu.Lock()
+	album, ok := p.cachedAlbums[id]
+	p.lidarrCacheMu.Unlock()
+	if ok {
+		p.logger.Debug("lidarr cache hit", "albumID", id)
+		return &album, nil
+	}
+
+	fetched, err := p.lidarr.GetAlbum(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	p.lidarrCacheMu.Lock()
+	defer p.lidarrCacheMu.Unlock()
+	if p.cachedAlbums == nil {
+		p.cachedAlbums = make(map[int]lidarr.Album)
+	}
+	if len(p.cachedAlbums) < maxCachedLidarrEntries {
+		p.cachedAlbums[id] = *fetched
+	}
+	album = *fetched // Callers may change their copy
+	return &album, nil
+}
+
+// lidarrTracks returns the track list of an album's release, or of its
+// default release if releaseID is 0, fetching it once per run
+func (p *Processor) lidarrTracks(ctx context.Context, albumID, releaseID int) ([]lidarr.Track, error) {
+	key := trackListKey{albumID: albumID, releaseID: releaseID}
+	p.lidarrCacheMu.Lock()
+	tracks, ok := p.cachedTracks[key]
+	p.lidarrCacheMu.Unlock()
+	if ok {
+		p.logger.Debug("lidarr cache hit", "albumID", albumID, "releaseID", releaseID, "tracks", len(tracks))
+		return slices.Clone(tracks), nil
+	}
+
+	var release *int
+	if releaseID != 0 {
+		release = &releaseID
+	}
+	tracks, err := p.lidarr.GetTracks(ctx, albumID, release)
+	if err != nil {
+		return nil, err
+	}
+
+	p.lidarrCacheMu.Lock()
+	defer p.lidarrCacheMu.Unlock()
+	if p.cachedTracks == nil {
+		p.cachedTracks = make(map[trackListKey][]lidarr.Track)
+	}
+	if len(p.cachedTracks) < maxCachedLidarrEntries {
+		p.cachedTracks[key] = tracks
+	}
+	return slices.Clone(tracks), nil
+}
+
+// resetLidarrCache makes the next run fetch albums and track lists again
+func (p *Processor) resetLidarrCache() {
+	p.lidarrCacheMu.Lock()
+	defer p.lidarrCacheMu.Unlock()
+	p.cachedAlbums = nil
+	p.cachedTracks = nil
+}
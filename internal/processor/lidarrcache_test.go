@@ -0,0 +1,124 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+)
+
+// mockLidarrClientCounting counts album and track list fetches
+type mockLidarrClientCounting struct {
+	mockLidarrClient
+	albumFetches int
+	trackFetches map[int]int // By release ID, 0 for the default release
+	err          error
+}
+
+func (m *mockLidarrClientCounting) GetAlbum(ctx context.Context, id int) (*lidarr.Album, error) {
+	m.albumFetches++
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &lidarr.Album{ID: id, Title: "Album", Releases: []lidarr.Release{{ID: 4, TrackCount: 2}}}, nil
+}
+
+func (m *mockLidarrClientCounting) GetTracks(ctx context.Context, albumID int, releaseID *int) ([]lidarr.Track, error) {
+	if m.trackFetches == nil {
+		m.trackFetches = make(map[int]int)
+	}
+	key := 0
+	if releaseID != nil {
+		key = *releaseID
+	}
+	m.trackFetches[key]++
+	return []lidarr.Track{{ID: 1, Title: "One"}, {ID: 2, Title: "Two"}}, nil
+}
+
+func TestLidarrCache_Albums(t *testing.T) {
+	lidarrClient := &mockLidarrClientCounting{}
+	p := newTestProcessor(t, lidarrClient, &mockSlskdClient{})
+	ctx := context.Background()
+
+	first, err := p.lidarrAlbum(ctx, 9)
+	if err != nil {
+		t.Fatalf("lidarrAlbum() error: %v", err)
+	}
+	first.Monitored = true // Changing a copy leaves the cached album alone
+
+	second, err := p.lidarrAlbum(ctx, 9)
+	if err != nil {
+		t.Fatalf("lidarrAlbum() error: %v", err)
+	}
+	if lidarrClient.albumFetches != 1 {
+		t.Errorf("expected 1 fetch, got %d", lidarrClient.albumFetches)
+	}
+	if second.Monitored || len(second.Releases) != 1 {
+		t.Errorf("unexpected cached album: %+v", second)
+	}
+
+	p.resetLidarrCache()
+	if _, err := p.lidarrAlbum(ctx, 9); err != nil {
+		t.Fatalf("lidarrAlbum() error: %v", err)
+	}
+	if lidarrClient.albumFetches != 2 {
+		t.Errorf("expected the album fetched again after a reset, got %d fetches", lidarrClient.albumFetches)
+	}
+}
+
+func TestLidarrCache_FailuresNotCached(t *testing.T) {
+	lidarrClient := &mockLidarrClientCounting{err: errors.New("connection refused")}
+	p := newTestProcessor(t, lidarrClient, &mockSlskdClient{})
+
+	for range 2 {
+		if _, err := p.lidarrAlbum(context.Background(), 9); err == nil {
+			t.Fatal("expected an error")
+		}
+	}
+	if lidarrClient.albumFetches != 2 {
+		t.Errorf("expected each attempt to fetch, got %d fetches", lidarrClient.albumFetches)
+	}
+}
+
+func TestLidarrCache_Tracks(t *testing.T) {
+	lidarrClient := &mockLidarrClientCounting{}
+	p := newTestProcessor(t, lidarrClient, &mockSlskdClient{})
+	ctx := context.Background()
+
+	for range 3 {
+		tracks, err := p.lidarrTracks(ctx, 9, 4)
+		if err != nil {
+			t.Fatalf("lidarrTracks() error: %v", err)
+		}
+		tracks[0].Title = "Changed" // Changing a copy leaves the cached list alone
+	}
+	tracks, err := p.lidarrTracks(ctx, 9, 0)
+	if err != nil {
+		t.Fatalf("lidarrTracks() error: %v", err)
+	}
+
+	if lidarrClient.trackFetches[4] != 1 || lidarrClient.trackFetches[0] != 1 {
+		t.Errorf("expected each track list fetched once, got %v", lidarrClient.trackFetches)
+	}
+	if cached, _ := p.lidarrTracks(ctx, 9, 4); cached[0].Title != "One" {
+		t.Errorf("expected the cached track list unchanged, got %q", cached[0].Title)
+	}
+	if tracks[0].Title != "One" {
+		t.Errorf("unexpected default release tracks: %+v", tracks)
+	}
+}
+
+func TestLidarrCache_Bounded(t *testing.T) {
+	lidarrClient := &mockLidarrClientCounting{}
+	p := newTestProcessor(t, lidarrClient, &mockSlskdClient{})
+
+	for id := 1; id <= maxCachedLidarrEntries+10; id++ {
+		if _, err := p.lidarrAlbum(context.Background(), id); err != nil {
+			t.Fatalf("lidarrAlbum() error: %v", err)
+		}
+	}
+	if len(p.cachedAlbums) != maxCachedLidarrEntries {
+		t.Errorf("expected %d cached albums, got %d", maxCachedLidarrEntries, len(p.cachedAlbums))
+	}
+}
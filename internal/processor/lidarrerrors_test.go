@@ -0,0 +1,81 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+)
+
+// mockLidarrClientStatus fails every album lookup with an HTTP status
+type mockLidarrClientStatus struct {
+	mockLidarrClient
+	status  int
+	lookups int
+}
+
+func (m *mockLidarrClientStatus) GetAlbum(ctx context.Context, id int) (*lidarr.Album, error) {
+	m.lookups++
+	return nil, &lidarr.StatusError{Code: m.status}
+}
+
+func TestSearchAndQueueDownloads_LidarrStatusErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		wantReasons []string
+		wantLookups int
+		wantErr     error
+	}{
+		{
+			name:        "album deleted",
+			status:      http.StatusNotFound,
+			wantReasons: []string{ReasonNotInLidarr, ReasonNotInLidarr},
+			wantLookups: 2,
+		},
+		{
+			name:        "api key rejected",
+			status:      http.StatusUnauthorized,
+			wantReasons: []string{ReasonUnavailable, ReasonUnavailable},
+			wantLookups: 1,
+			wantErr:     errLidarrRejected,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lidarrClient := &mockLidarrClientStatus{status: tt.status}
+			p := newTestProcessor(t, lidarrClient, &mockSlskdClient{})
+			p.cfg.Search.ConcurrentSearches = 1
+			p.current = &RunSummary{}
+
+			albums := concurrentAlbums(2)
+			for i := range albums {
+				albums[i].Releases = nil // Fetched from Lidarr
+			}
+			p.searchAndQueueDownloads(context.Background(), albums, nil)
+
+			if lidarrClient.lookups != tt.wantLookups {
+				t.Errorf("expected %d album lookups, got %d", tt.wantLookups, lidarrClient.lookups)
+			}
+			if len(p.current.Decisions) != len(tt.wantReasons) {
+				t.Fatalf("expected %d decisions, got %+v", len(tt.wantReasons), p.current.Decisions)
+			}
+			for i, decision := range p.current.Decisions {
+				if decision.Reason != tt.wantReasons[i] {
+					t.Errorf("album %d: expected reason %s, got %s", decision.AlbumID, tt.wantReasons[i], decision.Reason)
+				}
+			}
+			for _, album := range albums {
+				if entry := p.denylist.GetEntry(album.ID); entry != nil {
+					t.Errorf("expected no denylist entry for album %d, got %+v", album.ID, entry)
+				}
+			}
+			if err := p.lidarrRejectedErr(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("lidarrRejectedErr() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package processor
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+)
+
+// manualImportCommand is the name of the command PostManualImport starts
+const manualImportCommand = "ManualImport"
+
+// manualImport asks Lidarr which files in an organized album folder it would
+// import for the album and imports those, logging each rejected file. When
+// every file is rejected the folder is moved to failed_imports and no command
+// is returned
+func (p *Processor) manualImport(ctx context.Context, path string, albumID int, downloads []downloadCleanupInfo) (*lidarr.CommandResponse, error) {
+	items, err := p.lidarr.GetManualImport(ctx, path, albumID)
+	if err != nil {
+		return nil, err
+	}
+
+	var accepted []lidarr.ManualImportItem
+	var reasons []string
+	for _, item := range items {
+		reason := manualImportRejection(item)
+		if reason == "" {
+			accepted = append(accepted, item)
+			continue
+		}
+		p.logger.Warn("lidarr rejected file", "albumID", albumID, "file", item.Name, "reason", reason)
+		if !slices.Contains(reasons, reason) {
+			reasons = append(reasons, reason)
+		}
+	}
+
+	if len(accepted) == 0 {
+		if len(reasons) == 0 {
+			reasons = append(reasons, "no files found")
+		}
+		p.logger.Warn("import failed", "albumID", albumID, "path", path, "reason", strings.Join(reasons, "; "))
+		p.moveFailedImports(strings.Join(reasons, "; "), downloads)
+		return nil, nil
+	}
+
+	p.logger.Debug("importing accepted files", "albumID", albumID, "accepted", len(accepted), "rejected", len(items)-len(accepted))
+	return p.lidarr.PostManualImport(ctx, accepted)
+}
+
+// manualImportRejection returns why Lidarr won't import a file, empty if it will
+func manualImportRejection(item lidarr.ManualImportItem) string {
+	if len(item.Rejections) > 0 {
+		reasons := make([]string, len(item.Rejections))
+		for i, rejection := range item.Rejections {
+			reasons[i] = rejection.Reason
+		}
+		return strings.Join(reasons, ", ")
+	}
+	if item.Album == nil || len(item.Tracks) == 0 {
+		return "not matched to any track"
+	}
+	return ""
+}
@@ -0,0 +1,109 @@
+package processor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+)
+
+// mockLidarrClientManualImport proposes files per album and records imports
+type mockLidarrClientManualImport struct {
+	mockLidarrClient
+	items    map[int][]lidarr.ManualImportItem
+	folders  []string
+	imported [][]lidarr.ManualImportItem
+}
+
+func (m *mockLidarrClientManualImport) GetManualImport(ctx context.Context, folder string, albumID int) ([]lidarr.ManualImportItem, error) {
+	m.folders = append(m.folders, folder)
+	return m.items[albumID], nil
+}
+
+func (m *mockLidarrClientManualImport) PostManualImport(ctx context.Context, items []lidarr.ManualImportItem) (*lidarr.CommandResponse, error) {
+	m.imported = append(m.imported, items)
+	return &lidarr.CommandResponse{ID: len(m.imported), Name: manualImportCommand}, nil
+}
+
+func (m *mockLidarrClientManualImport) GetCommand(ctx context.Context, id int) (*lidarr.CommandResponse, error) {
+	return &lidarr.CommandResponse{ID: id, Name: manualImportCommand, Status: "completed", Message: "Failed to import 1 of 2 files"}, nil
+}
+
+func TestTriggerImport_ManualImport(t *testing.T) {
+	matched := func(name string) lidarr.ManualImportItem {
+		return lidarr.ManualImportItem{Name: name, Album: &lidarr.Album{ID: 1}, Tracks: []lidarr.Track{{ID: 1}}}
+	}
+	rejected := func(name, reason string) lidarr.ManualImportItem {
+		item := matched(name)
+		item.Rejections = []lidarr.Rejection{{Reason: reason, Type: "permanent"}}
+		return item
+	}
+	lidarrClient := &mockLidarrClientManualImport{items: map[int][]lidarr.ManualImportItem{
+		1: {matched("01 - First"), rejected("02 - Second", "Not an upgrade")},
+		2: {rejected("01 - Other", "Has unknown tracks"), {Name: "cover"}},
+	}}
+	p := newTestProcessor(t, lidarrClient, &mockSlskdClient{})
+	p.cfg.Lidarr.ImportMode = "manual"
+	p.cfg.Timing.ImportPollSeconds = 1
+	p.current = &RunSummary{Decisions: []AlbumDecision{{AlbumID: 1}, {AlbumID: 2}}}
+
+	downloads := []DownloadedItem{
+		{ArtistName: "Artist", AlbumName: "First", AlbumID: 1, Sources: []DownloadSource{{Username: "user", Directory: "Music/First"}}},
+		{ArtistName: "Artist", AlbumName: "Second", AlbumID: 2, Sources: []DownloadSource{{Username: "user", Directory: "Music/Second"}}},
+	}
+	for _, item := range downloads {
+		if err := os.MkdirAll(p.albumDir(item), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := p.triggerImport(context.Background(), downloads); err != nil {
+		t.Fatalf("triggerImport() error: %v", err)
+	}
+
+	if len(lidarrClient.folders) != 2 {
+		t.Fatalf("expected both folders proposed, got %v", lidarrClient.folders)
+	}
+	if len(lidarrClient.imported) != 1 || len(lidarrClient.imported[0]) != 1 || lidarrClient.imported[0][0].Name != "01 - First" {
+		t.Fatalf("expected only the accepted file imported, got %+v", lidarrClient.imported)
+	}
+
+	// The partly rejected album was imported and kept in place
+	if got := p.current.Decisions[0]; got.Outcome != OutcomeImported {
+		t.Errorf("expected the first album imported, got %+v", got)
+	}
+	if _, err := os.Stat(p.albumDir(downloads[0])); err != nil {
+		t.Errorf("expected the imported folder to stay: %v", err)
+	}
+
+	// The fully rejected album was moved aside
+	if got := p.current.Decisions[1]; got.Outcome != OutcomeFailed || got.Reason != ReasonImportFailed {
+		t.Errorf("expected the second album failed, got %+v", got)
+	}
+	if _, err := os.Stat(filepath.Join(p.cfg.Slskd.DownloadDir, "failed_imports", "Second")); err != nil {
+		t.Errorf("expected the rejected folder in failed_imports: %v", err)
+	}
+}
+
+func TestImportSucceeded(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  lidarr.CommandResponse
+		want bool
+	}{
+		{"scan completed", lidarr.CommandResponse{Name: "DownloadedAlbumsScan", Status: "completed"}, true},
+		{"scan completed with failures", lidarr.CommandResponse{Name: "DownloadedAlbumsScan", Status: "completed", Message: "Failed to import"}, false},
+		{"manual import completed", lidarr.CommandResponse{Name: manualImportCommand, Status: "completed", Message: "Failed to import 1 file"}, true},
+		{"manual import failed", lidarr.CommandResponse{Name: manualImportCommand, Status: "failed"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := importSucceeded(&tt.cmd); got != tt.want {
+				t.Errorf("importSucceeded() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
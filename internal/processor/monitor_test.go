@@ -0,0 +1,165 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// mockSlskdClientCountingDownloads counts GetDownloads calls, failing the first few
+type mockSlskdClientCountingDownloads struct {
+	mockSlskdClient
+	users    []string
+	failures int
+	calls    int
+}
+
+func (m *mockSlskdClientCountingDownloads) GetDownloads(ctx context.Context) (slskd.DownloadsResponse, error) {
+	m.calls++
+	if m.calls <= m.failures {
+		return nil, errors.New("slskd unavailable")
+	}
+
+	var response slskd.DownloadsResponse
+	for _, user := range m.users {
+		response = append(response, slskd.UserDownloads{Username: user, Directories: []slskd.DirectoryDownloads{{
+			Directory: `Music\` + user,
+			Files:     []slskd.DownloadFile{{ID: user + "-1", Filename: `Music\` + user + `\01.flac`, State: "Completed, Succeeded"}},
+		}}})
+	}
+	return response, nil
+}
+
+func TestMonitorDownloads_FetchesOncePerPoll(t *testing.T) {
+	tests := []struct {
+		name      string
+		failures  int
+		wantCalls int
+	}{
+		{name: "one call for every pending item", failures: 0, wantCalls: 1},
+		{name: "retries after a failed fetch", failures: 2, wantCalls: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var downloadList []DownloadedItem
+			var users []string
+			for i := 1; i <= 5; i++ {
+				user := fmt.Sprintf("user%d", i)
+				users = append(users, user)
+				downloadList = append(downloadList, DownloadedItem{
+					AlbumID:    i,
+					FolderName: user,
+					Sources:    []DownloadSource{{Username: user, Directory: "Music/" + user}},
+				})
+			}
+
+			slskdClient := &mockSlskdClientCountingDownloads{users: users, failures: tt.failures}
+			p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+			p.cfg.Slskd.StalledTimeout = 60
+			for _, user := range users {
+				writeDownloadedFile(t, p, user, "01.flac", 0)
+			}
+
+			succeeded, err := p.monitorDownloads(context.Background(), downloadList, nil, nil)
+			if err != nil {
+				t.Fatalf("monitorDownloads() error: %v", err)
+			}
+			if len(succeeded) != len(downloadList) {
+				t.Errorf("expected %d completed items, got %d", len(downloadList), len(succeeded))
+			}
+			if slskdClient.calls != tt.wantCalls {
+				t.Errorf("expected %d GetDownloads calls, got %d", tt.wantCalls, slskdClient.calls)
+			}
+		})
+	}
+}
+
+func TestMonitorDownloads_StopsOnCancel(t *testing.T) {
+	slskdClient := &mockSlskdClientCountingDownloads{failures: 1 << 30}
+	p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+	p.cfg.Slskd.StalledTimeout = 60
+	p.cfg.Timing.DownloadPollSeconds = 1
+
+	// The deadline lands while waiting out the backoff after a failed fetch
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := p.monitorDownloads(ctx, []DownloadedItem{{AlbumID: 1}}, nil, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected monitoring to stop promptly, took %v", elapsed)
+	}
+}
+
+func TestMonitorDownloads_RetrySettings(t *testing.T) {
+	tests := []struct {
+		name        string
+		retries     int
+		delay       int
+		wantRetries int
+		minElapsed  time.Duration
+	}{
+		{name: "zero retries gives up right away", retries: 0, wantRetries: 0},
+		{name: "retries wait out the delay", retries: 1, delay: 1, wantRetries: 1, minElapsed: time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slskdClient := &mockSlskdClientTruncated{}
+			p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+			p.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+			p.cfg.Slskd.StalledTimeout = 60
+			p.cfg.Download.MaxFileRetries = &tt.retries
+			p.cfg.Download.RetryDelaySeconds = tt.delay
+
+			downloadList := []DownloadedItem{{AlbumID: 1, FolderName: "Album", Sources: []DownloadSource{{Username: "user", Directory: "Music/Album"}}}}
+			start := time.Now()
+			if _, err := p.monitorDownloads(context.Background(), downloadList, nil, nil); err != nil {
+				t.Fatalf("monitorDownloads() error: %v", err)
+			}
+
+			if slskdClient.retries != tt.wantRetries {
+				t.Errorf("expected %d retries, got %d", tt.wantRetries, slskdClient.retries)
+			}
+			if elapsed := time.Since(start); elapsed < tt.minElapsed {
+				t.Errorf("expected the retry to wait %v, took %v", tt.minElapsed, elapsed)
+			}
+		})
+	}
+}
+
+func TestMonitorDownloads_GivesUpOnDownloadsMissingFromSlskd(t *testing.T) {
+	// slskd only lists another user's downloads
+	slskdClient := &mockSlskdClientCountingDownloads{users: []string{"other"}}
+	p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+	p.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	p.cfg.Slskd.StalledTimeout = 60
+	p.cfg.Search.WishlistOnDenylist = false
+	p.denylist.RecordAttempt(3, true)
+
+	downloadList := []DownloadedItem{{AlbumID: 3, FolderName: "Album", Sources: []DownloadSource{{Username: "user", Directory: "Music/Album"}}}}
+	succeeded, err := p.monitorDownloads(context.Background(), downloadList, nil, nil)
+	if err != nil {
+		t.Fatalf("monitorDownloads() error: %v", err)
+	}
+
+	if len(succeeded) != 0 {
+		t.Errorf("expected no completed items, got %d", len(succeeded))
+	}
+	if slskdClient.calls != maxMissingDownloadPolls {
+		t.Errorf("expected the downloads looked up %d times, got %d", maxMissingDownloadPolls, slskdClient.calls)
+	}
+	if entry := p.denylist.GetEntry(3); entry == nil || entry.Failures != 1 {
+		t.Errorf("expected a recorded failure, got %+v", entry)
+	}
+}
@@ -0,0 +1,24 @@
+package processor
+
+import (
+	"context"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+)
+
+// unmonitored reports whether an album, or with skipArtists its artist, was
+// unmonitored in Lidarr, which the wanted list can still return, and the
+// summary reason for skipping it. Albums asked for by ID are searched
+// regardless, and an artist missing from the record is taken as monitored
+func unmonitored(ctx context.Context, album lidarr.Album, skipArtists bool) (string, bool) {
+	if isRequested(ctx) {
+		return "", false
+	}
+	if !album.Monitored {
+		return ReasonUnmonitored, true
+	}
+	if skipArtists && album.Artist.ID != 0 && !album.Artist.Monitored {
+		return ReasonArtistUnmonitored, true
+	}
+	return "", false
+}
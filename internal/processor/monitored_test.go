@@ -0,0 +1,115 @@
+package processor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+)
+
+func TestUnmonitored(t *testing.T) {
+	tests := []struct {
+		name        string
+		album       lidarr.Album
+		requested   bool
+		keepArtists bool
+		want        bool
+		reason      string
+	}{
+		{
+			name:  "monitored",
+			album: lidarr.Album{Monitored: true, Artist: lidarr.Artist{ID: 1, Monitored: true}},
+		},
+		{
+			name:   "album unmonitored",
+			album:  lidarr.Album{Artist: lidarr.Artist{ID: 1, Monitored: true}},
+			want:   true,
+			reason: ReasonUnmonitored,
+		},
+		{
+			name:   "artist unmonitored",
+			album:  lidarr.Album{Monitored: true, Artist: lidarr.Artist{ID: 1, MonitorNewItems: "none"}},
+			want:   true,
+			reason: ReasonArtistUnmonitored,
+		},
+		{
+			name:        "artist unmonitored with skip_unmonitored_artists off",
+			album:       lidarr.Album{Monitored: true, Artist: lidarr.Artist{ID: 1}},
+			keepArtists: true,
+		},
+		{
+			name:   "album and artist unmonitored",
+			album:  lidarr.Album{Artist: lidarr.Artist{ID: 1}},
+			want:   true,
+			reason: ReasonUnmonitored,
+		},
+		{
+			name:  "artist not included",
+			album: lidarr.Album{Monitored: true, Artist: lidarr.Artist{ArtistName: "Artist"}},
+		},
+		{
+			name:      "requested",
+			album:     lidarr.Album{Artist: lidarr.Artist{ID: 1}},
+			requested: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.requested {
+				ctx = withRequested(ctx)
+			}
+			reason, got := unmonitored(ctx, tt.album, !tt.keepArtists)
+			if got != tt.want || reason != tt.reason {
+				t.Errorf("unmonitored() = %q, %v, want %q, %v", reason, got, tt.reason, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchAndQueueDownloads_SkipsUnmonitored(t *testing.T) {
+	tracks := []lidarr.Track{{Title: "First Song"}, {Title: "Second Song"}}
+	p := newTestProcessor(t, &mockLidarrClientWithAliases{tracks: tracks}, &mockSlskdClientConcurrent{})
+	p.cfg.Search.ConcurrentSearches = 1
+	p.current = &RunSummary{}
+
+	albums := concurrentAlbums(2)
+	albums[1].Monitored = false
+	downloadList, failed := p.searchAndQueueDownloads(context.Background(), albums, nil)
+
+	if failed != 0 || len(downloadList) != 1 {
+		t.Fatalf("expected 1 queued and none failed, got %d queued, %d failed", len(downloadList), failed)
+	}
+	if got := p.current.count(OutcomeSkipped); got != 1 {
+		t.Errorf("expected 1 skipped, got %d", got)
+	}
+	if entry := p.denylist.GetEntry(albums[1].ID); entry != nil {
+		t.Errorf("expected no denylist entry for the unmonitored album, got %+v", entry)
+	}
+}
+
+func TestSearchAndQueueDownloads_SkipsUnmonitoredArtists(t *testing.T) {
+	tracks := []lidarr.Track{{Title: "First Song"}, {Title: "Second Song"}}
+	p := newTestProcessor(t, &mockLidarrClientWithAliases{tracks: tracks}, &mockSlskdClientConcurrent{})
+	p.cfg.Search.ConcurrentSearches = 1
+	p.current = &RunSummary{}
+
+	albums := concurrentAlbums(2)
+	albums[1].Artist.ID = 7
+	albums[1].Artist.Monitored = false
+	downloadList, _ := p.searchAndQueueDownloads(context.Background(), albums, nil)
+
+	if len(downloadList) != 1 {
+		t.Fatalf("expected 1 queued, got %d", len(downloadList))
+	}
+	var reason string
+	for _, decision := range p.current.Decisions {
+		if decision.AlbumID == albums[1].ID {
+			reason = decision.Reason
+		}
+	}
+	if reason != ReasonArtistUnmonitored {
+		t.Errorf("expected the album skipped as %q, got %q", ReasonArtistUnmonitored, reason)
+	}
+}
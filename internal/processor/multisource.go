@@ -0,0 +1,59 @@
+package processor
+
+import (
+	"context"
+	"errors"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// assembleFromResults builds an album from the best file for each track across
+// every user's directories in one search, for albums that only exist as partial
+// shares. Every track must be found, and nothing is queued otherwise
+func (p *Processor) assembleFromResults(ctx context.Context, results []slskd.SearchResult, tracks []lidarr.Track, album lidarr.Album, release *lidarr.Release) (DownloadedItem, error) {
+	if len(tracks) == 0 {
+		return DownloadedItem{}, errNoMatch
+	}
+
+	found := make([]trackCandidate, 0, len(tracks))
+	used := make(map[string]string) // File chosen for each track, to catch two tracks matching one file
+	for _, track := range tracks {
+		candidate, ok := p.bestTrackFile(ctx, album, track, results)
+		if !ok {
+			p.logger.Debug("no user has track, can't assemble album from several users",
+				"album", album.Title,
+				"track", track.Title)
+			return DownloadedItem{}, errNoMatch
+		}
+
+		key := candidate.username + "\x00" + candidate.file.Filename
+		if other, taken := used[key]; taken {
+			p.logger.Debug("two tracks matched the same file, can't assemble album from several users",
+				"album", album.Title,
+				"track", track.Title,
+				"other", other,
+				"file", candidate.file.Filename)
+			return DownloadedItem{}, errNoMatch
+		}
+		used[key] = track.Title
+		found = append(found, candidate)
+	}
+
+	item, err := p.queueTrackCandidates(ctx, album, release, found, len(tracks))
+	if errors.Is(err, errTooFewTracks) {
+		return DownloadedItem{}, errEnqueueFailed
+	}
+	if err != nil {
+		return DownloadedItem{}, err
+	}
+
+	if !p.cfg.DryRun {
+		p.logger.Info("queued album from several users",
+			"album", album.Title,
+			"artist", album.Artist.ArtistName,
+			"tracks", len(item.Tracks),
+			"sources", len(item.Sources))
+	}
+	return item, nil
+}
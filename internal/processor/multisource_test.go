@@ -0,0 +1,67 @@
+package processor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+func TestSearchForAlbum_AssemblesFromSeveralUsers(t *testing.T) {
+	split := []slskd.SearchResult{
+		{Username: "first", Files: []slskd.SearchFile{{Filename: `Music\Album A\01 - First Song.flac`, Size: 100}}},
+		{Username: "second", Files: []slskd.SearchFile{{Filename: `Share\Album B\02 - Second Song.flac`, Size: 100}}},
+	}
+	oneTrack := []slskd.SearchResult{
+		{Username: "first", Files: []slskd.SearchFile{{Filename: `Music\Album A\01 - First Song.flac`, Size: 100}}},
+	}
+
+	tests := []struct {
+		name        string
+		multiSource bool
+		results     []slskd.SearchResult
+		wantErr     error
+	}{
+		{name: "disabled", results: split, wantErr: errNoMatch},
+		{name: "tracks split across users", multiSource: true, results: split},
+		{name: "track missing everywhere", multiSource: true, results: oneTrack, wantErr: errNoMatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			album, tracks := candidateAlbum()
+			slskdClient := &mockSlskdClientWithResults{results: map[string][]slskd.SearchResult{"Album": tt.results}}
+			p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+			p.cfg.Search.AllowMultiSource = tt.multiSource
+
+			item, err := p.searchForAlbum(context.Background(), "Album", tracks, album, &lidarr.Release{MediumCount: 1})
+			if tt.wantErr != nil {
+				if err != tt.wantErr {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				if len(slskdClient.enqueued) != 0 {
+					t.Errorf("expected nothing enqueued, got %v", slskdClient.enqueued)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("searchForAlbum() error: %v", err)
+			}
+
+			if len(item.Sources) != 2 || !item.hasSource("first", "Music/Album A") || !item.hasSource("second", "Share/Album B") {
+				t.Errorf("expected a source per user, got %+v", item.Sources)
+			}
+			if len(slskdClient.enqueued["first"]) != 1 || len(slskdClient.enqueued["second"]) != 1 {
+				t.Errorf("expected one file enqueued per user, got %v", slskdClient.enqueued)
+			}
+			folders := map[string]string{}
+			for _, track := range item.Tracks {
+				folders[track.Filename] = track.Folder
+			}
+			if folders["01 - First Song.flac"] != "Album A" || folders["02 - Second Song.flac"] != "Album B" {
+				t.Errorf("expected each track to keep its own folder, got %v", folders)
+			}
+		})
+	}
+}
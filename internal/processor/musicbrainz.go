@@ -0,0 +1,65 @@
+package processor
+
+import (
+	"context"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/musicbrainz"
+)
+
+// resolveTracks returns the track list to match against for an album
+// Lidarr's list is used unless it is empty, or verification is enabled and
+// MusicBrainz disagrees on the track count. Any MusicBrainz error falls back
+// to Lidarr's list
+func (p *Processor) resolveTracks(ctx context.Context, album lidarr.Album, release *lidarr.Release, tracks []lidarr.Track) []lidarr.Track {
+	if p.musicbrainz == nil {
+		return tracks
+	}
+	if len(tracks) > 0 && !p.cfg.Search.VerifyTracklistWithMB {
+		return tracks
+	}
+
+	mbTracks, err := p.musicbrainz.ReleaseTracks(ctx, release.ForeignReleaseID, album.ForeignAlbumID)
+	if err != nil {
+		p.logger.Warn("musicbrainz lookup failed, using lidarr track list",
+			"album", album.Title,
+			"error", err)
+		return tracks
+	}
+	if p.mbCache != nil {
+		if err := p.mbCache.Save(); err != nil {
+			p.logger.Warn("failed to save musicbrainz cache", "album", album.Title, "error", err)
+		}
+	}
+
+	if len(mbTracks) == 0 || len(mbTracks) == len(tracks) {
+		p.logger.Debug("using lidarr track list",
+			"album", album.Title,
+			"lidarrTracks", len(tracks),
+			"musicbrainzTracks", len(mbTracks))
+		return tracks
+	}
+
+	p.logger.Info("using musicbrainz track list",
+		"album", album.Title,
+		"artist", album.Artist.ArtistName,
+		"lidarrTracks", len(tracks),
+		"musicbrainzTracks", len(mbTracks))
+	return tracksFromMusicBrainz(album.ID, mbTracks)
+}
+
+// tracksFromMusicBrainz converts MusicBrainz tracks to Lidarr tracks so the rest
+// of the pipeline can stay unaware of where the list came from
+func tracksFromMusicBrainz(albumID int, mbTracks []musicbrainz.Track) []lidarr.Track {
+	tracks := make([]lidarr.Track, len(mbTracks))
+	for i, t := range mbTracks {
+		tracks[i] = lidarr.Track{
+			Title:               t.Title,
+			AlbumID:             albumID,
+			MediumNumber:        t.MediumNumber,
+			AbsoluteTrackNumber: i + 1,
+			Duration:            t.Length,
+		}
+	}
+	return tracks
+}
@@ -0,0 +1,85 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/musicbrainz"
+)
+
+// mockMusicBrainzClient returns a fixed track list
+type mockMusicBrainzClient struct {
+	tracks  []musicbrainz.Track
+	err     error
+	lookups int
+}
+
+func (m *mockMusicBrainzClient) ReleaseTracks(ctx context.Context, releaseID, releaseGroupID string) ([]musicbrainz.Track, error) {
+	m.lookups++
+	return m.tracks, m.err
+}
+
+func TestResolveTracks(t *testing.T) {
+	mbTracks := []musicbrainz.Track{
+		{Title: "One", Length: 180000, MediumNumber: 1, Position: 1},
+		{Title: "Two", Length: 200000, MediumNumber: 1, Position: 2},
+	}
+	lidarrTracks := []lidarr.Track{{Title: "Wrong Edition Track"}}
+
+	tests := []struct {
+		name        string
+		verify      bool
+		mb          *mockMusicBrainzClient
+		tracks      []lidarr.Track
+		wantTitle   string
+		wantLookups int
+	}{
+		{"lidarr empty", false, &mockMusicBrainzClient{tracks: mbTracks}, nil, "One", 1},
+		{"lidarr has tracks, verify off", false, &mockMusicBrainzClient{tracks: mbTracks}, lidarrTracks, "Wrong Edition Track", 0},
+		{"verify with differing count", true, &mockMusicBrainzClient{tracks: mbTracks}, lidarrTracks, "One", 1},
+		{"lookup fails", true, &mockMusicBrainzClient{err: errors.New("boom")}, lidarrTracks, "Wrong Edition Track", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestProcessor(t, &mockLidarrClient{}, &mockSlskdClient{})
+			p.cfg.Search.VerifyTracklistWithMB = tt.verify
+			p.musicbrainz = tt.mb
+
+			album := lidarr.Album{ID: 7, Title: "Album", ForeignAlbumID: "rg-1"}
+			got := p.resolveTracks(context.Background(), album, &lidarr.Release{ForeignReleaseID: "rel-1"}, tt.tracks)
+
+			if len(got) == 0 || got[0].Title != tt.wantTitle {
+				t.Errorf("expected first track %q, got %+v", tt.wantTitle, got)
+			}
+			if tt.mb.lookups != tt.wantLookups {
+				t.Errorf("expected %d lookups, got %d", tt.wantLookups, tt.mb.lookups)
+			}
+		})
+	}
+}
+
+func TestResolveTracks_Disabled(t *testing.T) {
+	p := newTestProcessor(t, &mockLidarrClient{}, &mockSlskdClient{})
+
+	got := p.resolveTracks(context.Background(), lidarr.Album{}, &lidarr.Release{}, nil)
+	if len(got) != 0 {
+		t.Errorf("expected lidarr's empty list when musicbrainz is disabled, got %+v", got)
+	}
+}
+
+func TestTracksFromMusicBrainz(t *testing.T) {
+	tracks := tracksFromMusicBrainz(7, []musicbrainz.Track{
+		{Title: "One", Length: 180000, MediumNumber: 1},
+		{Title: "Bonus", Length: 90000, MediumNumber: 2},
+	})
+
+	if len(tracks) != 2 {
+		t.Fatalf("expected 2 tracks, got %d", len(tracks))
+	}
+	if tracks[1].AlbumID != 7 || tracks[1].MediumNumber != 2 || tracks[1].Duration != 90000 || tracks[1].AbsoluteTrackNumber != 2 {
+		t.Errorf("unexpected converted track: %+v", tracks[1])
+	}
+}
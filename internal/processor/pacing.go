@@ -0,0 +1,47 @@
+package processor
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// waitForSearchSlot spaces slskd searches at least search_delay_seconds apart,
+// across every worker, so a large backlog doesn't trip the server's flood
+// protection. It returns early with the context's error on cancellation. The
+// wait doesn't count against per_album_timeout_seconds
+func (p *Processor) waitForSearchSlot(ctx context.Context) error {
+	delay := time.Duration(p.cfg.Timing.SearchDelaySeconds) * time.Second
+	if delay <= 0 {
+		return nil
+	}
+
+	// Claim the next slot under the lock and sleep outside it
+	p.searchMu.Lock()
+	now := time.Now()
+	start := now
+	if p.nextSearch.After(now) {
+		start = p.nextSearch
+	}
+	p.nextSearch = start.Add(jittered(delay, p.cfg.Timing.SearchDelayJitter, rand.Float64()))
+	p.searchMu.Unlock()
+
+	wait := start.Sub(now)
+	if wait <= 0 {
+		return nil
+	}
+	resume := pauseAlbumTimer(ctx)
+	defer resume()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(wait):
+		return nil
+	}
+}
+
+// jittered spreads d by up to ±fraction of itself; r is uniform in [0, 1)
+func jittered(d time.Duration, fraction, r float64) time.Duration {
+	return time.Duration(float64(d) * (1 + fraction*(2*r-1)))
+}
@@ -0,0 +1,78 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+func TestJittered(t *testing.T) {
+	tests := []struct {
+		name     string
+		fraction float64
+		r        float64
+		want     time.Duration
+	}{
+		{"no jitter", 0, 0.9, 10 * time.Second},
+		{"shortest", 0.5, 0, 5 * time.Second},
+		{"middle", 0.5, 0.5, 10 * time.Second},
+		{"longer", 0.2, 1, 12 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jittered(10*time.Second, tt.fraction, tt.r); got != tt.want {
+				t.Errorf("jittered() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWaitForSearchSlot(t *testing.T) {
+	p := newTestProcessor(t, &mockLidarrClient{}, &mockSlskdClient{})
+	p.cfg.Timing.SearchDelaySeconds = 60
+
+	start := time.Now()
+	if err := p.waitForSearchSlot(context.Background()); err != nil {
+		t.Fatalf("first search should not wait, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("first search waited %v", elapsed)
+	}
+
+	// The next search is a minute away, so cancellation must cut the wait short
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := p.waitForSearchSlot(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the wait to end with the context, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("cancelled wait took %v", elapsed)
+	}
+}
+
+func TestQueueAlbum_SearchPacingOutsideAlbumTimeout(t *testing.T) {
+	album, tracks := candidateAlbum()
+	album.Releases = []lidarr.Release{{Status: "Official", TrackCount: 2, MediumCount: 1}}
+	slskdClient := &mockSlskdClientWithResults{results: map[string][]slskd.SearchResult{
+		"Album": {albumResult("user", "flac", 900, 30_000_000)},
+	}}
+	p := newTestProcessor(t, &mockLidarrClientDryRun{tracks: tracks}, slskdClient)
+	p.cfg.Timing.PerAlbumTimeoutSeconds = 1
+	p.cfg.Timing.SearchDelaySeconds = 5
+	p.current = &RunSummary{}
+
+	// Another album's search holds the slot for longer than the album timeout
+	p.nextSearch = time.Now().Add(1500 * time.Millisecond)
+
+	if _, outcome := p.queueAlbum(context.Background(), album); outcome != OutcomeQueued {
+		t.Fatalf("expected an album waiting its turn to be queued, got %q", outcome)
+	}
+	if entry := p.denylist.GetEntry(album.ID); entry != nil && entry.Failures > 0 {
+		t.Errorf("expected no denylist failure, got %d", entry.Failures)
+	}
+}
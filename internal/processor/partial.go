@@ -0,0 +1,19 @@
+package processor
+
+import (
+	"context"
+)
+
+// discardPartial cleans up the finished files of an album too incomplete to import
+// and counts the album as a failed search, so it is searched again next run
+// until max_search_failures is reached
+func (p *Processor) discardPartial(ctx context.Context, item DownloadedItem, completed []sourceFile) {
+	for _, file := range completed {
+		// slskd deletes the file along with the transfer, wherever it wrote it
+		if err := p.slskd.RemoveDownload(ctx, file.username, file.ID, true); err != nil {
+			p.logger.Warn("failed to remove partial download", "album", item.AlbumName, "file", file.Filename, "error", err)
+		}
+	}
+
+	p.recordSearchFailure(ctx, item.album())
+}
@@ -0,0 +1,88 @@
+package processor
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// mockSlskdClientPartial reports one finished file and three failed ones
+type mockSlskdClientPartial struct {
+	mockSlskdClient
+	removed []string
+}
+
+func (m *mockSlskdClientPartial) GetDownloads(ctx context.Context) (slskd.DownloadsResponse, error) {
+	return slskd.DownloadsResponse{{Username: "user", Directories: []slskd.DirectoryDownloads{{
+		Directory: `Music\Album`,
+		Files: []slskd.DownloadFile{
+			{ID: "1", Filename: `Music\Album\01.flac`, State: "Completed, Succeeded"},
+			{ID: "2", Filename: `Music\Album\02.flac`, State: "Completed, Errored"},
+			{ID: "3", Filename: `Music\Album\03.flac`, State: "Completed, Errored"},
+			{ID: "4", Filename: `Music\Album\04.flac`, State: "Completed, Errored"},
+		},
+	}}}}, nil
+}
+
+func (m *mockSlskdClientPartial) RemoveDownload(ctx context.Context, username, downloadID string, deleteFile bool) error {
+	if deleteFile {
+		m.removed = append(m.removed, downloadID)
+	}
+	return nil
+}
+
+func TestMonitorDownloads_MinPartialImportRatio(t *testing.T) {
+	tests := []struct {
+		name         string
+		minRatio     float64
+		wantImported bool
+	}{
+		{name: "any finished file by default", minRatio: 0, wantImported: true},
+		{name: "at the threshold", minRatio: 0.25, wantImported: true},
+		{name: "below the threshold", minRatio: 0.5, wantImported: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slskdClient := &mockSlskdClientPartial{}
+			p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+			p.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+			p.cfg.Slskd.StalledTimeout = 60
+			p.cfg.Search.WishlistOnDenylist = false
+			p.cfg.Download.MaxFileRetries = intPtr(0)
+			p.cfg.Download.MinPartialImportRatio = tt.minRatio
+			writeDownloadedFile(t, p, "Album", "01.flac", 0)
+
+			downloadList := []DownloadedItem{{AlbumID: 3, FolderName: "Album", Sources: []DownloadSource{{Username: "user", Directory: "Music/Album"}}}}
+			succeeded, err := p.monitorDownloads(context.Background(), downloadList, nil, nil)
+			if err != nil {
+				t.Fatalf("monitorDownloads() error: %v", err)
+			}
+
+			if imported := len(succeeded) == 1; imported != tt.wantImported {
+				t.Fatalf("expected imported %v, got %v", tt.wantImported, imported)
+			}
+
+			_, statErr := os.Stat(filepath.Join(p.downloadDir, "Album", "01.flac"))
+			entry := p.denylist.GetEntry(3)
+			if tt.wantImported {
+				if statErr != nil || entry != nil || len(slskdClient.removed) != 0 {
+					t.Errorf("expected the finished file kept and no failure recorded, got stat %v, entry %+v, removed %v", statErr, entry, slskdClient.removed)
+				}
+				return
+			}
+			if entry == nil || entry.Failures != 1 {
+				t.Errorf("expected a recorded failure, got %+v", entry)
+			}
+			if !slices.Contains(slskdClient.removed, "1") {
+				t.Errorf("expected the finished transfer and its file to be removed by slskd, removed %v", slskdClient.removed)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package processor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/pathmap"
+)
+
+// mockLidarrClientRecordingCommands records posted commands
+type mockLidarrClientRecordingCommands struct {
+	mockLidarrClient
+	posted []lidarr.Command
+}
+
+func (m *mockLidarrClientRecordingCommands) PostCommand(ctx context.Context, cmd lidarr.Command) (*lidarr.CommandResponse, error) {
+	m.posted = append(m.posted, cmd)
+	return &lidarr.CommandResponse{ID: len(m.posted)}, nil
+}
+
+func TestTriggerImport_AppliesLidarrPathMapping(t *testing.T) {
+	lidarrClient := &mockLidarrClientRecordingCommands{}
+	p := newTestProcessor(t, lidarrClient, &mockSlskdClient{})
+	p.cfg.Lidarr.DownloadDir = "/lidarr/downloads" // Ignored once a mapping applies
+	p.cfg.Timing.ImportPollSeconds = 1
+
+	mapper, err := pathmap.New([]pathmap.Mapping{{FromPrefix: p.cfg.LocalDownloadDir(), ToPrefix: "/music/incoming"}})
+	if err != nil {
+		t.Fatalf("pathmap.New() error: %v", err)
+	}
+	p.toLidarr = mapper
+
+	downloads := []DownloadedItem{{ArtistName: "Some Artist", AlbumName: "Album", AlbumID: 1}}
+	if err := p.triggerImport(context.Background(), downloads); err != nil {
+		t.Fatalf("triggerImport() error: %v", err)
+	}
+
+	if len(lidarrClient.posted) != 1 {
+		t.Fatalf("expected 1 import command, got %d", len(lidarrClient.posted))
+	}
+	if got := lidarrClient.posted[0].Path; got != "/music/incoming/Some Artist/Album" {
+		t.Errorf("expected mapped import path, got %q", got)
+	}
+}
@@ -0,0 +1,26 @@
+package processor
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// remotePath normalizes a path reported by slskd, which keeps the uploader's
+// separators (usually backslashes), to forward slashes
+func remotePath(p string) string {
+	return strings.ReplaceAll(p, "\\", "/")
+}
+
+// localFolder returns the folder slskd saves a remote directory's files into,
+// relative to its download directory
+func localFolder(remoteDir string) string {
+	return path.Base(remotePath(remoteDir))
+}
+
+// localPath returns where a file downloaded from a remote directory lands on
+// seekarr's filesystem, with slskd's download directory mapped through
+// path_mappings.slskd_to_local
+func (p *Processor) localPath(remoteDir, remoteFilename string) string {
+	return filepath.Join(p.downloadDir, localFolder(remoteDir), path.Base(remotePath(remoteFilename)))
+}
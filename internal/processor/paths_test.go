@@ -0,0 +1,88 @@
+package processor
+
+import (
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/config"
+	"github.com/yuritomanek/seekarr/internal/pathmap"
+)
+
+func TestLocalFolder(t *testing.T) {
+	tests := []struct {
+		remoteDir string
+		want      string
+	}{
+		{`@@abcde\Music\Artist\Album`, "Album"},
+		{`C:\Users\me\Music\Album (2001) [FLAC]`, "Album (2001) [FLAC]"},
+		{`Music\Album\CD1`, "CD1"},
+		{"Music/Album", "Album"},
+	}
+
+	for _, tt := range tests {
+		if got := localFolder(tt.remoteDir); got != tt.want {
+			t.Errorf("localFolder(%q) = %q, want %q", tt.remoteDir, got, tt.want)
+		}
+	}
+}
+
+func TestLocalPath_MapsSlskdDownloadDir(t *testing.T) {
+	localDir := t.TempDir()
+	cfg := &config.Config{
+		Lidarr: config.LidarrConfig{DownloadDir: "/music/incoming"},
+		Slskd:  config.SlskdConfig{DownloadDir: "/app/downloads"},
+		Search: config.SearchSettings{SearchType: "first_page", MinimumFilenameMatchRatio: 0.8},
+		PathMappings: config.PathMappingSettings{
+			SlskdToLocal: []pathmap.Mapping{{FromPrefix: "/app/downloads", ToPrefix: localDir}},
+		},
+	}
+	p, err := NewProcessor(cfg, &mockLidarrClient{}, &mockSlskdClient{}, slog.Default())
+	if err != nil {
+		t.Fatalf("NewProcessor() error: %v", err)
+	}
+
+	got := p.localPath(`@@abcde\Music\Album`, `@@abcde\Music\Album\01 - Song.flac`)
+	if want := filepath.Join(localDir, "Album", "01 - Song.flac"); got != want {
+		t.Errorf("localPath() = %q, want %q", got, want)
+	}
+}
+
+func TestImportPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		mappings []pathmap.Mapping
+		albumDir string
+		want     string
+	}{
+		{name: "under lidarr download_dir", albumDir: "/data/slskd/Artist/Album", want: "/music/incoming/Artist/Album"},
+		{
+			name:     "mapped once through local_to_lidarr",
+			mappings: []pathmap.Mapping{{FromPrefix: "/data/slskd", ToPrefix: "/lidarr/incoming"}},
+			albumDir: "/data/slskd/Artist/Album",
+			want:     "/lidarr/incoming/Artist/Album",
+		},
+		{name: "outside the download directory", albumDir: "/elsewhere/Artist/Album", want: "/elsewhere/Artist/Album"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Lidarr: config.LidarrConfig{DownloadDir: "/music/incoming"},
+				Slskd:  config.SlskdConfig{DownloadDir: "/data/slskd"},
+				Search: config.SearchSettings{SearchType: "first_page", MinimumFilenameMatchRatio: 0.8},
+				PathMappings: config.PathMappingSettings{
+					LocalToLidarr: tt.mappings,
+				},
+			}
+			p, err := NewProcessor(cfg, &mockLidarrClient{}, &mockSlskdClient{}, slog.Default())
+			if err != nil {
+				t.Fatalf("NewProcessor() error: %v", err)
+			}
+
+			if got := p.importPath(tt.albumDir); got != tt.want {
+				t.Errorf("importPath(%q) = %q, want %q", tt.albumDir, got, tt.want)
+			}
+		})
+	}
+}
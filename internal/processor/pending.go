@@ -0,0 +1,166 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+
+	"github.com/yuritomanek/seekarr/internal/organizer"
+	"github.com/yuritomanek/seekarr/internal/state"
+)
+
+// toPending converts a queued item into its persisted form
+func toPending(item DownloadedItem) state.PendingDownload {
+	pending := state.PendingDownload{
+		AlbumID:      item.AlbumID,
+		ReleaseID:    item.ReleaseID,
+		ArtistID:     item.ArtistID,
+		ArtistName:   item.ArtistName,
+		ArtistFolder: item.ArtistFolder,
+		AlbumName:    item.AlbumName,
+		FolderName:   item.FolderName,
+		MediumCount:  item.MediumCount,
+		Quality:      item.Quality,
+		AlbumDir:     item.AlbumDir,
+	}
+	for _, source := range item.Sources {
+		pending.Sources = append(pending.Sources, state.PendingSource{Username: source.Username, Directory: source.Directory})
+	}
+	for _, track := range item.Tracks {
+		pending.Tracks = append(pending.Tracks, state.PendingTrack{Filename: track.Filename, MediumNumber: track.MediumNumber, Folder: track.Folder})
+	}
+	for _, file := range item.Companions {
+		pending.Companions = append(pending.Companions, state.PendingTrack{Filename: file.Filename, Folder: file.Folder})
+	}
+	return pending
+}
+
+// fromPending restores a queued item from its persisted form
+func fromPending(pending state.PendingDownload) DownloadedItem {
+	item := DownloadedItem{
+		ArtistID:     pending.ArtistID,
+		ArtistName:   pending.ArtistName,
+		ArtistFolder: pending.ArtistFolder,
+		AlbumName:    pending.AlbumName,
+		AlbumID:      pending.AlbumID,
+		ReleaseID:    pending.ReleaseID,
+		FolderName:   pending.FolderName,
+		MediumCount:  pending.MediumCount,
+		Quality:      pending.Quality,
+		AlbumDir:     pending.AlbumDir,
+	}
+	for _, source := range pending.Sources {
+		item.Sources = append(item.Sources, DownloadSource{Username: source.Username, Directory: source.Directory})
+	}
+	for _, track := range pending.Tracks {
+		item.Tracks = append(item.Tracks, organizer.DownloadedTrack{Filename: track.Filename, MediumNumber: track.MediumNumber, Folder: track.Folder})
+	}
+	for _, file := range pending.Companions {
+		item.Companions = append(item.Companions, organizer.DownloadedTrack{Filename: file.Filename, Folder: file.Folder})
+	}
+	return item
+}
+
+// savePending records queued items so monitoring can resume after a restart
+func (p *Processor) savePending(downloadList []DownloadedItem) {
+	for _, item := range downloadList {
+		p.pending.Add(toPending(item))
+	}
+	if err := p.pending.Save(); err != nil {
+		p.logger.Warn("failed to save pending downloads", "error", err)
+	}
+}
+
+// clearPending forgets items that no longer need monitoring
+func (p *Processor) clearPending(albumIDs ...int) {
+	for _, id := range albumIDs {
+		p.pending.Remove(id)
+	}
+	if err := p.pending.Save(); err != nil {
+		p.logger.Warn("failed to save pending downloads", "error", err)
+	}
+}
+
+// savePendingImports records the folders of organized items, so a run that
+// ends before Lidarr imports them retries the import rather than the download
+func (p *Processor) savePendingImports(items []DownloadedItem) {
+	if p.cfg.Lidarr.DisableSync {
+		albumIDs := make([]int, len(items))
+		for i, item := range items {
+			albumIDs[i] = item.AlbumID
+		}
+		p.clearPending(albumIDs...)
+		return
+	}
+	p.savePending(items)
+}
+
+// retryImports triggers the import of albums organized by an earlier run whose
+// import timed out. Folders that are gone were imported since, or by hand
+func (p *Processor) retryImports(ctx context.Context, items []DownloadedItem) error {
+	var gone []int
+	items = slices.DeleteFunc(items, func(item DownloadedItem) bool {
+		_, err := os.Stat(item.AlbumDir)
+		if p.cfg.Lidarr.DisableSync || errors.Is(err, fs.ErrNotExist) {
+			gone = append(gone, item.AlbumID)
+			return true
+		}
+		return false
+	})
+	if len(gone) > 0 {
+		p.clearPending(gone...)
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	p.logger.Info("retrying timed-out imports", "count", len(items))
+	phaseCtx, phaseSpan := p.startPhase(ctx, PhaseImporting)
+	defer phaseSpan.End()
+	if err := p.triggerImport(phaseCtx, items); err != nil {
+		return fmt.Errorf("trigger import: %w", err)
+	}
+	return nil
+}
+
+// resumePendingDownloads finishes downloads queued by an earlier run that was
+// interrupted, returning the IDs of the albums that completed
+func (p *Processor) resumePendingDownloads(ctx context.Context, summary *RunSummary) (map[int]bool, error) {
+	pending := p.pending.Pending()
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	// Albums already organized only need their import retried
+	var downloadList, importList []DownloadedItem
+	for _, entry := range pending {
+		if item := fromPending(entry); item.AlbumDir != "" {
+			importList = append(importList, item)
+		} else {
+			downloadList = append(downloadList, item)
+		}
+	}
+	summary.Resumed = len(pending)
+
+	resumed := make(map[int]bool)
+	for _, item := range importList {
+		resumed[item.AlbumID] = true
+	}
+	if err := p.retryImports(ctx, importList); err != nil {
+		return resumed, err
+	}
+	if len(downloadList) == 0 {
+		return resumed, nil
+	}
+
+	p.logger.Info("resuming unfinished downloads", "count", len(downloadList))
+	successfulDownloads, err := p.downloadAndImport(ctx, downloadList)
+	summary.Succeeded += len(successfulDownloads)
+	for _, item := range successfulDownloads {
+		resumed[item.AlbumID] = true
+	}
+	return resumed, err
+}
@@ -0,0 +1,154 @@
+package processor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/organizer"
+	"github.com/yuritomanek/seekarr/internal/state"
+)
+
+func TestResumePendingDownloads(t *testing.T) {
+	slskdClient := &mockSlskdClientCountingDownloads{users: []string{"user1"}}
+	p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+	p.cfg.Slskd.StalledTimeout = 60
+	p.cfg.Lidarr.DisableSync = true
+
+	// user1 finished while seekarr was down; user2's transfer is gone from slskd
+	finished := DownloadedItem{
+		ArtistName: "Artist",
+		AlbumName:  "Finished",
+		AlbumID:    1,
+		FolderName: "user1",
+		Sources:    []DownloadSource{{Username: "user1", Directory: "Music/user1"}},
+		Tracks:     []organizer.DownloadedTrack{{Filename: "01.flac", MediumNumber: 1}},
+	}
+	lost := DownloadedItem{
+		AlbumID:    2,
+		FolderName: "user2",
+		Sources:    []DownloadSource{{Username: "user2", Directory: "Music/user2"}},
+	}
+	p.savePending([]DownloadedItem{finished, lost})
+
+	writeDownloadedFile(t, p, "user1", "01.flac", 5)
+
+	// A restarted processor picks the entries up from disk
+	restarted, err := state.NewDownloads(filepath.Join(p.downloadDir, "pending_downloads.json"))
+	if err != nil {
+		t.Fatalf("NewDownloads() error: %v", err)
+	}
+	if restarted.Count() != 2 {
+		t.Fatalf("expected 2 persisted downloads, got %d", restarted.Count())
+	}
+	p.pending = restarted
+
+	summary := &RunSummary{}
+	resumed, err := p.resumePendingDownloads(context.Background(), summary)
+	if err != nil {
+		t.Fatalf("resumePendingDownloads() error: %v", err)
+	}
+
+	if !resumed[1] || resumed[2] {
+		t.Errorf("expected only the finished album resumed, got %v", resumed)
+	}
+	if summary.Resumed != 2 || summary.Succeeded != 1 {
+		t.Errorf("expected 2 resumed and 1 succeeded, got %+v", summary)
+	}
+	if p.pending.Count() != 0 {
+		t.Errorf("expected organized and failed entries removed, %d left", p.pending.Count())
+	}
+}
+
+func TestResumePendingDownloads_RetriesTimedOutImport(t *testing.T) {
+	// Command 2 stays started, as after Lidarr restarts mid-scan
+	lidarrClient := &mockLidarrClientWithCommands{commands: map[int]*lidarr.CommandResponse{
+		2: {ID: 2, Status: "started"},
+	}}
+	p := newTestProcessor(t, lidarrClient, &mockSlskdClient{})
+	p.cfg.Timing.ImportPollSeconds = 1
+	p.cfg.Timing.ImportTimeoutSeconds = 1
+
+	item := DownloadedItem{
+		ArtistName: "Artist",
+		AlbumName:  "Album",
+		AlbumID:    1,
+		Sources:    []DownloadSource{{Username: "user1", Directory: "Music/Album"}},
+		AlbumDir:   p.organizer.AlbumDir("Artist", "Album"),
+	}
+	if err := os.MkdirAll(item.AlbumDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	p.savePendingImports([]DownloadedItem{item})
+	if err := p.triggerImport(context.Background(), []DownloadedItem{item}); err != nil {
+		t.Fatalf("triggerImport() error: %v", err)
+	}
+
+	pending := p.pending.Pending()
+	if len(pending) != 1 || pending[0].AlbumDir != item.AlbumDir {
+		t.Fatalf("expected the timed-out album kept pending with its folder, got %+v", pending)
+	}
+
+	// Lidarr is back by the next run
+	delete(lidarrClient.commands, 2)
+	summary := &RunSummary{}
+	resumed, err := p.resumePendingDownloads(context.Background(), summary)
+	if err != nil {
+		t.Fatalf("resumePendingDownloads() error: %v", err)
+	}
+
+	if !resumed[1] {
+		t.Errorf("expected the album resumed, got %v", resumed)
+	}
+	if summary.Resumed != 1 || summary.Succeeded != 0 {
+		t.Errorf("expected 1 resumed and none downloaded, got %+v", summary)
+	}
+	if p.pending.Count() != 0 {
+		t.Errorf("expected the imported album removed, %d left", p.pending.Count())
+	}
+}
+
+func TestResumePendingDownloads_KeepsUnfinishedImportPending(t *testing.T) {
+	// Command 2 never finishes, as when Lidarr hangs mid-scan
+	lidarrClient := &mockLidarrClientWithCommands{commands: map[int]*lidarr.CommandResponse{
+		2: {ID: 2, Status: "started"},
+	}}
+	slskdClient := &mockSlskdClientCountingDownloads{users: []string{"user1"}}
+	p := newTestProcessor(t, lidarrClient, slskdClient)
+	p.cfg.Slskd.StalledTimeout = 60
+	p.cfg.Timing.ImportPollSeconds = 1
+	p.cfg.Timing.ImportTimeoutSeconds = 1
+
+	item := DownloadedItem{
+		ArtistName: "Artist",
+		AlbumName:  "Album",
+		AlbumID:    1,
+		FolderName: "user1",
+		Sources:    []DownloadSource{{Username: "user1", Directory: "Music/user1"}},
+		Tracks:     []organizer.DownloadedTrack{{Filename: "01.flac", MediumNumber: 1}},
+	}
+	p.savePending([]DownloadedItem{item})
+	writeDownloadedFile(t, p, "user1", "01.flac", 5)
+
+	if _, err := p.resumePendingDownloads(context.Background(), &RunSummary{}); err != nil {
+		t.Fatalf("resumePendingDownloads() error: %v", err)
+	}
+
+	pending := p.pending.Pending()
+	if len(pending) != 1 || pending[0].AlbumID != 1 || pending[0].AlbumDir == "" {
+		t.Fatalf("expected the organized album kept pending for its import, got %+v", pending)
+	}
+	if _, err := os.Stat(pending[0].AlbumDir); err != nil {
+		t.Errorf("expected the album folder left in place: %v", err)
+	}
+}
+
+func TestPendingKeepsArtistID(t *testing.T) {
+	item := DownloadedItem{ArtistID: 5, ArtistName: "Artist", AlbumID: 1}
+
+	if restored := fromPending(toPending(item)); restored.ArtistID != 5 {
+		t.Errorf("expected artist 5 restored, got %d", restored.ArtistID)
+	}
+}
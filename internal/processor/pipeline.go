@@ -0,0 +1,132 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+)
+
+// importQueue hands completed downloads from the monitor to the goroutine that
+// organizes and imports them, so a slow Lidarr import never holds up monitoring
+type importQueue struct {
+	mu    sync.Mutex
+	items []DownloadedItem
+	wake  chan struct{}
+}
+
+func newImportQueue() *importQueue {
+	return &importQueue{wake: make(chan struct{}, 1)}
+}
+
+// add queues a completed download and wakes the importer
+func (q *importQueue) add(item DownloadedItem) {
+	q.mu.Lock()
+	q.items = append(q.items, item)
+	q.mu.Unlock()
+
+	select {
+	case q.wake <- struct{}{}:
+	default: // The importer is already due to take this item
+	}
+}
+
+// take returns every download queued since the last call
+func (q *importQueue) take() []DownloadedItem {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	items := q.items
+	q.items = nil
+	return items
+}
+
+// searchAndDownload runs phases 2 to 6 with the searches feeding the download
+// monitor, so the first album queued is downloading while the rest are searched
+// Completed albums are organized and imported in batches: everything that
+// finished while the previous batch was being imported
+func (p *Processor) searchAndDownload(ctx context.Context, albums []lidarr.Album, summary *RunSummary) error {
+	queued := make(chan DownloadedItem, len(albums))
+	var downloadList []DownloadedItem
+	var failedCount int
+	searched := make(chan struct{})
+
+	p.setSearching(true)
+	go func() {
+		defer close(searched)
+		phaseCtx, phaseSpan := tracer.Start(ctx, "phase."+PhaseSearching)
+		downloadList, failedCount = p.searchAndQueueDownloads(phaseCtx, albums, queued)
+		phaseSpan.End()
+		close(queued)
+		p.setSearching(false)
+		p.logger.Info("searches complete", "queued", len(downloadList), "failed", failedCount)
+	}()
+
+	queue := newImportQueue()
+	var importErr error
+	imported := make(chan struct{})
+	go func() {
+		defer close(imported)
+		for range queue.wake {
+			// Albums left after a failure stay pending for the next run
+			if importErr == nil {
+				importErr = p.organizeAndImport(ctx, queue.take())
+			}
+		}
+	}()
+
+	phaseCtx, phaseSpan := p.startPhase(ctx, PhaseDownloading)
+	successfulDownloads, err := p.monitorDownloads(phaseCtx, nil, queued, queue.add)
+	phaseSpan.End()
+	close(queue.wake)
+	<-imported
+	<-searched
+
+	summary.Failed = failedCount
+	summary.Deferred = summary.count(OutcomeDeferred)
+	summary.Queued = len(downloadList)
+	summary.Succeeded += len(successfulDownloads)
+	if err != nil {
+		return fmt.Errorf("monitor downloads: %w", err)
+	}
+	if len(downloadList) == 0 {
+		p.logger.Info("no albums matched, nothing to download")
+		return nil
+	}
+
+	p.markUnfinished(downloadList, successfulDownloads)
+	if importErr != nil {
+		return importErr
+	}
+
+	p.saveRunState(summary)
+	return nil
+}
+
+// organizeAndImport organizes a batch of completed downloads and triggers their
+// Lidarr import
+func (p *Processor) organizeAndImport(ctx context.Context, batch []DownloadedItem) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	_, organizeSpan := p.startPhase(ctx, PhaseOrganizing)
+	err := p.organizeDownloads(batch)
+	organizeSpan.End()
+	if err != nil {
+		return fmt.Errorf("organize downloads: %w", err)
+	}
+
+	p.savePendingImports(batch)
+
+	if !p.cfg.Lidarr.DisableSync {
+		phaseCtx, phaseSpan := p.startPhase(ctx, PhaseImporting)
+		err = p.triggerImport(phaseCtx, batch)
+		phaseSpan.End()
+		if err != nil {
+			return fmt.Errorf("trigger import: %w", err)
+		}
+	}
+	p.setPhase(PhaseDownloading)
+	return nil
+}
@@ -0,0 +1,175 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+func TestMonitorDownloads_MonitorsIncomingItems(t *testing.T) {
+	users := []string{"user1", "user2"}
+	slskdClient := &mockSlskdClientCountingDownloads{users: users}
+	p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+	p.cfg.Slskd.StalledTimeout = 60
+	p.cfg.Timing.DownloadPollSeconds = 1
+	for _, user := range users {
+		writeDownloadedFile(t, p, user, "01.flac", 0)
+	}
+
+	item := func(id int, user string) DownloadedItem {
+		return DownloadedItem{
+			AlbumID:    id,
+			FolderName: user,
+			Sources:    []DownloadSource{{Username: user, Directory: "Music/" + user}},
+		}
+	}
+
+	incoming := make(chan DownloadedItem, 1)
+	ready := make(chan int, len(users))
+	type result struct {
+		succeeded []DownloadedItem
+		err       error
+	}
+	done := make(chan result)
+	go func() {
+		succeeded, err := p.monitorDownloads(context.Background(), nil, incoming, func(item DownloadedItem) {
+			ready <- item.AlbumID
+		})
+		done <- result{succeeded, err}
+	}()
+
+	// The first item completes while more may still be queued
+	incoming <- item(1, "user1")
+	select {
+	case id := <-ready:
+		if id != 1 {
+			t.Fatalf("expected album 1 to complete first, got %d", id)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("first item was not completed while incoming was open")
+	}
+
+	select {
+	case <-done:
+		t.Fatal("monitoring ended before incoming was closed")
+	default:
+	}
+
+	incoming <- item(2, "user2")
+	close(incoming)
+
+	select {
+	case res := <-done:
+		if res.err != nil {
+			t.Fatalf("monitorDownloads() error: %v", res.err)
+		}
+		if len(res.succeeded) != 2 {
+			t.Errorf("expected 2 completed items, got %d", len(res.succeeded))
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("monitoring did not end after incoming was closed")
+	}
+}
+
+func TestImportQueue_BatchesItemsAddedBeforeTake(t *testing.T) {
+	queue := newImportQueue()
+	queue.add(DownloadedItem{AlbumID: 1})
+	queue.add(DownloadedItem{AlbumID: 2})
+
+	// Both items share one wake-up
+	<-queue.wake
+	select {
+	case <-queue.wake:
+		t.Error("expected a single pending wake-up")
+	default:
+	}
+
+	if batch := queue.take(); len(batch) != 2 {
+		t.Errorf("expected a batch of 2, got %d", len(batch))
+	}
+	if batch := queue.take(); len(batch) != 0 {
+		t.Errorf("expected an empty batch after take, got %d", len(batch))
+	}
+}
+
+// mockSlskdClientSlowDownloads reports the first user's file in progress until
+// finishAt and every other user's file in progress for good
+type mockSlskdClientSlowDownloads struct {
+	mockSlskdClient
+	mu       sync.Mutex
+	users    []string
+	finishAt time.Time
+}
+
+func (m *mockSlskdClientSlowDownloads) addUser(user string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.users = append(m.users, user)
+}
+
+func (m *mockSlskdClientSlowDownloads) GetDownloads(ctx context.Context) (slskd.DownloadsResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var response slskd.DownloadsResponse
+	for i, user := range m.users {
+		state := "InProgress"
+		if i == 0 && time.Now().After(m.finishAt) {
+			state = "Completed, Succeeded"
+		}
+		response = append(response, slskd.UserDownloads{Username: user, Directories: []slskd.DirectoryDownloads{{
+			Directory: `Music\` + user,
+			Files:     []slskd.DownloadFile{{ID: user + "-1", Filename: `Music\` + user + `\01.flac`, State: state}},
+		}}})
+	}
+	return response, nil
+}
+
+func TestMonitorDownloads_TimeoutPerIncomingItem(t *testing.T) {
+	// The first item would finish after its own timeout has passed
+	slskdClient := &mockSlskdClientSlowDownloads{users: []string{"slow"}, finishAt: time.Now().Add(2 * time.Second)}
+	p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+	p.cfg.Slskd.StalledTimeout = 1
+	p.cfg.Timing.DownloadPollSeconds = 1
+	p.cfg.Timing.StallCheckIntervalSec = 60
+	p.cfg.Timing.StallChecks = 3
+	writeDownloadedFile(t, p, "slow", "01.flac", 0)
+
+	item := func(id int, user string) DownloadedItem {
+		return DownloadedItem{
+			AlbumID:    id,
+			FolderName: user,
+			Sources:    []DownloadSource{{Username: user, Directory: "Music/" + user}},
+		}
+	}
+
+	incoming := make(chan DownloadedItem, 1)
+	done := make(chan []DownloadedItem)
+	go func() {
+		succeeded, _ := p.monitorDownloads(context.Background(), nil, incoming, nil)
+		done <- succeeded
+	}()
+
+	// A trickle of later arrivals mustn't extend the first item's timeout
+	incoming <- item(1, "slow")
+	for id := 2; id <= 9; id++ {
+		time.Sleep(300 * time.Millisecond)
+		user := fmt.Sprintf("user%d", id)
+		slskdClient.addUser(user)
+		incoming <- item(id, user)
+	}
+	close(incoming)
+
+	select {
+	case succeeded := <-done:
+		if len(succeeded) != 0 {
+			t.Errorf("expected the first item to time out before finishing, got %+v", succeeded)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("monitoring did not end")
+	}
+}
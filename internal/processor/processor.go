@@ -2,21 +2,39 @@ package processor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"math/rand/v2"
+	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/yuritomanek/seekarr/internal/config"
 	"github.com/yuritomanek/seekarr/internal/filter"
+	"github.com/yuritomanek/seekarr/internal/hooks"
 	"github.com/yuritomanek/seekarr/internal/lidarr"
 	"github.com/yuritomanek/seekarr/internal/matcher"
+	"github.com/yuritomanek/seekarr/internal/musicbrainz"
 	"github.com/yuritomanek/seekarr/internal/organizer"
+	"github.com/yuritomanek/seekarr/internal/pathmap"
 	"github.com/yuritomanek/seekarr/internal/slskd"
 	"github.com/yuritomanek/seekarr/internal/state"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// tracer records pipeline spans; it is a no-op unless telemetry is configured
+var tracer = otel.Tracer("github.com/yuritomanek/seekarr/internal/processor")
+
 // Processor orchestrates the main workflow: fetch, search, download, organize, import
 type Processor struct {
 	cfg       *config.Config
@@ -26,24 +44,159 @@ type Processor struct {
 	filter    *filter.Filter
 	organizer *organizer.Organizer
 	denylist  *state.Denylist
-	pageTrack *state.PageTracker
+	wishlist  *state.Wishlist
+	searches  *state.Searches
+	pending   *state.Downloads              // Queued albums not yet organized, kept across restarts
+	pageTrack map[string]*state.PageTracker // incrementing_page position per search source
+	toLidarr  *pathmap.Mapper               // seekarr's filesystem -> Lidarr's view
+	hooks     *hooks.Runner
 	logger    *slog.Logger
+
+	// reputation records download outcomes per Soulseek user across runs
+	reputation *state.Reputation
+
+	// runEnds records when each run finished, bounding the Lidarr history checked
+	runEnds *state.LastRun
+
+	// cachedAlbums and cachedTracks hold the albums and track lists fetched
+	// from Lidarr during the current run, up to maxCachedLidarrEntries each
+	lidarrCacheMu sync.Mutex
+	cachedAlbums  map[int]lidarr.Album
+	cachedTracks  map[trackListKey][]lidarr.Track
+
+	// cutoffUnmet holds the albums listed by Lidarr's cutoff unmet endpoint
+	// in the current run, searched only for the tracks they need upgraded
+	cutoffMu    sync.Mutex
+	cutoffUnmet map[int]bool
+
+	// profiles caches Lidarr's quality profiles for the current run
+	profilesMu sync.Mutex
+	profiles   map[int]lidarr.QualityProfile
+
+	// nextSearch is the earliest time the next slskd search may start
+	searchMu   sync.Mutex
+	nextSearch time.Time
+
+	// shuffler orders the wanted list with sort_key random
+	shuffler *rand.Rand
+
+	// progressLevel is the level download progress is logged at on each poll
+	progressLevel slog.Level
+
+	// titleBlacklist holds the compiled "re:" entries of title_blacklist
+	titleBlacklist []*regexp.Regexp
+
+	// musicbrainz is nil unless the MusicBrainz fallback is enabled
+	musicbrainz musicbrainz.Client
+	mbCache     *musicbrainz.Cache
+
+	// approver, if set, is asked before each match is enqueued; declinedAll
+	// is set once it declines every remaining album
+	approver    Approver
+	declinedAll atomic.Bool
+
+	// lidarrRejected is set once Lidarr refuses the API key during a run,
+	// skipping the remaining searches and failing the run
+	lidarrRejected atomic.Bool
+
+	// statusMu guards the run status reported by Status; searching is set
+	// while searches overlap with the download phases
+	statusMu  sync.Mutex
+	phase     string
+	searching bool
+	lastRun   *RunSummary
+
+	// reservedBytes is the size of the files enqueued during the current run,
+	// counted against the download directory's free space and the run's byte
+	// budget; queuedAlbums counts against its album budget, budgetClaims
+	// the albums among them still being searched. budgetResolved is closed
+	// whenever one of those claims is resolved. writtenBytes holds how much of
+	// each album's files has already landed, which free space counts already
+	downloadDir    string
+	diskFree       func(path string) (uint64, error)
+	spaceMu        sync.Mutex
+	reservedBytes  int64
+	writtenBytes   map[int]int64
+	queuedAlbums   int
+	budgetClaims   int
+	budgetResolved chan struct{}
+
+	// queuedDirs maps each remote directory queued during the current run to
+	// the album it was queued for
+	queuedMu   sync.Mutex
+	queuedDirs map[string]string
+
+	// browsed caches the directories browsed during the current run, nil for
+	// those that couldn't be
+	browsedMu sync.Mutex
+	browsed   map[string][]slskd.SearchFile
+
+	// refusedUsers are users whose enqueue failed during the current run
+	refusedMu    sync.Mutex
+	refusedUsers map[string]bool
+
+	// current collects decisions for the run in progress; currentMu guards it
+	// and its decisions while albums are searched concurrently
+	currentMu sync.Mutex
+	current   *RunSummary
 }
 
 // DownloadedItem tracks a downloaded album for organization
 type DownloadedItem struct {
-	ArtistName  string
-	AlbumName   string
-	AlbumID     int
-	FolderName  string
-	Username    string
-	Directory   string
-	MediumCount int
-	Tracks      []organizer.DownloadedTrack
+	ArtistID     int // Lidarr artist, 0 for items saved before it was recorded
+	ArtistName   string
+	ArtistFolder string // Artist's folder name in Lidarr with use_artist_path
+	AlbumName    string
+	AlbumID      int
+	ReleaseID    int // Lidarr release whose track list the files matched
+	FolderName   string
+	Sources      []DownloadSource
+	MediumCount  int
+	Quality      string // Allowed filetype the files matched, e.g. "flac 24/192"
+	Tracks       []organizer.DownloadedTrack
+	Companions   []organizer.DownloadedTrack // Non-audio files from extensions_whitelist
+	AlbumDir     string                      // Folder the organizer moved the album into
+}
+
+// album returns the Lidarr album an item was downloaded for
+func (item DownloadedItem) album() lidarr.Album {
+	return lidarr.Album{
+		ID:       item.AlbumID,
+		Title:    item.AlbumName,
+		ArtistID: item.ArtistID,
+		Artist:   lidarr.Artist{ID: item.ArtistID, ArtistName: item.ArtistName},
+	}
+}
+
+// DownloadSource is a remote directory an album's files are downloaded from
+// An album found by a single search has one source; one assembled from track
+// searches may have several
+type DownloadSource struct {
+	Username  string
+	Directory string // Normalized to forward slashes
+}
+
+// hasSource reports whether the given remote directory is one of the item's sources
+func (item DownloadedItem) hasSource(username, directory string) bool {
+	for _, source := range item.Sources {
+		if source.Username == username && source.Directory == directory {
+			return true
+		}
+	}
+	return false
 }
 
 // downloadCleanupInfo tracks the original download info for cleanup
 type downloadCleanupInfo struct {
+	albumID   int
+	username  string
+	directory string
+	albumDir  string // Organized Artist/Album folder the import command covered
+}
+
+// sourceFile is a slskd download file together with the source it belongs to
+type sourceFile struct {
+	slskd.DownloadFile
 	username  string
 	directory string
 }
@@ -67,6 +220,14 @@ func formatOptionalInt(val *int) string {
 	return fmt.Sprintf("%d", *val)
 }
 
+// formatOptionalBool formats an optional bool pointer for logging
+func formatOptionalBool(val *bool) string {
+	if val == nil {
+		return "N/A"
+	}
+	return strconv.FormatBool(*val)
+}
+
 // NewProcessor creates a new processor with all dependencies
 func NewProcessor(
 	cfg *config.Config,
@@ -78,22 +239,81 @@ func NewProcessor(
 		logger = slog.Default()
 	}
 
+	// Path mappings between slskd, seekarr, and Lidarr
+	toLidarr, err := pathmap.New(cfg.PathMappings.LocalToLidarr)
+	if err != nil {
+		return nil, fmt.Errorf("local_to_lidarr path mappings: %w", err)
+	}
+
+	// slskd's download dir as seen from this host
+	downloadDir := cfg.LocalDownloadDir()
+	if downloadDir != cfg.Slskd.DownloadDir {
+		logger.Debug("applied slskd_to_local path mapping", "from", cfg.Slskd.DownloadDir, "to", downloadDir)
+	}
+
+	titleBlacklist, err := cfg.Search.TitleBlacklistPatterns()
+	if err != nil {
+		return nil, err
+	}
+
 	// Initialize components
 	m := matcher.NewMatcher(cfg.Search.MinimumFilenameMatchRatio)
 	f := filter.NewFilter(cfg.Search.AllowedFiletypes)
-	org := organizer.NewOrganizer(cfg.Slskd.DownloadDir, logger)
+	org := organizer.NewOrganizer(downloadDir, logger)
 
 	// Initialize state management
-	denylistPath := filepath.Join(cfg.Slskd.DownloadDir, "search_denylist.json")
+	denylistPath := filepath.Join(downloadDir, "search_denylist.json")
 	denylist, err := state.NewDenylist(denylistPath)
 	if err != nil {
 		return nil, fmt.Errorf("initialize denylist: %w", err)
 	}
 
-	pageTrackPath := filepath.Join(cfg.Slskd.DownloadDir, ".current_page.txt")
-	pageTrack, err := state.NewPageTracker(pageTrackPath, 1) // Start at page 1
+	wishlistPath := filepath.Join(downloadDir, "slskd_wishlist.json")
+	wishlist, err := state.NewWishlist(wishlistPath)
+	if err != nil {
+		return nil, fmt.Errorf("initialize wishlist: %w", err)
+	}
+
+	searches, err := state.NewSearches(filepath.Join(downloadDir, "slskd_searches.json"))
+	if err != nil {
+		return nil, fmt.Errorf("initialize searches: %w", err)
+	}
+
+	pending, err := state.NewDownloads(filepath.Join(downloadDir, "pending_downloads.json"))
+	if err != nil {
+		return nil, fmt.Errorf("initialize pending downloads: %w", err)
+	}
+
+	reputation, err := state.NewReputation(filepath.Join(downloadDir, "user_reputation.json"))
 	if err != nil {
-		return nil, fmt.Errorf("initialize page tracker: %w", err)
+		return nil, fmt.Errorf("initialize user reputation: %w", err)
+	}
+
+	runEnds, err := state.NewLastRun(filepath.Join(downloadDir, ".last_run.txt"))
+	if err != nil {
+		return nil, fmt.Errorf("initialize last run: %w", err)
+	}
+
+	pageTrack := make(map[string]*state.PageTracker)
+	for source, name := range pageTrackFiles {
+		pt, err := state.NewPageTracker(filepath.Join(downloadDir, name), 1) // Start at page 1
+		if err != nil {
+			return nil, fmt.Errorf("initialize %s page tracker: %w", source, err)
+		}
+		pageTrack[source] = pt
+	}
+
+	hookTimeout := time.Duration(cfg.Hooks.TimeoutSeconds) * time.Second
+	hookRunner := hooks.NewRunner(cfg.Hooks.PostImport, cfg.Hooks.PostRun, hookTimeout, logger)
+
+	var mbClient musicbrainz.Client
+	var mbCache *musicbrainz.Cache
+	if cfg.MusicBrainz.Enabled {
+		mbCache, err = musicbrainz.NewCache(filepath.Join(downloadDir, "musicbrainz_cache.json"))
+		if err != nil {
+			return nil, fmt.Errorf("initialize musicbrainz cache: %w", err)
+		}
+		mbClient = musicbrainz.NewClient(cfg.MusicBrainz.BaseURL, mbCache)
 	}
 
 	return &Processor{
@@ -104,21 +324,119 @@ func NewProcessor(
 		filter:    f,
 		organizer: org,
 		denylist:  denylist,
+		wishlist:  wishlist,
+		searches:  searches,
+		pending:   pending,
 		pageTrack: pageTrack,
+		toLidarr:  toLidarr,
+		hooks:     hookRunner,
 		logger:    logger,
+		phase:     PhaseIdle,
+
+		progressLevel: slog.LevelDebug,
+		shuffler:      rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
+
+		downloadDir: downloadDir,
+		diskFree:    freeSpace,
+
+		reputation:     reputation,
+		runEnds:        runEnds,
+		titleBlacklist: titleBlacklist,
+
+		musicbrainz: mbClient,
+		mbCache:     mbCache,
 	}, nil
 }
 
 // Run executes the main processing workflow
 func (p *Processor) Run(ctx context.Context) error {
+	return p.run(ctx, p.fetchWantedAlbums)
+}
+
+// RunAlbums processes the given Lidarr albums instead of the wanted list
+// The albums are searched even if denylisted, since they were asked for explicitly
+func (p *Processor) RunAlbums(ctx context.Context, ids []int) error {
+	return p.run(withRequested(ctx), func(ctx context.Context) ([]lidarr.Album, error) {
+		return p.fetchRequestedAlbums(ctx, ids)
+	})
+}
+
+// run searches, downloads and imports the albums returned by fetch
+func (p *Processor) run(ctx context.Context, fetch func(context.Context) ([]lidarr.Album, error)) (err error) {
 	p.logger.Info("starting seekarr processor")
 
+	ctx, span := tracer.Start(ctx, "run")
+	ctx = slskd.WithThrottleNotice(ctx) // Warn once per run if slskd rate limits seekarr
+	summary := &RunSummary{StartedAt: time.Now(), DryRun: p.cfg.DryRun}
+	p.currentMu.Lock()
+	p.current = summary
+	p.currentMu.Unlock()
+	p.resetRefusedUsers()
+	p.resetReservedSpace()
+	p.resetQueuedDirs()
+	p.resetBrowsed()
+	p.resetQualityProfiles()
+	p.resetLidarrCache()
+	p.resetCutoffUnmet()
+	p.expireAutoIgnores()
+	p.declinedAll.Store(false)
+	p.lidarrRejected.Store(false)
+	defer func() {
+		span.SetAttributes(
+			attribute.Int("run.wanted", summary.Wanted),
+			attribute.Int("run.queued", summary.Queued),
+			attribute.Int("run.failed", summary.Failed),
+			attribute.Int("run.succeeded", summary.Succeeded),
+		)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+		p.saveSearches()
+		p.finishRun(summary, err)
+	}()
+
+	if err := p.checkServerState(ctx); err != nil {
+		return err
+	}
+
+	// Clear out old searches before adding more
+	if !p.cfg.DryRun {
+		p.pruneSearches(ctx)
+	}
+
+	// Drop wishlist entries for albums Lidarr no longer wants
+	if p.cfg.Search.WishlistOnDenylist && !p.cfg.DryRun && !isRequested(ctx) {
+		p.reconcileWishlist(ctx)
+	}
+
+	// Finish downloads queued before a restart ahead of a new search pass
+	var resumed map[int]bool
+	if !p.cfg.DryRun {
+		resumed, err = p.resumePendingDownloads(ctx, summary)
+		if err != nil {
+			return fmt.Errorf("resume pending downloads: %w", err)
+		}
+	}
+
 	// Phase 1: Fetch wanted albums from Lidarr
-	albums, err := p.fetchWantedAlbums(ctx)
+	phaseCtx, phaseSpan := p.startPhase(ctx, PhaseFetching)
+	albums, err := fetch(phaseCtx)
+	phaseSpan.End()
+	if errors.Is(err, lidarr.ErrUnauthorized) {
+		return fmt.Errorf("fetch wanted albums, check lidarr.api_key: %w", err)
+	}
 	if err != nil {
 		return fmt.Errorf("fetch wanted albums: %w", err)
 	}
 
+	// Lidarr may still list albums that were just resumed
+	if len(resumed) > 0 {
+		albums = slices.DeleteFunc(albums, func(album lidarr.Album) bool { return resumed[album.ID] })
+	}
+
+	summary.Wanted = len(albums)
 	if len(albums) == 0 {
 		p.logger.Info("no wanted albums found")
 		return nil
@@ -126,8 +444,36 @@ func (p *Processor) Run(ctx context.Context) error {
 
 	p.logger.Info("found wanted albums", "count", len(albums))
 
+	// Phase 2 to 5 overlap, monitoring each album as soon as it is queued
+	if !p.cfg.DryRun && !p.cfg.Download.SequentialPhases {
+		if err := p.searchAndDownload(ctx, albums, summary); err != nil {
+			return err
+		}
+		return p.lidarrRejectedErr()
+	}
+
 	// Phase 2: Search and queue downloads
-	downloadList, failedCount := p.searchAndQueueDownloads(ctx, albums)
+	phaseCtx, phaseSpan = p.startPhase(ctx, PhaseSearching)
+	downloadList, failedCount := p.searchAndQueueDownloads(phaseCtx, albums, nil)
+	phaseSpan.End()
+	summary.Failed = failedCount
+	summary.Deferred = summary.count(OutcomeDeferred)
+
+	if err := p.lidarrRejectedErr(); err != nil {
+		if !p.cfg.DryRun && len(downloadList) > 0 {
+			summary.Queued = len(downloadList)
+			p.savePending(downloadList) // Finished by the next run
+		}
+		return err
+	}
+
+	// A dry run stops before anything is downloaded, organized or imported
+	if p.cfg.DryRun {
+		summary.WouldDownload = len(downloadList)
+		p.logger.Info("dry run complete", "would_download", len(downloadList), "failed", failedCount)
+		return nil
+	}
+	summary.Queued = len(downloadList)
 
 	if len(downloadList) == 0 {
 		p.logger.Info("no albums matched, nothing to download")
@@ -135,38 +481,183 @@ func (p *Processor) Run(ctx context.Context) error {
 	}
 
 	p.logger.Info("queued downloads", "count", len(downloadList), "failed", failedCount)
+	p.savePending(downloadList)
 
-	// Phase 3: Monitor downloads
-	successfulDownloads, err := p.monitorDownloads(ctx, downloadList)
+	// Phase 3 to 5: Download, organize and import
+	successfulDownloads, err := p.downloadAndImport(ctx, downloadList)
+	summary.Succeeded += len(successfulDownloads)
 	if err != nil {
-		return fmt.Errorf("monitor downloads: %w", err)
+		return err
 	}
 
-	// Phase 4: Organize files
-	if err := p.organizeDownloads(successfulDownloads); err != nil {
-		return fmt.Errorf("organize downloads: %w", err)
+	// Phase 6: Save state
+	p.saveRunState(summary)
+	return nil
+}
+
+// saveRunState saves the denylist and wishlist once a run's downloads are done
+func (p *Processor) saveRunState(summary *RunSummary) {
+	if err := p.denylist.Save(); err != nil {
+		p.logger.Warn("failed to save denylist", "error", err)
+	}
+	if err := p.wishlist.Save(); err != nil {
+		p.logger.Warn("failed to save wishlist", "error", err)
+	}
+
+	p.logger.Info("processing complete", "successful", summary.Succeeded, "failed", summary.Failed, "deferred", summary.Deferred)
+}
+
+// downloadAndImport monitors queued downloads, organizes each album as soon as
+// it completes and triggers the Lidarr import, returning the completed items
+func (p *Processor) downloadAndImport(ctx context.Context, downloadList []DownloadedItem) ([]DownloadedItem, error) {
+	// Phase 3 and 4: Monitor downloads, organizing each album as soon as it completes
+	var organized []DownloadedItem
+	var organizeErr error
+	phaseCtx, phaseSpan := p.startPhase(ctx, PhaseDownloading)
+	successfulDownloads, err := p.monitorDownloads(phaseCtx, downloadList, nil, func(item DownloadedItem) {
+		if organizeErr != nil {
+			return
+		}
+		_, organizeSpan := p.startPhase(phaseCtx, PhaseOrganizing)
+		batch := []DownloadedItem{item}
+		organizeErr = p.organizeDownloads(batch)
+		organizeSpan.End()
+		p.setPhase(PhaseDownloading)
+		if organizeErr == nil {
+			organized = append(organized, batch...)
+			p.savePendingImports(batch)
+		}
+	})
+	phaseSpan.End()
+	if err != nil {
+		return nil, fmt.Errorf("monitor downloads: %w", err)
+	}
+
+	p.markUnfinished(downloadList, successfulDownloads)
+
+	if organizeErr != nil {
+		return successfulDownloads, fmt.Errorf("organize downloads: %w", organizeErr)
 	}
 
 	// Phase 5: Trigger Lidarr import
 	if !p.cfg.Lidarr.DisableSync {
-		if err := p.triggerImport(ctx, successfulDownloads); err != nil {
-			return fmt.Errorf("trigger import: %w", err)
+		phaseCtx, phaseSpan = p.startPhase(ctx, PhaseImporting)
+		err = p.triggerImport(phaseCtx, organized)
+		phaseSpan.End()
+		if err != nil {
+			return successfulDownloads, fmt.Errorf("trigger import: %w", err)
 		}
 	}
 
-	// Phase 6: Save state
-	if err := p.denylist.Save(); err != nil {
-		p.logger.Warn("failed to save denylist", "error", err)
+	return successfulDownloads, nil
+}
+
+// markUnfinished records the queued items that never completed as failed downloads
+func (p *Processor) markUnfinished(downloadList, successfulDownloads []DownloadedItem) {
+	completed := make(map[int]bool)
+	for _, item := range successfulDownloads {
+		completed[item.AlbumID] = true
+	}
+	var failed []int
+	for _, item := range downloadList {
+		if !completed[item.AlbumID] {
+			p.updateDecision(item.AlbumID, OutcomeFailed, ReasonDownloadFailed)
+			failed = append(failed, item.AlbumID)
+		}
+	}
+	if len(failed) > 0 {
+		p.clearPending(failed...)
 	}
+}
 
-	p.logger.Info("processing complete", "successful", len(successfulDownloads), "failed", failedCount)
-	return nil
+// Search sources for wanted albums
+const (
+	SourceMissing     = "missing"
+	SourceCutoffUnmet = "cutoff_unmet"
+	SourceAll         = "all"
+)
+
+// pageTrackFiles names the incrementing_page state file of each search source
+// The missing source keeps the original file name so existing positions survive upgrades
+var pageTrackFiles = map[string]string{
+	SourceMissing:     ".current_page.txt",
+	SourceCutoffUnmet: ".current_page_cutoff_unmet.txt",
 }
 
-// fetchWantedAlbums retrieves wanted albums from Lidarr with pagination
+// searchSources expands the configured search_source into Lidarr wanted endpoints
+func (p *Processor) searchSources() []string {
+	if p.cfg.Search.SearchSource == SourceAll {
+		return []string{SourceMissing, SourceCutoffUnmet}
+	}
+	return []string{p.cfg.Search.SearchSource}
+}
+
+// fetchWantedAlbums retrieves wanted albums from the configured search sources
+// With search_source "all", missing and cutoff-unmet albums are merged and
+// deduplicated by album ID
 func (p *Processor) fetchWantedAlbums(ctx context.Context) ([]lidarr.Album, error) {
+	var allAlbums []lidarr.Album
+	for _, source := range p.searchSources() {
+		albums, err := p.fetchWantedFromSource(ctx, source)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", source, err)
+		}
+		p.logger.Debug("fetched wanted albums", "source", source, "count", len(albums))
+		if source == SourceCutoffUnmet {
+			p.markCutoffUnmet(albums)
+		}
+		allAlbums = append(allAlbums, albums...)
+	}
+
+	// Filter out albums already in Lidarr's queue
+	albums, err := p.filterQueuedAlbums(ctx, p.dedupeAlbums(allAlbums))
+	if err != nil {
+		return nil, err
+	}
+
+	// Then albums slskd is still downloading from an earlier run
+	if p.cfg.Search.SkipActiveDownloads() && len(albums) > 0 {
+		albums = p.filterActiveDownloads(ctx, albums)
+	}
+	return albums, nil
+}
+
+// dedupeAlbums drops albums listed more than once, first by ID and then by
+// normalized artist and title, keeping the first occurrence
+func (p *Processor) dedupeAlbums(albums []lidarr.Album) []lidarr.Album {
+	byID := make(map[int]lidarr.Album)
+	byName := make(map[string]lidarr.Album)
+
+	var unique []lidarr.Album
+	for _, album := range albums {
+		if first, ok := byID[album.ID]; ok {
+			p.logger.Debug("skipping duplicate album", "album", album.Title, "albumID", album.ID, "duplicateOf", first.ID)
+			continue
+		}
+		byID[album.ID] = album
+
+		key := p.matcher.Normalize(album.Artist.ArtistName) + "\x00" + p.matcher.Normalize(album.Title)
+		if first, ok := byName[key]; ok {
+			p.logger.Debug("skipping duplicate album",
+				"album", album.Title,
+				"artist", album.Artist.ArtistName,
+				"albumID", album.ID,
+				"duplicateOf", first.ID)
+			continue
+		}
+		byName[key] = album
+		unique = append(unique, album)
+	}
+
+	return unique
+}
+
+// fetchWantedFromSource retrieves wanted albums from one Lidarr wanted endpoint with pagination
+func (p *Processor) fetchWantedFromSource(ctx context.Context, source string) ([]lidarr.Album, error) {
 	var allAlbums []lidarr.Album
 	searchType := p.cfg.Search.SearchType
+	missing := source != SourceCutoffUnmet
+	random := p.cfg.Search.SortKey == config.SortRandom
 
 	// Determine page size from config
 	pageSize := p.cfg.Search.NumberOfAlbumsToGrab
@@ -176,59 +667,69 @@ func (p *Processor) fetchWantedAlbums(ctx context.Context) ([]lidarr.Album, erro
 
 	switch searchType {
 	case "all":
-		// Fetch all pages
-		page := 1
-		for {
-			resp, err := p.lidarr.GetWanted(ctx, lidarr.GetWantedOptions{
-				Page:     page,
-				PageSize: pageSize,
-				Missing:  true,
-				SortKey:  p.cfg.Search.SortKey,
-				SortDir:  p.cfg.Search.SortDir,
-			})
-			if err != nil {
-				return nil, fmt.Errorf("fetch page %d: %w", page, err)
-			}
-
-			allAlbums = append(allAlbums, resp.Records...)
-
-			if len(allAlbums) >= resp.TotalRecords {
-				break
-			}
-			page++
+		albums, err := p.fetchAllWanted(ctx, pageSize, missing)
+		if err != nil {
+			return nil, err
+		}
+		allAlbums = albums
+		if random {
+			p.shuffleAlbums(allAlbums)
 		}
 
 	case "incrementing_page":
 		// Fetch current page and increment
-		page := p.pageTrack.Current()
-		resp, err := p.lidarr.GetWanted(ctx, lidarr.GetWantedOptions{
-			Page:     page,
-			PageSize: pageSize,
-			Missing:  true,
-			SortKey:  p.cfg.Search.SortKey,
-			SortDir:  p.cfg.Search.SortDir,
-		})
+		pageTrack := p.pageTrack[source]
+		page := pageTrack.Current()
+		resp, err := p.fetchWantedPage(ctx, page, pageSize, missing)
 		if err != nil {
 			return nil, fmt.Errorf("fetch page %d: %w", page, err)
 		}
 
+		// The saved page can be past the end once the wanted list shrinks or
+		// the page size changes, so start over rather than stall on it
+		if totalPages := (resp.TotalRecords + pageSize - 1) / pageSize; page > totalPages && totalPages > 0 {
+			p.logger.Debug("saved page is past the end of the wanted list, starting over",
+				"source", source, "page", page, "totalRecords", resp.TotalRecords)
+			page = 1
+			resp, err = p.fetchWantedPage(ctx, page, pageSize, missing)
+			if err != nil {
+				return nil, fmt.Errorf("fetch page %d: %w", page, err)
+			}
+			if !p.cfg.DryRun {
+				if err := pageTrack.Reset(); err != nil {
+					p.logger.Warn("failed to reset page", "source", source, "error", err)
+				}
+			}
+		}
+
 		allAlbums = resp.Records
+		if random {
+			p.shuffleAlbums(allAlbums)
+		}
 
 		// Calculate total pages and increment
 		totalPages := (resp.TotalRecords + pageSize - 1) / pageSize // Round up
-		if err := p.pageTrack.Next(totalPages); err != nil {
-			p.logger.Warn("failed to increment page", "error", err)
+		if p.cfg.DryRun {
+			break // Leave the position for the next real run
+		}
+		if err := pageTrack.Next(totalPages); err != nil {
+			p.logger.Warn("failed to increment page", "source", source, "error", err)
 		}
 
 	case "first_page":
+		// A random first page is drawn from the whole wanted list
+		if random {
+			albums, err := p.fetchAllWanted(ctx, pageSize, missing)
+			if err != nil {
+				return nil, err
+			}
+			p.shuffleAlbums(albums)
+			allAlbums = albums[:min(len(albums), pageSize)]
+			break
+		}
+
 		// Fetch only first page
-		resp, err := p.lidarr.GetWanted(ctx, lidarr.GetWantedOptions{
-			Page:     1,
-			PageSize: pageSize,
-			Missing:  true,
-			SortKey:  p.cfg.Search.SortKey,
-			SortDir:  p.cfg.Search.SortDir,
-		})
+		resp, err := p.fetchWantedPage(ctx, 1, pageSize, missing)
 		if err != nil {
 			return nil, fmt.Errorf("fetch first page: %w", err)
 		}
@@ -239,26 +740,69 @@ func (p *Processor) fetchWantedAlbums(ctx context.Context) ([]lidarr.Album, erro
 		return nil, fmt.Errorf("invalid search_type: %s", searchType)
 	}
 
-	// Filter out albums already in Lidarr's queue
-	return p.filterQueuedAlbums(ctx, allAlbums)
+	return allAlbums, nil
+}
+
+// shuffleAlbums puts the wanted albums in random order
+func (p *Processor) shuffleAlbums(albums []lidarr.Album) {
+	p.shuffler.Shuffle(len(albums), func(i, j int) {
+		albums[i], albums[j] = albums[j], albums[i]
+	})
 }
 
+// wantedOptions returns the request for a page of wanted albums in the configured order
+func (p *Processor) wantedOptions(page, pageSize int, missing bool) lidarr.GetWantedOptions {
+	opts := lidarr.GetWantedOptions{
+		Page:      page,
+		PageSize:  pageSize,
+		Missing:   missing,
+		SortKey:   p.cfg.Search.LidarrSortKey(),
+		Monitored: true,
+	}
+	if opts.SortKey != "" {
+		opts.SortDir = p.cfg.Search.SortDir
+	}
+	return opts
+}
+
+// fetchWantedPage retrieves one page of wanted albums in the configured order
+func (p *Processor) fetchWantedPage(ctx context.Context, page, pageSize int, missing bool) (*lidarr.WantedResponse, error) {
+	return p.lidarr.GetWanted(ctx, p.wantedOptions(page, pageSize, missing))
+}
+
+// fetchAllWanted retrieves every page of wanted albums
+func (p *Processor) fetchAllWanted(ctx context.Context, pageSize int, missing bool) ([]lidarr.Album, error) {
+	albums, err := p.lidarr.GetAllWanted(ctx, p.wantedOptions(1, pageSize, missing))
+	if err != nil {
+		return nil, fmt.Errorf("fetch all pages: %w", err)
+	}
+	return albums, nil
+}
+
+// queuePageSize is how many queue entries are fetched per request
+const queuePageSize = 1000
+
 // filterQueuedAlbums removes albums that are already in Lidarr's download queue
 func (p *Processor) filterQueuedAlbums(ctx context.Context, albums []lidarr.Album) ([]lidarr.Album, error) {
-	queue, err := p.lidarr.GetQueue(ctx, 1, 1000) // page=1, pageSize=1000
+	queue, err := p.lidarr.GetAllQueue(ctx, queuePageSize)
 	if err != nil {
 		p.logger.Warn("failed to fetch queue, skipping queue filtering", "error", err)
 		return albums, nil
 	}
 
-	// Build set of queued album IDs
+	// Build set of queued album IDs, leaving out stale entries seekarr removed
 	queuedAlbums := make(map[int]bool)
-	for _, item := range queue.Records {
+	for _, item := range p.clearStaleQueueItems(ctx, queue, albums) {
 		if item.AlbumID != nil && *item.AlbumID > 0 {
 			queuedAlbums[*item.AlbumID] = true
 		}
 	}
 
+	// Grabs that already finished have left the queue
+	for albumID := range p.recentlyHandledAlbums(ctx) {
+		queuedAlbums[albumID] = true
+	}
+
 	// Filter albums
 	var filtered []lidarr.Album
 	for _, album := range albums {
@@ -266,6 +810,7 @@ func (p *Processor) filterQueuedAlbums(ctx context.Context, albums []lidarr.Albu
 			filtered = append(filtered, album)
 		} else {
 			p.logger.Debug("skipping queued album", "album", album.Title, "artist", album.Artist.ArtistName)
+			p.recordDecision(album, OutcomeSkipped, ReasonQueued, "")
 		}
 	}
 
@@ -273,81 +818,522 @@ func (p *Processor) filterQueuedAlbums(ctx context.Context, albums []lidarr.Albu
 }
 
 // searchAndQueueDownloads searches for albums and queues downloads
-func (p *Processor) searchAndQueueDownloads(ctx context.Context, albums []lidarr.Album) ([]DownloadedItem, int) {
+// Up to search.concurrent_searches albums are searched at once
+// Each queued item is also recorded as pending and sent to queued, if set, as
+// soon as its album is queued
+func (p *Processor) searchAndQueueDownloads(ctx context.Context, albums []lidarr.Album, queued chan<- DownloadedItem) ([]DownloadedItem, int) {
+	workers := max(p.cfg.Search.ConcurrentSearches, 1)
+
+	// Results are stored by album index so the download list keeps the wanted order
+	items := make([]DownloadedItem, len(albums))
+	outcomes := make([]string, len(albums))
+
+	jobs := make(chan int)
+	var budgetDeferred atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				if reason, ok := unmonitored(ctx, albums[idx], p.cfg.Search.SkipUnmonitoredArtist()); ok {
+					p.logger.Info("skipping unmonitored album",
+						"album", albums[idx].Title,
+						"artist", albums[idx].Artist.ArtistName,
+						"reason", reason,
+						"monitorNewItems", albums[idx].Artist.MonitorNewItems,
+						"metadataProfileID", albums[idx].Artist.MetadataProfileID)
+					p.recordDecision(albums[idx], OutcomeSkipped, reason, "")
+					outcomes[idx] = OutcomeSkipped
+					continue
+				}
+				// Albums past the run's budget wait for the next run
+				if limit, ok := p.claimBudget(ctx); !ok {
+					p.logger.Debug("deferring album, run budget reached", "album", albums[idx].Title, "limit", limit)
+					p.recordDecision(albums[idx], OutcomeDeferred, ReasonBudget, "")
+					outcomes[idx] = OutcomeDeferred
+					budgetDeferred.Add(1)
+					continue
+				}
+				items[idx], outcomes[idx] = p.queueAlbum(ctx, albums[idx])
+				if outcomes[idx] == OutcomeQueued || outcomes[idx] == OutcomeWouldDownload {
+					p.confirmBudget()
+				} else {
+					p.releaseBudget()
+				}
+				if queued != nil && outcomes[idx] == OutcomeQueued {
+					p.savePending([]DownloadedItem{items[idx]})
+					queued <- items[idx]
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for idx := range albums {
+		select {
+		case jobs <- idx:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if n := budgetDeferred.Load(); n > 0 {
+		p.logger.Info("run budget reached, remaining albums left for the next run", "deferred", n)
+	}
+
 	var downloadList []DownloadedItem
 	failedCount := 0
+	for idx, outcome := range outcomes {
+		switch outcome {
+		case OutcomeQueued, OutcomeWouldDownload:
+			downloadList = append(downloadList, items[idx])
+		case OutcomeFailed:
+			failedCount++
+		}
+	}
 
-	for _, album := range albums {
-		// Check title blacklist
-		albumTitle := strings.ToLower(album.Title)
-		blacklisted := false
-		for _, term := range p.cfg.Search.TitleBlacklist {
-			if strings.Contains(albumTitle, strings.ToLower(term)) {
-				p.logger.Debug("skipping blacklisted album",
-					"album", album.Title,
-					"artist", album.Artist.ArtistName,
-					"term", term)
-				blacklisted = true
-				break
-			}
+	return downloadList, failedCount
+}
+
+// queueAlbum runs the blacklist, denylist, release and search steps for one album
+// and records the resulting decision
+func (p *Processor) queueAlbum(ctx context.Context, album lidarr.Album) (item DownloadedItem, outcome string) {
+	ctx, span := tracer.Start(ctx, "album", trace.WithAttributes(
+		attribute.Int("album.id", album.ID),
+		attribute.String("album.title", album.Title),
+		attribute.String("album.artist", album.Artist.ArtistName),
+		attribute.String("album.mbid", album.ForeignAlbumID),
+	))
+	defer func() {
+		span.SetAttributes(attribute.String("album.outcome", outcome))
+		span.End()
+	}()
+	logger := p.albumLogger(album, nil)
+
+	// Check title blacklist
+	if term, ok := p.blacklistedBy(album.Title); ok {
+		level := slog.LevelDebug
+		if isRequested(ctx) {
+			level = slog.LevelWarn
 		}
-		if blacklisted {
-			continue
+		logger.Log(ctx, level, "skipping blacklisted album",
+			"album", album.Title,
+			"artist", album.Artist.ArtistName,
+			"term", term)
+		p.recordDecision(album, OutcomeSkipped, ReasonBlacklist, "")
+		return DownloadedItem{}, OutcomeSkipped
+	}
+
+	// Check album types
+	if albumType, ok := p.excludedAlbumType(album); ok {
+		level := slog.LevelDebug
+		if isRequested(ctx) {
+			level = slog.LevelWarn
 		}
+		logger.Log(ctx, level, "skipping excluded album type",
+			"album", album.Title,
+			"artist", album.Artist.ArtistName,
+			"type", albumType)
+		p.recordDecision(album, OutcomeSkipped, ReasonAlbumType, "")
+		return DownloadedItem{}, OutcomeSkipped
+	}
+
+	// Check denylist
+	if p.denylist.IsDenylisted(album.ID, p.cfg.Search.MaxSearchFailures) && isRequested(ctx) {
+		logger.Info("searching denylisted album because it was requested",
+			"album", album.Title,
+			"artist", album.Artist.ArtistName,
+			"failures", p.denylist.GetEntry(album.ID).Failures)
+	} else if p.denylist.IsDenylisted(album.ID, p.cfg.Search.MaxSearchFailures) {
+		entry := p.denylist.GetEntry(album.ID)
+		logger.Debug("skipping denylisted album",
+			"album", album.Title,
+			"artist", album.Artist.ArtistName,
+			"failures", entry.Failures)
+		p.recordDecision(album, OutcomeSkipped, ReasonDenylist, "")
+		return DownloadedItem{}, OutcomeSkipped
+	}
+
+	if p.declinedAll.Load() {
+		logger.Debug("skipping album, remaining albums were declined", "album", album.Title)
+		p.recordDecision(album, OutcomeSkipped, ReasonDeclined, "")
+		return DownloadedItem{}, OutcomeSkipped
+	}
+
+	if p.lidarrRejected.Load() {
+		logger.Debug("skipping album, lidarr rejected the API key", "album", album.Title)
+		p.recordDecision(album, OutcomeSkipped, ReasonUnavailable, "")
+		return DownloadedItem{}, OutcomeSkipped
+	}
+
+	// Bound the album's Lidarr and slskd calls so one wedged album can't stall
+	// the run. Failures are recorded with the run's context, which outlives it
+	runCtx := ctx
+	if timeout := time.Duration(p.cfg.Timing.PerAlbumTimeoutSeconds) * time.Second; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = withAlbumTimer(ctx, timeout)
+		defer cancel()
+	}
+
+	ctx = withFilter(ctx, p.albumFilter(ctx, album))
 
-		// Check denylist
-		if p.denylist.IsDenylisted(album.ID, p.cfg.Search.MaxSearchFailures) {
-			entry := p.denylist.GetEntry(album.ID)
-			p.logger.Debug("skipping denylisted album",
+	// Choose best release
+	release, err := p.chooseRelease(ctx, album)
+	if err != nil {
+		logger.Warn("failed to choose release",
+			"album", album.Title,
+			"error", err)
+		return DownloadedItem{}, p.lidarrFailed(runCtx, album, err)
+	}
+	logger = p.albumLogger(album, release)
+	span.SetAttributes(attribute.String("album.release_mbid", release.ForeignReleaseID))
+
+	// Get the chosen release's tracks, not those of Lidarr's default release
+	tracks, err := p.lidarrTracks(ctx, album.ID, release.ID)
+	if err != nil {
+		logger.Warn("failed to fetch tracks",
+			"album", album.Title,
+			"error", err)
+		return DownloadedItem{}, p.lidarrFailed(runCtx, album, &unavailableError{service: "lidarr", err: err})
+	}
+	tracks = p.resolveTracks(ctx, album, release, tracks)
+	if p.isCutoffUnmet(album.ID) {
+		ctx, tracks = p.cutoffSearch(ctx, album, tracks)
+	}
+
+	// Attempt to search and download, retrying with the other query form
+	queries := p.albumQueries(album)
+	query := queries[0]
+	item, err = p.searchForAlbum(ctx, query, tracks, album, release)
+	for _, alt := range queries[1:] {
+		if !noCandidates(err) {
+			break
+		}
+		logger.Debug("retrying with alternate query form", "album", album.Title, "query", alt)
+		if altItem, altErr := p.searchForAlbum(ctx, alt, tracks, album, release); altErr == nil {
+			item, query, err = altItem, alt, nil
+		} else if isUnavailable(altErr) {
+			err = altErr
+		}
+	}
+	if err == nil {
+		logger.Debug("album query matched", "album", album.Title, "query", query)
+	}
+	if noCandidates(err) {
+		if aliasItem, aliasQuery, ok := p.searchArtistAliases(ctx, tracks, album, release); ok {
+			item, query, err = aliasItem, aliasQuery, nil
+		}
+	}
+	if p.cfg.Search.SearchForTracks && noCandidates(err) {
+		// The whole fallback counts as one failed attempt below, so
+		// max_search_failures bounds how often an album is searched track by track
+		if trackItem, trackErr := p.searchForTracks(ctx, tracks, album, release); trackErr == nil {
+			item, err = trackItem, nil
+		} else if errors.Is(trackErr, context.Canceled) || errors.Is(trackErr, errInsufficientSpace) || errors.Is(trackErr, errDeclined) || isUnavailable(trackErr) {
+			err = trackErr
+		}
+	}
+	if err != nil && runCtx.Err() != nil {
+		// An interrupted search doesn't count against the album
+		p.recordDecision(album, OutcomeFailed, ReasonError, query)
+		return DownloadedItem{}, OutcomeFailed
+	}
+	if errors.Is(err, errDeclined) {
+		// Declining is not a failed search, so the album isn't denylisted
+		p.recordDecision(album, OutcomeSkipped, ReasonDeclined, query)
+		return DownloadedItem{}, OutcomeSkipped
+	}
+	if err != nil && ctx.Err() != nil {
+		// Only this album ran out of time; it counts like finding no match
+		err = errAlbumTimeout
+	}
+	if errors.Is(err, errInsufficientSpace) {
+		// Deferred albums are searched again once there is room
+		p.recordDecision(album, OutcomeDeferred, ReasonDiskSpace, query)
+		return DownloadedItem{}, OutcomeDeferred
+	}
+	if err != nil {
+		if !isUnavailable(err) {
+			logger.Warn("no match found",
 				"album", album.Title,
 				"artist", album.Artist.ArtistName,
-				"failures", entry.Failures)
-			continue
+				"reason", err)
 		}
+		return DownloadedItem{}, p.searchFailed(runCtx, album, err, query)
+	}
 
-		// Choose best release
-		release, err := p.chooseRelease(ctx, album)
-		if err != nil {
-			p.logger.Warn("failed to choose release",
-				"album", album.Title,
-				"error", err)
-			p.denylist.RecordAttempt(album.ID, false)
-			failedCount++
+	item.ArtistFolder = p.artistFolder(runCtx, album)
+	if p.cfg.DryRun {
+		p.recordDecision(album, OutcomeWouldDownload, "", query)
+		return item, OutcomeWouldDownload
+	}
+
+	p.denylist.RecordAttempt(album.ID, true)
+	logger.Info("queued download",
+		"album", album.Title,
+		"artist", album.Artist.ArtistName,
+		"sources", len(item.Sources))
+	p.recordDecision(album, OutcomeQueued, "", query)
+	return item, OutcomeQueued
+}
+
+// albumLogger returns the logger for an album's decisions, tagging each line
+// with its MusicBrainz release group and, once chosen, release IDs
+func (p *Processor) albumLogger(album lidarr.Album, release *lidarr.Release) *slog.Logger {
+	logger := p.logger
+	if album.ForeignAlbumID != "" {
+		logger = logger.With("releaseGroupMBID", album.ForeignAlbumID)
+	}
+	if release != nil && release.ForeignReleaseID != "" {
+		logger = logger.With("releaseMBID", release.ForeignReleaseID)
+	}
+	return logger
+}
+
+// blacklistedBy returns the title_blacklist entry that matches an album title
+// Plain entries match as case-insensitive substrings, "re:" entries as regular expressions
+func (p *Processor) blacklistedBy(title string) (string, bool) {
+	lower := strings.ToLower(title)
+	for _, term := range p.cfg.Search.TitleBlacklist {
+		if strings.HasPrefix(term, config.TitleBlacklistRegexPrefix) {
 			continue
 		}
+		if strings.Contains(lower, strings.ToLower(term)) {
+			return term, true
+		}
+	}
+	for _, re := range p.titleBlacklist {
+		if re.MatchString(title) {
+			return config.TitleBlacklistRegexPrefix + re.String(), true
+		}
+	}
+	return "", false
+}
 
-		// Get tracks
-		tracks, err := p.lidarr.GetTracks(ctx, album.ID, nil)
-		if err != nil {
-			p.logger.Warn("failed to fetch tracks",
+// lidarrPath converts a local path to the path Lidarr sees, reporting false
+// when no local_to_lidarr mapping applies
+func (p *Processor) lidarrPath(localPath string) (string, bool) {
+	mapped, ok := p.toLidarr.Map(localPath)
+	if ok {
+		p.logger.Debug("applied local_to_lidarr path mapping", "from", localPath, "to", mapped)
+	}
+	return mapped, ok
+}
+
+// albumQuery builds the slskd search text for an album
+func albumQuery(album lidarr.Album) string {
+	if isVariousArtists(album) {
+		return album.Title // The artist name only narrows results for compilations
+	}
+	return fmt.Sprintf("%s %s", album.Artist.ArtistName, album.Title)
+}
+
+// albumQueries returns the query forms to try for an album, preferred first
+// album_prepend_artist chooses between "Artist Title" and "Title"; the other
+// form is the fallback. Both are sanitized, and the raw preferred form is tried
+// last for titles whose punctuation or qualifiers matter
+func (p *Processor) albumQueries(album lidarr.Album) []string {
+	withArtist := albumQuery(album)
+	titleOnly := album.Title
+
+	queries := []string{titleOnly, withArtist}
+	if p.cfg.Search.AlbumPrependArtist {
+		queries = []string{withArtist, titleOnly}
+	}
+
+	if strings.TrimSpace(album.Artist.ArtistName) == "" || isVariousArtists(album) {
+		queries = queries[:1]
+	}
+
+	raw := queries[0]
+	for i, query := range queries {
+		queries[i] = p.sanitizeQuery(query)
+	}
+	if !slices.Contains(queries, raw) {
+		queries = append(queries, raw)
+	}
+	return queries
+}
+
+// noCandidates reports whether a search error means nothing qualified, as
+// opposed to the search itself failing
+func noCandidates(err error) bool {
+	return errors.Is(err, errNoResults) || errors.Is(err, errNoMatch) || errors.Is(err, errEnqueueFailed)
+}
+
+// maxAliasQueries bounds how many artist aliases are tried after the primary query fails
+const maxAliasQueries = 3
+
+// searchArtistAliases retries an album search once per Lidarr artist alias
+// Returns the queued item and the query that matched, or ok=false if none did
+func (p *Processor) searchArtistAliases(ctx context.Context, tracks []lidarr.Track, album lidarr.Album, release *lidarr.Release) (DownloadedItem, string, bool) {
+	if isVariousArtists(album) {
+		return DownloadedItem{}, "", false
+	}
+	artist, err := p.lidarr.GetArtist(ctx, album.ArtistID)
+	if err != nil {
+		p.logger.Debug("failed to fetch artist aliases", "album", album.Title, "artist", album.Artist.ArtistName, "error", err)
+		return DownloadedItem{}, "", false
+	}
+
+	aliases := artistAliases(album.Artist.ArtistName, artist.Aliases)
+	for _, alias := range aliases {
+		query := p.sanitizeQuery(fmt.Sprintf("%s %s", alias, album.Title))
+		p.logger.Info("retrying search with artist alias",
+			"album", album.Title,
+			"artist", album.Artist.ArtistName,
+			"alias", alias)
+
+		item, err := p.searchForAlbum(ctx, query, tracks, album, release)
+		if err == nil {
+			p.logger.Info("artist alias produced a match",
 				"album", album.Title,
-				"error", err)
-			p.denylist.RecordAttempt(album.ID, false)
-			failedCount++
+				"artist", album.Artist.ArtistName,
+				"alias", alias)
+			return item, query, true
+		}
+	}
+
+	if len(aliases) > 0 {
+		p.logger.Debug("no artist alias produced a match", "album", album.Title, "tried", len(aliases))
+	}
+	return DownloadedItem{}, "", false
+}
+
+// artistAliases returns up to maxAliasQueries distinct aliases that differ from the artist name
+func artistAliases(name string, aliases []string) []string {
+	seen := map[string]bool{strings.ToLower(strings.TrimSpace(name)): true}
+	var result []string
+	for _, alias := range aliases {
+		alias = strings.TrimSpace(alias)
+		key := strings.ToLower(alias)
+		if alias == "" || seen[key] {
 			continue
 		}
+		seen[key] = true
+		result = append(result, alias)
+		if len(result) == maxAliasQueries {
+			break
+		}
+	}
+	return result
+}
 
-		// Attempt to search and download
-		query := fmt.Sprintf("%s %s", album.Artist.ArtistName, album.Title)
-		item, found := p.searchForAlbum(ctx, query, tracks, album, release)
+// searchFailed records the decision for an album that wasn't queued and returns its outcome
+// Only searches that ran and found nothing usable count toward max_search_failures;
+// failures reaching Lidarr or slskd leave the denylist untouched
+func (p *Processor) searchFailed(ctx context.Context, album lidarr.Album, err error, query string) string {
+	reason := ReasonError
+	switch {
+	case isUnavailable(err):
+		p.logger.Warn("search failed on our side, not counting it against the album",
+			"album", album.Title,
+			"artist", album.Artist.ArtistName,
+			"error", err)
+		p.recordDecision(album, OutcomeFailed, ReasonUnavailable, query)
+		return OutcomeFailed
+	case errors.Is(err, errNoResults):
+		reason = ReasonNoResults
+	case errors.Is(err, errNoMatch):
+		reason = ReasonNoQualityMatch
+	case errors.Is(err, errEnqueueFailed):
+		reason = ReasonEnqueueFailed
+	case errors.Is(err, errAlbumTimeout):
+		reason = ReasonTimeout
+	}
 
-		if found {
-			downloadList = append(downloadList, item)
-			p.denylist.RecordAttempt(album.ID, true)
-			p.logger.Info("queued download",
-				"album", album.Title,
-				"artist", album.Artist.ArtistName,
-				"username", item.Username)
-		} else {
-			p.denylist.RecordAttempt(album.ID, false)
-			failedCount++
-			p.logger.Warn("no match found",
-				"album", album.Title,
-				"artist", album.Artist.ArtistName)
+	p.recordSearchFailure(ctx, album)
+	p.recordDecision(album, OutcomeFailed, reason, query)
+	return OutcomeFailed
+}
+
+// errLidarrRejected fails a run in which Lidarr refused the API key
+var errLidarrRejected = errors.New("lidarr rejected the API key, check lidarr.api_key")
+
+// lidarrRejectedErr returns errLidarrRejected if Lidarr refused the API key
+// during the current run
+func (p *Processor) lidarrRejectedErr() error {
+	if p.lidarrRejected.Load() {
+		return errLidarrRejected
+	}
+	return nil
+}
+
+// lidarrFailed records the decision for an album whose Lidarr lookups failed
+// and returns its outcome. An album deleted from Lidarr since it was listed is
+// skipped without counting against it, and a refused API key stops the run's
+// remaining searches
+func (p *Processor) lidarrFailed(ctx context.Context, album lidarr.Album, err error) string {
+	switch {
+	case errors.Is(err, lidarr.ErrNotFound):
+		p.logger.Info("skipping album no longer in Lidarr",
+			"album", album.Title,
+			"artist", album.Artist.ArtistName)
+		p.recordDecision(album, OutcomeSkipped, ReasonNotInLidarr, "")
+		return OutcomeSkipped
+	case errors.Is(err, lidarr.ErrUnauthorized):
+		if !p.lidarrRejected.Swap(true) {
+			p.logger.Error("lidarr rejected the API key, skipping the remaining albums; check lidarr.api_key", "error", err)
 		}
+		p.recordDecision(album, OutcomeFailed, ReasonUnavailable, "")
+		return OutcomeFailed
 	}
+	return p.searchFailed(ctx, album, err, "")
+}
 
-	return downloadList, failedCount
+// recordSearchFailure records a failed attempt for an album and, once it reaches
+// the failure limit, hands it off to the slskd wishlist if configured
+func (p *Processor) recordSearchFailure(ctx context.Context, album lidarr.Album) {
+	if p.cfg.DryRun {
+		return // Dry runs leave the denylist untouched
+	}
+	p.denylist.RecordAttempt(album.ID, false)
+
+	if !p.denylist.IsDenylisted(album.ID, p.cfg.Search.MaxSearchFailures) {
+		return
+	}
+
+	if p.cfg.Search.WishlistOnDenylist {
+		p.registerWishlist(ctx, album)
+	}
+	if p.cfg.Search.RemoveWantedOnFailure && p.unmonitorAlbum(ctx, album) {
+		// Keeps reconcileWishlist from taking the album for found
+		p.wishlist.MarkUnmonitored(album.ID)
+	}
+}
+
+// unmonitorAlbum stops Lidarr monitoring an album, removing it from the wanted list
+// It reports whether the album was unmonitored
+func (p *Processor) unmonitorAlbum(ctx context.Context, album lidarr.Album) bool {
+	fullAlbum, err := p.lidarr.GetAlbum(ctx, album.ID)
+	if err != nil {
+		p.logger.Warn("failed to fetch album to unmonitor",
+			"album", album.Title,
+			"artist", album.Artist.ArtistName,
+			"error", err)
+		return false
+	}
+
+	fullAlbum.Monitored = false
+	if _, err := p.lidarr.UpdateAlbum(ctx, fullAlbum); err != nil {
+		p.logger.Warn("failed to unmonitor album",
+			"album", album.Title,
+			"artist", album.Artist.ArtistName,
+			"error", err)
+		return false
+	}
+
+	p.logger.Info("unmonitored album after repeated search failures",
+		"album", album.Title,
+		"artist", album.Artist.ArtistName,
+		"failures", p.cfg.Search.MaxSearchFailures)
+	return true
 }
 
 // chooseRelease selects the best release variant for an album
@@ -355,9 +1341,9 @@ func (p *Processor) chooseRelease(ctx context.Context, album lidarr.Album) (*lid
 	// If album already has releases, use them; otherwise fetch
 	releases := album.Releases
 	if len(releases) == 0 {
-		fullAlbum, err := p.lidarr.GetAlbum(ctx, album.ID)
+		fullAlbum, err := p.lidarrAlbum(ctx, album.ID)
 		if err != nil {
-			return nil, fmt.Errorf("fetch album: %w", err)
+			return nil, &unavailableError{service: "lidarr", err: fmt.Errorf("fetch album: %w", err)}
 		}
 		releases = fullAlbum.Releases
 	}
@@ -366,51 +1352,206 @@ func (p *Processor) chooseRelease(ctx context.Context, album lidarr.Album) (*lid
 		return nil, fmt.Errorf("no releases available")
 	}
 
-	// Find most common track count
-	trackCounts := make(map[int]int)
-	for _, r := range releases {
-		trackCounts[r.TrackCount]++
+	// Apply the release: constraints, falling back to every release if none survive
+	candidates := p.filterReleases(album, releases)
+	if len(candidates) == 0 {
+		candidates = releases
 	}
 
 	mostCommonCount := 0
-	maxOccurrences := 0
-	for count, occurrences := range trackCounts {
-		if occurrences > maxOccurrences {
-			mostCommonCount = count
-			maxOccurrences = occurrences
+	if p.cfg.Release.MostCommonTrackNum() {
+		// Find most common track count
+		trackCounts := make(map[int]int)
+		for _, r := range candidates {
+			trackCounts[r.TrackCount]++
 		}
-	}
 
-	// Try to find matching release - prefer official releases with most common track count
-	for _, release := range releases {
-		if release.Status == "Official" && release.TrackCount == mostCommonCount {
-			p.logger.Debug("selected release",
-				"album", album.Title,
-				"format", release.Format,
-				"country", release.Country,
-				"tracks", release.TrackCount)
-			return &release, nil
+		maxOccurrences := 0
+		for count, occurrences := range trackCounts {
+			if occurrences > maxOccurrences || (occurrences == maxOccurrences && count < mostCommonCount) {
+				mostCommonCount = count
+				maxOccurrences = occurrences
+			}
+		}
+
+		// Try to find matching release - prefer official releases with most common track count
+		for _, release := range candidates {
+			if release.Status == "Official" && release.TrackCount == mostCommonCount {
+				p.logger.Debug("selected release",
+					"album", album.Title,
+					"releaseID", release.ID,
+					"format", release.Format,
+					"country", release.Country,
+					"tracks", release.TrackCount)
+				return &release, nil
+			}
 		}
 	}
 
 	// Fallback: first official release
-	for _, release := range releases {
+	for _, release := range candidates {
 		if release.Status == "Official" {
 			p.logger.Debug("selected first official release",
 				"album", album.Title,
+				"releaseID", release.ID,
 				"format", release.Format)
 			return &release, nil
 		}
 	}
 
 	// Fallback: return first release
-	p.logger.Debug("no ideal release found, using first available", "album", album.Title)
-	return &releases[0], nil
+	p.logger.Debug("no ideal release found, using first available", "album", album.Title, "releaseID", candidates[0].ID)
+	return &candidates[0], nil
 }
 
-// searchForAlbum searches Slskd for an album and queues download if found
-func (p *Processor) searchForAlbum(ctx context.Context, query string, tracks []lidarr.Track, album lidarr.Album, release *lidarr.Release) (DownloadedItem, bool) {
-	p.logger.Info("searching", "query", query)
+// filterReleases applies the region, format and multi-disc constraints in turn
+// Returns nil, after logging the constraint responsible, if a step removes every release
+func (p *Processor) filterReleases(album lidarr.Album, releases []lidarr.Release) []lidarr.Release {
+	type constraint struct {
+		name   string
+		active bool
+		accept func(lidarr.Release) bool
+	}
+
+	settings := p.cfg.Release
+	constraints := []constraint{
+		{
+			name:   "accepted_countries",
+			active: !settings.SkipRegionCheck && len(settings.AcceptedCountries) > 0,
+			accept: func(r lidarr.Release) bool { return containsAnyFold(settings.AcceptedCountries, r.Country...) },
+		},
+		{
+			name:   "accepted_formats",
+			active: len(settings.AcceptedFormats) > 0,
+			accept: func(r lidarr.Release) bool { return containsAnyFold(settings.AcceptedFormats, releaseFormat(r.Format)) },
+		},
+		{
+			name:   "allow_multi_disc",
+			active: !settings.MultiDiscAllowed(),
+			accept: func(r lidarr.Release) bool { return r.MediumCount <= 1 },
+		},
+	}
+
+	candidates := releases
+	for _, c := range constraints {
+		if !c.active {
+			continue
+		}
+
+		var kept []lidarr.Release
+		for _, r := range candidates {
+			if c.accept(r) {
+				kept = append(kept, r)
+			}
+		}
+
+		if len(kept) == 0 {
+			p.logger.Warn("release constraint eliminated every release, ignoring release settings",
+				"album", album.Title,
+				"artist", album.Artist.ArtistName,
+				"constraint", c.name,
+				"releases", len(candidates))
+			return nil
+		}
+		candidates = kept
+	}
+
+	return candidates
+}
+
+// releaseFormat strips the medium count from a Lidarr format, e.g. "2xCD" -> "CD"
+func releaseFormat(format string) string {
+	if i := strings.Index(format, "x"); i > 0 {
+		if _, err := strconv.Atoi(format[:i]); err == nil {
+			return format[i+1:]
+		}
+	}
+	return format
+}
+
+// containsAnyFold reports whether any of values is in list, ignoring case
+func containsAnyFold(list []string, values ...string) bool {
+	for _, v := range values {
+		for _, item := range list {
+			if strings.EqualFold(strings.TrimSpace(item), strings.TrimSpace(v)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Sentinel errors returned by searchForAlbum when nothing was queued
+var (
+	errNoResults = errors.New("no search results")
+	errNoMatch   = errors.New("no result matched quality and track requirements")
+
+	// errEnqueueFailed means directories matched but every user refused the download
+	errEnqueueFailed = errors.New("every matching user refused the download")
+
+	// errAlbumTimeout means the album's search ran past per_album_timeout_seconds
+	errAlbumTimeout = errors.New("album search timed out")
+)
+
+// unavailableError is a failed request to Lidarr or slskd, as opposed to a
+// search that ran and found nothing
+type unavailableError struct {
+	service string
+	err     error
+}
+
+func (e *unavailableError) Error() string {
+	return fmt.Sprintf("%s unavailable: %v", e.service, e.err)
+}
+
+func (e *unavailableError) Unwrap() error {
+	return e.err
+}
+
+// isUnavailable reports whether err came from Lidarr or slskd being unreachable or failing
+func isUnavailable(err error) bool {
+	var unavailable *unavailableError
+	return errors.As(err, &unavailable)
+}
+
+// deleteSearchTimeout bounds the cleanup of a search, which may run after the
+// album's context was cancelled
+const deleteSearchTimeout = 10 * time.Second
+
+// searchStateTimeout bounds each poll of a search's state, which is small and
+// polled often, so a slow answer counts as a failed check instead of holding up the wait
+const searchStateTimeout = 10 * time.Second
+
+// maxSearchStateFailures is how many search state checks in a row may fail
+// before the wait for a search is abandoned and its results fetched anyway
+const maxSearchStateFailures = 3
+
+// errSearchExpired is returned when slskd no longer has a search by the time
+// its results are fetched
+var errSearchExpired = errors.New("search expired in slskd")
+
+// runSearch executes a slskd search, waits for it to complete and returns its
+// results, searching again once if slskd expired the search before they were fetched
+func (p *Processor) runSearch(ctx context.Context, album lidarr.Album, query string) ([]slskd.SearchResult, error) {
+	results, err := p.executeSearch(ctx, album, query)
+	if errors.Is(err, errSearchExpired) {
+		p.logger.Info("search expired before its results were fetched, searching again", "album", album.Title, "query", query)
+		results, err = p.executeSearch(ctx, album, query)
+	}
+	if errors.Is(err, errSearchExpired) {
+		p.logger.Warn("failed to get search results", "album", album.Title, "error", err)
+		return nil, &unavailableError{service: "slskd", err: fmt.Errorf("get search results: %w", err)}
+	}
+	return results, err
+}
+
+// executeSearch makes a single slskd search and returns its results
+func (p *Processor) executeSearch(ctx context.Context, album lidarr.Album, query string) ([]slskd.SearchResult, error) {
+	if err := p.waitForSearchSlot(ctx); err != nil {
+		return nil, err
+	}
+
+	p.logger.Info("searching", "album", album.Title, "query", query)
 
 	// Execute search
 	searchReq := slskd.SearchRequest{
@@ -423,90 +1564,287 @@ func (p *Processor) searchForAlbum(ctx context.Context, query string, tracks []l
 
 	searchResp, err := p.slskd.Search(ctx, searchReq)
 	if err != nil {
-		p.logger.Warn("search failed", "error", err)
-		return DownloadedItem{}, false
+		p.logger.Warn("search failed", "album", album.Title, "error", err)
+		return nil, &unavailableError{service: "slskd", err: fmt.Errorf("search: %w", err)}
 	}
 
-	p.logger.Debug("search initiated", "searchID", searchResp.ID, "state", searchResp.State)
+	p.logger.Debug("search initiated", "album", album.Title, "searchID", searchResp.ID, "state", searchResp.State)
+	p.recordSearch(searchResp.ID, query)
 
-	// Delete search when done if configured
-	if p.cfg.Slskd.DeleteSearches {
-		defer func() {
-			if err := p.slskd.DeleteSearch(ctx, searchResp.ID); err != nil {
-				p.logger.Debug("failed to delete search", "searchID", searchResp.ID, "error", err)
+	// Stop the search if the wait for it was cancelled, and delete it when
+	// done if configured, even if ctx has expired by then
+	finished := false
+	defer func() {
+		if finished && !p.cfg.Slskd.DeleteSearches {
+			return
+		}
+		cleanupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), deleteSearchTimeout)
+		defer cancel()
+		if !finished && ctx.Err() != nil {
+			if err := p.slskd.StopSearch(cleanupCtx, searchResp.ID); err != nil {
+				p.logger.Debug("failed to stop search", "album", album.Title, "searchID", searchResp.ID, "error", err)
 			}
-		}()
-	}
+		}
+		if p.cfg.Slskd.DeleteSearches {
+			if err := p.slskd.DeleteSearch(cleanupCtx, searchResp.ID); err != nil {
+				p.logger.Debug("failed to delete search", "album", album.Title, "searchID", searchResp.ID, "error", err)
+			}
+		}
+	}()
 
 	// Wait for search to complete by polling state
 	maxWaitTime := time.Duration(p.cfg.Timing.SearchWaitSeconds) * time.Second
 	pollInterval := 500 * time.Millisecond
 	startTime := time.Now()
 
+	var last *slskd.SearchResponse
+	stateFailures := 0
 	for {
-		state, err := p.slskd.GetSearchState(ctx, searchResp.ID)
+		stateCtx, cancel := context.WithTimeout(ctx, searchStateTimeout)
+		state, err := p.slskd.GetSearchState(stateCtx, searchResp.ID)
+		cancel()
+		if errors.Is(err, slskd.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %s", errSearchExpired, searchResp.ID)
+		}
 		if err != nil {
-			p.logger.Warn("failed to get search state", "searchID", searchResp.ID, "error", err)
+			stateFailures++
+			if stateFailures >= maxSearchStateFailures {
+				p.logger.Warn("failed to get search state, no longer waiting for the search",
+					"album", album.Title,
+					"searchID", searchResp.ID,
+					"failures", stateFailures,
+					"error", err)
+				break
+			}
+			p.logger.Debug("failed to get search state, retrying", "album", album.Title, "searchID", searchResp.ID, "error", err)
+		} else {
+			stateFailures = 0
+			last = state
+			p.logger.Debug("search state", "album", album.Title, "searchID", searchResp.ID, "state", state.State)
+
+			if strings.HasPrefix(state.State, "Completed") {
+				finished = true
+				break
+			}
+		}
+
+		if time.Since(startTime) >= maxWaitTime {
+			p.logger.Debug("search timeout reached", "album", album.Title, "searchID", searchResp.ID, "elapsed", time.Since(startTime))
 			break
 		}
 
-		p.logger.Debug("search state", "searchID", searchResp.ID, "state", state.State)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
 
-		if strings.HasPrefix(state.State, "Completed") {
-			break
+	if last != nil {
+		p.logger.Debug("search finished",
+			"album", album.Title,
+			"searchID", searchResp.ID,
+			"state", last.State,
+			"responses", last.ResponseCount,
+			"files", last.FileCount)
+	}
+
+	// Get search results once the search is done, retrying once after a blip
+	results, err := p.searchResults(ctx, searchResp.ID)
+	if errors.Is(err, slskd.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s", errSearchExpired, searchResp.ID)
+	}
+	if err != nil {
+		p.logger.Debug("failed to get search results, retrying", "album", album.Title, "searchID", searchResp.ID, "error", err)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(pollInterval):
 		}
+		results, err = p.searchResults(ctx, searchResp.ID)
+		if errors.Is(err, slskd.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %s", errSearchExpired, searchResp.ID)
+		}
+	}
+	if err != nil {
+		p.logger.Warn("failed to get search results", "album", album.Title, "searchID", searchResp.ID, "error", err)
+		return nil, &unavailableError{service: "slskd", err: fmt.Errorf("get search results: %w", err)}
+	}
 
-		if time.Since(startTime) >= maxWaitTime {
-			p.logger.Debug("search timeout reached", "searchID", searchResp.ID, "elapsed", time.Since(startTime))
-			break
+	p.logger.Debug("fetched search results", "album", album.Title, "searchID", searchResp.ID, "results", len(results))
+	if len(results) == 0 {
+		p.logger.Debug("no search results", "album", album.Title, "searchID", searchResp.ID)
+	}
+
+	return results, nil
+}
+
+// searchResults fetches the responses to a search, reading at most
+// max_results_to_consider of them
+func (p *Processor) searchResults(ctx context.Context, searchID string) ([]slskd.SearchResult, error) {
+	limit := p.cfg.Search.MaxResultsToConsider
+	var results []slskd.SearchResult
+	err := p.slskd.StreamSearchResults(ctx, searchID, func(result slskd.SearchResult) bool {
+		results = append(results, result)
+		return limit <= 0 || len(results) < limit
+	})
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(results) == limit {
+		p.logger.Debug("reached max_results_to_consider, ignoring further responses", "searchID", searchID, "limit", limit)
+	}
+	return results, nil
+}
+
+// searchForAlbum searches Slskd for an album and queues download if found
+func (p *Processor) searchForAlbum(ctx context.Context, query string, tracks []lidarr.Track, album lidarr.Album, release *lidarr.Release) (DownloadedItem, error) {
+	results, err := p.runSearch(ctx, album, query)
+	if err != nil {
+		return DownloadedItem{}, err
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("search.results", len(results)))
+
+	if len(results) == 0 {
+		return DownloadedItem{}, errNoResults
+	}
+
+	p.logger.Debug("processing search results", "album", album.Title, "results", len(results))
+
+	candidates := p.matchCandidates(ctx, results, tracks, album, release)
+	if len(candidates) == 0 {
+		// Shares often hold a different edition than the chosen release
+		candidates = p.matchAlternateReleases(ctx, results, album, release)
+	}
+
+	if len(candidates) == 0 {
+		if p.cfg.Search.AllowMultiSource {
+			return p.assembleFromResults(ctx, results, tracks, album, release)
+		}
+		return DownloadedItem{}, errNoMatch
+	}
+
+	p.rankCandidates(candidates, p.filterFor(ctx))
+	p.logCandidates(album, candidates)
+
+	// Enqueue the best candidate, falling back to the runners-up if slskd refuses
+	for _, candidate := range candidates {
+		if p.userRefused(candidate.username) {
+			p.logger.Debug("skipping candidate from user that refused an earlier download",
+				"album", album.Title,
+				"username", candidate.username,
+				"directory", candidate.dir)
+			continue
+		}
+
+		if owner, ok := p.claimDirectory(candidate.username, candidate.dir, album.Title); !ok {
+			p.logger.Debug("skipping directory already queued this run",
+				"album", album.Title,
+				"username", candidate.username,
+				"directory", candidate.dir,
+				"queuedFor", owner)
+			continue
+		}
+
+		if p.cfg.DryRun {
+			p.logger.Info("dry run: would download",
+				"album", album.Title,
+				"artist", album.Artist.ArtistName,
+				"username", candidate.username,
+				"directory", candidate.dir,
+				"files", len(candidate.files),
+				"companions", len(candidate.companions),
+				"bytes", candidate.downloadSize(),
+				"ratio", fmt.Sprintf("%.2f", candidate.ratio))
+			return p.albumItem(candidate, album), nil
 		}
 
-		time.Sleep(pollInterval)
-	}
+		p.logger.Info("found match",
+			"album", album.Title,
+			"username", candidate.username,
+			"directory", candidate.dir,
+			"ratio", fmt.Sprintf("%.2f", candidate.ratio),
+			"score", fmt.Sprintf("%.1f", candidate.score),
+			"files", len(candidate.files),
+			"companions", len(candidate.companions))
 
-	// Get search results
-	results, err := p.slskd.GetSearchResults(ctx, searchResp.ID)
-	if err != nil {
-		p.logger.Warn("failed to get search results", "searchID", searchResp.ID, "error", err)
-		return DownloadedItem{}, false
-	}
+		enqueueFiles := candidate.enqueueFiles()
+
+		if err := p.approve(ctx, p.candidateMatch(ctx, album, candidate)); err != nil {
+			p.releaseDirectory(candidate.username, candidate.dir)
+			return DownloadedItem{}, err
+		}
+		if err := p.reserveSpace(album.Title, candidate.downloadSize()); err != nil {
+			p.releaseDirectory(candidate.username, candidate.dir)
+			return DownloadedItem{}, err
+		}
+		if _, err := p.enqueue(ctx, candidate.username, enqueueFiles); err != nil {
+			p.logger.Warn("failed to enqueue downloads, trying next candidate",
+				"album", album.Title,
+				"username", candidate.username,
+				"directory", candidate.dir,
+				"error", err)
+			p.releaseSpace(candidate.downloadSize())
+			p.releaseDirectory(candidate.username, candidate.dir)
+			p.markRefused(candidate.username)
+			continue
+		}
 
-	p.logger.Debug("fetched search results", "searchID", searchResp.ID, "results", len(results))
+		trace.SpanFromContext(ctx).SetAttributes(
+			attribute.String("slskd.username", candidate.username),
+			attribute.Int("download.files", len(enqueueFiles)),
+			attribute.Int64("download.bytes", candidate.downloadSize()),
+		)
 
-	if len(results) == 0 {
-		p.logger.Debug("no search results", "searchID", searchResp.ID)
-		return DownloadedItem{}, false
+		return p.albumItem(candidate, album), nil
 	}
 
-	p.logger.Debug("processing search results", "results", len(results))
+	return DownloadedItem{}, errEnqueueFailed
+}
 
+// matchCandidates collects every directory in results that matches a release's track list
+func (p *Processor) matchCandidates(ctx context.Context, results []slskd.SearchResult, tracks []lidarr.Track, album lidarr.Album, release *lidarr.Release) []albumCandidate {
 	// Build expected track list (without extensions - matcher will handle file format variations)
 	expectedTracks := make([]string, len(tracks))
+	expectedDurations := make([]int, len(tracks))
 	for i, track := range tracks {
 		expectedTracks[i] = track.Title
+		expectedDurations[i] = track.Duration
 	}
 
-	// Try to match results
+	albumFilter := p.filterFor(ctx)
+	minFiles := p.minimumResponseFiles(len(tracks))
+	tooFewFiles := 0
+	var candidates []albumCandidate
 	for _, result := range results {
 		// Check ignored users
-		ignored := false
-		for _, ignoredUser := range p.cfg.Search.IgnoredUsers {
-			if strings.EqualFold(result.Username, ignoredUser) {
-				p.logger.Debug("skipping ignored user", "username", result.Username)
-				ignored = true
-				break
-			}
+		if p.isIgnoredUser(result.Username) {
+			p.logger.Debug("skipping ignored user", "album", album.Title, "username", result.Username)
+			continue
 		}
-		if ignored {
+		if reason, busy := p.busyReason(result); busy {
+			p.logger.Debug("skipping busy user",
+				"album", album.Title,
+				"username", result.Username,
+				"reason", reason,
+				"queueLength", result.QueueLength)
 			continue
 		}
 
 		p.logger.Debug("processing result",
+			"album", album.Title,
 			"username", result.Username,
-			"totalFiles", len(result.Files))
+			"totalFiles", len(result.Files),
+			"lockedFiles", len(result.LockedFiles),
+			"fileCount", result.FileCount,
+			"lockedFileCount", result.LockedFileCount,
+			"uploadSpeed", result.UploadSpeed,
+			"queueLength", result.QueueLength,
+			"freeUploadSlot", formatOptionalBool(result.HasFreeUploadSlot))
 
-		// Filter files by allowed filetypes first
-		filteredFiles, filterInfo := p.filter.FilterFilesDebug(result.Files)
+		// Filter files by allowed filetypes first, leaving out locked files
+		filteredFiles, filterInfo := albumFilter.FilterFilesDebug(unlockedFiles(result.Files))
 
 		// Log sample of filtered files (first 5)
 		sampleSize := 5
@@ -516,56 +1854,82 @@ func (p *Processor) searchForAlbum(ctx context.Context, query string, tracks []l
 		for i := 0; i < sampleSize; i++ {
 			info := filterInfo[i]
 			p.logger.Debug("file filter",
+				"album", album.Title,
 				"username", result.Username,
 				"file", info.Filename,
 				"ext", info.Extension,
 				"bitrate", formatOptionalInt(info.BitRate),
 				"sampleRate", formatOptionalInt(info.SampleRate),
 				"bitDepth", formatOptionalInt(info.BitDepth),
+				"length", formatOptionalInt(info.Length),
 				"matched", info.Matched)
 		}
 
 		p.logger.Debug("filtered by filetype",
+			"album", album.Title,
 			"username", result.Username,
 			"before", len(result.Files),
 			"after", len(filteredFiles),
-			"allowedTypes", strings.Join(p.cfg.Search.AllowedFiletypes, ", "))
+			"allowedTypes", strings.Join(albumFilter.AllowedFiletypes(), ", "))
+
+		filteredFiles = p.dropImplausibleFiles(album, result.Username, filteredFiles)
 
 		if len(filteredFiles) == 0 {
 			p.logger.Debug("skipping user - no files match allowed filetypes",
+				"album", album.Title,
 				"username", result.Username)
 			continue
 		}
+		if len(filteredFiles) < minFiles {
+			tooFewFiles++
+			continue
+		}
+
+		// Search hits leave out files whose names didn't match the query
+		filteredFiles = p.completePartialDirectories(ctx, album, expectedTracks, result.Username, filteredFiles)
 
 		// Group files by directory
 		// Note: slskd returns paths with backslashes regardless of OS
 		dirFiles := make(map[string][]string)
+		dirLengths := make(map[string][]int)
 		for _, file := range filteredFiles {
 			// Normalize Windows backslashes to forward slashes
 			normalizedPath := strings.ReplaceAll(file.Filename, "\\", "/")
 			dir := filepath.Dir(normalizedPath)
 			filename := filepath.Base(normalizedPath)
 			dirFiles[dir] = append(dirFiles[dir], filename)
+
+			length := 0
+			if file.Length != nil {
+				length = *file.Length
+			}
+			dirLengths[dir] = append(dirLengths[dir], length)
 		}
 
+		groups := groupDirectories(dirFiles, dirLengths)
 		p.logger.Debug("grouped into directories",
+			"album", album.Title,
 			"username", result.Username,
-			"directories", len(dirFiles))
+			"directories", len(dirFiles),
+			"discSets", len(groups)-len(dirFiles))
 
-		// Check each directory for matches
-		for dir, files := range dirFiles {
+		// Check each directory, and each set of disc folders, for matches
+		for _, group := range groups {
+			dir, files := group.dir, group.files
 			p.logger.Debug("checking directory",
+				"album", album.Title,
 				"username", result.Username,
 				"directory", dir,
 				"files", len(files),
 				"expectedTracks", len(expectedTracks))
 
 			// Use debug matcher to get detailed match info
-			matched, ratio, matchInfo := p.matcher.MatchTracksDebug(expectedTracks, files)
+			matched, ratio, matchInfo := p.matcherFor(album).MatchTracksDebug(expectedTracks, files)
 
 			// Log each track match attempt
 			for _, info := range matchInfo {
 				p.logger.Debug("track match",
+					"album", album.Title,
 					"expected", info.ExpectedTrack,
 					"bestMatch", info.BestMatch,
 					"ratio", fmt.Sprintf("%.2f", info.BestRatio),
@@ -574,6 +1938,7 @@ func (p *Processor) searchForAlbum(ctx context.Context, query string, tracks []l
 			}
 
 			p.logger.Debug("directory match result",
+				"album", album.Title,
 				"username", result.Username,
 				"directory", dir,
 				"matched", matched,
@@ -581,108 +1946,349 @@ func (p *Processor) searchForAlbum(ctx context.Context, query string, tracks []l
 				"matchedTracks", countMatched(matchInfo),
 				"totalTracks", len(expectedTracks))
 
-			if matched {
-				p.logger.Info("found match",
+			if matched && len(expectedDurations) > 0 && !matcher.DurationsMatch(expectedDurations, group.lengths) {
+				p.logger.Debug("rejecting directory - total length differs from expected track list",
+					"album", album.Title,
+					"username", result.Username,
+					"directory", dir)
+				continue
+			}
+
+			if !matched {
+				continue
+			}
+			if p.cfg.Search.StrictArtistMatch && !p.pathNamesArtist(album, dir) {
+				p.logger.Debug("rejecting directory - path doesn't name the artist",
+					"album", album.Title,
+					"artist", album.Artist.ArtistName,
+					"username", result.Username,
+					"directory", dir)
+				continue
+			}
+
+			candidate := albumCandidate{
+				release:     release,
+				tracks:      tracks,
+				username:    result.Username,
+				dir:         dir,
+				discs:       group.discs,
+				ratio:       ratio,
+				uploadSpeed: result.UploadSpeed,
+				queueLength: result.QueueLength,
+				freeSlot:    result.HasFreeUploadSlot != nil && *result.HasFreeUploadSlot,
+			}
+			var groupFiles []slskd.SearchFile
+			for _, file := range filteredFiles {
+				normalizedPath := strings.ReplaceAll(file.Filename, "\\", "/")
+				if slices.Contains(group.members, filepath.Dir(normalizedPath)) {
+					groupFiles = append(groupFiles, file)
+				}
+			}
+			if limit, ok := p.cfg.Search.ExtraFilesLimit(); ok && len(groupFiles) > len(tracks)+limit {
+				groupFiles = matchedFiles(groupFiles, group.paths, matchInfo)
+				p.logger.Debug("directory holds extra files, keeping only the matched ones",
+					"album", album.Title,
 					"username", result.Username,
 					"directory", dir,
-					"ratio", fmt.Sprintf("%.2f", ratio),
-					"files", len(files))
-
-				// Build file objects to download (from filtered files)
-				var enqueueFiles []slskd.EnqueueFile
-				for _, file := range filteredFiles {
-					normalizedPath := strings.ReplaceAll(file.Filename, "\\", "/")
-					if filepath.Dir(normalizedPath) == dir {
-						enqueueFiles = append(enqueueFiles, slskd.EnqueueFile{
-							Filename: file.Filename, // Keep original path for slskd
-							Size:     file.Size,
-						})
-					}
+					"files", len(files),
+					"kept", len(groupFiles))
+				if len(groupFiles) < len(tracks) {
+					continue // Several tracks matched the same file
 				}
-
-				// Enqueue downloads
-				if err := p.slskd.EnqueueDownloads(ctx, result.Username, enqueueFiles); err != nil {
-					p.logger.Warn("failed to enqueue downloads", "error", err)
-					continue
+			}
+			if kbps, window, implausible := p.implausibleBitrate(groupFiles, tracks, matchInfo); implausible {
+				p.logger.Debug("rejecting directory - size implies an implausible bitrate",
+					"album", album.Title,
+					"username", result.Username,
+					"directory", dir,
+					"kbps", kbps,
+					"minKbps", window.Min,
+					"maxKbps", window.Max)
+				continue
+			}
+			for _, file := range groupFiles {
+				if candidate.quality == "" {
+					candidate.quality = albumFilter.MatchedFiletype(file)
 				}
+				candidate.qualityRank = max(candidate.qualityRank, albumFilter.Rank(file))
+				candidate.totalSize += file.Size
+				candidate.files = append(candidate.files, file)
+			}
+			if existing, ok := existingQualityFor(ctx); ok && candidate.qualityRank >= existing.rank {
+				p.logger.Debug("rejecting directory - no better than the files on disk",
+					"album", album.Title,
+					"username", result.Username,
+					"directory", dir,
+					"quality", candidate.quality,
+					"existing", existing.name)
+				continue
+			}
+			candidate.companions = p.companionFiles(unlockedFiles(result.Files), group.members, candidate.files)
+			candidates = append(candidates, candidate)
+		}
+	}
 
-				// Build downloaded item
-				item := DownloadedItem{
-					ArtistName:  album.Artist.ArtistName,
-					AlbumName:   album.Title,
-					AlbumID:     album.ID,
-					FolderName:  filepath.Base(dir),
-					Username:    result.Username,
-					Directory:   dir,
-					MediumCount: release.MediumCount,
-				}
+	if tooFewFiles > 0 {
+		p.logger.Debug("results skipped for too few files",
+			"album", album.Title,
+			"skipped", tooFewFiles,
+			"minimumFiles", minFiles)
+	}
 
-				// Build track list from actual downloaded files
-				// Map track titles to their medium numbers for lookup
-				trackMediums := make(map[string]int)
-				for _, track := range tracks {
-					trackMediums[strings.ToLower(track.Title)] = track.MediumNumber
-				}
+	return candidates
+}
 
-				for _, file := range filteredFiles {
-					normalizedPath := strings.ReplaceAll(file.Filename, "\\", "/")
-					if filepath.Dir(normalizedPath) == dir {
-						filename := filepath.Base(normalizedPath)
-						// Try to determine medium number by matching filename to track title
-						mediumNum := 1 // Default to disc 1
-						filenameNoExt := matcher.ExtractFilename(filename)
-						for title, medium := range trackMediums {
-							if strings.Contains(strings.ToLower(filenameNoExt), title) {
-								mediumNum = medium
-								break
-							}
-						}
+// albumItem builds the download item for an enqueued album candidate
+func (p *Processor) albumItem(candidate albumCandidate, album lidarr.Album) DownloadedItem {
+	item := DownloadedItem{
+		ArtistID:    album.ArtistID,
+		ArtistName:  album.Artist.ArtistName,
+		AlbumName:   album.Title,
+		AlbumID:     album.ID,
+		ReleaseID:   candidate.release.ID,
+		FolderName:  localFolder(candidate.dir),
+		Sources:     []DownloadSource{{Username: candidate.username, Directory: candidate.dir}},
+		MediumCount: candidate.release.MediumCount,
+		Quality:     candidate.quality,
+	}
 
-						item.Tracks = append(item.Tracks, organizer.DownloadedTrack{
-							Filename:     filename,
-							MediumNumber: mediumNum,
-						})
-					}
-				}
+	// Disc folders download separately and are gathered into a folder named
+	// after the album's directory, each keeping its own subfolder
+	if candidate.discs != nil {
+		item.Sources = nil
+		for _, file := range candidate.files {
+			dir := filepath.Dir(strings.ReplaceAll(file.Filename, "\\", "/"))
+			if !item.hasSource(candidate.username, dir) {
+				item.Sources = append(item.Sources, DownloadSource{Username: candidate.username, Directory: dir})
+			}
+		}
+		item.MediumCount = max(item.MediumCount, len(candidate.discs))
+	}
+
+	// Build track list from actual downloaded files
+	// Map track titles to their medium numbers for lookup
+	trackMediums := make(map[string]int)
+	for _, track := range candidate.tracks {
+		trackMediums[strings.ToLower(track.Title)] = track.MediumNumber
+	}
+
+	for _, file := range candidate.files {
+		normalizedPath := strings.ReplaceAll(file.Filename, "\\", "/")
+		filename := filepath.Base(normalizedPath)
+
+		// Files from a disc folder take its disc number
+		if disc, ok := candidate.discs[filepath.Dir(normalizedPath)]; ok {
+			item.Tracks = append(item.Tracks, organizer.DownloadedTrack{
+				Filename:     filename,
+				MediumNumber: disc,
+				Folder:       localFolder(filepath.Dir(normalizedPath)),
+			})
+			continue
+		}
 
-				return item, true
+		// Try to determine medium number by matching filename to track title
+		mediumNum := 1 // Default to disc 1
+		filenameNoExt := matcher.ExtractFilename(filename)
+		for title, medium := range trackMediums {
+			if strings.Contains(strings.ToLower(filenameNoExt), title) {
+				mediumNum = medium
+				break
 			}
 		}
+
+		item.Tracks = append(item.Tracks, organizer.DownloadedTrack{
+			Filename:     filename,
+			MediumNumber: mediumNum,
+		})
+	}
+
+	// Companion files are moved with the tracks but never tagged
+	for _, file := range candidate.companions {
+		normalizedPath := strings.ReplaceAll(file.Filename, "\\", "/")
+		companion := organizer.DownloadedTrack{Filename: filepath.Base(normalizedPath)}
+		if candidate.discs != nil {
+			companion.Folder = localFolder(filepath.Dir(normalizedPath))
+		}
+		item.Companions = append(item.Companions, companion)
 	}
 
-	return DownloadedItem{}, false
+	return item
 }
 
+// maxDownloadsBackoffShift caps the wait after repeated download fetch failures at 8 poll intervals
+const maxDownloadsBackoffShift = 3
+
+// maxMissingDownloadPolls is how many polls in a row an item's files may be
+// absent from slskd before the item is given up on
+const maxMissingDownloadPolls = 3
+
 // monitorDownloads polls Slskd until all downloads complete or timeout
+// Items received on incoming, if set, are monitored as they arrive, and
+// monitoring only ends once it is closed
+// ready, if set, is called for each item as soon as it completes
 // Returns only the successfully completed downloads
-func (p *Processor) monitorDownloads(ctx context.Context, downloadList []DownloadedItem) ([]DownloadedItem, error) {
-	if len(downloadList) == 0 {
+func (p *Processor) monitorDownloads(ctx context.Context, downloadList []DownloadedItem, incoming <-chan DownloadedItem, ready func(DownloadedItem)) ([]DownloadedItem, error) {
+	if len(downloadList) == 0 && incoming == nil {
 		return nil, nil
 	}
 
-	p.logger.Info("monitoring downloads", "count", len(downloadList))
+	if len(downloadList) > 0 {
+		p.logger.Info("monitoring downloads", "count", len(downloadList))
+	}
 
-	startTime := time.Now()
 	pollInterval := time.Duration(p.cfg.Timing.DownloadPollSeconds) * time.Second
 	stalledTimeout := time.Duration(p.cfg.Slskd.StalledTimeout) * time.Second
+	stalls := newStallTracker(time.Duration(p.cfg.Timing.StallCheckIntervalSec*p.cfg.Timing.StallChecks)*time.Second, float64(p.cfg.Timing.StallMinBytesPerSecond))
+	queue := newQueueTracker(time.Duration(p.cfg.Timing.StallCheckIntervalSec) * time.Second)
 
 	// Track which items are still pending, which succeeded, and retry counts
 	pending := make(map[int]bool)
 	succeeded := make(map[int]bool)
+	started := make(map[int]time.Time) // When each item began to be monitored
 	retryCount := make(map[int]int)
-	maxRetries := 3
+	retryAt := make(map[int]time.Time)         // When a delayed retry of an item's failed files is due
+	missingPolls := make(map[int]int)          // Consecutive polls an item's files were absent from slskd
+	watched := make(retryWatch)                // Items checked by transfer ID since their failed files were re-enqueued
+	droppedCompanions := make(map[string]bool) // Companion transfers given up on, by transferKey
+	maxRetries := p.cfg.Download.FileRetries()
+	retryDelay := time.Duration(p.cfg.Download.RetryDelaySeconds) * time.Second
+	now := time.Now()
 	for i := range downloadList {
 		pending[i] = true
+		started[i] = now
 		retryCount[i] = 0
 	}
 
+	complete := func(idx int) {
+		succeeded[idx] = true
+		if ready != nil {
+			ready(downloadList[idx])
+		}
+	}
+
+	// Items arriving on incoming join downloadList and get their own stalled
+	// timeout; incoming is set to nil once it is closed
+	receive := func(item DownloadedItem, ok bool) {
+		if !ok {
+			incoming = nil
+			return
+		}
+		p.logger.Info("monitoring download", "album", item.AlbumName, "directory", item.FolderName)
+		downloadList = append(downloadList, item)
+		pending[len(downloadList)-1] = true
+		started[len(downloadList)-1] = time.Now()
+	}
+	monitoring := func() bool {
+		for _, ok := range pending {
+			if ok {
+				return true
+			}
+		}
+		return false
+	}
+	// interrupt picks up items queued just before cancellation so they are
+	// cancelled along with the rest
+	interrupt := func() error {
+		for incoming != nil {
+			select {
+			case item, ok := <-incoming:
+				receive(item, ok)
+			default:
+				incoming = nil
+			}
+		}
+		return p.interruptMonitoring(ctx, downloadList, pending)
+	}
+	// timedOut gives up on each pending item whose stalled timeout has passed
+	// since it began to be monitored, reporting whether none are left
+	timedOut := func() bool {
+		for idx, ok := range pending {
+			if !ok || time.Since(started[idx]) <= stalledTimeout {
+				continue
+			}
+			p.logger.Warn("download timeout reached",
+				"album", downloadList[idx].AlbumName,
+				"directory", downloadList[idx].FolderName,
+				"elapsed", time.Since(started[idx]))
+			pending[idx] = false
+		}
+		return !monitoring()
+	}
+
+	// Follow transfer events when slskd offers them, polling otherwise
+	feedCtx, stopFeed := context.WithCancel(ctx)
+	defer stopFeed()
+	feed := p.subscribeTransfers(feedCtx)
+
+	fetchFailures := 0
 	for {
 		select {
 		case <-ctx.Done():
-			return nil, ctx.Err()
+			return nil, interrupt()
 		default:
 		}
 
+		// Pick up items queued since the last poll, waiting for one when
+		// nothing else is left to monitor
+		for incoming != nil {
+			if monitoring() {
+				select {
+				case item, ok := <-incoming:
+					receive(item, ok)
+					continue
+				default:
+				}
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return nil, interrupt()
+			case item, ok := <-incoming:
+				receive(item, ok)
+			}
+		}
+		if !monitoring() {
+			break
+		}
+
+		// One downloads snapshot is shared by every pending item, unless only
+		// re-enqueued files with known IDs are left to check
+		var downloads slskd.DownloadsResponse
+		var err error
+		targeted := false
+		if feed != nil && feed.seeded {
+			downloads = feed.downloads
+		} else {
+			if feed == nil && watched.covers(pending) {
+				p.refreshWatched(ctx, watched, pending)
+				targeted = watched.covers(pending)
+			}
+			if !targeted {
+				downloads, err = p.slskd.GetDownloads(ctx)
+			}
+		}
+		if err != nil {
+			fetchFailures++
+			backoff := pollInterval * time.Duration(1<<min(fetchFailures-1, maxDownloadsBackoffShift))
+			p.logger.Warn("failed to fetch downloads", "error", err, "retryIn", backoff)
+
+			// Items may still arrive after the pending ones time out
+			if timedOut() && incoming == nil {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return nil, interrupt()
+			case <-time.After(backoff):
+			}
+			continue
+		}
+		fetchFailures = 0
+		if feed != nil && !feed.seeded {
+			feed.seed(downloads)
+			clear(watched) // The snapshot is checked instead
+		}
+
 		unfinished := 0
 
 		for idx, item := range downloadList {
@@ -690,55 +2296,130 @@ func (p *Processor) monitorDownloads(ctx context.Context, downloadList []Downloa
 				continue // Already completed or errored
 			}
 
-			// Get downloads for this user
-			downloads, err := p.slskd.GetDownloads(ctx)
-			if err != nil {
-				p.logger.Warn("failed to fetch downloads", "error", err)
-				time.Sleep(pollInterval)
-				continue
+			// Collect the item's files from every source directory
+			var dirFiles []sourceFile
+			if targeted {
+				dirFiles = watched[idx]
 			}
-
-			// Find matching directory
-			var dirFiles []slskd.DownloadFile
 			for _, userDownload := range downloads {
-				if userDownload.Username != item.Username {
-					continue
-				}
 				for _, dirDownload := range userDownload.Directories {
-					// Normalize both paths for comparison
+					// Normalize paths for comparison
 					normalizedDownloadDir := strings.ReplaceAll(dirDownload.Directory, "\\", "/")
-					if normalizedDownloadDir == item.Directory {
-						dirFiles = dirDownload.Files
-						break
+					if !item.hasSource(userDownload.Username, normalizedDownloadDir) {
+						continue
+					}
+					for _, file := range dirDownload.Files {
+						dirFiles = append(dirFiles, sourceFile{username: userDownload.Username, directory: normalizedDownloadDir, DownloadFile: file})
 					}
 				}
 			}
 
+			// slskd can lag behind a fresh enqueue, so give it a few polls
 			if len(dirFiles) == 0 {
-				p.logger.Debug("no downloads found for item", "username", item.Username, "directory", item.Directory)
+				missingPolls[idx]++
+				if feed != nil {
+					feed.seeded = false // Events may have been missed, so fetch again
+				}
+				if missingPolls[idx] < maxMissingDownloadPolls {
+					p.logger.Debug("no downloads found for item yet", "album", item.AlbumName, "sources", len(item.Sources), "polls", missingPolls[idx])
+					unfinished++
+					continue
+				}
+				p.logger.Warn("downloads not found in slskd, giving up",
+					"album", item.AlbumName,
+					"directory", item.FolderName,
+					"polls", missingPolls[idx])
+				p.recordSearchFailure(ctx, item.album())
 				pending[idx] = false
 				continue
 			}
+			delete(missingPolls, idx)
+			p.recordWritten(item.AlbumID, dirFiles)
 
 			// Separate files into completed, in-progress, and errored
-			var completedFiles []slskd.DownloadFile
-			var erroredFiles []slskd.DownloadFile
-			var inProgressFiles []slskd.DownloadFile
+			var completedFiles []sourceFile
+			var erroredFiles []sourceFile
+			var inProgressFiles []sourceFile
+			var queuedTooFar []sourceFile
 
+			now := time.Now()
 			for _, file := range dirFiles {
-				if file.IsErrored() {
+				if droppedCompanions[transferKey(file.username, file.Filename)] {
+					continue
+				}
+				switch {
+				case p.queuedTooFar(ctx, queue, item, file, now):
+					queuedTooFar = append(queuedTooFar, file)
+				case file.IsErrored():
 					erroredFiles = append(erroredFiles, file)
-				} else if file.IsCompleted() {
+				case file.IsCompleted():
+					// A missing or truncated file is retried like a failed transfer
+					if err := p.verifyDownload(file); err != nil {
+						p.logger.Warn("downloaded file failed verification",
+							"album", item.AlbumName,
+							"username", file.username,
+							"file", file.Filename,
+							"error", err)
+						if err := os.Remove(p.localPath(file.directory, file.Filename)); err != nil && !os.IsNotExist(err) {
+							p.logger.Debug("failed to remove unverified file", "error", err)
+						}
+						erroredFiles = append(erroredFiles, file)
+						continue
+					}
 					completedFiles = append(completedFiles, file)
-				} else {
+				case stalls.stalled(file, now):
+					// Cancelled and retried with the errored files below
+					p.logger.Warn("download stalled",
+						"album", item.AlbumName,
+						"username", file.username,
+						"file", file.Filename,
+						"state", file.State,
+						"bytesTransferred", file.BytesTransferred)
+					erroredFiles = append(erroredFiles, file)
+				default:
 					inProgressFiles = append(inProgressFiles, file)
 				}
 			}
 
+			// Companion files don't count towards the album; failed ones are dropped
+			completedFiles, finishedCompanions := item.splitCompanions(completedFiles)
+			erroredFiles, failedCompanions := item.splitCompanions(erroredFiles)
+			queuedTooFar, queuedCompanions := item.splitCompanions(queuedTooFar)
+			p.dropCompanions(ctx, item, slices.Concat(failedCompanions, queuedCompanions), droppedCompanions)
+
+			// A retry would queue behind the same uploads, so search again next run
+			if len(queuedTooFar) > 0 {
+				p.logger.Warn("download queued too far back, giving up to search for another source next run",
+					"album", item.AlbumName,
+					"directory", item.FolderName,
+					"username", queuedTooFar[0].username,
+					"queued", len(queuedTooFar),
+					"maxPosition", p.cfg.Download.MaxQueuePosition)
+				p.abandonQueued(ctx, item, slices.Concat(completedFiles, finishedCompanions), slices.Concat(queuedTooFar, inProgressFiles, erroredFiles))
+				pending[idx] = false
+				continue
+			}
+
+			// Wait out the retry delay without holding up the other items
+			if len(erroredFiles) > 0 && retryDelay > 0 && retryCount[idx] < maxRetries {
+				if retryAt[idx].IsZero() {
+					retryAt[idx] = now.Add(retryDelay)
+					p.logger.Info("delaying retry of failed files",
+						"directory", item.FolderName,
+						"errored", len(erroredFiles),
+						"retryIn", retryDelay)
+				}
+				if now.Before(retryAt[idx]) {
+					unfinished++
+					continue
+				}
+				delete(retryAt, idx)
+			}
+
 			// Handle errors with retry logic
 			if len(erroredFiles) > 0 {
 				p.logger.Warn("some files failed",
-					"directory", item.Directory,
+					"directory", item.FolderName,
 					"completed", len(completedFiles),
 					"errored", len(erroredFiles),
 					"inProgress", len(inProgressFiles),
@@ -747,7 +2428,7 @@ func (p *Processor) monitorDownloads(ctx context.Context, downloadList []Downloa
 				// Cancel the errored files from slskd
 				for _, file := range erroredFiles {
 					p.logger.Debug("cancelling failed file", "file", file.Filename, "state", file.State)
-					if err := p.slskd.CancelDownload(ctx, item.Username, file.ID); err != nil {
+					if err := p.slskd.CancelDownload(ctx, file.username, file.ID); err != nil {
 						p.logger.Debug("failed to cancel download", "error", err)
 					}
 				}
@@ -756,29 +2437,42 @@ func (p *Processor) monitorDownloads(ctx context.Context, downloadList []Downloa
 				if retryCount[idx] < maxRetries {
 					retryCount[idx]++
 					p.logger.Info("retrying failed files",
-						"directory", item.Directory,
+						"directory", item.FolderName,
 						"filesCount", len(erroredFiles),
 						"attempt", retryCount[idx])
 
-					// Re-enqueue the failed files
-					var retryFiles []slskd.EnqueueFile
+					// Re-enqueue the failed files with the user they came from
+					retryFiles := make(map[string][]slskd.EnqueueFile)
 					for _, file := range erroredFiles {
 						// Extract just the filename from the full path
 						normalizedPath := strings.ReplaceAll(file.Filename, "\\", "/")
-						if filepath.Dir(normalizedPath) == item.Directory {
-							retryFiles = append(retryFiles, slskd.EnqueueFile{
+						if filepath.Dir(normalizedPath) == file.directory {
+							retryFiles[file.username] = append(retryFiles[file.username], slskd.EnqueueFile{
 								Filename: file.Filename,
 								Size:     file.Size,
 							})
+							stalls.reset(file.username, file.Filename)
 						}
 					}
 
-					if len(retryFiles) > 0 {
-						if err := p.slskd.EnqueueDownloads(ctx, item.Username, retryFiles); err != nil {
-							p.logger.Warn("failed to re-enqueue files", "error", err)
+					requeued := make(map[string]slskd.DownloadFile)
+					for username, files := range retryFiles {
+						result, err := p.enqueue(ctx, username, files)
+						if err != nil {
+							p.logger.Warn("failed to re-enqueue files", "username", username, "error", err)
+							continue
+						}
+						for _, file := range result.Enqueued {
+							requeued[transferKey(username, file.Filename)] = file
 						}
 					}
 
+					// Check on the new transfers directly while slskd
+					// reported every one of them
+					if feed == nil {
+						watched.watch(idx, slices.Concat(completedFiles, finishedCompanions, inProgressFiles), erroredFiles, requeued)
+					}
+
 					// Keep monitoring this item
 					unfinished++
 				} else {
@@ -786,56 +2480,105 @@ func (p *Processor) monitorDownloads(ctx context.Context, downloadList []Downloa
 					// If there are still files in progress, wait for them to finish
 					if len(inProgressFiles) > 0 {
 						p.logger.Debug("max retries exceeded but files still in progress, waiting",
-							"directory", item.Directory,
+							"directory", item.FolderName,
 							"inProgress", len(inProgressFiles))
 						unfinished++
 					} else {
 						// All files done - import any successful tracks
 						// Lidarr will track what's still missing for the next run
-						if len(completedFiles) > 0 {
-							totalFiles := len(completedFiles) + len(erroredFiles)
-							successRate := float64(len(completedFiles)) / float64(totalFiles)
+						totalFiles := len(completedFiles) + len(erroredFiles)
+						successRate := float64(len(completedFiles)) / float64(totalFiles)
+						if len(completedFiles) > 0 && successRate < p.cfg.Download.MinPartialImportRatio {
+							p.logger.Warn("max retries exceeded, too few files finished to import partial album",
+								"directory", item.FolderName,
+								"retries", retryCount[idx],
+								"completed", len(completedFiles),
+								"failed", len(erroredFiles),
+								"successRate", fmt.Sprintf("%.0f%%", successRate*100),
+								"minRatio", fmt.Sprintf("%.0f%%", p.cfg.Download.MinPartialImportRatio*100))
+							p.discardPartial(ctx, item, slices.Concat(completedFiles, finishedCompanions))
+						} else if len(completedFiles) > 0 {
 							p.logger.Warn("max retries exceeded, importing partial album",
-								"directory", item.Directory,
+								"directory", item.FolderName,
 								"retries", retryCount[idx],
 								"completed", len(completedFiles),
 								"failed", len(erroredFiles),
 								"successRate", fmt.Sprintf("%.0f%%", successRate*100))
-							succeeded[idx] = true
+							complete(idx)
 						} else {
 							// No files succeeded at all
 							p.logger.Error("giving up after max retries - no files succeeded",
-								"directory", item.Directory,
+								"directory", item.FolderName,
 								"retries", retryCount[idx])
 						}
+						p.recordTransfers(item, completedFiles, erroredFiles)
 						pending[idx] = false
 					}
 				}
 			} else if len(inProgressFiles) > 0 {
 				// Still downloading
+				p.logProgress(ctx, item, dirFiles)
 				unfinished++
+			} else if len(completedFiles) == 0 {
+				// Only companion files finished, which is nothing to import
+				p.logger.Warn("no audio files finished, giving up",
+					"album", item.AlbumName,
+					"directory", item.FolderName)
+				p.discardPartial(ctx, item, finishedCompanions)
+				pending[idx] = false
 			} else {
 				// All complete, no errors
-				p.logger.Info("download complete", "directory", item.Directory, "files", len(completedFiles))
+				p.logger.Info("download complete", "directory", item.FolderName, "files", len(completedFiles))
+				p.recordTransfers(item, completedFiles, nil)
 				pending[idx] = false
-				succeeded[idx] = true
+				complete(idx)
 			}
 		}
 
 		// Check if all done
 		if unfinished == 0 {
+			if incoming != nil {
+				continue
+			}
 			p.logger.Info("all downloads complete")
 			break
 		}
 
 		// Check for timeout
-		if time.Since(startTime) > stalledTimeout {
-			p.logger.Warn("download timeout reached", "elapsed", time.Since(startTime))
+		if timedOut() {
+			if incoming != nil {
+				continue
+			}
 			break
 		}
 
+		// Transfer events wake the loop early; the poll interval still
+		// applies so stalls and the timeout are noticed without them
+		var events <-chan slskd.TransferEvent
+		if feed != nil {
+			events = feed.events
+		}
 		p.logger.Debug("downloads in progress", "remaining", unfinished)
-		time.Sleep(pollInterval)
+		select {
+		case <-ctx.Done():
+			return nil, interrupt()
+		case item, ok := <-incoming:
+			receive(item, ok)
+		case event, ok := <-events:
+			if ok {
+				feed.apply(event)
+				ok = feed.collect(ctx, transferEventWindow)
+			}
+			if !ok {
+				p.logger.Info("slskd transfer events ended, polling downloads")
+				feed = nil
+			}
+		case <-time.After(pollInterval):
+		}
+	}
+
+	if err := p.reputation.Save(); err != nil {
+		p.logger.Warn("failed to save user reputation", "error", err)
 	}
 
 	// Build list of successful downloads
@@ -854,7 +2597,8 @@ func (p *Processor) monitorDownloads(ctx context.Context, downloadList []Downloa
 	return successfulDownloads, nil
 }
 
-// organizeDownloads organizes downloaded files into proper structure
+// organizeDownloads organizes downloaded files into proper structure,
+// recording each item's organized folder
 func (p *Processor) organizeDownloads(downloadList []DownloadedItem) error {
 	if len(downloadList) == 0 {
 		return nil
@@ -865,16 +2609,22 @@ func (p *Processor) organizeDownloads(downloadList []DownloadedItem) error {
 	var albums []organizer.DownloadedAlbum
 	for _, item := range downloadList {
 		album := organizer.DownloadedAlbum{
-			ArtistName:  item.ArtistName,
-			AlbumName:   item.AlbumName,
-			FolderPath:  item.FolderName,
-			MediumCount: item.MediumCount,
-			Tracks:      item.Tracks,
+			ArtistName:   item.ArtistName,
+			ArtistFolder: item.ArtistFolder,
+			AlbumName:    item.AlbumName,
+			FolderPath:   item.FolderName,
+			MediumCount:  item.MediumCount,
+			Tracks:       item.Tracks,
+			Companions:   item.Companions,
 		}
 		albums = append(albums, album)
 	}
 
-	if err := p.organizer.OrganizeAlbums(albums); err != nil {
+	dirs, err := p.organizer.OrganizeAlbums(albums)
+	for i, dir := range dirs {
+		downloadList[i].AlbumDir = dir
+	}
+	if err != nil {
 		return fmt.Errorf("organize albums: %w", err)
 	}
 
@@ -890,56 +2640,144 @@ func (p *Processor) triggerImport(ctx context.Context, downloadList []Downloaded
 
 	p.logger.Info("triggering Lidarr import", "count", len(downloadList))
 
-	// Group by artist for import, and track original download info for cleanup
-	artistFolders := make(map[string]bool)
-	artistToDownloads := make(map[string][]downloadCleanupInfo)
+	// Group by organized album folder, so Lidarr scans only the albums
+	// downloaded rather than everything under the artist
+	var albumDirs []string
+	dirToDownloads := make(map[string][]downloadCleanupInfo)
+	dirToAlbum := make(map[string]int)
 	for _, item := range downloadList {
-		sanitized := matcher.SanitizeFolderName(item.ArtistName)
-		artistFolders[sanitized] = true
-		artistToDownloads[sanitized] = append(artistToDownloads[sanitized], downloadCleanupInfo{
-			username:  item.Username,
-			directory: item.Directory,
-		})
+		albumDir := p.albumDir(item)
+		if !slices.Contains(albumDirs, albumDir) {
+			albumDirs = append(albumDirs, albumDir)
+			dirToAlbum[albumDir] = item.AlbumID
+		}
+		for _, source := range item.Sources {
+			dirToDownloads[albumDir] = append(dirToDownloads[albumDir], downloadCleanupInfo{
+				albumID:   item.AlbumID,
+				username:  source.Username,
+				directory: source.Directory,
+				albumDir:  albumDir,
+			})
+		}
 	}
 
-	// Trigger import for each artist folder
+	// Trigger import for each album folder
 	// Map commandID to download cleanup info for later
 	commandToDownloads := make(map[int][]downloadCleanupInfo)
-	for artistFolder := range artistFolders {
-		path := filepath.Join(p.cfg.Lidarr.DownloadDir, artistFolder)
+	for _, albumDir := range albumDirs {
+		path := p.importPath(albumDir)
 
-		cmd := lidarr.Command{
-			Name: "DownloadedAlbumsScan",
-			Path: path,
+		var resp *lidarr.CommandResponse
+		var err error
+		if p.cfg.Lidarr.ImportMode == "manual" {
+			resp, err = p.manualImport(ctx, path, dirToAlbum[albumDir], dirToDownloads[albumDir])
+		} else {
+			resp, err = p.lidarr.PostCommand(ctx, lidarr.Command{
+				Name: "DownloadedAlbumsScan",
+				Path: path,
+			})
 		}
-
-		resp, err := p.lidarr.PostCommand(ctx, cmd)
 		if err != nil {
 			p.logger.Warn("failed to trigger import", "path", path, "error", err)
 			continue
 		}
+		if resp == nil {
+			continue // Nothing Lidarr would import
+		}
 
-		commandToDownloads[resp.ID] = artistToDownloads[artistFolder]
+		commandToDownloads[resp.ID] = dirToDownloads[albumDir]
 		p.logger.Info("triggered import", "path", path, "commandID", resp.ID)
 	}
 
-	// Poll for completion and clean up successful imports
+	// Poll for completion
+	var successfulDownloads, timedOutDownloads []downloadCleanupInfo
 	if len(commandToDownloads) > 0 {
-		successfulDownloads := p.pollImportCompletion(ctx, commandToDownloads)
+		successfulDownloads, timedOutDownloads = p.pollImportCompletion(ctx, commandToDownloads)
+	}
+
+	// Record which albums made it into Lidarr
+	imported := make(map[int]bool)
+	for _, download := range successfulDownloads {
+		imported[download.albumID] = true
+	}
+	timedOut := make(map[int]bool)
+	for _, download := range timedOutDownloads {
+		timedOut[download.albumID] = true
+	}
+	if p.cfg.Lidarr.RefreshArtistAfterImport {
+		if artists := importedArtists(downloadList, imported); len(artists) > 0 {
+			p.refreshArtists(ctx, artists)
+		}
+	}
+	for _, item := range downloadList {
+		if imported[item.AlbumID] {
+			p.updateDecision(item.AlbumID, OutcomeImported, "")
+			p.hooks.PostImport(ctx, hooks.Album{
+				Artist:  item.ArtistName,
+				Album:   item.AlbumName,
+				AlbumID: item.AlbumID,
+				Path:    p.albumDir(item),
+				Quality: item.Quality,
+			})
+		} else if timedOut[item.AlbumID] {
+			p.updateDecision(item.AlbumID, OutcomeFailed, ReasonTimeout)
+		} else {
+			p.updateDecision(item.AlbumID, OutcomeFailed, ReasonImportFailed)
+		}
+	}
 
-		// Clean up successful imports if configured
-		if p.cfg.Daemon.DeleteAfterImport && len(successfulDownloads) > 0 {
-			p.cleanupImportedDownloads(ctx, successfulDownloads)
+	// Timed-out albums stay pending so the next run retries their import
+	var finished []int
+	for _, item := range downloadList {
+		if !timedOut[item.AlbumID] {
+			finished = append(finished, item.AlbumID)
 		}
 	}
+	p.clearPending(finished...)
+
+	// Clean up successful imports if configured, once the hooks have seen the
+	// album folder
+	if p.cfg.Daemon.DeleteAfterImport && len(successfulDownloads) > 0 {
+		p.cleanupImportedDownloads(ctx, successfulDownloads)
+	}
 
 	return nil
 }
 
-// pollImportCompletion polls Lidarr until import commands complete
-// Returns a list of artist folders that were successfully imported
-func (p *Processor) pollImportCompletion(ctx context.Context, commandToDownloads map[int][]downloadCleanupInfo) []downloadCleanupInfo {
+// albumDir returns the folder an item was organized into, or the folder it
+// would be organized into if it hasn't been
+func (p *Processor) albumDir(item DownloadedItem) string {
+	if item.AlbumDir != "" {
+		return item.AlbumDir
+	}
+	artist := item.ArtistFolder
+	if artist == "" {
+		artist = item.ArtistName
+	}
+	return p.organizer.AlbumDir(artist, item.AlbumName)
+}
+
+// importPath returns Lidarr's path to an organized album folder: the folder
+// mapped through local_to_lidarr when a mapping applies, otherwise the same
+// folder under lidarr.download_dir
+func (p *Processor) importPath(albumDir string) string {
+	if mapped, ok := p.lidarrPath(albumDir); ok {
+		return mapped
+	}
+	rel, err := filepath.Rel(p.cfg.LocalDownloadDir(), albumDir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return albumDir
+	}
+	return filepath.Join(p.cfg.Lidarr.DownloadDir, rel)
+}
+
+// pollImportCompletion polls Lidarr until import commands complete or
+// import_timeout_seconds passes. Returns the downloads covered by the commands
+// that succeeded and by those still running at the deadline or when ctx is
+// cancelled, whose folders are left in place
+func (p *Processor) pollImportCompletion(ctx context.Context, commandToDownloads map[int][]downloadCleanupInfo) (successful, timedOut []downloadCleanupInfo) {
 	pollInterval := time.Duration(p.cfg.Timing.ImportPollSeconds) * time.Second
+	deadline := time.Now().Add(p.cfg.Timing.ImportTimeout())
 	pending := make(map[int]bool)
 	for id := range commandToDownloads {
 		pending[id] = true
@@ -947,12 +2785,10 @@ func (p *Processor) pollImportCompletion(ctx context.Context, commandToDownloads
 
 	p.logger.Info("polling import completion", "commands", len(commandToDownloads))
 
-	var successfulDownloads []downloadCleanupInfo
-
 	for len(pending) > 0 {
 		select {
 		case <-ctx.Done():
-			return successfulDownloads
+			return successful, unfinishedImports(pending, commandToDownloads)
 		default:
 		}
 
@@ -970,26 +2806,87 @@ func (p *Processor) pollImportCompletion(ctx context.Context, commandToDownloads
 					"message", cmd.Message,
 					"body", cmd.Body)
 
-				// Check if import was successful (completed without "failed" in message)
-				if cmd.Status == "completed" && !strings.Contains(strings.ToLower(cmd.Message), "failed") {
-					downloads := commandToDownloads[id]
-					successfulDownloads = append(successfulDownloads, downloads...)
+				if importSucceeded(cmd) {
+					successful = append(successful, commandToDownloads[id]...)
 				} else {
-					// TODO: Move to failed imports
-					p.logger.Warn("import failed", "commandID", id, "body", cmd.Body)
+					p.logger.Warn("import failed", "commandID", id, "message", cmd.Message, "body", cmd.Body)
+					p.moveFailedImports(cmd.Message, commandToDownloads[id])
 				}
 
 				delete(pending, id)
 			}
 		}
 
-		if len(pending) > 0 {
-			time.Sleep(pollInterval)
+		if len(pending) == 0 {
+			break
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			for id := range pending {
+				p.logger.Warn("import command timed out, leaving its folders in place",
+					"commandID", id,
+					"timeout", p.cfg.Timing.ImportTimeout())
+			}
+			return successful, unfinishedImports(pending, commandToDownloads)
+		}
+		select {
+		case <-ctx.Done():
+			return successful, unfinishedImports(pending, commandToDownloads)
+		case <-time.After(min(pollInterval, remaining)):
 		}
 	}
 
 	p.logger.Info("all imports complete")
-	return successfulDownloads
+	return successful, nil
+}
+
+// unfinishedImports returns the downloads of commands still running, which
+// keep their folders and stay pending so the next run retries them
+func unfinishedImports(pending map[int]bool, commandToDownloads map[int][]downloadCleanupInfo) []downloadCleanupInfo {
+	var downloads []downloadCleanupInfo
+	for id := range pending {
+		downloads = append(downloads, commandToDownloads[id]...)
+	}
+	return downloads
+}
+
+// importSucceeded reports whether a finished import command imported its files
+// DownloadedAlbumsScan completes even when it imported nothing, so its message
+// is checked for failures; ManualImport only imports files Lidarr accepted
+func importSucceeded(cmd *lidarr.CommandResponse) bool {
+	if cmd.Status != "completed" {
+		return false
+	}
+	if cmd.Name == manualImportCommand {
+		return true
+	}
+	return !strings.Contains(strings.ToLower(cmd.Message), "failed")
+}
+
+// moveFailedImports moves the album folders of a failed import into
+// failed_imports so they are kept out of the artist folder
+func (p *Processor) moveFailedImports(reason string, downloads []downloadCleanupInfo) {
+	moved := make(map[string]bool)
+	for _, download := range downloads {
+		if download.albumDir == "" || moved[download.albumDir] {
+			continue
+		}
+		moved[download.albumDir] = true
+
+		if _, err := os.Stat(download.albumDir); err != nil {
+			p.logger.Debug("failed import folder not found", "path", download.albumDir, "error", err)
+			continue
+		}
+
+		p.logger.Warn("moving failed import",
+			"albumID", download.albumID,
+			"path", download.albumDir,
+			"reason", reason)
+		if err := p.organizer.MoveToFailedImports(download.albumDir); err != nil {
+			p.logger.Warn("failed to move failed import", "path", download.albumDir, "error", err)
+		}
+	}
 }
 
 // cleanupImportedDownloads deletes successfully imported folders and cleans up slskd
@@ -1014,6 +2911,10 @@ func (p *Processor) cleanupImportedDownloads(ctx context.Context, downloads []do
 		}
 	}
 
+	if p.cfg.Daemon.DeleteImportedFiles {
+		p.deleteImportedAlbumDirs(downloads)
+	}
+
 	// Get all current downloads from slskd
 	allDownloads, err := p.slskd.GetDownloads(ctx)
 	if err != nil {
@@ -1052,7 +2953,9 @@ func (p *Processor) cleanupImportedDownloads(ctx context.Context, downloads []do
 						"directory", download.directory,
 						"file_count", len(dirDownload.Files))
 
-					// Remove ALL files in this directory (completed, failed, whatever)
+					// Remove ALL files in this directory (completed, failed, whatever).
+					// Finished transfers are removed from slskd's list, keeping the
+					// files, anything else is cancelled
 					for _, file := range dirDownload.Files {
 						p.logger.Debug("removing file from slskd",
 							"username", download.username,
@@ -1060,7 +2963,13 @@ func (p *Processor) cleanupImportedDownloads(ctx context.Context, downloads []do
 							"state", file.State,
 							"id", file.ID)
 
-						if err := p.slskd.CancelDownload(ctx, download.username, file.ID); err != nil {
+						var err error
+						if file.IsCompleted() {
+							err = p.slskd.RemoveDownload(ctx, download.username, file.ID, false)
+						} else {
+							err = p.slskd.CancelDownload(ctx, download.username, file.ID)
+						}
+						if err != nil {
 							p.logger.Warn("failed to remove download from slskd",
 								"username", download.username,
 								"file", file.Filename,
@@ -1097,3 +3006,34 @@ func (p *Processor) cleanupImportedDownloads(ctx context.Context, downloads []do
 			"not_found", notFoundCount)
 	}
 }
+
+// deleteImportedAlbumDirs deletes what Lidarr left of each imported album
+// folder, such as artwork or files it copied rather than moved
+func (p *Processor) deleteImportedAlbumDirs(downloads []downloadCleanupInfo) {
+	downloadDir := p.cfg.LocalDownloadDir()
+	var deleted []string
+	for _, download := range downloads {
+		albumDir := download.albumDir
+		if albumDir == "" || slices.Contains(deleted, albumDir) {
+			continue
+		}
+		// Never delete anything outside slskd's download directory
+		rel, err := filepath.Rel(downloadDir, albumDir)
+		if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			p.logger.Warn("not deleting album folder outside the download directory", "path", albumDir)
+			continue
+		}
+
+		if err := os.RemoveAll(albumDir); err != nil {
+			p.logger.Warn("failed to delete imported album folder", "path", albumDir, "error", err)
+			continue
+		}
+		deleted = append(deleted, albumDir)
+		p.logger.Info("deleted imported album folder", "path", albumDir)
+
+		// Drop the artist folder once its last album is gone
+		if parent := filepath.Dir(albumDir); parent != filepath.Clean(downloadDir) {
+			os.Remove(parent)
+		}
+	}
+}
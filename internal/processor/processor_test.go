@@ -2,8 +2,13 @@ package processor
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/yuritomanek/seekarr/internal/config"
 	"github.com/yuritomanek/seekarr/internal/lidarr"
@@ -17,14 +22,26 @@ func (m *mockLidarrClient) GetWanted(ctx context.Context, opts lidarr.GetWantedO
 	return &lidarr.WantedResponse{Records: []lidarr.Album{}}, nil
 }
 
+func (m *mockLidarrClient) GetAllWanted(ctx context.Context, opts lidarr.GetWantedOptions) ([]lidarr.Album, error) {
+	return nil, nil
+}
+
 func (m *mockLidarrClient) GetAlbum(ctx context.Context, id int) (*lidarr.Album, error) {
 	return &lidarr.Album{}, nil
 }
 
+func (m *mockLidarrClient) GetArtist(ctx context.Context, id int) (*lidarr.Artist, error) {
+	return &lidarr.Artist{ID: id}, nil
+}
+
 func (m *mockLidarrClient) GetTracks(ctx context.Context, albumID int, releaseID *int) ([]lidarr.Track, error) {
 	return []lidarr.Track{}, nil
 }
 
+func (m *mockLidarrClient) GetTrackFiles(ctx context.Context, albumID int) ([]lidarr.TrackFile, error) {
+	return nil, nil
+}
+
 func (m *mockLidarrClient) UpdateAlbum(ctx context.Context, album *lidarr.Album) (*lidarr.Album, error) {
 	return album, nil
 }
@@ -33,6 +50,14 @@ func (m *mockLidarrClient) GetQueue(ctx context.Context, page int, pageSize int)
 	return &lidarr.QueueResponse{Records: []lidarr.QueueItem{}}, nil
 }
 
+func (m *mockLidarrClient) GetAllQueue(ctx context.Context, pageSize int) ([]lidarr.QueueItem, error) {
+	return nil, nil
+}
+
+func (m *mockLidarrClient) DeleteQueueItem(ctx context.Context, id int, removeFromClient, blocklist bool) error {
+	return nil
+}
+
 func (m *mockLidarrClient) PostCommand(ctx context.Context, cmd lidarr.Command) (*lidarr.CommandResponse, error) {
 	return &lidarr.CommandResponse{ID: 1}, nil
 }
@@ -41,6 +66,26 @@ func (m *mockLidarrClient) GetCommand(ctx context.Context, id int) (*lidarr.Comm
 	return &lidarr.CommandResponse{ID: id, Status: "completed"}, nil
 }
 
+func (m *mockLidarrClient) GetQualityProfiles(ctx context.Context) ([]lidarr.QualityProfile, error) {
+	return nil, nil
+}
+
+func (m *mockLidarrClient) GetHistorySince(ctx context.Context, since time.Time, eventType string) ([]lidarr.HistoryRecord, error) {
+	return nil, nil
+}
+
+func (m *mockLidarrClient) GetManualImport(ctx context.Context, folder string, albumID int) ([]lidarr.ManualImportItem, error) {
+	return nil, nil
+}
+
+func (m *mockLidarrClient) PostManualImport(ctx context.Context, items []lidarr.ManualImportItem) (*lidarr.CommandResponse, error) {
+	return &lidarr.CommandResponse{ID: 1}, nil
+}
+
+func (m *mockLidarrClient) GetSystemStatus(ctx context.Context) (*lidarr.SystemStatus, error) {
+	return &lidarr.SystemStatus{Version: "2.9.6"}, nil
+}
+
 // mockSlskdClient is a minimal mock for testing
 type mockSlskdClient struct{}
 
@@ -48,10 +93,22 @@ func (m *mockSlskdClient) GetVersion(ctx context.Context) (string, error) {
 	return "0.22.3", nil
 }
 
+func (m *mockSlskdClient) GetServerState(ctx context.Context) (*slskd.ServerState, error) {
+	return &slskd.ServerState{State: "Connected, LoggedIn", IsConnected: true, IsLoggedIn: true}, nil
+}
+
+func (m *mockSlskdClient) SubscribeTransfers(ctx context.Context) (<-chan slskd.TransferEvent, error) {
+	return nil, errors.New("transfer events not supported")
+}
+
 func (m *mockSlskdClient) Search(ctx context.Context, req slskd.SearchRequest) (*slskd.SearchResponse, error) {
 	return &slskd.SearchResponse{ID: "test-search"}, nil
 }
 
+func (m *mockSlskdClient) GetSearches(ctx context.Context) ([]slskd.SearchResponse, error) {
+	return nil, nil
+}
+
 func (m *mockSlskdClient) GetSearchState(ctx context.Context, searchID string) (*slskd.SearchResponse, error) {
 	return &slskd.SearchResponse{ID: searchID, State: "Completed"}, nil
 }
@@ -60,6 +117,29 @@ func (m *mockSlskdClient) GetSearchResults(ctx context.Context, searchID string)
 	return []slskd.SearchResult{}, nil
 }
 
+func (m *mockSlskdClient) StreamSearchResults(ctx context.Context, searchID string, fn func(slskd.SearchResult) bool) error {
+	results, err := m.GetSearchResults(ctx, searchID)
+	return yieldResults(results, err, fn)
+}
+
+// yieldResults passes results to fn as StreamSearchResults does, for mocks
+// that build the results in one go
+func yieldResults(results []slskd.SearchResult, err error, fn func(slskd.SearchResult) bool) error {
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		if !fn(result) {
+			break
+		}
+	}
+	return nil
+}
+
+func (m *mockSlskdClient) StopSearch(ctx context.Context, searchID string) error {
+	return nil
+}
+
 func (m *mockSlskdClient) DeleteSearch(ctx context.Context, searchID string) error {
 	return nil
 }
@@ -68,8 +148,8 @@ func (m *mockSlskdClient) GetDirectory(ctx context.Context, username, directory
 	return &slskd.Directory{}, nil
 }
 
-func (m *mockSlskdClient) EnqueueDownloads(ctx context.Context, username string, files []slskd.EnqueueFile) error {
-	return nil
+func (m *mockSlskdClient) EnqueueDownloads(ctx context.Context, username string, files []slskd.EnqueueFile) (*slskd.EnqueueResult, error) {
+	return &slskd.EnqueueResult{}, nil
 }
 
 func (m *mockSlskdClient) GetDownloads(ctx context.Context) (slskd.DownloadsResponse, error) {
@@ -80,14 +160,62 @@ func (m *mockSlskdClient) GetUserDownloads(ctx context.Context, username string)
 	return &slskd.UserDownloads{}, nil
 }
 
+func (m *mockSlskdClient) GetDownload(ctx context.Context, username, downloadID string) (*slskd.DownloadFile, error) {
+	return nil, slskd.ErrNotFound
+}
+
+func (m *mockSlskdClient) GetQueuePosition(ctx context.Context, username, downloadID string) (int, error) {
+	return 0, nil
+}
+
 func (m *mockSlskdClient) CancelDownload(ctx context.Context, username, downloadID string) error {
 	return nil
 }
 
+func (m *mockSlskdClient) RemoveDownload(ctx context.Context, username, downloadID string, deleteFile bool) error {
+	return nil
+}
+
 func (m *mockSlskdClient) RemoveCompletedDownloads(ctx context.Context) error {
 	return nil
 }
 
+func (m *mockSlskdClient) GetWishlist(ctx context.Context) ([]slskd.WishlistEntry, error) {
+	return []slskd.WishlistEntry{}, nil
+}
+
+func (m *mockSlskdClient) CreateWishlistEntry(ctx context.Context, searchText, filter string) (*slskd.WishlistEntry, error) {
+	return &slskd.WishlistEntry{ID: "wishlist-" + searchText, SearchText: searchText, Filter: filter}, nil
+}
+
+func (m *mockSlskdClient) DeleteWishlistEntry(ctx context.Context, id string) error {
+	return nil
+}
+
+// newTestProcessor returns a processor over the given clients with a minimal
+// config whose download directories point at a temporary directory
+func newTestProcessor(t *testing.T, lidarrClient lidarr.Client, slskdClient slskd.Client) *Processor {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+	cfg := &config.Config{
+		Lidarr: config.LidarrConfig{DownloadDir: tmpDir},
+		Slskd:  config.SlskdConfig{DownloadDir: tmpDir},
+		Search: config.SearchSettings{
+			SearchType:                "first_page",
+			MinimumFilenameMatchRatio: 0.8,
+			MaxSearchFailures:         2,
+			WishlistOnDenylist:        true,
+		},
+	}
+
+	p, err := NewProcessor(cfg, lidarrClient, slskdClient, slog.Default())
+	if err != nil {
+		t.Fatalf("NewProcessor() error: %v", err)
+	}
+	return p
+}
+
 func TestNewProcessor(t *testing.T) {
 	// Create temporary directory for state files
 	tmpDir := t.TempDir()
@@ -142,8 +270,12 @@ func TestNewProcessor(t *testing.T) {
 		t.Error("processor denylist not initialized")
 	}
 
-	if processor.pageTrack == nil {
-		t.Error("processor page tracker not initialized")
+	if processor.wishlist == nil {
+		t.Error("processor wishlist not initialized")
+	}
+
+	if processor.pageTrack[SourceMissing] == nil || processor.pageTrack[SourceCutoffUnmet] == nil {
+		t.Error("processor page trackers not initialized")
 	}
 }
 
@@ -173,6 +305,7 @@ func (m *mockLidarrClientWithCommands) PostCommand(ctx context.Context, cmd lida
 type mockSlskdClientWithTracking struct {
 	mockSlskdClient
 	canceledDownloads []string              // Track which downloads were canceled
+	removedDownloads  []string              // Track which downloads were removed from the list
 	downloads         []downloadCleanupInfo // Track which downloads we should return
 }
 
@@ -205,11 +338,49 @@ func (m *mockSlskdClientWithTracking) CancelDownload(ctx context.Context, userna
 	return nil
 }
 
+func (m *mockSlskdClientWithTracking) RemoveDownload(ctx context.Context, username, downloadID string, deleteFile bool) error {
+	if deleteFile {
+		return errors.New("downloaded files should be kept")
+	}
+	m.removedDownloads = append(m.removedDownloads, downloadID)
+	return nil
+}
+
 func (m *mockSlskdClientWithTracking) RemoveCompletedDownloads(ctx context.Context) error {
 	// No longer used
 	return nil
 }
 
+func TestTriggerImport_ScansEachAlbumFolder(t *testing.T) {
+	lidarrClient := &mockLidarrClientRecordingCommands{}
+	p := newTestProcessor(t, lidarrClient, &mockSlskdClient{})
+	p.cfg.Lidarr.DownloadDir = "/music/incoming"
+	p.cfg.Timing.ImportPollSeconds = 1
+
+	source := func(dir string) []DownloadSource {
+		return []DownloadSource{{Username: "user", Directory: dir}}
+	}
+	downloads := []DownloadedItem{
+		{ArtistName: "Artist", AlbumName: "First", AlbumID: 1, Sources: source("Music/First")},
+		{ArtistName: "Artist", AlbumName: "Second", AlbumID: 2, Sources: source("Music/Second")},
+		{ArtistName: "Artist", AlbumName: "First", AlbumID: 3, Sources: source("Music/First (Remaster)"),
+			AlbumDir: filepath.Join(p.cfg.Slskd.DownloadDir, "Artist", "First_1")},
+		{ArtistName: "Artist", AlbumName: "Second", AlbumID: 2, Sources: source("Music/Second CD2")},
+	}
+	if err := p.triggerImport(context.Background(), downloads); err != nil {
+		t.Fatalf("triggerImport() error: %v", err)
+	}
+
+	var paths []string
+	for _, cmd := range lidarrClient.posted {
+		paths = append(paths, cmd.Path)
+	}
+	want := []string{"/music/incoming/Artist/First", "/music/incoming/Artist/Second", "/music/incoming/Artist/First_1"}
+	if !slices.Equal(paths, want) {
+		t.Errorf("expected import paths %v, got %v", want, paths)
+	}
+}
+
 func TestPollImportCompletion(t *testing.T) {
 	tests := []struct {
 		name                string
@@ -285,7 +456,7 @@ func TestPollImportCompletion(t *testing.T) {
 			}
 
 			ctx := context.Background()
-			successful := processor.pollImportCompletion(ctx, tt.commandToDownloads)
+			successful, _ := processor.pollImportCompletion(ctx, tt.commandToDownloads)
 
 			if len(successful) != tt.wantSuccessfulCount {
 				t.Errorf("got %d successful downloads, want %d", len(successful), tt.wantSuccessfulCount)
@@ -294,12 +465,121 @@ func TestPollImportCompletion(t *testing.T) {
 	}
 }
 
+func TestPollImportCompletion_MovesFailedImports(t *testing.T) {
+	lidarrClient := &mockLidarrClientWithCommands{commands: map[int]*lidarr.CommandResponse{
+		1: {ID: 1, Status: "completed", Message: "Importing 5 tracks"},
+		2: {ID: 2, Status: "completed", Message: "Failed to import"},
+	}}
+	p := newTestProcessor(t, lidarrClient, &mockSlskdClient{})
+
+	imported := p.organizer.AlbumDir("Artist One", "Good Album")
+	failed := p.organizer.AlbumDir("Artist Two", "Bad Album")
+	for _, dir := range []string{imported, failed} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p.pollImportCompletion(context.Background(), map[int][]downloadCleanupInfo{
+		1: {{albumID: 1, username: "user1", directory: "Music/Good Album", albumDir: imported}},
+		2: {
+			{albumID: 2, username: "user2", directory: "Music/Bad Album/CD1", albumDir: failed},
+			{albumID: 2, username: "user2", directory: "Music/Bad Album/CD2", albumDir: failed},
+		},
+	})
+
+	if _, err := os.Stat(imported); err != nil {
+		t.Errorf("expected the imported album left in place: %v", err)
+	}
+	if _, err := os.Stat(failed); !os.IsNotExist(err) {
+		t.Errorf("expected the failed album moved away, stat error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(p.cfg.Slskd.DownloadDir, "failed_imports", "Bad Album")); err != nil {
+		t.Errorf("expected the failed album in failed_imports: %v", err)
+	}
+}
+
+func TestPollImportCompletion_Timeout(t *testing.T) {
+	// Command 2 stays started, as after Lidarr restarts mid-scan
+	lidarrClient := &mockLidarrClientWithCommands{commands: map[int]*lidarr.CommandResponse{
+		1: {ID: 1, Status: "completed", Message: "Importing 5 tracks"},
+		2: {ID: 2, Status: "started"},
+	}}
+	p := newTestProcessor(t, lidarrClient, &mockSlskdClient{})
+	p.cfg.Timing.ImportPollSeconds = 1
+	p.cfg.Timing.ImportTimeoutSeconds = 1
+
+	stuck := p.organizer.AlbumDir("Artist Two", "Stuck Album")
+	if err := os.MkdirAll(stuck, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	successful, timedOut := p.pollImportCompletion(context.Background(), map[int][]downloadCleanupInfo{
+		1: {{albumID: 1, username: "user1", directory: "Music/Good Album"}},
+		2: {{albumID: 2, username: "user2", directory: "Music/Stuck Album", albumDir: stuck}},
+	})
+
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("expected polling to stop at the 1s deadline, took %v", elapsed)
+	}
+	if len(successful) != 1 || successful[0].albumID != 1 {
+		t.Errorf("expected album 1 imported, got %+v", successful)
+	}
+	if len(timedOut) != 1 || timedOut[0].albumID != 2 {
+		t.Errorf("expected album 2 timed out, got %+v", timedOut)
+	}
+	if _, err := os.Stat(stuck); err != nil {
+		t.Errorf("expected the timed-out album left in place: %v", err)
+	}
+}
+
+func TestTriggerImport_CancelledKeepsRunningImportsPending(t *testing.T) {
+	// Command 2 is still running when seekarr is stopped
+	lidarrClient := &mockLidarrClientWithCommands{commands: map[int]*lidarr.CommandResponse{
+		2: {ID: 2, Status: "started"},
+	}}
+	p := newTestProcessor(t, lidarrClient, &mockSlskdClient{})
+	p.cfg.Timing.ImportPollSeconds = 1
+	p.cfg.Timing.ImportTimeoutSeconds = 60
+
+	item := DownloadedItem{
+		ArtistName: "Artist",
+		AlbumName:  "Album",
+		AlbumID:    1,
+		Sources:    []DownloadSource{{Username: "user1", Directory: "Music/Album"}},
+		AlbumDir:   p.organizer.AlbumDir("Artist", "Album"),
+	}
+	if err := os.MkdirAll(item.AlbumDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	p.savePendingImports([]DownloadedItem{item})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	start := time.Now()
+	if err := p.triggerImport(ctx, []DownloadedItem{item}); err != nil {
+		t.Fatalf("triggerImport() error: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("expected polling to stop on cancellation, took %v", elapsed)
+	}
+	pending := p.pending.Pending()
+	if len(pending) != 1 || pending[0].AlbumID != 1 {
+		t.Fatalf("expected the running import kept pending, got %+v", pending)
+	}
+	if _, err := os.Stat(item.AlbumDir); err != nil {
+		t.Errorf("expected the album folder left in place: %v", err)
+	}
+}
+
 func TestCleanupImportedDownloads(t *testing.T) {
 	tests := []struct {
 		name                string
 		downloads           []downloadCleanupInfo
 		cleanupDelaySeconds int
-		wantCanceledCount   int
+		wantRemovedCount    int
 	}{
 		{
 			name: "cleanup with downloads",
@@ -308,7 +588,7 @@ func TestCleanupImportedDownloads(t *testing.T) {
 				{username: "user2", directory: "/Artist Two"},
 			},
 			cleanupDelaySeconds: 0,
-			wantCanceledCount:   2, // One file per download
+			wantRemovedCount:    2, // One file per download
 		},
 		{
 			name: "cleanup with delay",
@@ -316,13 +596,13 @@ func TestCleanupImportedDownloads(t *testing.T) {
 				{username: "user1", directory: "/Artist One"},
 			},
 			cleanupDelaySeconds: 1,
-			wantCanceledCount:   1,
+			wantRemovedCount:    1,
 		},
 		{
 			name:                "no downloads",
 			downloads:           []downloadCleanupInfo{},
 			cleanupDelaySeconds: 0,
-			wantCanceledCount:   0,
+			wantRemovedCount:    0,
 		},
 	}
 
@@ -352,10 +632,189 @@ func TestCleanupImportedDownloads(t *testing.T) {
 			ctx := context.Background()
 			processor.cleanupImportedDownloads(ctx, tt.downloads)
 
-			// Verify individual downloads were canceled
-			if len(slskdClient.canceledDownloads) != tt.wantCanceledCount {
-				t.Errorf("canceled %d downloads, want %d",
-					len(slskdClient.canceledDownloads), tt.wantCanceledCount)
+			// Verify the completed downloads were removed, not cancelled
+			if len(slskdClient.removedDownloads) != tt.wantRemovedCount {
+				t.Errorf("removed %d downloads, want %d",
+					len(slskdClient.removedDownloads), tt.wantRemovedCount)
+			}
+			if len(slskdClient.canceledDownloads) != 0 {
+				t.Errorf("expected no downloads cancelled, got %v", slskdClient.canceledDownloads)
+			}
+		})
+	}
+}
+
+func TestCleanupImportedDownloads_DeletesImportedFiles(t *testing.T) {
+	tests := []struct {
+		name                string
+		deleteImportedFiles bool
+		wantDeleted         bool
+	}{
+		{name: "files kept by default", wantDeleted: false},
+		{name: "files deleted when enabled", deleteImportedFiles: true, wantDeleted: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			downloads := []downloadCleanupInfo{{username: "user1", directory: "Music/Album"}}
+			slskdClient := &mockSlskdClientWithTracking{downloads: downloads}
+			p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+			p.cfg.Daemon.CleanupDelaySeconds = 0
+			p.cfg.Daemon.DeleteImportedFiles = tt.deleteImportedFiles
+
+			albumDir := p.organizer.AlbumDir("Artist", "Album")
+			writeDownloadedFile(t, p, filepath.Join("Artist", "Album"), "cover.jpg", 10)
+			downloads[0].albumDir = albumDir
+
+			p.cleanupImportedDownloads(context.Background(), downloads)
+
+			_, err := os.Stat(albumDir)
+			if deleted := os.IsNotExist(err); deleted != tt.wantDeleted {
+				t.Errorf("album folder deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+			if len(slskdClient.removedDownloads) != 1 {
+				t.Errorf("expected 1 download removed from slskd, got %d", len(slskdClient.removedDownloads))
+			}
+		})
+	}
+}
+
+// mockLidarrClientRecordingUpdates records albums passed to UpdateAlbum
+type mockLidarrClientRecordingUpdates struct {
+	mockLidarrClient
+	updated []lidarr.Album
+}
+
+func (m *mockLidarrClientRecordingUpdates) GetAlbum(ctx context.Context, id int) (*lidarr.Album, error) {
+	return &lidarr.Album{ID: id, Title: "Lost Album", Monitored: true}, nil
+}
+
+func (m *mockLidarrClientRecordingUpdates) UpdateAlbum(ctx context.Context, album *lidarr.Album) (*lidarr.Album, error) {
+	m.updated = append(m.updated, *album)
+	return album, nil
+}
+
+func TestRecordSearchFailure_RemoveWantedOnFailure(t *testing.T) {
+	tests := []struct {
+		name        string
+		enabled     bool
+		failures    int
+		wantUpdates int
+	}{
+		{name: "disabled", enabled: false, failures: 2, wantUpdates: 0},
+		{name: "below limit", enabled: true, failures: 1, wantUpdates: 0},
+		{name: "at limit", enabled: true, failures: 2, wantUpdates: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lidarrClient := &mockLidarrClientRecordingUpdates{}
+			p := newTestProcessor(t, lidarrClient, &mockSlskdClientWithWishlist{})
+			p.cfg.Search.WishlistOnDenylist = false
+			p.cfg.Search.RemoveWantedOnFailure = tt.enabled
+
+			album := lidarr.Album{ID: 7, Title: "Lost Album"}
+			for i := 0; i < tt.failures; i++ {
+				p.recordSearchFailure(context.Background(), album)
+			}
+
+			if len(lidarrClient.updated) != tt.wantUpdates {
+				t.Fatalf("expected %d updates, got %d", tt.wantUpdates, len(lidarrClient.updated))
+			}
+			if tt.wantUpdates > 0 && lidarrClient.updated[0].Monitored {
+				t.Error("expected album to be unmonitored")
+			}
+		})
+	}
+}
+
+// mockLidarrClientFailingTracks fails every track lookup, as if Lidarr were down
+type mockLidarrClientFailingTracks struct {
+	mockLidarrClient
+}
+
+func (m *mockLidarrClientFailingTracks) GetTracks(ctx context.Context, albumID int, releaseID *int) ([]lidarr.Track, error) {
+	return nil, errors.New("connection refused")
+}
+
+// mockSlskdClientFailingSearch fails every search, as if slskd were down
+type mockSlskdClientFailingSearch struct {
+	mockSlskdClient
+}
+
+func (m *mockSlskdClientFailingSearch) Search(ctx context.Context, req slskd.SearchRequest) (*slskd.SearchResponse, error) {
+	return nil, errors.New("503 service unavailable")
+}
+
+func TestQueueAlbum_UnavailableServicesNotDenylisted(t *testing.T) {
+	album := concurrentAlbums(1)[0]
+	tracks := []lidarr.Track{{Title: "First Song"}, {Title: "Second Song"}}
+	tests := []struct {
+		name         string
+		lidarr       lidarr.Client
+		slskd        slskd.Client
+		wantReason   string
+		wantFailures int
+	}{
+		{name: "lidarr down", lidarr: &mockLidarrClientFailingTracks{}, slskd: &mockSlskdClient{}, wantReason: ReasonUnavailable},
+		{name: "slskd down", lidarr: &mockLidarrClientWithAliases{tracks: tracks}, slskd: &mockSlskdClientFailingSearch{}, wantReason: ReasonUnavailable},
+		{name: "no results", lidarr: &mockLidarrClientWithAliases{tracks: tracks}, slskd: &mockSlskdClient{}, wantReason: ReasonNoResults, wantFailures: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestProcessor(t, tt.lidarr, tt.slskd)
+			p.cfg.Search.WishlistOnDenylist = false
+			p.current = &RunSummary{}
+
+			_, outcome := p.queueAlbum(context.Background(), album)
+
+			if outcome != OutcomeFailed {
+				t.Fatalf("expected outcome %q, got %q", OutcomeFailed, outcome)
+			}
+			if d := p.current.Decisions[0]; d.Reason != tt.wantReason {
+				t.Errorf("expected reason %q, got %q", tt.wantReason, d.Reason)
+			}
+			failures := 0
+			if entry := p.denylist.GetEntry(album.ID); entry != nil {
+				failures = entry.Failures
+			}
+			if failures != tt.wantFailures {
+				t.Errorf("expected %d recorded failures, got %d", tt.wantFailures, failures)
+			}
+		})
+	}
+}
+
+func TestBlacklistedBy(t *testing.T) {
+	blacklist := []string{"Karaoke", `re:(?i)\blive (at|in|from)\b`, `re:^Demo`}
+
+	tests := []struct {
+		title    string
+		wantTerm string
+	}{
+		{"Greatest Hits (Karaoke Version)", "Karaoke"},
+		{"greatest hits karaoke", "Karaoke"},
+		{"Live at Budokan", `re:(?i)\blive (at|in|from)\b`},
+		{"Alive in the Studio", ""},
+		{"Demos and Rarities", `re:^Demo`},
+		{"The Demos", ""},
+		{"Studio Album", ""},
+	}
+
+	p := newTestProcessor(t, &mockLidarrClient{}, &mockSlskdClient{})
+	p.cfg.Search.TitleBlacklist = blacklist
+	patterns, err := p.cfg.Search.TitleBlacklistPatterns()
+	if err != nil {
+		t.Fatalf("TitleBlacklistPatterns() error: %v", err)
+	}
+	p.titleBlacklist = patterns
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			term, ok := p.blacklistedBy(tt.title)
+			if ok != (tt.wantTerm != "") || term != tt.wantTerm {
+				t.Errorf("blacklistedBy(%q) = %q, %v, want %q", tt.title, term, ok, tt.wantTerm)
 			}
 		})
 	}
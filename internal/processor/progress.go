@@ -0,0 +1,54 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// SetProgressLevel sets the level download progress is logged at on each poll,
+// debug unless changed, e.g. to info for plain console output
+func (p *Processor) SetProgressLevel(level slog.Level) {
+	p.progressLevel = level
+}
+
+// logProgress reports how much of an item has downloaded, how fast, and
+// roughly how long the rest will take
+func (p *Processor) logProgress(ctx context.Context, item DownloadedItem, files []sourceFile) {
+	var total, done int64
+	var speed float64
+	for _, file := range files {
+		total += file.Size
+		if file.IsCompleted() {
+			done += file.Size
+			continue
+		}
+		done += file.BytesTransferred
+		if strings.HasPrefix(file.State, "InProgress") {
+			speed += file.AverageSpeed
+		}
+	}
+	if total <= 0 {
+		return
+	}
+
+	eta := "unknown"
+	if speed > 0 {
+		eta = (time.Duration(float64(total-done)/speed) * time.Second).String()
+	}
+	p.logger.Log(ctx, p.progressLevel, "download progress",
+		"album", item.AlbumName,
+		"percent", fmt.Sprintf("%.0f%%", float64(done)/float64(total)*100),
+		"speed", formatSpeed(speed),
+		"eta", eta)
+}
+
+// formatSpeed renders a transfer speed in MB/s, or KB/s for slow transfers
+func formatSpeed(bytesPerSecond float64) string {
+	if bytesPerSecond < 1024*1024 {
+		return fmt.Sprintf("%.0f KB/s", bytesPerSecond/1024)
+	}
+	return fmt.Sprintf("%.1f MB/s", bytesPerSecond/(1024*1024))
+}
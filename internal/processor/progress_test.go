@@ -0,0 +1,75 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+func TestLogProgress(t *testing.T) {
+	file := func(state string, size, transferred int64, speed float64) sourceFile {
+		return sourceFile{DownloadFile: slskd.DownloadFile{
+			Filename:         "01.flac",
+			State:            state,
+			Size:             size,
+			BytesTransferred: transferred,
+			AverageSpeed:     speed,
+		}}
+	}
+
+	tests := []struct {
+		name  string
+		files []sourceFile
+		want  []string
+	}{
+		{
+			name: "completed and in-progress files",
+			files: []sourceFile{
+				file("Completed, Succeeded", 4<<20, 4<<20, 0),
+				file("InProgress", 4<<20, 2<<20, 512*1024),
+				file("Queued, Remotely", 2<<20, 0, 0),
+			},
+			want: []string{"percent=60%", `speed="512 KB/s"`, "eta=8s"},
+		},
+		{
+			name:  "speed summed across transfers",
+			files: []sourceFile{file("InProgress", 10<<20, 0, 1<<20), file("InProgress", 10<<20, 0, 1<<20)},
+			want:  []string{"percent=0%", `speed="2.0 MB/s"`, "eta=10s"},
+		},
+		{
+			name:  "no speed yet",
+			files: []sourceFile{file("Queued, Remotely", 1<<20, 0, 0)},
+			want:  []string{`speed="0 KB/s"`, "eta=unknown"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			p := &Processor{logger: slog.New(slog.NewTextHandler(&buf, nil)), progressLevel: slog.LevelInfo}
+			p.logProgress(context.Background(), DownloadedItem{AlbumName: "Album"}, tt.files)
+
+			out := buf.String()
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("log %q missing %q", out, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLogProgress_Level(t *testing.T) {
+	var buf bytes.Buffer
+	p := &Processor{logger: slog.New(slog.NewTextHandler(&buf, nil)), progressLevel: slog.LevelDebug}
+	files := []sourceFile{{DownloadFile: slskd.DownloadFile{State: "InProgress", Size: 100}}}
+	p.logProgress(context.Background(), DownloadedItem{AlbumName: "Album"}, files)
+
+	if buf.Len() != 0 {
+		t.Errorf("progress logged below the handler's level: %q", buf.String())
+	}
+}
@@ -0,0 +1,145 @@
+package processor
+
+import (
+	"context"
+	"slices"
+
+	"github.com/yuritomanek/seekarr/internal/filter"
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+)
+
+// lidarrQualityPatterns maps Lidarr qualities to the allowed_filetypes pattern
+// accepting exactly those files. Qualities missing here, such as VBR MP3 or
+// AAC, can't be told apart by extension and bitrate alone
+var lidarrQualityPatterns = map[string]string{
+	"FLAC 24bit": "flac 24",
+	"FLAC":       "flac",
+	"WAV":        "wav",
+	"APE":        "ape",
+	"WavPack":    "wv",
+	"MP3-320":    "mp3 320",
+	"MP3-256":    "mp3 256",
+	"MP3-224":    "mp3 224",
+	"MP3-192":    "mp3 192",
+	"MP3-160":    "mp3 160",
+	"MP3-128":    "mp3 128",
+}
+
+// profilePatterns turns a quality profile's allowed qualities into filetype
+// patterns, best first. It reports false if any allowed quality has no pattern
+func profilePatterns(profile lidarr.QualityProfile) ([]string, bool) {
+	var qualities []string
+	var collect func(items []lidarr.QualityProfileItem, allowed bool)
+	collect = func(items []lidarr.QualityProfileItem, allowed bool) {
+		for _, item := range items {
+			if item.Quality == nil {
+				// A group allows all of its qualities
+				collect(item.Items, allowed || item.Allowed)
+				continue
+			}
+			if allowed || item.Allowed {
+				qualities = append(qualities, item.Quality.Name)
+			}
+		}
+	}
+	collect(profile.Items, false)
+
+	// Profiles list qualities lowest first
+	var patterns []string
+	for _, quality := range slices.Backward(qualities) {
+		pattern, ok := lidarrQualityPatterns[quality]
+		if !ok {
+			return nil, false
+		}
+		if !slices.Contains(patterns, pattern) {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns, len(patterns) > 0
+}
+
+// albumFilter returns the filter for an album's files: its artist's Lidarr
+// quality profile with use_lidarr_quality_profile, otherwise allowed_filetypes
+func (p *Processor) albumFilter(ctx context.Context, album lidarr.Album) *filter.Filter {
+	if !p.cfg.Search.UseLidarrQualityProfile {
+		return p.filter
+	}
+
+	profileID := album.Artist.QualityProfileID
+	if profileID == 0 {
+		artist, err := p.lidarr.GetArtist(ctx, album.ArtistID)
+		if err != nil {
+			p.logger.Warn("failed to fetch artist quality profile, using allowed_filetypes", "album", album.Title, "error", err)
+			return p.filter
+		}
+		profileID = artist.QualityProfileID
+	}
+
+	profile, err := p.qualityProfile(ctx, profileID)
+	if err != nil {
+		p.logger.Warn("failed to fetch quality profiles, using allowed_filetypes", "album", album.Title, "error", err)
+		return p.filter
+	}
+	if profile == nil {
+		p.logger.Debug("quality profile not found, using allowed_filetypes", "album", album.Title, "profileID", profileID)
+		return p.filter
+	}
+
+	patterns, ok := profilePatterns(*profile)
+	if !ok {
+		p.logger.Debug("quality profile doesn't map to filetypes exactly, using allowed_filetypes",
+			"album", album.Title,
+			"profile", profile.Name)
+		return p.filter
+	}
+
+	p.logger.Debug("using lidarr quality profile", "album", album.Title, "profile", profile.Name, "filetypes", patterns)
+	return filter.NewFilter(patterns)
+}
+
+// qualityProfile returns a Lidarr quality profile by ID, fetching the profiles
+// once per run. It returns nil if no profile has the ID
+func (p *Processor) qualityProfile(ctx context.Context, id int) (*lidarr.QualityProfile, error) {
+	p.profilesMu.Lock()
+	defer p.profilesMu.Unlock()
+
+	if p.profiles == nil {
+		profiles, err := p.lidarr.GetQualityProfiles(ctx)
+		if err != nil {
+			return nil, err
+		}
+		p.profiles = make(map[int]lidarr.QualityProfile, len(profiles))
+		for _, profile := range profiles {
+			p.profiles[profile.ID] = profile
+		}
+	}
+
+	profile, ok := p.profiles[id]
+	if !ok {
+		return nil, nil
+	}
+	return &profile, nil
+}
+
+// resetQualityProfiles makes the next run fetch quality profiles again
+func (p *Processor) resetQualityProfiles() {
+	p.profilesMu.Lock()
+	defer p.profilesMu.Unlock()
+	p.profiles = nil
+}
+
+// filterKey is the context key for the filter chosen for an album
+type filterKey struct{}
+
+// withFilter attaches an album's filter to the context its search runs in
+func withFilter(ctx context.Context, f *filter.Filter) context.Context {
+	return context.WithValue(ctx, filterKey{}, f)
+}
+
+// filterFor returns the filter attached to ctx, or allowed_filetypes if none is
+func (p *Processor) filterFor(ctx context.Context) *filter.Filter {
+	if f, ok := ctx.Value(filterKey{}).(*filter.Filter); ok {
+		return f
+	}
+	return p.filter
+}
@@ -0,0 +1,140 @@
+package processor
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/filter"
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+func quality(name string, allowed bool) lidarr.QualityProfileItem {
+	return lidarr.QualityProfileItem{Quality: &lidarr.Quality{Name: name}, Allowed: allowed}
+}
+
+func TestProfilePatterns(t *testing.T) {
+	tests := []struct {
+		name     string
+		items    []lidarr.QualityProfileItem
+		expected []string
+		ok       bool
+	}{
+		{
+			name: "allowed qualities best first",
+			items: []lidarr.QualityProfileItem{
+				quality("MP3-192", false),
+				quality("MP3-320", true),
+				quality("FLAC", true),
+			},
+			expected: []string{"flac", "mp3 320"},
+			ok:       true,
+		},
+		{
+			name: "allowed group includes its qualities",
+			items: []lidarr.QualityProfileItem{
+				quality("MP3-320", false),
+				{Name: "Lossless", Allowed: true, Items: []lidarr.QualityProfileItem{
+					quality("FLAC", false),
+					quality("FLAC 24bit", false),
+				}},
+			},
+			expected: []string{"flac 24", "flac"},
+			ok:       true,
+		},
+		{
+			name: "unmappable quality falls back",
+			items: []lidarr.QualityProfileItem{
+				quality("MP3-VBR-V0", true),
+				quality("FLAC", true),
+			},
+			ok: false,
+		},
+		{
+			name: "unmappable quality not allowed is ignored",
+			items: []lidarr.QualityProfileItem{
+				quality("AAC-320", false),
+				quality("FLAC", true),
+			},
+			expected: []string{"flac"},
+			ok:       true,
+		},
+		{
+			name:  "nothing allowed",
+			items: []lidarr.QualityProfileItem{quality("FLAC", false)},
+			ok:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patterns, ok := profilePatterns(lidarr.QualityProfile{Items: tt.items})
+			if ok != tt.ok {
+				t.Fatalf("expected ok=%v, got %v", tt.ok, ok)
+			}
+			if ok && !slices.Equal(patterns, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, patterns)
+			}
+		})
+	}
+}
+
+// mockLidarrClientWithProfiles serves quality profiles and counts the requests
+type mockLidarrClientWithProfiles struct {
+	mockLidarrClient
+	profiles []lidarr.QualityProfile
+	requests int
+}
+
+func (m *mockLidarrClientWithProfiles) GetQualityProfiles(ctx context.Context) ([]lidarr.QualityProfile, error) {
+	m.requests++
+	return m.profiles, nil
+}
+
+func TestAlbumFilter(t *testing.T) {
+	lidarrMock := &mockLidarrClientWithProfiles{profiles: []lidarr.QualityProfile{
+		{ID: 1, Name: "Lossy", Items: []lidarr.QualityProfileItem{quality("MP3-320", true)}},
+		{ID: 2, Name: "Any", Items: []lidarr.QualityProfileItem{quality("AAC-256", true)}},
+	}}
+	p := newTestProcessor(t, lidarrMock, &mockSlskdClientWithResults{})
+	p.cfg.Search.UseLidarrQualityProfile = true
+
+	flac := slskd.SearchFile{Filename: "01 - Song.flac", BitDepth: intPtr(16), SampleRate: intPtr(44100)}
+	mp3 := slskd.SearchFile{Filename: "01 - Song.mp3", BitRate: intPtr(320)}
+
+	lossy := p.albumFilter(context.Background(), lidarr.Album{Title: "A", Artist: lidarr.Artist{QualityProfileID: 1}})
+	if !lossy.FileMatches(mp3) || lossy.FileMatches(flac) {
+		t.Error("expected the Lossy profile to allow only mp3 320")
+	}
+	if got := lossy.AllowedFiletypes(); !slices.Equal(got, []string{"mp3 320"}) {
+		t.Errorf("expected the Lossy profile's filetypes, got %v", got)
+	}
+
+	if f := p.albumFilter(context.Background(), lidarr.Album{Title: "B", Artist: lidarr.Artist{QualityProfileID: 2}}); f != p.filter {
+		t.Error("expected an unmappable profile to fall back to allowed_filetypes")
+	}
+	if f := p.albumFilter(context.Background(), lidarr.Album{Title: "C", Artist: lidarr.Artist{QualityProfileID: 9}}); f != p.filter {
+		t.Error("expected an unknown profile to fall back to allowed_filetypes")
+	}
+	if lidarrMock.requests != 1 {
+		t.Errorf("expected profiles fetched once per run, got %d requests", lidarrMock.requests)
+	}
+
+	p.cfg.Search.UseLidarrQualityProfile = false
+	if f := p.albumFilter(context.Background(), lidarr.Album{Artist: lidarr.Artist{QualityProfileID: 1}}); f != p.filter {
+		t.Error("expected allowed_filetypes when the option is off")
+	}
+}
+
+func TestFilterFor(t *testing.T) {
+	p := newTestProcessor(t, &mockLidarrClient{}, &mockSlskdClientWithResults{})
+	if p.filterFor(context.Background()) != p.filter {
+		t.Error("expected allowed_filetypes without an album filter")
+	}
+
+	f := filter.NewFilter([]string{"mp3"})
+	if p.filterFor(withFilter(context.Background(), f)) != f {
+		t.Error("expected the album filter attached to the context")
+	}
+}
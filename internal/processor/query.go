@@ -0,0 +1,56 @@
+package processor
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// bracketedPattern matches a parenthesized or bracketed part of a title
+var bracketedPattern = regexp.MustCompile(`\s*[(\[][^()\[\]]*[)\]]`)
+
+// sanitizeQuery prepares search text for Soulseek. Bracketed qualifiers naming
+// one of editionKeywords are dropped, punctuation peers treat specially or fail
+// to match is replaced with spaces, and whitespace is collapsed
+// The query is returned unchanged if nothing would be left of it
+func sanitizeQuery(query string, editionKeywords []string) string {
+	sanitized := query
+	if len(editionKeywords) > 0 {
+		sanitized = bracketedPattern.ReplaceAllStringFunc(sanitized, func(part string) string {
+			if hasEditionKeyword(part, editionKeywords) {
+				return ""
+			}
+			return part
+		})
+	}
+
+	sanitized = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			return r
+		}
+		return ' '
+	}, sanitized)
+	sanitized = strings.Join(strings.Fields(sanitized), " ")
+
+	if sanitized == "" {
+		return query
+	}
+	return sanitized
+}
+
+// hasEditionKeyword reports whether text contains one of keywords as whole words
+func hasEditionKeyword(text string, keywords []string) bool {
+	words := " " + strings.ToLower(sanitizeQuery(text, nil)) + " "
+	for _, keyword := range keywords {
+		keyword = strings.ToLower(sanitizeQuery(keyword, nil))
+		if strings.TrimSpace(keyword) != "" && strings.Contains(words, " "+keyword+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitizeQuery applies the configured edition keywords to sanitizeQuery
+func (p *Processor) sanitizeQuery(query string) string {
+	return sanitizeQuery(query, p.cfg.Search.StripEditionKeywords)
+}
@@ -0,0 +1,61 @@
+package processor
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+)
+
+func TestSanitizeQuery(t *testing.T) {
+	keywords := []string{"deluxe", "remastered", "Anniversary Edition"}
+
+	tests := []struct {
+		name     string
+		query    string
+		keywords []string
+		want     string
+	}{
+		{"plain", "Abbey Road", keywords, "Abbey Road"},
+		{"punctuation", "What's Going On", keywords, "What s Going On"},
+		{"edition qualifiers", "What's Going On (Deluxe Edition) [Remastered]", keywords, "What s Going On"},
+		{"multi-word keyword", "Nevermind (20th Anniversary Edition)", keywords, "Nevermind"},
+		{"other qualifier kept", "Blue (Live)", keywords, "Blue Live"},
+		{"no keywords", "Blue (Deluxe)", nil, "Blue Deluxe"},
+		{"keyword inside a word", "Blue (Deluxeness)", keywords, "Blue Deluxeness"},
+		{"whitespace", "  Artist  -  Title\t", keywords, "Artist Title"},
+		{"non-latin", "Кино — Группа крови", keywords, "Кино Группа крови"},
+		{"nothing left", "???", keywords, "???"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeQuery(tt.query, tt.keywords); got != tt.want {
+				t.Errorf("sanitizeQuery(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueueAlbum_RetriesWithRawTitle(t *testing.T) {
+	slskdClient := &mockSlskdClientWithQueries{matchText: "Album (Deluxe)"}
+	lidarrClient := &mockLidarrClientWithAliases{tracks: []lidarr.Track{{Title: "Opening"}}}
+	p := newTestProcessor(t, lidarrClient, slskdClient)
+	p.cfg.Search.StripEditionKeywords = []string{"deluxe"}
+	p.current = &RunSummary{}
+
+	album := lidarr.Album{
+		ID:       1,
+		Title:    "Album (Deluxe)",
+		Artist:   lidarr.Artist{ArtistName: "The Band"},
+		Releases: []lidarr.Release{{Status: "Official", TrackCount: 1}},
+	}
+
+	if _, outcome := p.queueAlbum(context.Background(), album); outcome != OutcomeQueued {
+		t.Fatalf("expected album to be queued, got %q", outcome)
+	}
+	if want := []string{"Album", "The Band Album", "Album (Deluxe)"}; !reflect.DeepEqual(slskdClient.queries, want) {
+		t.Errorf("expected searches %v, got %v", want, slskdClient.queries)
+	}
+}
@@ -0,0 +1,84 @@
+package processor
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// queueTracker remembers where remotely queued files stand in their uploaders'
+// queues, asking each uploader at most once per interval
+type queueTracker struct {
+	interval time.Duration
+	checked  map[string]time.Time
+	position map[string]int
+}
+
+func newQueueTracker(interval time.Duration) *queueTracker {
+	return &queueTracker{
+		interval: interval,
+		checked:  make(map[string]time.Time),
+		position: make(map[string]int),
+	}
+}
+
+// remotelyQueued reports whether a file is waiting in the uploader's queue
+func remotelyQueued(file sourceFile) bool {
+	return strings.HasPrefix(file.State, "Queued") && strings.Contains(file.State, "Remotely")
+}
+
+// queuePosition returns a remotely queued file's place in the uploader's
+// queue, if known. With max_queue_position set the uploader is asked for it
+// once per interval, since slskd only learns it when asked
+func (p *Processor) queuePosition(ctx context.Context, queue *queueTracker, file sourceFile, now time.Time) (int, bool) {
+	key := stallKey(file.username, file.Filename)
+	if file.PlaceInQueue != nil {
+		queue.position[key] = *file.PlaceInQueue
+	}
+
+	if p.cfg.Download.MaxQueuePosition > 0 && now.Sub(queue.checked[key]) >= queue.interval {
+		queue.checked[key] = now
+		position, err := p.slskd.GetQueuePosition(ctx, file.username, file.ID)
+		if err != nil {
+			p.logger.Debug("failed to get queue position", "username", file.username, "file", file.Filename, "error", err)
+		} else {
+			queue.position[key] = position
+		}
+	}
+
+	position, ok := queue.position[key]
+	return position, ok
+}
+
+// queuedTooFar reports whether a remotely queued file is further back in the
+// uploader's queue than max_queue_position allows. Retrying would only queue
+// it again behind the same uploads, so the album is searched again instead
+func (p *Processor) queuedTooFar(ctx context.Context, queue *queueTracker, item DownloadedItem, file sourceFile, now time.Time) bool {
+	if !remotelyQueued(file) {
+		return false
+	}
+	position, ok := p.queuePosition(ctx, queue, file, now)
+	if !ok {
+		return false
+	}
+
+	p.logger.Debug("download queued",
+		"album", item.AlbumName,
+		"username", file.username,
+		"file", file.Filename,
+		"position", position)
+	limit := p.cfg.Download.MaxQueuePosition
+	return limit > 0 && position > limit
+}
+
+// abandonQueued gives up on an item whose files sit too far back in an
+// uploader's queue, cancelling its transfers and counting a failed search so
+// another source is found next run
+func (p *Processor) abandonQueued(ctx context.Context, item DownloadedItem, completed, unfinished []sourceFile) {
+	for _, file := range unfinished {
+		if err := p.slskd.CancelDownload(ctx, file.username, file.ID); err != nil {
+			p.logger.Debug("failed to cancel download", "file", file.Filename, "error", err)
+		}
+	}
+	p.discardPartial(ctx, item, completed)
+}
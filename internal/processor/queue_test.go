@@ -0,0 +1,116 @@
+package processor
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// mockSlskdClientQueued reports a finished file and one waiting in the
+// uploader's queue at position
+type mockSlskdClientQueued struct {
+	mockSlskdClient
+	position  int
+	lookups   int
+	cancelled []string
+	removed   []string
+}
+
+func (m *mockSlskdClientQueued) GetDownloads(ctx context.Context) (slskd.DownloadsResponse, error) {
+	return slskd.DownloadsResponse{{Username: "user", Directories: []slskd.DirectoryDownloads{{
+		Directory: `Music\Album`,
+		Files: []slskd.DownloadFile{
+			{ID: "1", Filename: `Music\Album\01.flac`, State: "Completed, Succeeded"},
+			{ID: "2", Filename: `Music\Album\02.flac`, State: "Queued, Remotely"},
+		},
+	}}}}, nil
+}
+
+func (m *mockSlskdClientQueued) GetQueuePosition(ctx context.Context, username, downloadID string) (int, error) {
+	m.lookups++
+	return m.position, nil
+}
+
+func (m *mockSlskdClientQueued) CancelDownload(ctx context.Context, username, downloadID string) error {
+	m.cancelled = append(m.cancelled, downloadID)
+	return nil
+}
+
+func (m *mockSlskdClientQueued) RemoveDownload(ctx context.Context, username, downloadID string, deleteFile bool) error {
+	m.removed = append(m.removed, downloadID)
+	return nil
+}
+
+func TestMonitorDownloads_QueuedTooFarBack(t *testing.T) {
+	slskdClient := &mockSlskdClientQueued{position: 400}
+	p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+	p.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	p.cfg.Slskd.StalledTimeout = 60
+	p.cfg.Search.WishlistOnDenylist = false
+	p.cfg.Download.MaxQueuePosition = 100
+	writeDownloadedFile(t, p, "Album", "01.flac", 0)
+
+	downloadList := []DownloadedItem{{AlbumID: 3, FolderName: "Album", Sources: []DownloadSource{{Username: "user", Directory: "Music/Album"}}}}
+	succeeded, err := p.monitorDownloads(context.Background(), downloadList, nil, nil)
+	if err != nil {
+		t.Fatalf("monitorDownloads() error: %v", err)
+	}
+
+	if len(succeeded) != 0 {
+		t.Errorf("expected the album to be given up, got %d succeeded", len(succeeded))
+	}
+	if !slices.Equal(slskdClient.cancelled, []string{"2"}) || !slices.Equal(slskdClient.removed, []string{"1"}) {
+		t.Errorf("expected the queued transfer cancelled and the finished one removed, got cancelled %v, removed %v", slskdClient.cancelled, slskdClient.removed)
+	}
+	if entry := p.denylist.GetEntry(3); entry == nil || entry.Failures != 1 {
+		t.Errorf("expected a recorded search failure, got %+v", entry)
+	}
+}
+
+func TestQueuedTooFar(t *testing.T) {
+	queued := func(place *int) sourceFile {
+		return sourceFile{
+			DownloadFile: slskd.DownloadFile{ID: "2", Filename: `Music\Album\02.flac`, State: "Queued, Remotely", PlaceInQueue: place},
+			username:     "user",
+		}
+	}
+
+	tests := []struct {
+		name        string
+		limit       int
+		position    int
+		file        sourceFile
+		want        bool
+		wantLookups int
+	}{
+		{name: "beyond the limit", limit: 100, position: 101, file: queued(nil), want: true, wantLookups: 1},
+		{name: "within the limit", limit: 100, position: 100, file: queued(nil), want: false, wantLookups: 1},
+		{name: "no limit never asks the uploader", limit: 0, position: 1000, file: queued(intPtr(1000)), want: false, wantLookups: 0},
+		{name: "locally queued files aren't checked", limit: 100, position: 1000, file: sourceFile{DownloadFile: slskd.DownloadFile{State: "Queued, Locally"}}, want: false, wantLookups: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slskdClient := &mockSlskdClientQueued{position: tt.position}
+			p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+			p.cfg.Download.MaxQueuePosition = tt.limit
+			queue := newQueueTracker(time.Minute)
+
+			// The uploader is asked once per interval
+			now := time.Now()
+			for _, at := range []time.Time{now, now.Add(30 * time.Second)} {
+				if got := p.queuedTooFar(context.Background(), queue, DownloadedItem{}, tt.file, at); got != tt.want {
+					t.Errorf("queuedTooFar() = %v, want %v", got, tt.want)
+				}
+			}
+			if slskdClient.lookups != tt.wantLookups {
+				t.Errorf("expected %d position lookups, got %d", tt.wantLookups, slskdClient.lookups)
+			}
+		})
+	}
+}
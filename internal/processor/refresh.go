@@ -0,0 +1,76 @@
+package processor
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+)
+
+// refreshArtistCommand is the Lidarr command that rescans an artist's files
+// and updates their statistics
+const refreshArtistCommand = "RefreshArtist"
+
+// artistRefreshTimeout bounds how long imports wait for artist refreshes
+const artistRefreshTimeout = 2 * time.Minute
+
+// importedArtists returns the Lidarr artists of the imported items, once each
+func importedArtists(downloadList []DownloadedItem, imported map[int]bool) []int {
+	var artists []int
+	for _, item := range downloadList {
+		if imported[item.AlbumID] && item.ArtistID > 0 && !slices.Contains(artists, item.ArtistID) {
+			artists = append(artists, item.ArtistID)
+		}
+	}
+	return artists
+}
+
+// refreshArtists asks Lidarr to refresh each artist after their albums were
+// imported, so the next run's wanted list and Lidarr's statistics reflect the
+// imports, and waits up to artistRefreshTimeout for the refreshes to finish
+func (p *Processor) refreshArtists(ctx context.Context, artistIDs []int) {
+	pending := make(map[int]int) // command ID to artist ID
+	for _, artistID := range artistIDs {
+		resp, err := p.lidarr.PostCommand(ctx, lidarr.Command{Name: refreshArtistCommand, ArtistID: artistID})
+		if err != nil {
+			p.logger.Warn("failed to refresh artist", "artistID", artistID, "error", err)
+			continue
+		}
+		pending[resp.ID] = artistID
+	}
+	if len(pending) == 0 {
+		return
+	}
+	p.logger.Info("refreshing imported artists", "artists", len(pending))
+
+	ctx, cancel := context.WithTimeout(ctx, artistRefreshTimeout)
+	defer cancel()
+	pollInterval := time.Duration(p.cfg.Timing.ImportPollSeconds) * time.Second
+	for {
+		for id, artistID := range pending {
+			cmd, err := p.lidarr.GetCommand(ctx, id)
+			if err != nil {
+				p.logger.Debug("failed to fetch artist refresh status", "artistID", artistID, "commandID", id, "error", err)
+				continue
+			}
+			switch cmd.Status {
+			case "completed":
+				delete(pending, id)
+			case "failed":
+				p.logger.Warn("artist refresh failed", "artistID", artistID, "commandID", id, "message", cmd.Message)
+				delete(pending, id)
+			}
+		}
+		if len(pending) == 0 {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			p.logger.Warn("stopped waiting for artist refreshes", "pending", len(pending))
+			return
+		case <-time.After(pollInterval):
+		}
+	}
+}
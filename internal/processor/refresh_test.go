@@ -0,0 +1,93 @@
+package processor
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+)
+
+// mockLidarrClientRefresh records posted commands, failing the import scans
+// of the given album folders
+type mockLidarrClientRefresh struct {
+	mockLidarrClient
+	posted      []lidarr.Command
+	failedPaths []string
+}
+
+func (m *mockLidarrClientRefresh) PostCommand(ctx context.Context, cmd lidarr.Command) (*lidarr.CommandResponse, error) {
+	m.posted = append(m.posted, cmd)
+	return &lidarr.CommandResponse{ID: len(m.posted)}, nil
+}
+
+func (m *mockLidarrClientRefresh) GetCommand(ctx context.Context, id int) (*lidarr.CommandResponse, error) {
+	if slices.Contains(m.failedPaths, m.posted[id-1].Path) {
+		return &lidarr.CommandResponse{ID: id, Status: "failed", Message: "import failed"}, nil
+	}
+	return &lidarr.CommandResponse{ID: id, Status: "completed"}, nil
+}
+
+// refreshedArtists returns the artists of the RefreshArtist commands posted
+func (m *mockLidarrClientRefresh) refreshedArtists() []int {
+	var artists []int
+	for _, cmd := range m.posted {
+		if cmd.Name == refreshArtistCommand {
+			artists = append(artists, cmd.ArtistID)
+		}
+	}
+	return artists
+}
+
+func TestTriggerImport_RefreshArtistAfterImport(t *testing.T) {
+	item := func(artistID, albumID int, artist, album string) DownloadedItem {
+		return DownloadedItem{
+			ArtistID:   artistID,
+			ArtistName: artist,
+			AlbumName:  album,
+			AlbumID:    albumID,
+			Sources:    []DownloadSource{{Username: "user", Directory: artist + "/" + album}},
+		}
+	}
+	downloads := []DownloadedItem{
+		item(5, 1, "Queen", "A Night at the Opera"),
+		item(5, 2, "Queen", "News of the World"),
+		item(6, 3, "ABBA", "Arrival"),
+		item(0, 4, "Unknown", "Restored"), // Saved before artist IDs were recorded
+	}
+
+	tests := []struct {
+		name        string
+		enabled     bool
+		failedPaths []string
+		want        []int
+	}{
+		{"disabled", false, nil, nil},
+		{"each imported artist once", true, nil, []int{5, 6}},
+		{"failed import", true, []string{"/downloads/ABBA/Arrival"}, []int{5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lidarrClient := &mockLidarrClientRefresh{failedPaths: tt.failedPaths}
+			p := newTestProcessor(t, lidarrClient, &mockSlskdClient{})
+			p.cfg.Lidarr.DownloadDir = "/downloads"
+			p.cfg.Lidarr.RefreshArtistAfterImport = tt.enabled
+
+			if err := p.triggerImport(context.Background(), downloads); err != nil {
+				t.Fatalf("triggerImport() error: %v", err)
+			}
+
+			if got := lidarrClient.refreshedArtists(); !slices.Equal(got, tt.want) {
+				t.Errorf("expected artists %v refreshed, got %v", tt.want, got)
+			}
+			// Refreshes follow every import scan
+			for i, cmd := range lidarrClient.posted {
+				if cmd.Name == refreshArtistCommand && i < len(downloads) {
+					t.Errorf("refresh posted before the imports: %+v", lidarrClient.posted)
+					break
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package processor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/config"
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+)
+
+func TestChooseRelease_AppliesReleaseSettings(t *testing.T) {
+	disabled := false
+
+	releases := []lidarr.Release{
+		{ID: 1, Status: "Official", TrackCount: 12, MediumCount: 1, Country: []string{"Japan"}, Format: "CD"},
+		{ID: 2, Status: "Official", TrackCount: 10, MediumCount: 1, Country: []string{"United States"}, Format: "Vinyl"},
+		{ID: 3, Status: "Official", TrackCount: 20, MediumCount: 2, Country: []string{"Europe"}, Format: "2xCD"},
+		{ID: 4, Status: "Official", TrackCount: 10, MediumCount: 1, Country: []string{"Europe"}, Format: "Digital Media"},
+	}
+
+	tests := []struct {
+		name     string
+		settings config.ReleaseSettings
+		wantID   int
+	}{
+		{
+			name:     "no constraints uses most common track count",
+			settings: config.ReleaseSettings{},
+			wantID:   2,
+		},
+		{
+			name:     "country filter",
+			settings: config.ReleaseSettings{AcceptedCountries: []string{"japan"}},
+			wantID:   1,
+		},
+		{
+			name:     "skip region check ignores countries",
+			settings: config.ReleaseSettings{AcceptedCountries: []string{"Japan"}, SkipRegionCheck: true},
+			wantID:   2,
+		},
+		{
+			name:     "format filter strips medium count",
+			settings: config.ReleaseSettings{AcceptedCountries: []string{"Europe"}, AcceptedFormats: []string{"CD"}},
+			wantID:   3,
+		},
+		{
+			name:     "multi-disc disallowed",
+			settings: config.ReleaseSettings{AcceptedCountries: []string{"Europe"}, AllowMultiDisc: &disabled},
+			wantID:   4,
+		},
+		{
+			name:     "most common track count disabled takes first official",
+			settings: config.ReleaseSettings{UseMostCommonTrackNum: &disabled},
+			wantID:   1,
+		},
+		{
+			name:     "constraint eliminating everything falls back",
+			settings: config.ReleaseSettings{AcceptedFormats: []string{"Cassette"}},
+			wantID:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestProcessor(t, &mockLidarrClient{}, &mockSlskdClient{})
+			p.cfg.Release = tt.settings
+
+			album := lidarr.Album{ID: 1, Title: "Album", Releases: releases}
+			release, err := p.chooseRelease(context.Background(), album)
+			if err != nil {
+				t.Fatalf("chooseRelease() error: %v", err)
+			}
+			if release.ID != tt.wantID {
+				t.Errorf("chooseRelease() = release %d, want %d", release.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestReleaseFormat(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"CD", "CD"},
+		{"2xCD", "CD"},
+		{"12xVinyl", "Vinyl"},
+		{"Digital Media", "Digital Media"},
+		{"xCD", "xCD"},
+	}
+
+	for _, tt := range tests {
+		if got := releaseFormat(tt.format); got != tt.want {
+			t.Errorf("releaseFormat(%q) = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package processor
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// reportPrefix names every report file so pruning never touches other files
+const reportPrefix = "seekarr-report-"
+
+// runReport is the JSON layout of a run report
+type runReport struct {
+	StartedAt  time.Time       `json:"started_at"`
+	FinishedAt time.Time       `json:"finished_at"`
+	Albums     []AlbumDecision `json:"albums"`
+}
+
+// writeReport writes the skipped and failed albums of a run, plus what a dry
+// run would have downloaded, to the report directory in each configured
+// format, then prunes expired reports
+func (p *Processor) writeReport(summary *RunSummary) error {
+	dir := p.cfg.Report.Dir
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("create report dir: %w", err)
+	}
+
+	var albums []AlbumDecision
+	for _, d := range summary.Decisions {
+		if d.Outcome == OutcomeSkipped || d.Outcome == OutcomeFailed || d.Outcome == OutcomeWouldDownload {
+			albums = append(albums, d)
+		}
+	}
+
+	base := filepath.Join(dir, reportPrefix+summary.StartedAt.Format("20060102-150405"))
+	for _, format := range p.cfg.Report.Formats {
+		var err error
+		switch format {
+		case "csv":
+			err = writeCSVReport(base+".csv", albums)
+		case "json":
+			err = writeJSONReport(base+".json", runReport{
+				StartedAt:  summary.StartedAt,
+				FinishedAt: summary.FinishedAt,
+				Albums:     albums,
+			})
+		default:
+			err = fmt.Errorf("unknown report format: %s", format)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	p.logger.Info("wrote run report", "path", base, "albums", len(albums))
+
+	retention := time.Duration(p.cfg.Report.RetentionDays) * 24 * time.Hour
+	if err := pruneReports(dir, time.Now().Add(-retention)); err != nil {
+		p.logger.Warn("failed to prune old reports", "error", err)
+	}
+
+	return nil
+}
+
+// writeCSVReport writes one row per album
+func writeCSVReport(path string, albums []AlbumDecision) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create csv report: %w", err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.Write([]string{"outcome", "reason", "artist", "album", "album_id", "failures", "query"})
+	for _, d := range albums {
+		w.Write([]string{
+			d.Outcome,
+			d.Reason,
+			d.Artist,
+			d.Album,
+			strconv.Itoa(d.AlbumID),
+			strconv.Itoa(d.Failures),
+			d.Query,
+		})
+	}
+	w.Flush()
+
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("write csv report: %w", err)
+	}
+	return nil
+}
+
+// writeJSONReport writes the report as indented JSON
+func writeJSONReport(path string, report runReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal json report: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("write json report: %w", err)
+	}
+	return nil
+}
+
+// pruneReports deletes report files last modified before cutoff
+func pruneReports(dir string, cutoff time.Time) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("read report dir: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), reportPrefix) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		if info.ModTime().Before(cutoff) {
+			if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+				return fmt.Errorf("remove %s: %w", entry.Name(), err)
+			}
+		}
+	}
+
+	return nil
+}
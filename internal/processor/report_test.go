@@ -0,0 +1,119 @@
+package processor
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+)
+
+func TestWriteReport(t *testing.T) {
+	p := newTestProcessor(t, &mockLidarrClient{}, &mockSlskdClient{})
+	reportDir := filepath.Join(t.TempDir(), "reports")
+	p.cfg.Report.Dir = reportDir
+	p.cfg.Report.Formats = []string{"csv", "json"}
+	p.cfg.Report.RetentionDays = 30
+
+	summary := &RunSummary{
+		StartedAt:  time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		FinishedAt: time.Date(2026, 1, 2, 3, 14, 5, 0, time.UTC),
+		Decisions: []AlbumDecision{
+			{AlbumID: 1, Artist: "A", Album: "Skipped", Outcome: OutcomeSkipped, Reason: ReasonBlacklist},
+			{AlbumID: 2, Artist: "B", Album: "Failed", Outcome: OutcomeFailed, Reason: ReasonNoResults, Failures: 2, Query: "B Failed"},
+			{AlbumID: 3, Artist: "C", Album: "Imported", Outcome: OutcomeImported, Query: "C Imported"},
+		},
+	}
+
+	if err := p.writeReport(summary); err != nil {
+		t.Fatalf("writeReport() error: %v", err)
+	}
+
+	// CSV: header plus the skipped and failed albums only
+	f, err := os.Open(filepath.Join(reportDir, "seekarr-report-20260102-030405.csv"))
+	if err != nil {
+		t.Fatalf("open csv report: %v", err)
+	}
+	defer f.Close()
+
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("read csv report: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 csv rows, got %d", len(rows))
+	}
+	want := []string{"failed", "no_results", "B", "Failed", "2", "2", "B Failed"}
+	for i, v := range want {
+		if rows[2][i] != v {
+			t.Errorf("csv column %d = %q, want %q", i, rows[2][i], v)
+		}
+	}
+
+	// JSON
+	data, err := os.ReadFile(filepath.Join(reportDir, "seekarr-report-20260102-030405.json"))
+	if err != nil {
+		t.Fatalf("read json report: %v", err)
+	}
+	var report runReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("decode json report: %v", err)
+	}
+	if len(report.Albums) != 2 {
+		t.Errorf("expected 2 albums in json report, got %d", len(report.Albums))
+	}
+}
+
+func TestPruneReports(t *testing.T) {
+	dir := t.TempDir()
+
+	old := filepath.Join(dir, "seekarr-report-20200101-000000.csv")
+	recent := filepath.Join(dir, "seekarr-report-20260101-000000.csv")
+	unrelated := filepath.Join(dir, "notes.txt")
+	for _, path := range []string{old, recent, unrelated} {
+		if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	longAgo := time.Now().Add(-90 * 24 * time.Hour)
+	os.Chtimes(old, longAgo, longAgo)
+	os.Chtimes(unrelated, longAgo, longAgo)
+
+	if err := pruneReports(dir, time.Now().Add(-30*24*time.Hour)); err != nil {
+		t.Fatalf("pruneReports() error: %v", err)
+	}
+
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Error("expired report should be removed")
+	}
+	if _, err := os.Stat(recent); err != nil {
+		t.Error("recent report should be kept")
+	}
+	if _, err := os.Stat(unrelated); err != nil {
+		t.Error("files that are not reports should be kept")
+	}
+}
+
+func TestQueueAlbum_RecordsDecision(t *testing.T) {
+	p := newTestProcessor(t, &mockLidarrClient{}, &mockSlskdClient{})
+	p.cfg.Search.TitleBlacklist = []string{"live"}
+	p.current = &RunSummary{}
+
+	album := lidarr.Album{ID: 7, Title: "Live at Somewhere", Artist: lidarr.Artist{ArtistName: "Band"}}
+	_, outcome := p.queueAlbum(context.Background(), album)
+
+	if outcome != OutcomeSkipped {
+		t.Fatalf("expected outcome %q, got %q", OutcomeSkipped, outcome)
+	}
+	if len(p.current.Decisions) != 1 {
+		t.Fatalf("expected 1 decision, got %d", len(p.current.Decisions))
+	}
+	if d := p.current.Decisions[0]; d.Reason != ReasonBlacklist || d.AlbumID != 7 {
+		t.Errorf("unexpected decision: %+v", d)
+	}
+}
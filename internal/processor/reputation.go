@@ -0,0 +1,88 @@
+package processor
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	reputationWeight = 10.0 // Score range a user's download history adds or removes
+	repeatedFailures = 2    // Failed downloads in a row that rank a user's candidates last
+)
+
+// userReputation scores a user's download history between -reputationWeight
+// and reputationWeight, and reports whether their recent downloads keep failing
+func (p *Processor) userReputation(username string) (float64, bool) {
+	entry := p.reputation.Get(username)
+	if entry == nil {
+		return 0, false
+	}
+
+	score := 0.0
+	if total := entry.Successes + entry.Failures; total > 0 {
+		score = reputationWeight * float64(entry.Successes-entry.Failures) / float64(total)
+	}
+	return score, entry.ConsecutiveFailures >= repeatedFailures
+}
+
+// recordTransfers updates the reputation of every user that supplied files to a
+// finished download. Users whose files failed for good are recorded as failures,
+// and after max_user_failures in a row they are ignored for the rest of the run.
+// After auto_ignore_after_failures errored files they are ignored for auto_ignore_days
+func (p *Processor) recordTransfers(item DownloadedItem, completed, errored []sourceFile) {
+	var failed []string
+	erroredFiles := make(map[string]int)
+	for _, file := range errored {
+		if !slices.Contains(failed, file.username) {
+			failed = append(failed, file.username)
+		}
+		erroredFiles[file.username]++
+	}
+
+	var succeeded []string
+	for _, file := range completed {
+		if !slices.Contains(failed, file.username) && !slices.Contains(succeeded, file.username) {
+			succeeded = append(succeeded, file.username)
+		}
+	}
+
+	for _, username := range succeeded {
+		p.reputation.RecordSuccess(username, item.Quality)
+	}
+
+	limit := p.cfg.Search.MaxUserFailures
+	for _, username := range failed {
+		failures := p.reputation.RecordFailure(username)
+		if limit > 0 && failures >= limit && !p.userRefused(username) {
+			p.logger.Warn("ignoring user for the rest of the run after repeated failed downloads",
+				"username", username,
+				"album", item.AlbumName,
+				"consecutiveFailures", failures)
+			p.markRefused(username)
+		}
+
+		transfers := p.reputation.RecordErroredTransfers(username, erroredFiles[username])
+		if autoLimit := p.cfg.Search.AutoIgnoreAfterFailures; autoLimit > 0 && transfers >= autoLimit && !p.reputation.IsIgnored(username, time.Now()) {
+			until := time.Now().AddDate(0, 0, p.cfg.Search.AutoIgnoreDays)
+			p.reputation.Ignore(username, until)
+			p.logger.Info("auto-ignoring user after repeated failed transfers",
+				"username", username,
+				"erroredTransfers", transfers,
+				"until", until.Format(time.DateOnly))
+		}
+	}
+}
+
+// expireAutoIgnores gives users whose automatic ignore has run out another chance
+func (p *Processor) expireAutoIgnores() {
+	expired := p.reputation.ExpireIgnores(time.Now())
+	for _, username := range expired {
+		p.logger.Info("auto-ignore expired, searching user's shares again", "username", username)
+	}
+	if len(expired) == 0 {
+		return
+	}
+	if err := p.reputation.Save(); err != nil {
+		p.logger.Warn("failed to save user reputation", "error", err)
+	}
+}
@@ -0,0 +1,132 @@
+package processor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yuritomanek/seekarr/internal/filter"
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+func TestSearchForAlbum_UsesUserReputation(t *testing.T) {
+	tests := []struct {
+		name     string
+		history  func(p *Processor)
+		wantUser string
+	}{
+		{
+			name:     "no history keeps the better offer",
+			history:  func(p *Processor) {},
+			wantUser: "lossless",
+		},
+		{
+			name: "reliable user breaks a tie",
+			history: func(p *Processor) {
+				p.reputation.RecordSuccess("reliable", "flac")
+				p.reputation.RecordFailure("lossless")
+			},
+			wantUser: "reliable",
+		},
+		{
+			name: "repeated failures rank last",
+			history: func(p *Processor) {
+				p.reputation.RecordFailure("lossless")
+				p.reputation.RecordFailure("lossless")
+				p.reputation.RecordFailure("reliable")
+				p.reputation.RecordFailure("reliable")
+			},
+			wantUser: "lossy",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			album, tracks := candidateAlbum()
+			slskdClient := &mockSlskdClientWithResults{results: map[string][]slskd.SearchResult{
+				"Album": {
+					albumResult("lossy", "mp3", 128, 3_000_000),
+					albumResult("lossless", "flac", 900, 30_000_000),
+					albumResult("reliable", "flac", 900, 30_000_000),
+				},
+			}}
+			p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+			p.filter = filter.NewFilter([]string{"flac", "mp3 320", "mp3"})
+			tt.history(p)
+
+			item, err := p.searchForAlbum(context.Background(), "Album", tracks, album, &lidarr.Release{MediumCount: 1})
+			if err != nil {
+				t.Fatalf("searchForAlbum() error: %v", err)
+			}
+			if item.Sources[0].Username != tt.wantUser {
+				t.Errorf("expected %q to be chosen, got %q", tt.wantUser, item.Sources[0].Username)
+			}
+		})
+	}
+}
+
+func TestRecordTransfers(t *testing.T) {
+	p := newTestProcessor(t, &mockLidarrClient{}, &mockSlskdClient{})
+	p.cfg.Search.MaxUserFailures = 2
+	item := DownloadedItem{AlbumName: "Album", Quality: "flac"}
+
+	file := func(username string) sourceFile {
+		return sourceFile{username: username, directory: "Music/Album"}
+	}
+
+	// A user with any failed file counts as failed, even if other files finished
+	p.recordTransfers(item, []sourceFile{file("good"), file("flaky")}, []sourceFile{file("flaky")})
+	if entry := p.reputation.Get("good"); entry == nil || entry.Successes != 1 || entry.LastQuality != "flac" {
+		t.Errorf("unexpected reputation for good: %+v", entry)
+	}
+	if entry := p.reputation.Get("flaky"); entry == nil || entry.Successes != 0 || entry.Failures != 1 {
+		t.Errorf("unexpected reputation for flaky: %+v", entry)
+	}
+	if p.userRefused("flaky") {
+		t.Error("user should not be ignored before reaching max_user_failures")
+	}
+
+	p.recordTransfers(item, nil, []sourceFile{file("flaky")})
+	if !p.userRefused("flaky") {
+		t.Error("expected user to be ignored after max_user_failures failed downloads")
+	}
+	if p.userRefused("good") {
+		t.Error("reliable user should not be ignored")
+	}
+}
+
+func TestRecordTransfers_AutoIgnore(t *testing.T) {
+	p := newTestProcessor(t, &mockLidarrClient{}, &mockSlskdClient{})
+	p.cfg.Search.AutoIgnoreAfterFailures = 3
+	p.cfg.Search.AutoIgnoreDays = 30
+	item := DownloadedItem{AlbumName: "Album"}
+
+	file := func(username, name string) sourceFile {
+		return sourceFile{DownloadFile: slskd.DownloadFile{Filename: name}, username: username, directory: "Music/Album"}
+	}
+
+	p.recordTransfers(item, nil, []sourceFile{file("flaky", "01.flac"), file("flaky", "02.flac")})
+	if p.isIgnoredUser("flaky") {
+		t.Fatal("user should not be ignored before auto_ignore_after_failures errored files")
+	}
+
+	p.recordTransfers(item, nil, []sourceFile{file("flaky", "03.flac")})
+	if !p.isIgnoredUser("flaky") {
+		t.Fatal("expected user to be ignored after auto_ignore_after_failures errored files")
+	}
+	entry := p.reputation.Get("flaky")
+	if days := time.Until(entry.IgnoredUntil).Hours() / 24; days < 29 || days > 30 {
+		t.Errorf("expected an ignore of 30 days, got %.1f", days)
+	}
+
+	// Once the ignore runs out the user gets another chance
+	p.reputation.Ignore("flaky", time.Now().Add(-time.Minute))
+	p.expireAutoIgnores()
+	if p.isIgnoredUser("flaky") {
+		t.Error("expected the ignore to expire")
+	}
+	if entry := p.reputation.Get("flaky"); entry.ErroredTransfers != 0 {
+		t.Errorf("expected errored transfers reset, got %d", entry.ErroredTransfers)
+	}
+}
@@ -0,0 +1,48 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+)
+
+// requestedKey is the context key marking a run over explicitly requested albums
+type requestedKey struct{}
+
+// withRequested marks ctx as belonging to a RunAlbums run
+func withRequested(ctx context.Context) context.Context {
+	return context.WithValue(ctx, requestedKey{}, true)
+}
+
+// isRequested reports whether ctx belongs to a RunAlbums run
+func isRequested(ctx context.Context) bool {
+	requested, _ := ctx.Value(requestedKey{}).(bool)
+	return requested
+}
+
+// fetchRequestedAlbums retrieves albums by Lidarr ID, skipping IDs that can't be
+// fetched. It fails only if none of the albums could be fetched
+func (p *Processor) fetchRequestedAlbums(ctx context.Context, ids []int) ([]lidarr.Album, error) {
+	var albums []lidarr.Album
+	var errs []error
+	for _, id := range ids {
+		if slices.ContainsFunc(albums, func(album lidarr.Album) bool { return album.ID == id }) {
+			continue
+		}
+		album, err := p.lidarrAlbum(ctx, id)
+		if err != nil {
+			p.logger.Error("failed to fetch requested album", "albumID", id, "error", err)
+			errs = append(errs, fmt.Errorf("album %d: %w", id, err))
+			continue
+		}
+		albums = append(albums, *album)
+	}
+
+	if len(albums) == 0 && len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return albums, nil
+}
@@ -0,0 +1,77 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// mockLidarrClientRequested serves albums by ID on top of a wanted list
+type mockLidarrClientRequested struct {
+	mockLidarrClientDryRun
+	albums map[int]lidarr.Album
+}
+
+func (m *mockLidarrClientRequested) GetAlbum(ctx context.Context, id int) (*lidarr.Album, error) {
+	album, ok := m.albums[id]
+	if !ok {
+		return nil, fmt.Errorf("album %d not found", id)
+	}
+	return &album, nil
+}
+
+func TestRunAlbums(t *testing.T) {
+	requested, tracks := candidateAlbum()
+	wanted := lidarr.Album{ID: 10, Title: "Wanted", Artist: lidarr.Artist{ArtistName: "Artist"}}
+	for _, a := range []*lidarr.Album{&requested, &wanted} {
+		a.Releases = []lidarr.Release{{Status: "Official", TrackCount: 2, MediumCount: 1}}
+	}
+
+	lidarrClient := &mockLidarrClientRequested{
+		mockLidarrClientDryRun: mockLidarrClientDryRun{
+			mockLidarrClientWithWanted: mockLidarrClientWithWanted{wanted: []lidarr.Album{wanted}},
+			tracks:                     tracks,
+		},
+		albums: map[int]lidarr.Album{requested.ID: requested},
+	}
+	slskdClient := &mockSlskdClientWithResults{results: map[string][]slskd.SearchResult{
+		"Album": {albumResult("user", "flac", 900, 30_000_000)},
+	}}
+	p := newTestProcessor(t, lidarrClient, slskdClient)
+	p.cfg.DryRun = true
+	p.cfg.Search.MaxSearchFailures = 1
+	p.denylist.RecordAttempt(requested.ID, false)
+
+	// The unknown ID is logged and skipped
+	if err := p.RunAlbums(context.Background(), []int{requested.ID, 99, requested.ID}); err != nil {
+		t.Fatalf("RunAlbums() error: %v", err)
+	}
+
+	summary := p.Status().LastRun
+	if summary == nil {
+		t.Fatal("expected a run summary")
+	}
+	if summary.Wanted != 1 || summary.WouldDownload != 1 {
+		t.Errorf("expected only the denylisted requested album to be searched, got %+v", summary)
+	}
+	if len(summary.Decisions) != 1 || summary.Decisions[0].AlbumID != requested.ID {
+		t.Errorf("unexpected decisions: %+v", summary.Decisions)
+	}
+
+	// Without a requested album there is nothing to run
+	if err := p.RunAlbums(context.Background(), []int{99}); err == nil {
+		t.Error("expected an error when no requested album can be fetched")
+	}
+
+	// Regular runs still honour the denylist
+	lidarrClient.wanted = []lidarr.Album{requested}
+	if err := p.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if summary := p.Status().LastRun; summary.WouldDownload != 0 || summary.Decisions[0].Reason != ReasonDenylist {
+		t.Errorf("expected the denylisted album skipped, got %+v", summary.Decisions)
+	}
+}
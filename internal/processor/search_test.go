@@ -0,0 +1,217 @@
+package processor
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// flakySlskdServer serves one search whose state and results endpoints fail
+// the given number of times before answering
+type flakySlskdServer struct {
+	mu            sync.Mutex
+	stateFails    int
+	resultsFails  int
+	resultsStatus int // Status of a failed results request (default: 502)
+	searchCalls   int
+	stateCalls    int
+	resultsCalls  int
+	searchResults []slskd.SearchResult
+}
+
+func (s *flakySlskdServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/searches"):
+		s.searchCalls++
+		json.NewEncoder(w).Encode(slskd.SearchResponse{ID: "search-1", State: "InProgress"})
+	case strings.HasSuffix(r.URL.Path, "/searches/search-1/responses"):
+		s.resultsCalls++
+		if s.resultsCalls <= s.resultsFails {
+			http.Error(w, "unavailable", cmp.Or(s.resultsStatus, http.StatusBadGateway))
+			return
+		}
+		json.NewEncoder(w).Encode(s.searchResults)
+	case strings.HasSuffix(r.URL.Path, "/searches/search-1"):
+		s.stateCalls++
+		if s.stateCalls <= s.stateFails {
+			http.Error(w, "unavailable", http.StatusBadGateway)
+			return
+		}
+		json.NewEncoder(w).Encode(slskd.SearchResponse{ID: "search-1", State: "Completed"})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestRunSearch_ToleratesTransientErrors(t *testing.T) {
+	tests := []struct {
+		name             string
+		stateFails       int
+		resultsFails     int
+		resultsStatus    int
+		wantSearchCalls  int
+		wantStateCalls   int
+		wantResultsCalls int
+		wantErr          bool
+	}{
+		{name: "no errors", wantStateCalls: 1, wantResultsCalls: 1},
+		{name: "state blip", stateFails: 1, wantStateCalls: 2, wantResultsCalls: 1},
+		{name: "state keeps failing", stateFails: 100, wantStateCalls: maxSearchStateFailures, wantResultsCalls: 1},
+		{name: "results blip", resultsFails: 1, wantStateCalls: 1, wantResultsCalls: 2},
+		{name: "results keep failing", resultsFails: 2, wantStateCalls: 1, wantResultsCalls: 2, wantErr: true},
+		{name: "search expired", resultsFails: 1, resultsStatus: http.StatusNotFound, wantSearchCalls: 2, wantStateCalls: 2, wantResultsCalls: 2},
+		{name: "search keeps expiring", resultsFails: 2, resultsStatus: http.StatusNotFound, wantSearchCalls: 2, wantStateCalls: 2, wantResultsCalls: 2, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &flakySlskdServer{
+				stateFails:    tt.stateFails,
+				resultsFails:  tt.resultsFails,
+				resultsStatus: tt.resultsStatus,
+				searchResults: []slskd.SearchResult{albumResult("user", "flac", 900, 30_000_000)},
+			}
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			p := newTestProcessor(t, &mockLidarrClient{}, slskd.NewClient(server.URL, "key", ""))
+			p.cfg.Timing.SearchWaitSeconds = 5
+
+			results, err := p.runSearch(context.Background(), lidarr.Album{Title: "Album"}, "Album")
+			if tt.wantErr {
+				if !isUnavailable(err) {
+					t.Errorf("expected slskd to be reported unavailable, got %v", err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("runSearch() error: %v", err)
+				}
+				if len(results) != 1 {
+					t.Errorf("expected 1 result, got %d", len(results))
+				}
+			}
+			if want := max(tt.wantSearchCalls, 1); handler.searchCalls != want {
+				t.Errorf("expected %d searches, got %d", want, handler.searchCalls)
+			}
+			if handler.stateCalls != tt.wantStateCalls {
+				t.Errorf("expected %d search state calls, got %d", tt.wantStateCalls, handler.stateCalls)
+			}
+			if handler.resultsCalls != tt.wantResultsCalls {
+				t.Errorf("expected %d search results calls, got %d", tt.wantResultsCalls, handler.resultsCalls)
+			}
+		})
+	}
+}
+
+func TestSearchResults_MaxResultsToConsider(t *testing.T) {
+	results := []slskd.SearchResult{
+		albumResult("user1", "flac", 900, 30_000_000),
+		albumResult("user2", "flac", 900, 30_000_000),
+		albumResult("user3", "flac", 900, 30_000_000),
+	}
+
+	tests := []struct {
+		name      string
+		limit     int
+		wantUsers []string
+	}{
+		{name: "no limit", limit: 0, wantUsers: []string{"user1", "user2", "user3"}},
+		{name: "limited", limit: 2, wantUsers: []string{"user1", "user2"}},
+		{name: "limit above results", limit: 10, wantUsers: []string{"user1", "user2", "user3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slskdClient := &mockSlskdClientWithResults{results: map[string][]slskd.SearchResult{"Album": results}}
+			p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+			p.cfg.Search.MaxResultsToConsider = tt.limit
+
+			got, err := p.searchResults(context.Background(), "Album")
+			if err != nil {
+				t.Fatalf("searchResults() error: %v", err)
+			}
+			var users []string
+			for _, result := range got {
+				users = append(users, result.Username)
+			}
+			if !slices.Equal(users, tt.wantUsers) {
+				t.Errorf("expected results from %v, got %v", tt.wantUsers, users)
+			}
+		})
+	}
+}
+
+// runningSlskdServer serves a search that never completes, cancelling the
+// caller's context once its state has been polled
+type runningSlskdServer struct {
+	mu      sync.Mutex
+	polled  func()
+	methods []string // Methods of the requests made to the search
+}
+
+func (s *runningSlskdServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/searches"):
+		json.NewEncoder(w).Encode(slskd.SearchResponse{ID: "search-1", State: "InProgress"})
+	case strings.HasSuffix(r.URL.Path, "/searches/search-1"):
+		s.methods = append(s.methods, r.Method)
+		if r.Method == http.MethodGet {
+			json.NewEncoder(w).Encode(slskd.SearchResponse{ID: "search-1", State: "InProgress"})
+			s.polled()
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestExecuteSearch_CleansUpWhenCancelled(t *testing.T) {
+	tests := []struct {
+		name           string
+		deleteSearches bool
+		wantMethods    []string
+	}{
+		{name: "stops the search", wantMethods: []string{http.MethodGet, http.MethodPut}},
+		{name: "stops and deletes the search", deleteSearches: true, wantMethods: []string{http.MethodGet, http.MethodPut, http.MethodDelete}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			handler := &runningSlskdServer{polled: cancel}
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			p := newTestProcessor(t, &mockLidarrClient{}, slskd.NewClient(server.URL, "key", ""))
+			p.cfg.Timing.SearchWaitSeconds = 60
+			p.cfg.Slskd.DeleteSearches = tt.deleteSearches
+
+			if _, err := p.executeSearch(ctx, lidarr.Album{Title: "Album"}, "Album"); err != context.Canceled {
+				t.Fatalf("expected context.Canceled, got %v", err)
+			}
+
+			handler.mu.Lock()
+			defer handler.mu.Unlock()
+			if !slices.Equal(handler.methods, tt.wantMethods) {
+				t.Errorf("expected requests %v for the search, got %v", tt.wantMethods, handler.methods)
+			}
+		})
+	}
+}
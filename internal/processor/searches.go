@@ -0,0 +1,83 @@
+package processor
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// tracksSearches reports whether the searches seekarr starts are recorded, so
+// they can be deleted once they are older than max_search_age_hours
+func (p *Processor) tracksSearches() bool {
+	return p.cfg.Slskd.MaxSearchAgeHours > 0 && !p.cfg.Slskd.DeleteSearches
+}
+
+// recordSearch remembers a search seekarr started in slskd
+func (p *Processor) recordSearch(id, query string) {
+	if p.tracksSearches() {
+		p.searches.Add(id, query)
+	}
+}
+
+// saveSearches writes the recorded searches to disk
+func (p *Processor) saveSearches() {
+	if !p.tracksSearches() {
+		return
+	}
+	if err := p.searches.Save(); err != nil {
+		p.logger.Warn("failed to save searches", "error", err)
+	}
+}
+
+// pruneSearches deletes the searches seekarr started that are older than
+// max_search_age_hours from slskd's history. Searches it didn't start, such as
+// ones made in slskd's UI, and newer ones are never deleted
+func (p *Processor) pruneSearches(ctx context.Context) {
+	if !p.tracksSearches() || p.searches.Count() == 0 {
+		return
+	}
+
+	searches, err := p.slskd.GetSearches(ctx)
+	if err != nil {
+		p.logger.Warn("failed to list slskd searches, skipping search cleanup", "error", err)
+		return
+	}
+
+	maxAge := time.Duration(p.cfg.Slskd.MaxSearchAgeHours) * time.Hour
+	inHistory := make(map[string]bool, len(searches))
+	deleted := 0
+	for _, search := range searches {
+		inHistory[search.ID] = true
+		record := p.searches.Get(search.ID)
+		if record == nil || !strings.HasPrefix(search.State, "Completed") {
+			continue
+		}
+
+		startedAt := search.StartedAt
+		if startedAt.IsZero() {
+			startedAt = record.StartedAt
+		}
+		if time.Since(startedAt) < maxAge {
+			continue
+		}
+
+		if err := p.slskd.DeleteSearch(ctx, search.ID); err != nil {
+			p.logger.Debug("failed to delete old search", "searchID", search.ID, "query", search.SearchText, "error", err)
+			continue
+		}
+		p.searches.Remove(search.ID)
+		deleted++
+	}
+
+	// Forget searches that were deleted in slskd some other way
+	for _, record := range p.searches.Records() {
+		if !inHistory[record.ID] {
+			p.searches.Remove(record.ID)
+		}
+	}
+
+	if deleted > 0 {
+		p.logger.Info("deleted old searches from slskd", "count", deleted, "maxAgeHours", p.cfg.Slskd.MaxSearchAgeHours)
+	}
+	p.saveSearches()
+}
@@ -0,0 +1,99 @@
+package processor
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// mockSlskdClientWithSearches lists a fixed search history and records deletions
+type mockSlskdClientWithSearches struct {
+	mockSlskdClient
+	history []slskd.SearchResponse
+	deleted []string
+}
+
+func (m *mockSlskdClientWithSearches) GetSearches(ctx context.Context) ([]slskd.SearchResponse, error) {
+	return m.history, nil
+}
+
+func (m *mockSlskdClientWithSearches) DeleteSearch(ctx context.Context, searchID string) error {
+	m.deleted = append(m.deleted, searchID)
+	return nil
+}
+
+func TestPruneSearches(t *testing.T) {
+	old := time.Now().Add(-48 * time.Hour)
+	recent := time.Now().Add(-time.Hour)
+	history := []slskd.SearchResponse{
+		{ID: "old", SearchText: "Artist Album", State: "Completed, Succeeded", StartedAt: old},
+		{ID: "recent", SearchText: "Artist Other", State: "Completed, Succeeded", StartedAt: recent},
+		{ID: "running", SearchText: "Artist Third", State: "InProgress", StartedAt: old},
+		{ID: "manual", SearchText: "Something I Searched", State: "Completed, Succeeded", StartedAt: old},
+	}
+
+	tests := []struct {
+		name        string
+		maxAgeHours int
+		wantDeleted []string
+		wantTracked int
+	}{
+		{name: "disabled", maxAgeHours: 0, wantTracked: 4},
+		{name: "deletes only old searches seekarr started", maxAgeHours: 24, wantDeleted: []string{"old"}, wantTracked: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slskdClient := &mockSlskdClientWithSearches{history: history}
+			p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+			p.cfg.Slskd.MaxSearchAgeHours = tt.maxAgeHours
+
+			// seekarr started all but the manual search; "gone" was since deleted in slskd
+			for _, id := range []string{"old", "recent", "running", "gone"} {
+				p.searches.Add(id, "query")
+			}
+
+			p.pruneSearches(context.Background())
+
+			if !slices.Equal(slskdClient.deleted, tt.wantDeleted) {
+				t.Errorf("expected %v deleted, got %v", tt.wantDeleted, slskdClient.deleted)
+			}
+			if got := p.searches.Count(); got != tt.wantTracked {
+				t.Errorf("expected %d searches still tracked, got %d", tt.wantTracked, got)
+			}
+		})
+	}
+}
+
+func TestRunSearch_RecordsSearches(t *testing.T) {
+	tests := []struct {
+		name           string
+		maxAgeHours    int
+		deleteSearches bool
+		wantRecorded   bool
+	}{
+		{name: "cleanup disabled", wantRecorded: false},
+		{name: "cleanup enabled", maxAgeHours: 24, wantRecorded: true},
+		{name: "searches deleted right away", maxAgeHours: 24, deleteSearches: true, wantRecorded: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestProcessor(t, &mockLidarrClient{}, &mockSlskdClient{})
+			p.cfg.Slskd.MaxSearchAgeHours = tt.maxAgeHours
+			p.cfg.Slskd.DeleteSearches = tt.deleteSearches
+
+			if _, err := p.runSearch(context.Background(), lidarr.Album{Title: "Album"}, "Artist Album"); err != nil {
+				t.Fatalf("runSearch() error: %v", err)
+			}
+
+			if recorded := p.searches.Count() == 1; recorded != tt.wantRecorded {
+				t.Errorf("search recorded = %v, want %v", recorded, tt.wantRecorded)
+			}
+		})
+	}
+}
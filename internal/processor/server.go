@@ -0,0 +1,23 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// errSoulseekDisconnected fails a run started while slskd is not logged in to Soulseek
+var errSoulseekDisconnected = errors.New("slskd not connected to Soulseek")
+
+// checkServerState makes sure slskd is logged in to Soulseek before anything is
+// searched, since every search would otherwise come back empty and count as a failure
+func (p *Processor) checkServerState(ctx context.Context) error {
+	state, err := p.slskd.GetServerState(ctx)
+	if err != nil {
+		return &unavailableError{service: "slskd", err: err}
+	}
+	if !state.Ready() {
+		return fmt.Errorf("%w, skipping run (state %q)", errSoulseekDisconnected, state.State)
+	}
+	return nil
+}
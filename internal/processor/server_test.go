@@ -0,0 +1,60 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// mockSlskdClientServerState reports a fixed Soulseek connection state
+type mockSlskdClientServerState struct {
+	mockSlskdClientWithResults
+	state *slskd.ServerState
+	err   error
+}
+
+func (m *mockSlskdClientServerState) GetServerState(ctx context.Context) (*slskd.ServerState, error) {
+	return m.state, m.err
+}
+
+func TestRun_SkippedWhileSoulseekDisconnected(t *testing.T) {
+	tests := []struct {
+		name        string
+		state       *slskd.ServerState
+		err         error
+		wantErr     error
+		unavailable bool
+	}{
+		{name: "connected", state: &slskd.ServerState{State: "Connected, LoggedIn", IsConnected: true, IsLoggedIn: true}},
+		{name: "disconnected", state: &slskd.ServerState{State: "Disconnected"}, wantErr: errSoulseekDisconnected},
+		{name: "connected but not logged in", state: &slskd.ServerState{State: "Connected", IsConnected: true}, wantErr: errSoulseekDisconnected},
+		{name: "state unavailable", err: errors.New("connection refused"), unavailable: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			album, _ := candidateAlbum()
+			slskdClient := &mockSlskdClientServerState{state: tt.state, err: tt.err}
+			p := newTestProcessor(t, &mockLidarrClientWithWanted{wanted: []lidarr.Album{album}}, slskdClient)
+			p.cfg.DryRun = true
+
+			err := p.Run(context.Background())
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.unavailable && !isUnavailable(err) {
+				t.Errorf("expected slskd to be reported unavailable, got %v", err)
+			}
+			if tt.wantErr == nil && !tt.unavailable && err != nil {
+				t.Errorf("Run() error: %v", err)
+			}
+
+			if skipped := p.lastRun.Wanted == 0; skipped != (err != nil) {
+				t.Errorf("expected wanted albums fetched only when the run went ahead, got %d", p.lastRun.Wanted)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package processor
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// interruptMonitoring handles a run stopped while downloads are being monitored
+// With download.cancel_on_shutdown set, the unfinished files of the albums
+// still pending are cancelled in slskd. Albums with finished files stay in the
+// pending downloads file for the next run to organize. It returns ctx.Err()
+func (p *Processor) interruptMonitoring(ctx context.Context, downloadList []DownloadedItem, pending map[int]bool) error {
+	if !p.cfg.Download.CancelOnShutdown {
+		return ctx.Err()
+	}
+
+	var items []DownloadedItem
+	for idx, item := range downloadList {
+		if pending[idx] {
+			items = append(items, item)
+		}
+	}
+	if len(items) > 0 {
+		p.cancelUnfinished(items)
+	}
+	return ctx.Err()
+}
+
+// cancelUnfinished cancels every file of the given items that slskd has not finished
+// The run's context is already cancelled, so the cleanup gets its own timeout
+func (p *Processor) cancelUnfinished(items []DownloadedItem) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(p.cfg.Daemon.ShutdownTimeoutSeconds)*time.Second)
+	defer cancel()
+
+	downloads, err := p.slskd.GetDownloads(ctx)
+	if err != nil {
+		p.logger.Warn("failed to fetch downloads to cancel on shutdown", "error", err)
+		return
+	}
+
+	p.logger.Info("cancelling unfinished downloads", "albums", len(items))
+
+	var abandoned []int
+	for _, item := range items {
+		cancelled, finished := 0, 0
+		for _, userDownload := range downloads {
+			for _, dirDownload := range userDownload.Directories {
+				if !item.hasSource(userDownload.Username, strings.ReplaceAll(dirDownload.Directory, "\\", "/")) {
+					continue
+				}
+				for _, file := range dirDownload.Files {
+					if file.IsCompleted() {
+						if !file.IsErrored() {
+							finished++
+						}
+						continue
+					}
+					if err := p.slskd.CancelDownload(ctx, userDownload.Username, file.ID); err != nil {
+						p.logger.Debug("failed to cancel download", "file", file.Filename, "error", err)
+						continue
+					}
+					cancelled++
+				}
+			}
+		}
+
+		p.logger.Info("cancelled unfinished files",
+			"album", item.AlbumName,
+			"cancelled", cancelled,
+			"finished", finished)
+		if finished == 0 {
+			abandoned = append(abandoned, item.AlbumID)
+		}
+	}
+
+	// Albums with nothing on disk have nothing left to organize
+	if len(abandoned) > 0 {
+		p.clearPending(abandoned...)
+	}
+}
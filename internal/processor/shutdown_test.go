@@ -0,0 +1,59 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMonitorDownloads_CancelsUnfinishedOnShutdown(t *testing.T) {
+	tests := []struct {
+		name          string
+		cancel        bool
+		wantCancelled int
+		wantPending   []int
+	}{
+		{name: "downloads left running by default", cancel: false, wantCancelled: 0, wantPending: []int{1, 2}},
+		{name: "unfinished files cancelled", cancel: true, wantCancelled: 1, wantPending: []int{2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slskdClient := &mockSlskdClientStalling{}
+			p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+			p.cfg.Slskd.StalledTimeout = 60
+			p.cfg.Timing.StallCheckIntervalSec = 60
+			p.cfg.Timing.StallChecks = 5
+			p.cfg.Timing.DownloadPollSeconds = 1
+			p.cfg.Daemon.ShutdownTimeoutSeconds = 5
+			p.cfg.Download.CancelOnShutdown = tt.cancel
+			writeDownloadedFile(t, p, "Done", "01.flac", 0)
+
+			downloadList := []DownloadedItem{
+				{AlbumID: 1, AlbumName: "Stuck", FolderName: "Stuck", Sources: []DownloadSource{{Username: "dead", Directory: "Music/Stuck"}}},
+				{AlbumID: 2, AlbumName: "Done", FolderName: "Done", Sources: []DownloadSource{{Username: "fast", Directory: "Music/Done"}}},
+			}
+			p.savePending(downloadList)
+
+			// Shut down while waiting for the next poll
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			_, err := p.monitorDownloads(ctx, downloadList, nil, nil)
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+			}
+
+			if len(slskdClient.cancelled) != tt.wantCancelled {
+				t.Errorf("expected %d cancelled files, got %v", tt.wantCancelled, slskdClient.cancelled)
+			}
+			var pending []int
+			for _, entry := range p.pending.Pending() {
+				pending = append(pending, entry.AlbumID)
+			}
+			if len(pending) != len(tt.wantPending) || pending[0] != tt.wantPending[0] {
+				t.Errorf("expected pending albums %v, got %v", tt.wantPending, pending)
+			}
+		})
+	}
+}
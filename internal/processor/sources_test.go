@@ -0,0 +1,242 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+)
+
+// mockLidarrClientWithSources returns different wanted lists for missing and cutoff unmet
+type mockLidarrClientWithSources struct {
+	mockLidarrClient
+	missing     []lidarr.Album
+	cutoffUnmet []lidarr.Album
+	requests    []lidarr.GetWantedOptions
+}
+
+func (m *mockLidarrClientWithSources) GetWanted(ctx context.Context, opts lidarr.GetWantedOptions) (*lidarr.WantedResponse, error) {
+	m.requests = append(m.requests, opts)
+	records := m.cutoffUnmet
+	if opts.Missing {
+		records = m.missing
+	}
+	return &lidarr.WantedResponse{Records: records, TotalRecords: 30}, nil
+}
+
+func TestFetchWantedAlbums_SearchSource(t *testing.T) {
+	tests := []struct {
+		source  string
+		wantIDs []int
+	}{
+		{SourceMissing, []int{1, 2}},
+		{SourceCutoffUnmet, []int{2, 3}},
+		{SourceAll, []int{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			lidarrClient := &mockLidarrClientWithSources{
+				missing:     []lidarr.Album{{ID: 1, Title: "One"}, {ID: 2, Title: "Two"}},
+				cutoffUnmet: []lidarr.Album{{ID: 2, Title: "Two"}, {ID: 3, Title: "Three"}},
+			}
+			p := newTestProcessor(t, lidarrClient, &mockSlskdClient{})
+			p.cfg.Search.SearchSource = tt.source
+			p.current = &RunSummary{}
+
+			albums, err := p.fetchWantedAlbums(context.Background())
+			if err != nil {
+				t.Fatalf("fetchWantedAlbums() error: %v", err)
+			}
+
+			var ids []int
+			for _, album := range albums {
+				ids = append(ids, album.ID)
+			}
+			sort.Ints(ids)
+			if len(ids) != len(tt.wantIDs) {
+				t.Fatalf("expected albums %v, got %v", tt.wantIDs, ids)
+			}
+			for i := range ids {
+				if ids[i] != tt.wantIDs[i] {
+					t.Errorf("expected albums %v, got %v", tt.wantIDs, ids)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestFetchWantedAlbums_PagesTrackedPerSource(t *testing.T) {
+	lidarrClient := &mockLidarrClientWithSources{}
+	p := newTestProcessor(t, lidarrClient, &mockSlskdClient{})
+	p.cfg.Search.SearchType = "incrementing_page"
+	p.cfg.Search.NumberOfAlbumsToGrab = 10
+	p.current = &RunSummary{}
+
+	// Two runs on missing, then one on cutoff unmet
+	p.cfg.Search.SearchSource = SourceMissing
+	for i := 0; i < 2; i++ {
+		if _, err := p.fetchWantedAlbums(context.Background()); err != nil {
+			t.Fatalf("fetchWantedAlbums() error: %v", err)
+		}
+	}
+	p.cfg.Search.SearchSource = SourceCutoffUnmet
+	if _, err := p.fetchWantedAlbums(context.Background()); err != nil {
+		t.Fatalf("fetchWantedAlbums() error: %v", err)
+	}
+
+	pages := []int{lidarrClient.requests[0].Page, lidarrClient.requests[1].Page, lidarrClient.requests[2].Page}
+	if pages[0] != 1 || pages[1] != 2 || pages[2] != 1 {
+		t.Errorf("expected pages [1 2 1], got %v", pages)
+	}
+
+	// The missing source keeps the original state file
+	data, err := os.ReadFile(filepath.Join(p.cfg.Slskd.DownloadDir, ".current_page.txt"))
+	if err != nil || string(data) != "3" {
+		t.Errorf("expected missing page 3 in .current_page.txt, got %q (%v)", data, err)
+	}
+}
+
+func TestDedupeAlbums(t *testing.T) {
+	artist := lidarr.Artist{ArtistName: "Björk"}
+	p := newTestProcessor(t, &mockLidarrClient{}, &mockSlskdClient{})
+
+	albums := p.dedupeAlbums([]lidarr.Album{
+		{ID: 1, Title: "Homogenic", Artist: artist},
+		{ID: 2, Title: "Post", Artist: artist},
+		{ID: 1, Title: "Homogenic", Artist: artist},                                    // Overlapping pages
+		{ID: 3, Title: "homogenic ", Artist: lidarr.Artist{ArtistName: "Bjork"}},       // Same album, another edition
+		{ID: 4, Title: "Homogenic", Artist: lidarr.Artist{ArtistName: "Someone Else"}}, // Same title, other artist
+	})
+
+	var ids []int
+	for _, album := range albums {
+		ids = append(ids, album.ID)
+	}
+	if want := []int{1, 2, 4}; !slices.Equal(ids, want) {
+		t.Errorf("expected albums %v, got %v", want, ids)
+	}
+}
+
+// mockLidarrClientPaged pages one wanted list the way Lidarr does
+type mockLidarrClientPaged struct {
+	mockLidarrClient
+	albums   []lidarr.Album
+	requests []lidarr.GetWantedOptions
+}
+
+func (m *mockLidarrClientPaged) GetWanted(ctx context.Context, opts lidarr.GetWantedOptions) (*lidarr.WantedResponse, error) {
+	m.requests = append(m.requests, opts)
+	start := min((opts.Page-1)*opts.PageSize, len(m.albums))
+	end := min(start+opts.PageSize, len(m.albums))
+	return &lidarr.WantedResponse{
+		Page:         opts.Page,
+		PageSize:     opts.PageSize,
+		TotalRecords: len(m.albums),
+		Records:      m.albums[start:end],
+	}, nil
+}
+
+func (m *mockLidarrClientPaged) GetAllWanted(ctx context.Context, opts lidarr.GetWantedOptions) ([]lidarr.Album, error) {
+	m.requests = append(m.requests, opts)
+	return m.albums, nil
+}
+
+func pagedAlbums(n int) []lidarr.Album {
+	albums := make([]lidarr.Album, n)
+	for i := range albums {
+		albums[i] = lidarr.Album{ID: i + 1, Title: fmt.Sprintf("Album %d", i+1)}
+	}
+	return albums
+}
+
+func TestFetchWantedFromSource_SortKey(t *testing.T) {
+	tests := []struct {
+		sortKey     string
+		wantSortKey string
+		wantSortDir string
+	}{
+		{"", "", ""},
+		{"artistName", "artists.sortName", "descending"},
+		{"releaseDate", "albums.releaseDate", "descending"},
+		{"albums.title", "albums.title", "descending"},
+		{"random", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sortKey, func(t *testing.T) {
+			lidarrClient := &mockLidarrClientPaged{albums: pagedAlbums(3)}
+			p := newTestProcessor(t, lidarrClient, &mockSlskdClient{})
+			p.cfg.Search.SortKey = tt.sortKey
+			p.cfg.Search.SortDir = "descending"
+
+			if _, err := p.fetchWantedFromSource(context.Background(), SourceMissing); err != nil {
+				t.Fatalf("fetchWantedFromSource() error: %v", err)
+			}
+			for _, req := range lidarrClient.requests {
+				if req.SortKey != tt.wantSortKey || req.SortDir != tt.wantSortDir {
+					t.Errorf("expected sort %q %q, got %q %q", tt.wantSortKey, tt.wantSortDir, req.SortKey, req.SortDir)
+				}
+			}
+		})
+	}
+}
+
+func TestFetchWantedFromSource_RandomFirstPage(t *testing.T) {
+	lidarrClient := &mockLidarrClientPaged{albums: pagedAlbums(25)}
+	p := newTestProcessor(t, lidarrClient, &mockSlskdClient{})
+	p.cfg.Search.SortKey = "random"
+	p.cfg.Search.NumberOfAlbumsToGrab = 10
+	p.shuffler = rand.New(rand.NewPCG(1, 2))
+
+	albums, err := p.fetchWantedFromSource(context.Background(), SourceMissing)
+	if err != nil {
+		t.Fatalf("fetchWantedFromSource() error: %v", err)
+	}
+
+	if len(albums) != 10 {
+		t.Fatalf("expected 10 albums, got %d", len(albums))
+	}
+	var ids []int
+	for _, album := range albums {
+		ids = append(ids, album.ID)
+	}
+	if slices.Equal(ids, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}) {
+		t.Errorf("expected albums in random order, got %v", ids)
+	}
+	if !slices.ContainsFunc(ids, func(id int) bool { return id > 10 }) {
+		t.Errorf("expected albums from past the first page, got %v", ids)
+	}
+}
+
+func TestFetchWantedFromSource_IncrementingPagePastEnd(t *testing.T) {
+	lidarrClient := &mockLidarrClientPaged{albums: pagedAlbums(15)}
+	p := newTestProcessor(t, lidarrClient, &mockSlskdClient{})
+	p.cfg.Search.SearchType = "incrementing_page"
+	p.cfg.Search.NumberOfAlbumsToGrab = 10
+
+	// Saved while the wanted list was longer
+	pageTrack := p.pageTrack[SourceMissing]
+	for range 4 {
+		pageTrack.Next(10)
+	}
+
+	albums, err := p.fetchWantedFromSource(context.Background(), SourceMissing)
+	if err != nil {
+		t.Fatalf("fetchWantedFromSource() error: %v", err)
+	}
+
+	if len(albums) != 10 || albums[0].ID != 1 {
+		t.Errorf("expected the first page, got %d albums", len(albums))
+	}
+	if got := pageTrack.Current(); got != 2 {
+		t.Errorf("expected page 2 next, got %d", got)
+	}
+}
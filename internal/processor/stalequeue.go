@@ -0,0 +1,69 @@
+package processor
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+)
+
+// staleQueueItem reports whether a queue entry for a wanted album stopped
+// downloading before cutoff and only keeps the album from being searched
+func staleQueueItem(item lidarr.QueueItem, wanted map[int]bool, cutoff time.Time) bool {
+	if item.AlbumID == nil || !wanted[*item.AlbumID] || item.Added == nil || !item.Added.Before(cutoff) {
+		return false
+	}
+	return strings.EqualFold(item.Status, lidarr.QueueStatusCompleted) || strings.EqualFold(item.Status, lidarr.QueueStatusFailed)
+}
+
+// clearStaleQueueItems removes the queue entries of wanted albums whose
+// download completed or failed over lidarr.clear_stale_queue_items_hours ago,
+// such as an import Lidarr gave up on, which would otherwise skip the album
+// every run. Entries for albums seekarr isn't searching are left alone. It
+// returns the entries still in the queue
+func (p *Processor) clearStaleQueueItems(ctx context.Context, records []lidarr.QueueItem, albums []lidarr.Album) []lidarr.QueueItem {
+	hours := p.cfg.Lidarr.ClearStaleQueueItemsHours
+	if hours <= 0 {
+		return records
+	}
+
+	wanted := make(map[int]bool, len(albums))
+	titles := make(map[int]string, len(albums))
+	for _, album := range albums {
+		wanted[album.ID] = true
+		titles[album.ID] = album.Title
+	}
+	cutoff := time.Now().Add(-time.Duration(hours) * time.Hour)
+
+	var kept []lidarr.QueueItem
+	for _, item := range records {
+		if !staleQueueItem(item, wanted, cutoff) {
+			kept = append(kept, item)
+			continue
+		}
+		if p.cfg.DryRun {
+			p.logger.Info("would remove stale lidarr queue item",
+				"album", titles[*item.AlbumID],
+				"title", item.Title,
+				"status", item.Status,
+				"added", item.Added.Format(time.RFC3339))
+			kept = append(kept, item)
+			continue
+		}
+
+		// The download client keeps its files, which may hold the only copy
+		if err := p.lidarr.DeleteQueueItem(ctx, item.ID, false, false); err != nil {
+			p.logger.Warn("failed to remove stale lidarr queue item", "album", titles[*item.AlbumID], "title", item.Title, "error", err)
+			kept = append(kept, item)
+			continue
+		}
+		p.logger.Warn("removed stale lidarr queue item",
+			"album", titles[*item.AlbumID],
+			"title", item.Title,
+			"status", item.Status,
+			"downloadClient", item.DownloadClient,
+			"added", item.Added.Format(time.RFC3339))
+	}
+	return kept
+}
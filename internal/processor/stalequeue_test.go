@@ -0,0 +1,122 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+)
+
+// mockLidarrClientWithQueue serves a fixed queue and records deleted entries
+type mockLidarrClientWithQueue struct {
+	mockLidarrClient
+	queue     []lidarr.QueueItem
+	deleted   []int
+	deleteErr error
+}
+
+func (m *mockLidarrClientWithQueue) GetAllQueue(ctx context.Context, pageSize int) ([]lidarr.QueueItem, error) {
+	return m.queue, nil
+}
+
+func (m *mockLidarrClientWithQueue) DeleteQueueItem(ctx context.Context, id int, removeFromClient, blocklist bool) error {
+	if removeFromClient || blocklist {
+		return errors.New("unexpected removeFromClient or blocklist")
+	}
+	if m.deleteErr != nil {
+		return m.deleteErr
+	}
+	m.deleted = append(m.deleted, id)
+	return nil
+}
+
+func staleQueue() []lidarr.QueueItem {
+	old := time.Now().Add(-48 * time.Hour)
+	recent := time.Now().Add(-time.Hour)
+	return []lidarr.QueueItem{
+		{ID: 1, AlbumID: intPtr(10), Status: "completed", Added: &old},
+		{ID: 2, AlbumID: intPtr(20), Status: "failed", Added: &old},
+		{ID: 3, AlbumID: intPtr(30), Status: "downloading", Added: &old},
+		{ID: 4, AlbumID: intPtr(40), Status: "completed", Added: &recent},
+		{ID: 5, AlbumID: intPtr(50), Status: "completed", Added: &old}, // Not wanted
+		{ID: 6, AlbumID: intPtr(60), Status: "failed"},                 // Added unknown
+	}
+}
+
+func staleQueueAlbums() []lidarr.Album {
+	var albums []lidarr.Album
+	for _, id := range []int{10, 20, 30, 40, 60} {
+		albums = append(albums, lidarr.Album{ID: id, Title: "Album"})
+	}
+	return albums
+}
+
+func TestFilterQueuedAlbums_ClearStaleQueueItems(t *testing.T) {
+	tests := []struct {
+		name        string
+		hours       int
+		dryRun      bool
+		deleteErr   error
+		wantDeleted []int
+		wantAlbums  []int
+	}{
+		{"disabled", 0, false, nil, nil, nil},
+		{"completed and failed entries older than the threshold", 24, false, nil, []int{1, 2}, []int{10, 20}},
+		{"threshold longer than their age", 72, false, nil, nil, nil},
+		{"dry run", 24, true, nil, nil, nil},
+		{"delete fails", 24, false, errors.New("server error"), nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lidarrClient := &mockLidarrClientWithQueue{queue: staleQueue(), deleteErr: tt.deleteErr}
+			p := newTestProcessor(t, lidarrClient, &mockSlskdClient{})
+			p.cfg.Lidarr.ClearStaleQueueItemsHours = tt.hours
+			p.cfg.DryRun = tt.dryRun
+			p.current = &RunSummary{}
+
+			albums, err := p.filterQueuedAlbums(context.Background(), staleQueueAlbums())
+			if err != nil {
+				t.Fatalf("filterQueuedAlbums() error: %v", err)
+			}
+
+			if !slices.Equal(lidarrClient.deleted, tt.wantDeleted) {
+				t.Errorf("expected queue items %v deleted, got %v", tt.wantDeleted, lidarrClient.deleted)
+			}
+			var ids []int
+			for _, album := range albums {
+				ids = append(ids, album.ID)
+			}
+			if !slices.Equal(ids, tt.wantAlbums) {
+				t.Errorf("expected albums %v searched, got %v", tt.wantAlbums, ids)
+			}
+		})
+	}
+}
+
+func TestFilterQueuedAlbums_WholeQueue(t *testing.T) {
+	// A queue longer than a single page of 1000 entries
+	queue := make([]lidarr.QueueItem, 2500)
+	for i := range queue {
+		queue[i] = lidarr.QueueItem{ID: i + 1, AlbumID: intPtr(i + 1)}
+	}
+	lidarrClient := &mockLidarrClientWithQueue{queue: queue}
+	p := newTestProcessor(t, lidarrClient, &mockSlskdClient{})
+	p.current = &RunSummary{}
+
+	var wanted []lidarr.Album
+	for _, id := range []int{1, 1000, 1001, 2000, 2500, 2501} {
+		wanted = append(wanted, lidarr.Album{ID: id, Title: "Album"})
+	}
+
+	albums, err := p.filterQueuedAlbums(context.Background(), wanted)
+	if err != nil {
+		t.Fatalf("filterQueuedAlbums() error: %v", err)
+	}
+	if len(albums) != 1 || albums[0].ID != 2501 {
+		t.Errorf("expected only album 2501 left, got %+v", albums)
+	}
+}
@@ -0,0 +1,64 @@
+package processor
+
+import (
+	"strings"
+	"time"
+)
+
+// stallTracker remembers when each transfer last made progress
+type stallTracker struct {
+	timeout  time.Duration
+	minSpeed float64 // Bytes per second below which progress doesn't count, 0 to count any
+	progress map[string]fileProgress
+}
+
+// fileProgress is a transfer's byte count and when it last changed
+type fileProgress struct {
+	bytes int64
+	at    time.Time
+}
+
+func newStallTracker(timeout time.Duration, minSpeed float64) *stallTracker {
+	return &stallTracker{
+		timeout:  timeout,
+		minSpeed: minSpeed,
+		progress: make(map[string]fileProgress),
+	}
+}
+
+// stalled records a file's progress and reports whether a queued or in-progress
+// transfer has made none for longer than the timeout. Bytes trickling in slower
+// than minSpeed since the last progress that counted don't count either
+func (s *stallTracker) stalled(file sourceFile, now time.Time) bool {
+	if !strings.HasPrefix(file.State, "InProgress") && !strings.HasPrefix(file.State, "Queued") {
+		return false
+	}
+
+	key := stallKey(file.username, file.Filename)
+	last, seen := s.progress[key]
+	if !seen || (file.BytesTransferred != last.bytes && !s.trickling(file, last, now)) {
+		s.progress[key] = fileProgress{bytes: file.BytesTransferred, at: now}
+		return false
+	}
+
+	return now.Sub(last.at) >= s.timeout
+}
+
+// trickling reports whether a file's transfer since its last counted progress
+// is slower than minSpeed
+func (s *stallTracker) trickling(file sourceFile, last fileProgress, now time.Time) bool {
+	elapsed := now.Sub(last.at).Seconds()
+	if s.minSpeed <= 0 || elapsed <= 0 {
+		return false
+	}
+	return float64(file.BytesTransferred-last.bytes)/elapsed < s.minSpeed
+}
+
+// reset forgets a file's progress, e.g. after it was re-enqueued
+func (s *stallTracker) reset(username, filename string) {
+	delete(s.progress, stallKey(username, filename))
+}
+
+func stallKey(username, filename string) string {
+	return username + "\x00" + filename
+}
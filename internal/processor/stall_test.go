@@ -0,0 +1,167 @@
+package processor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+func TestStallTracker(t *testing.T) {
+	start := time.Now()
+	file := func(state string, bytes int64) sourceFile {
+		return sourceFile{
+			DownloadFile: slskd.DownloadFile{Filename: `Music\Album\01.flac`, State: state, BytesTransferred: bytes},
+			username:     "user",
+		}
+	}
+	tests := []struct {
+		name     string
+		minSpeed float64
+		polls    []sourceFile
+		offset   []time.Duration
+		want     bool
+	}{
+		{
+			name:   "first sighting is never stalled",
+			polls:  []sourceFile{file("InProgress", 10)},
+			offset: []time.Duration{0},
+			want:   false,
+		},
+		{
+			name:   "no progress past the timeout",
+			polls:  []sourceFile{file("InProgress", 10), file("InProgress", 10)},
+			offset: []time.Duration{0, 2 * time.Minute},
+			want:   true,
+		},
+		{
+			name:   "no progress within the timeout",
+			polls:  []sourceFile{file("InProgress", 10), file("InProgress", 10)},
+			offset: []time.Duration{0, 30 * time.Second},
+			want:   false,
+		},
+		{
+			name:   "progress resets the clock",
+			polls:  []sourceFile{file("InProgress", 10), file("InProgress", 20), file("InProgress", 20)},
+			offset: []time.Duration{0, 90 * time.Second, 2 * time.Minute},
+			want:   false,
+		},
+		{
+			name:     "trickling progress doesn't reset the clock",
+			minSpeed: 1024,
+			polls:    []sourceFile{file("InProgress", 10), file("InProgress", 20), file("InProgress", 30)},
+			offset:   []time.Duration{0, 30 * time.Second, 2 * time.Minute},
+			want:     true,
+		},
+		{
+			name:   "trickling progress counts without a minimum speed",
+			polls:  []sourceFile{file("InProgress", 10), file("InProgress", 20), file("InProgress", 30)},
+			offset: []time.Duration{0, 30 * time.Second, 2 * time.Minute},
+			want:   false,
+		},
+		{
+			name:     "a fast transfer's progress resets the clock",
+			minSpeed: 1024,
+			polls:    []sourceFile{file("InProgress", 10), file("InProgress", 10_000_000), file("InProgress", 10_000_010)},
+			offset:   []time.Duration{0, 30 * time.Second, 80 * time.Second},
+			want:     false,
+		},
+		{
+			name:     "a fast start doesn't excuse a later trickle",
+			minSpeed: 1024,
+			polls:    []sourceFile{file("InProgress", 10), file("InProgress", 10_000_000), file("InProgress", 10_000_010), file("InProgress", 10_000_020)},
+			offset:   []time.Duration{0, 30 * time.Second, 60 * time.Second, 100 * time.Second},
+			want:     true,
+		},
+		{
+			name:   "remotely queued files stall too",
+			polls:  []sourceFile{file("Queued, Remotely", 0), file("Queued, Remotely", 0)},
+			offset: []time.Duration{0, 2 * time.Minute},
+			want:   true,
+		},
+		{
+			name:   "other states are ignored",
+			polls:  []sourceFile{file("Requested", 0), file("Requested", 0)},
+			offset: []time.Duration{0, 2 * time.Minute},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := newStallTracker(time.Minute, tt.minSpeed)
+			var got bool
+			for i, f := range tt.polls {
+				got = tracker.stalled(f, start.Add(tt.offset[i]))
+			}
+			if got != tt.want {
+				t.Errorf("stalled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// mockSlskdClientStalling reports one finished directory and one that never progresses
+type mockSlskdClientStalling struct {
+	mockSlskdClient
+	cancelled []string
+	retries   int
+}
+
+func (m *mockSlskdClientStalling) GetDownloads(ctx context.Context) (slskd.DownloadsResponse, error) {
+	return slskd.DownloadsResponse{
+		{Username: "fast", Directories: []slskd.DirectoryDownloads{{
+			Directory: `Music\Done`,
+			Files:     []slskd.DownloadFile{{ID: "done-1", Filename: `Music\Done\01.flac`, State: "Completed, Succeeded"}},
+		}}},
+		{Username: "dead", Directories: []slskd.DirectoryDownloads{{
+			Directory: `Music\Stuck`,
+			Files:     []slskd.DownloadFile{{ID: "stuck-1", Filename: `Music\Stuck\01.flac`, State: "InProgress", BytesTransferred: 10}},
+		}}},
+	}, nil
+}
+
+func (m *mockSlskdClientStalling) CancelDownload(ctx context.Context, username, downloadID string) error {
+	m.cancelled = append(m.cancelled, downloadID)
+	return nil
+}
+
+func (m *mockSlskdClientStalling) EnqueueDownloads(ctx context.Context, username string, files []slskd.EnqueueFile) (*slskd.EnqueueResult, error) {
+	m.retries++
+	return &slskd.EnqueueResult{}, nil
+}
+
+func TestMonitorDownloads_CancelsStalledFiles(t *testing.T) {
+	slskdClient := &mockSlskdClientStalling{}
+	p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+	p.cfg.Slskd.StalledTimeout = 60
+	p.cfg.Timing.StallChecks = 1 // With no check interval, one poll without progress is a stall
+	writeDownloadedFile(t, p, "Done", "01.flac", 0)
+
+	downloadList := []DownloadedItem{
+		{AlbumID: 1, AlbumName: "Stuck", FolderName: "Stuck", Sources: []DownloadSource{{Username: "dead", Directory: "Music/Stuck"}}},
+		{AlbumID: 2, AlbumName: "Done", FolderName: "Done", Sources: []DownloadSource{{Username: "fast", Directory: "Music/Done"}}},
+	}
+
+	var ready []int
+	succeeded, err := p.monitorDownloads(context.Background(), downloadList, nil, func(item DownloadedItem) {
+		ready = append(ready, item.AlbumID)
+	})
+	if err != nil {
+		t.Fatalf("monitorDownloads() error: %v", err)
+	}
+
+	if len(succeeded) != 1 || succeeded[0].AlbumID != 2 {
+		t.Errorf("expected only the finished album to succeed, got %+v", succeeded)
+	}
+	if len(ready) != 1 || ready[0] != 2 {
+		t.Errorf("expected the finished album to be handed over once, got %v", ready)
+	}
+	if len(slskdClient.cancelled) != 4 {
+		t.Errorf("expected the stalled file cancelled on each of 4 attempts, got %v", slskdClient.cancelled)
+	}
+	if slskdClient.retries != 3 {
+		t.Errorf("expected 3 retries, got %d", slskdClient.retries)
+	}
+}
@@ -0,0 +1,249 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Phase names reported while a run is in progress
+const (
+	PhaseIdle        = "idle"
+	PhaseFetching    = "fetching"
+	PhaseSearching   = "searching"
+	PhaseDownloading = "downloading"
+	PhaseOrganizing  = "organizing"
+	PhaseImporting   = "importing"
+)
+
+// Outcomes recorded for each wanted album in a run
+const (
+	OutcomeSkipped  = "skipped"
+	OutcomeFailed   = "failed"
+	OutcomeQueued   = "queued"
+	OutcomeImported = "imported"
+
+	// OutcomeWouldDownload replaces OutcomeQueued in dry runs
+	OutcomeWouldDownload = "would_download"
+
+	// OutcomeDeferred marks an album left for a later run, e.g. for lack of disk space
+	OutcomeDeferred = "deferred"
+)
+
+// Reasons explaining why an album was skipped or failed
+const (
+	ReasonBlacklist         = "blacklist"
+	ReasonAlbumType         = "album_type"
+	ReasonDenylist          = "denylist"
+	ReasonQueued            = "queued"
+	ReasonUnmonitored       = "unmonitored"
+	ReasonArtistUnmonitored = "artist_unmonitored"
+	ReasonNotInLidarr       = "not_in_lidarr"
+	ReasonNoResults         = "no_results"
+	ReasonNoQualityMatch    = "no_quality_match"
+	ReasonEnqueueFailed     = "enqueue_failed"
+	ReasonDiskSpace         = "disk_space"
+	ReasonBudget            = "run_budget"
+	ReasonDeclined          = "declined"
+	ReasonDownloadFailed    = "download_failed"
+	ReasonImportFailed      = "import_failed"
+	ReasonUnavailable       = "service_unavailable"
+	ReasonTimeout           = "timeout"
+	ReasonError             = "error"
+)
+
+// AlbumDecision records what happened to one wanted album during a run
+type AlbumDecision struct {
+	AlbumID  int    `json:"album_id"`
+	Artist   string `json:"artist"`
+	Album    string `json:"album"`
+	Outcome  string `json:"outcome"`
+	Reason   string `json:"reason,omitempty"`
+	Failures int    `json:"failures"`
+	Query    string `json:"query,omitempty"`
+}
+
+// RunSummary describes the outcome of a single processor run
+type RunSummary struct {
+	StartedAt     time.Time       `json:"started_at"`
+	FinishedAt    time.Time       `json:"finished_at"`
+	Wanted        int             `json:"wanted"`
+	Queued        int             `json:"queued"`
+	Failed        int             `json:"failed"`
+	Succeeded     int             `json:"succeeded"`
+	Resumed       int             `json:"resumed,omitempty"`
+	Deferred      int             `json:"deferred,omitempty"`
+	DryRun        bool            `json:"dry_run,omitempty"`
+	WouldDownload int             `json:"would_download,omitempty"`
+	Error         string          `json:"error,omitempty"`
+	Decisions     []AlbumDecision `json:"-"`
+}
+
+// ReasonCounts tallies decisions by reason, ignoring albums that went through
+func (s *RunSummary) ReasonCounts() map[string]int {
+	counts := make(map[string]int)
+	for _, d := range s.Decisions {
+		if d.Reason != "" {
+			counts[d.Reason]++
+		}
+	}
+	return counts
+}
+
+// count returns the number of albums with the given outcome
+func (s *RunSummary) count(outcome string) int {
+	n := 0
+	for _, d := range s.Decisions {
+		if d.Outcome == outcome {
+			n++
+		}
+	}
+	return n
+}
+
+// Status is a point-in-time view of the processor
+type Status struct {
+	Phase         string      `json:"phase"`
+	LastRun       *RunSummary `json:"last_run,omitempty"`
+	DenylistCount int         `json:"denylist_count"`
+}
+
+// Status returns the current phase, the summary of the last finished run,
+// and the number of denylisted albums
+func (p *Processor) Status() Status {
+	p.statusMu.Lock()
+	defer p.statusMu.Unlock()
+
+	status := Status{
+		Phase:         p.phase,
+		DenylistCount: p.denylist.Count(),
+	}
+	if p.searching {
+		status.Phase = PhaseSearching
+	}
+	if p.lastRun != nil {
+		summary := *p.lastRun
+		status.LastRun = &summary
+	}
+	return status
+}
+
+// setPhase records the phase the current run has reached
+func (p *Processor) setPhase(phase string) {
+	p.statusMu.Lock()
+	defer p.statusMu.Unlock()
+	p.phase = phase
+}
+
+// setSearching marks whether searches are still running alongside downloads
+// Until they finish, searching is reported as the current phase
+func (p *Processor) setSearching(searching bool) {
+	p.statusMu.Lock()
+	defer p.statusMu.Unlock()
+	p.searching = searching
+}
+
+// startPhase records the new phase and opens a span covering it
+// The caller ends the span when the phase completes
+func (p *Processor) startPhase(ctx context.Context, phase string) (context.Context, trace.Span) {
+	p.setPhase(phase)
+	return tracer.Start(ctx, "phase."+phase)
+}
+
+// recordDecision adds an album's outcome to the current run
+// It is a no-op outside of Run
+func (p *Processor) recordDecision(album lidarr.Album, outcome, reason, query string) {
+	failures := 0
+	if entry := p.denylist.GetEntry(album.ID); entry != nil {
+		failures = entry.Failures
+	}
+
+	p.currentMu.Lock()
+	defer p.currentMu.Unlock()
+	if p.current == nil {
+		return
+	}
+	p.current.Decisions = append(p.current.Decisions, AlbumDecision{
+		AlbumID:  album.ID,
+		Artist:   album.Artist.ArtistName,
+		Album:    album.Title,
+		Outcome:  outcome,
+		Reason:   reason,
+		Failures: failures,
+		Query:    query,
+	})
+}
+
+// updateDecision changes the outcome of an album already recorded in the current run
+func (p *Processor) updateDecision(albumID int, outcome, reason string) {
+	p.currentMu.Lock()
+	defer p.currentMu.Unlock()
+	if p.current == nil {
+		return
+	}
+	for i := range p.current.Decisions {
+		if p.current.Decisions[i].AlbumID == albumID {
+			p.current.Decisions[i].Outcome = outcome
+			p.current.Decisions[i].Reason = reason
+			return
+		}
+	}
+}
+
+// finishRun stores the summary of a completed run and resets the phase
+func (p *Processor) finishRun(summary *RunSummary, err error) {
+	// Stop collecting decisions before the summary is read
+	p.currentMu.Lock()
+	p.current = nil
+	p.currentMu.Unlock()
+
+	summary.FinishedAt = time.Now()
+	if err != nil {
+		summary.Error = err.Error()
+	}
+	if err := p.runEnds.Record(summary.FinishedAt); err != nil {
+		p.logger.Warn("failed to record run end", "error", err)
+	}
+
+	if counts := summary.ReasonCounts(); len(counts) > 0 {
+		reasons := make([]string, 0, len(counts))
+		for reason := range counts {
+			reasons = append(reasons, reason)
+		}
+		sort.Strings(reasons)
+
+		args := make([]any, 0, len(counts)*2)
+		for _, reason := range reasons {
+			args = append(args, reason, counts[reason])
+		}
+		p.logger.Info("run summary", args...)
+	}
+
+	if p.cfg.Report.Dir != "" {
+		if err := p.writeReport(summary); err != nil {
+			p.logger.Warn("failed to write run report", "error", err)
+		}
+	}
+
+	if p.hooks.HasPostRun() {
+		// The run context may already be cancelled; hooks have their own timeout
+		payload, err := json.Marshal(struct {
+			*RunSummary
+			Albums []AlbumDecision `json:"albums"`
+		}{summary, summary.Decisions})
+		if err != nil {
+			p.logger.Warn("failed to encode run summary for hooks", "error", err)
+		} else {
+			p.hooks.PostRun(context.Background(), payload)
+		}
+	}
+
+	p.statusMu.Lock()
+	defer p.statusMu.Unlock()
+	p.phase = PhaseIdle
+	p.lastRun = summary
+}
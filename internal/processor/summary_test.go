@@ -0,0 +1,72 @@
+package processor
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+)
+
+func TestStatus_FinishRun(t *testing.T) {
+	p := newTestProcessor(t, &mockLidarrClient{}, &mockSlskdClient{})
+
+	status := p.Status()
+	if status.Phase != PhaseIdle {
+		t.Errorf("expected initial phase %q, got %q", PhaseIdle, status.Phase)
+	}
+	if status.LastRun != nil {
+		t.Error("expected no last run before the first run")
+	}
+
+	p.setPhase(PhaseSearching)
+	if got := p.Status().Phase; got != PhaseSearching {
+		t.Errorf("expected phase %q, got %q", PhaseSearching, got)
+	}
+
+	p.finishRun(&RunSummary{StartedAt: time.Now(), Wanted: 5, Queued: 2}, errors.New("boom"))
+
+	status = p.Status()
+	if status.Phase != PhaseIdle {
+		t.Errorf("expected phase to reset to %q, got %q", PhaseIdle, status.Phase)
+	}
+	if status.LastRun == nil {
+		t.Fatal("expected last run summary")
+	}
+	if status.LastRun.Wanted != 5 || status.LastRun.Queued != 2 {
+		t.Errorf("unexpected summary counts: %+v", status.LastRun)
+	}
+	if status.LastRun.Error != "boom" {
+		t.Errorf("expected error to be recorded, got %q", status.LastRun.Error)
+	}
+	if status.LastRun.FinishedAt.IsZero() {
+		t.Error("expected FinishedAt to be set")
+	}
+	if at := p.runEnds.At(); !at.Equal(status.LastRun.FinishedAt) {
+		t.Errorf("expected the run's end to bound the next history check, got %v", at)
+	}
+}
+
+func TestRecordDecision_ConcurrentWithFinishRun(t *testing.T) {
+	p := newTestProcessor(t, &mockLidarrClient{}, &mockSlskdClient{})
+	summary := &RunSummary{StartedAt: time.Now()}
+	p.current = summary
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Go(func() {
+			album := lidarr.Album{ID: i, Title: "Album"}
+			p.recordDecision(album, OutcomeFailed, ReasonNoResults, "")
+			p.updateDecision(album.ID, OutcomeQueued, "")
+		})
+	}
+	p.finishRun(summary, nil)
+	wg.Wait()
+
+	// Decisions recorded after the run finished are dropped
+	p.recordDecision(lidarr.Album{ID: 99}, OutcomeFailed, ReasonNoResults, "")
+	if got := len(p.Status().LastRun.Decisions); got > 8 {
+		t.Errorf("expected at most 8 decisions, got %d", got)
+	}
+}
@@ -0,0 +1,74 @@
+package processor
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// albumTimer cancels an album's context once it has run for
+// per_album_timeout_seconds. Its clock stops while the album waits on
+// something other than its own Lidarr and slskd calls, such as an approval prompt
+type albumTimer struct {
+	runCtx    context.Context // The context the album's was derived from
+	mu        sync.Mutex
+	timer     *time.Timer
+	remaining time.Duration
+	resumed   time.Time
+	paused    int
+	expired   bool
+}
+
+type albumTimerKey struct{}
+
+// withAlbumTimer returns a context cancelled once the album has run for
+// timeout, not counting paused time, and a func releasing it
+func withAlbumTimer(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	albumCtx, cancel := context.WithCancel(ctx)
+	t := &albumTimer{runCtx: ctx, remaining: timeout, resumed: time.Now()}
+	t.timer = time.AfterFunc(timeout, func() {
+		t.mu.Lock()
+		t.expired = true
+		t.mu.Unlock()
+		cancel()
+	})
+	return context.WithValue(albumCtx, albumTimerKey{}, t), func() {
+		t.timer.Stop()
+		cancel()
+	}
+}
+
+// pauseAlbumTimer stops the album's clock until the returned func is called
+// Pauses may overlap; without an album timer it does nothing
+func pauseAlbumTimer(ctx context.Context) (resume func()) {
+	t, ok := ctx.Value(albumTimerKey{}).(*albumTimer)
+	if !ok {
+		return func() {}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.paused == 0 && t.timer.Stop() {
+		t.remaining -= time.Since(t.resumed)
+	}
+	t.paused++
+
+	return sync.OnceFunc(func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.paused--
+		if t.paused == 0 && !t.expired {
+			t.resumed = time.Now()
+			t.timer.Reset(max(t.remaining, 0))
+		}
+	})
+}
+
+// runContextFor returns the context an album's timer was started from, or ctx
+// itself when the album has no timer
+func runContextFor(ctx context.Context) context.Context {
+	if t, ok := ctx.Value(albumTimerKey{}).(*albumTimer); ok {
+		return t.runCtx
+	}
+	return ctx
+}
@@ -0,0 +1,68 @@
+package processor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// mockSlskdClientWedged never returns search results until its context ends
+type mockSlskdClientWedged struct {
+	mockSlskdClient
+	deleted    int
+	deleteLive bool // Whether DeleteSearch got a context that was still usable
+}
+
+func (m *mockSlskdClientWedged) GetSearchResults(ctx context.Context, searchID string) ([]slskd.SearchResult, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (m *mockSlskdClientWedged) StreamSearchResults(ctx context.Context, searchID string, fn func(slskd.SearchResult) bool) error {
+	results, err := m.GetSearchResults(ctx, searchID)
+	return yieldResults(results, err, fn)
+}
+
+func (m *mockSlskdClientWedged) DeleteSearch(ctx context.Context, searchID string) error {
+	m.deleted++
+	m.deleteLive = ctx.Err() == nil
+	return nil
+}
+
+func TestQueueAlbum_PerAlbumTimeout(t *testing.T) {
+	slskdClient := &mockSlskdClientWedged{}
+	p := newTestProcessor(t, &mockLidarrClientWithAliases{tracks: []lidarr.Track{{Title: "First Song"}, {Title: "Second Song"}}}, slskdClient)
+	p.cfg.Timing.PerAlbumTimeoutSeconds = 1
+	p.cfg.Slskd.DeleteSearches = true
+	p.cfg.Search.WishlistOnDenylist = false
+	p.current = &RunSummary{}
+
+	album := concurrentAlbums(1)[0]
+	done := make(chan string)
+	go func() {
+		_, outcome := p.queueAlbum(context.Background(), album)
+		done <- outcome
+	}()
+
+	select {
+	case outcome := <-done:
+		if outcome != OutcomeFailed {
+			t.Errorf("expected outcome %q, got %q", OutcomeFailed, outcome)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("album search did not time out")
+	}
+
+	if d := p.current.Decisions[0]; d.Reason != ReasonTimeout {
+		t.Errorf("expected reason %q, got %q", ReasonTimeout, d.Reason)
+	}
+	if entry := p.denylist.GetEntry(album.ID); entry == nil || entry.Failures != 1 {
+		t.Errorf("expected a timed-out album to count as a failed search, got %+v", entry)
+	}
+	if slskdClient.deleted != 1 || !slskdClient.deleteLive {
+		t.Errorf("expected the search to be deleted with a live context, deleted %d, live %v", slskdClient.deleted, slskdClient.deleteLive)
+	}
+}
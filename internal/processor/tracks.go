@@ -0,0 +1,321 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/organizer"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// errTooFewTracks is returned when a track-by-track search can't reach minimum_track_fraction
+var errTooFewTracks = errors.New("track search found too few tracks")
+
+// trackCandidate is the file chosen for one track by a track search
+type trackCandidate struct {
+	track    lidarr.Track
+	username string
+	dir      string // Normalized to forward slashes
+	file     slskd.SearchFile
+	ratio    float64
+}
+
+// trackQuery builds the slskd search text for a single track
+func (p *Processor) trackQuery(album lidarr.Album, track lidarr.Track) string {
+	if p.cfg.Search.TrackPrependArtist && !isVariousArtists(album) {
+		return fmt.Sprintf("%s %s", album.Artist.ArtistName, track.Title)
+	}
+	return track.Title
+}
+
+// searchForTracks searches for each track individually and queues the best file
+// found for each one. Nothing is queued unless at least minimum_track_fraction of
+// the tracks were found, and searching stops as soon as that can't be reached
+func (p *Processor) searchForTracks(ctx context.Context, tracks []lidarr.Track, album lidarr.Album, release *lidarr.Release) (DownloadedItem, error) {
+	if len(tracks) == 0 {
+		return DownloadedItem{}, errNoMatch
+	}
+
+	required := int(math.Ceil(float64(len(tracks)) * p.cfg.Search.MinimumTrackFraction))
+	if required < 1 {
+		required = 1
+	}
+	allowedMisses := len(tracks) - required
+
+	p.logger.Info("falling back to track search",
+		"album", album.Title,
+		"artist", album.Artist.ArtistName,
+		"tracks", len(tracks),
+		"required", required)
+
+	var found []trackCandidate
+	misses := 0
+	for _, track := range tracks {
+		candidate, ok, err := p.findTrack(ctx, album, track)
+		if err != nil {
+			return DownloadedItem{}, err
+		}
+		if !ok {
+			misses++
+			p.logger.Debug("track not found", "album", album.Title, "track", track.Title)
+			if misses > allowedMisses {
+				p.logger.Info("giving up on track search",
+					"album", album.Title,
+					"found", len(found),
+					"missing", misses,
+					"required", required)
+				return DownloadedItem{}, errTooFewTracks
+			}
+			continue
+		}
+		found = append(found, candidate)
+	}
+
+	item, err := p.queueTrackCandidates(ctx, album, release, found, required)
+	if err != nil {
+		return DownloadedItem{}, err
+	}
+
+	if !p.cfg.DryRun {
+		p.logger.Info("queued album from track search",
+			"album", album.Title,
+			"artist", album.Artist.ArtistName,
+			"tracks", len(item.Tracks),
+			"of", len(tracks),
+			"sources", len(item.Sources))
+	}
+
+	return item, nil
+}
+
+// queueTrackCandidates enqueues the files chosen track by track, grouped by user,
+// and builds the album from the ones slskd accepted. It fails with
+// errTooFewTracks if fewer than required tracks could be enqueued, after
+// withdrawing the transfers that were
+func (p *Processor) queueTrackCandidates(ctx context.Context, album lidarr.Album, release *lidarr.Release, found []trackCandidate, required int) (DownloadedItem, error) {
+	// Enqueue per user
+	var usernames []string
+	filesByUser := make(map[string][]slskd.EnqueueFile)
+	for _, c := range found {
+		if _, ok := filesByUser[c.username]; !ok {
+			usernames = append(usernames, c.username)
+		}
+		filesByUser[c.username] = append(filesByUser[c.username], slskd.EnqueueFile{
+			Filename: c.file.Filename, // Keep original path for slskd
+			Size:     c.file.Size,
+		})
+	}
+
+	if !p.cfg.DryRun {
+		if err := p.approve(ctx, p.trackMatch(ctx, album, release, found)); err != nil {
+			return DownloadedItem{}, err
+		}
+		var size int64
+		for _, c := range found {
+			size += c.file.Size
+		}
+		if err := p.reserveSpace(album.Title, size); err != nil {
+			return DownloadedItem{}, err
+		}
+	}
+
+	failedUsers := make(map[string]bool)
+	enqueued := make(map[string]*slskd.EnqueueResult)
+	for _, username := range usernames {
+		if p.cfg.DryRun {
+			p.logDryRunTracks(album, username, found)
+			continue
+		}
+		result, err := p.enqueue(ctx, username, filesByUser[username])
+		if err != nil {
+			p.logger.Warn("failed to enqueue track downloads", "album", album.Title, "username", username, "error", err)
+			failedUsers[username] = true
+			p.markRefused(username)
+			for _, file := range filesByUser[username] {
+				p.releaseSpace(file.Size)
+			}
+			continue
+		}
+		enqueued[username] = result
+	}
+
+	item := DownloadedItem{
+		ArtistID:    album.ArtistID,
+		ArtistName:  album.Artist.ArtistName,
+		AlbumName:   album.Title,
+		AlbumID:     album.ID,
+		ReleaseID:   release.ID,
+		MediumCount: release.MediumCount,
+	}
+	for _, c := range found {
+		if failedUsers[c.username] {
+			continue
+		}
+
+		folder := localFolder(c.dir)
+		if item.FolderName == "" {
+			item.FolderName = folder
+			item.Quality = p.filterFor(ctx).MatchedFiletype(c.file)
+		}
+		if !item.hasSource(c.username, c.dir) {
+			item.Sources = append(item.Sources, DownloadSource{Username: c.username, Directory: c.dir})
+		}
+		item.Tracks = append(item.Tracks, organizer.DownloadedTrack{
+			Filename:     filepath.Base(strings.ReplaceAll(c.file.Filename, "\\", "/")),
+			MediumNumber: c.track.MediumNumber,
+			Folder:       folder,
+		})
+	}
+
+	if len(item.Tracks) < required {
+		p.withdrawTracks(ctx, album, enqueued, filesByUser)
+		return DownloadedItem{}, errTooFewTracks
+	}
+
+	return item, nil
+}
+
+// withdrawTracks cancels the transfers of a track search that fell short and
+// gives back the room reserved for them
+func (p *Processor) withdrawTracks(ctx context.Context, album lidarr.Album, enqueued map[string]*slskd.EnqueueResult, filesByUser map[string][]slskd.EnqueueFile) {
+	for username, result := range enqueued {
+		for _, file := range result.Enqueued {
+			if file.ID == "" {
+				continue
+			}
+			if err := p.slskd.CancelDownload(ctx, username, file.ID); err != nil {
+				p.logger.Warn("failed to cancel track download", "album", album.Title, "username", username, "file", file.Filename, "error", err)
+			}
+		}
+		for _, file := range filesByUser[username] {
+			p.releaseSpace(file.Size)
+		}
+	}
+}
+
+// logDryRunTracks logs what a track search would have downloaded from one user
+func (p *Processor) logDryRunTracks(album lidarr.Album, username string, found []trackCandidate) {
+	var dirs []string
+	var totalBytes int64
+	totalRatio := 0.0
+	files := 0
+	for _, c := range found {
+		if c.username != username {
+			continue
+		}
+		if !slices.Contains(dirs, c.dir) {
+			dirs = append(dirs, c.dir)
+		}
+		totalBytes += c.file.Size
+		totalRatio += c.ratio
+		files++
+	}
+
+	p.logger.Info("dry run: would download",
+		"album", album.Title,
+		"artist", album.Artist.ArtistName,
+		"username", username,
+		"directory", strings.Join(dirs, ", "),
+		"files", files,
+		"bytes", totalBytes,
+		"ratio", fmt.Sprintf("%.2f", totalRatio/float64(files)))
+}
+
+// findTrack searches for a single track and returns the best matching file
+func (p *Processor) findTrack(ctx context.Context, album lidarr.Album, track lidarr.Track) (trackCandidate, bool, error) {
+	results, err := p.runSearch(ctx, album, p.trackQuery(album, track))
+	if err != nil {
+		if ctx.Err() != nil {
+			return trackCandidate{}, false, ctx.Err()
+		}
+		if isUnavailable(err) {
+			return trackCandidate{}, false, err
+		}
+		return trackCandidate{}, false, nil
+	}
+
+	best, ok := p.bestTrackFile(ctx, album, track, results)
+	return best, ok, nil
+}
+
+// bestTrackFile returns the file in results allowed by the album's filter that
+// best matches one of its tracks. A cutoff search only takes files better than
+// those on disk
+func (p *Processor) bestTrackFile(ctx context.Context, album lidarr.Album, track lidarr.Track, results []slskd.SearchResult) (trackCandidate, bool) {
+	f := p.filterFor(ctx)
+	existing, upgrading := existingQualityFor(ctx)
+	trackMatcher := p.matcherFor(album)
+	var best trackCandidate
+	bestRatio := 0.0
+	for _, result := range results {
+		if p.isIgnoredUser(result.Username) || p.userRefused(result.Username) {
+			continue
+		}
+		if _, busy := p.busyReason(result); busy {
+			continue
+		}
+
+		filtered, _ := f.FilterFilesDebug(unlockedFiles(result.Files))
+		filtered = p.dropImplausibleFiles(album, result.Username, filtered)
+		for _, file := range filtered {
+			if upgrading && f.Rank(file) >= existing.rank {
+				continue
+			}
+			normalizedPath := strings.ReplaceAll(file.Filename, "\\", "/")
+			matched, ratio := trackMatcher.MatchTracks([]string{track.Title}, []string{filepath.Base(normalizedPath)})
+			if matched && ratio > bestRatio {
+				bestRatio = ratio
+				best = trackCandidate{
+					track:    track,
+					username: result.Username,
+					dir:      filepath.Dir(normalizedPath),
+					file:     file,
+					ratio:    ratio,
+				}
+			}
+		}
+	}
+
+	return best, bestRatio > 0
+}
+
+// markRefused remembers for the rest of the run that a user refused a download
+func (p *Processor) markRefused(username string) {
+	p.refusedMu.Lock()
+	defer p.refusedMu.Unlock()
+	if p.refusedUsers == nil {
+		p.refusedUsers = make(map[string]bool)
+	}
+	p.refusedUsers[username] = true
+}
+
+// userRefused reports whether a user refused a download earlier in the run
+func (p *Processor) userRefused(username string) bool {
+	p.refusedMu.Lock()
+	defer p.refusedMu.Unlock()
+	return p.refusedUsers[username]
+}
+
+// resetRefusedUsers forgets refused users at the start of a run
+func (p *Processor) resetRefusedUsers() {
+	p.refusedMu.Lock()
+	defer p.refusedMu.Unlock()
+	p.refusedUsers = nil
+}
+
+// isIgnoredUser reports whether results from username should be skipped
+func (p *Processor) isIgnoredUser(username string) bool {
+	for _, ignoredUser := range p.cfg.Search.IgnoredUsers {
+		if strings.EqualFold(username, ignoredUser) {
+			return true
+		}
+	}
+	return p.reputation.IsIgnored(username, time.Now())
+}
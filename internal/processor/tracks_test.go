@@ -0,0 +1,191 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// mockSlskdClientWithResults returns fixed results per search text and records enqueues
+type mockSlskdClientWithResults struct {
+	mockSlskdClient
+	results  map[string][]slskd.SearchResult
+	searches []string
+	enqueued map[string][]slskd.EnqueueFile
+}
+
+func (m *mockSlskdClientWithResults) Search(ctx context.Context, req slskd.SearchRequest) (*slskd.SearchResponse, error) {
+	m.searches = append(m.searches, req.SearchText)
+	return &slskd.SearchResponse{ID: req.SearchText}, nil
+}
+
+func (m *mockSlskdClientWithResults) GetSearchResults(ctx context.Context, searchID string) ([]slskd.SearchResult, error) {
+	return m.results[searchID], nil
+}
+
+func (m *mockSlskdClientWithResults) StreamSearchResults(ctx context.Context, searchID string, fn func(slskd.SearchResult) bool) error {
+	results, err := m.GetSearchResults(ctx, searchID)
+	return yieldResults(results, err, fn)
+}
+
+func (m *mockSlskdClientWithResults) EnqueueDownloads(ctx context.Context, username string, files []slskd.EnqueueFile) (*slskd.EnqueueResult, error) {
+	if m.enqueued == nil {
+		m.enqueued = make(map[string][]slskd.EnqueueFile)
+	}
+	m.enqueued[username] = append(m.enqueued[username], files...)
+	return &slskd.EnqueueResult{}, nil
+}
+
+func trackResult(username, filename string) []slskd.SearchResult {
+	return []slskd.SearchResult{{Username: username, Files: []slskd.SearchFile{{Filename: filename, Size: 100}}}}
+}
+
+func trackSearchAlbum() (lidarr.Album, []lidarr.Track) {
+	album := lidarr.Album{
+		ID:       5,
+		Title:    "Album",
+		Artist:   lidarr.Artist{ArtistName: "Artist"},
+		Releases: []lidarr.Release{{Status: "Official", TrackCount: 4, MediumCount: 1}},
+	}
+	tracks := []lidarr.Track{
+		{Title: "First Song", MediumNumber: 1},
+		{Title: "Second Song", MediumNumber: 1},
+		{Title: "Third Song", MediumNumber: 1},
+		{Title: "Fourth Song", MediumNumber: 1},
+	}
+	return album, tracks
+}
+
+func TestQueueAlbum_FallsBackToTrackSearch(t *testing.T) {
+	album, tracks := trackSearchAlbum()
+	slskdClient := &mockSlskdClientWithResults{results: map[string][]slskd.SearchResult{
+		"Artist First Song":  trackResult("alice", `Music\Album\01 - First Song.flac`),
+		"Artist Second Song": trackResult("alice", `Music\Album\02 - Second Song.flac`),
+		"Artist Fourth Song": trackResult("bob", `Shared\Singles\Fourth Song.flac`),
+	}}
+	p := newTestProcessor(t, &mockLidarrClientWithAliases{tracks: tracks}, slskdClient)
+	p.cfg.Search.SearchForTracks = true
+	p.cfg.Search.TrackPrependArtist = true
+	p.cfg.Search.MinimumTrackFraction = 0.75
+	p.current = &RunSummary{}
+
+	item, outcome := p.queueAlbum(context.Background(), album)
+	if outcome != OutcomeQueued {
+		t.Fatalf("expected album to be queued from track search, got %q", outcome)
+	}
+
+	if len(item.Tracks) != 3 {
+		t.Errorf("expected 3 tracks, got %d", len(item.Tracks))
+	}
+	if len(item.Sources) != 2 {
+		t.Errorf("expected 2 sources, got %+v", item.Sources)
+	}
+	if item.FolderName != "Album" || item.Tracks[2].Folder != "Singles" {
+		t.Errorf("unexpected folders: item %q, last track %q", item.FolderName, item.Tracks[2].Folder)
+	}
+	if len(slskdClient.enqueued["alice"]) != 2 || len(slskdClient.enqueued["bob"]) != 1 {
+		t.Errorf("expected files enqueued per user, got %v", slskdClient.enqueued)
+	}
+}
+
+func TestSearchForTracks_StopsWhenMinimumUnreachable(t *testing.T) {
+	album, tracks := trackSearchAlbum()
+	slskdClient := &mockSlskdClientWithResults{results: map[string][]slskd.SearchResult{
+		"Fourth Song": trackResult("alice", `Music\Album\04 - Fourth Song.flac`),
+	}}
+	p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+	p.cfg.Search.MinimumTrackFraction = 0.75
+
+	_, err := p.searchForTracks(context.Background(), tracks, album, &album.Releases[0])
+	if err != errTooFewTracks {
+		t.Fatalf("expected errTooFewTracks, got %v", err)
+	}
+
+	// One miss is allowed; the second makes 3 of 4 unreachable
+	if len(slskdClient.searches) != 2 {
+		t.Errorf("expected 2 searches before giving up, got %v", slskdClient.searches)
+	}
+	if len(slskdClient.enqueued) != 0 {
+		t.Errorf("nothing should be enqueued, got %v", slskdClient.enqueued)
+	}
+}
+
+// mockSlskdClientPartialEnqueue accepts alice's files, refuses everyone
+// else's and records cancelled transfers
+type mockSlskdClientPartialEnqueue struct {
+	mockSlskdClient
+	cancelled []string
+}
+
+func (m *mockSlskdClientPartialEnqueue) EnqueueDownloads(ctx context.Context, username string, files []slskd.EnqueueFile) (*slskd.EnqueueResult, error) {
+	if username != "alice" {
+		return nil, errors.New("peer refused")
+	}
+	result := &slskd.EnqueueResult{}
+	for i, file := range files {
+		result.Enqueued = append(result.Enqueued, slskd.DownloadFile{ID: fmt.Sprintf("%s-%d", username, i), Filename: file.Filename})
+	}
+	return result, nil
+}
+
+func (m *mockSlskdClientPartialEnqueue) CancelDownload(ctx context.Context, username, downloadID string) error {
+	m.cancelled = append(m.cancelled, downloadID)
+	return nil
+}
+
+func TestQueueTrackCandidates_WithdrawsWhenTooFewEnqueued(t *testing.T) {
+	album, tracks := trackSearchAlbum()
+	slskdClient := &mockSlskdClientPartialEnqueue{}
+	p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+
+	var found []trackCandidate
+	for i, track := range tracks {
+		username := "alice"
+		if i >= 2 {
+			username = "bob"
+		}
+		found = append(found, trackCandidate{
+			track:    track,
+			username: username,
+			dir:      "Music/Album",
+			file:     slskd.SearchFile{Filename: fmt.Sprintf(`Music\Album\0%d - %s.flac`, i+1, track.Title), Size: 100},
+		})
+	}
+
+	_, err := p.queueTrackCandidates(context.Background(), album, &album.Releases[0], found, 3)
+	if err != errTooFewTracks {
+		t.Fatalf("expected errTooFewTracks, got %v", err)
+	}
+	slices.Sort(slskdClient.cancelled)
+	if !slices.Equal(slskdClient.cancelled, []string{"alice-0", "alice-1"}) {
+		t.Errorf("expected alice's transfers cancelled, got %v", slskdClient.cancelled)
+	}
+	if p.reservedBytes != 0 {
+		t.Errorf("expected the reserved space released, %d bytes still reserved", p.reservedBytes)
+	}
+}
+
+func TestTrackQuery(t *testing.T) {
+	p := newTestProcessor(t, &mockLidarrClient{}, &mockSlskdClient{})
+	album := lidarr.Album{Artist: lidarr.Artist{ArtistName: "Artist"}}
+	track := lidarr.Track{Title: "Song"}
+
+	if got := p.trackQuery(album, track); got != "Song" {
+		t.Errorf("trackQuery() = %q, want %q", got, "Song")
+	}
+
+	p.cfg.Search.TrackPrependArtist = true
+	if got := p.trackQuery(album, track); got != "Artist Song" {
+		t.Errorf("trackQuery() with track_prepend_artist = %q, want %q", got, "Artist Song")
+	}
+
+	compilation := lidarr.Album{Artist: lidarr.Artist{ArtistName: "Various Artists"}}
+	if got := p.trackQuery(compilation, track); got != "Song" {
+		t.Errorf("trackQuery() for a compilation = %q, want %q", got, "Song")
+	}
+}
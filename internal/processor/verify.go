@@ -0,0 +1,29 @@
+package processor
+
+import (
+	"fmt"
+	"os"
+)
+
+// sizeTolerance is how far, as a fraction of the reported size, a downloaded
+// file's size may be off before it counts as truncated
+const sizeTolerance = 0.01
+
+// verifyDownload checks that a file slskd reports as complete exists on disk
+// with the size slskd reported. A size of zero means slskd did not report one
+func (p *Processor) verifyDownload(file sourceFile) error {
+	path := p.localPath(file.directory, file.Filename)
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("stat downloaded file: %w", err)
+	}
+
+	if file.Size > 0 {
+		diff := info.Size() - file.Size
+		if float64(max(diff, -diff)) > float64(file.Size)*sizeTolerance {
+			return fmt.Errorf("%s is %d bytes, expected %d", path, info.Size(), file.Size)
+		}
+	}
+
+	return nil
+}
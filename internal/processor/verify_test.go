@@ -0,0 +1,98 @@
+package processor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// writeDownloadedFile creates a file of the given size where slskd would have saved it
+func writeDownloadedFile(t *testing.T, p *Processor, folder, name string, size int) {
+	t.Helper()
+	dir := filepath.Join(p.downloadDir, folder)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), make([]byte, size), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifyDownload(t *testing.T) {
+	tests := []struct {
+		name     string
+		onDisk   int // -1 for a missing file
+		reported int64
+		wantErr  bool
+	}{
+		{name: "matching size", onDisk: 1000, reported: 1000},
+		{name: "within tolerance", onDisk: 995, reported: 1000},
+		{name: "truncated", onDisk: 500, reported: 1000, wantErr: true},
+		{name: "missing", onDisk: -1, reported: 1000, wantErr: true},
+		{name: "size not reported", onDisk: 10, reported: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestProcessor(t, &mockLidarrClient{}, &mockSlskdClient{})
+			if tt.onDisk >= 0 {
+				writeDownloadedFile(t, p, "Album", "01.flac", tt.onDisk)
+			}
+
+			file := sourceFile{
+				DownloadFile: slskd.DownloadFile{Filename: `Music\Album\01.flac`, Size: tt.reported},
+				username:     "user",
+				directory:    "Music/Album",
+			}
+			if err := p.verifyDownload(file); (err != nil) != tt.wantErr {
+				t.Errorf("verifyDownload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// mockSlskdClientTruncated reports one complete file, counting retries
+type mockSlskdClientTruncated struct {
+	mockSlskdClient
+	retries int
+}
+
+func (m *mockSlskdClientTruncated) GetDownloads(ctx context.Context) (slskd.DownloadsResponse, error) {
+	return slskd.DownloadsResponse{{Username: "user", Directories: []slskd.DirectoryDownloads{{
+		Directory: `Music\Album`,
+		Files:     []slskd.DownloadFile{{ID: "file-1", Filename: `Music\Album\01.flac`, State: "Completed, Succeeded", Size: 1000}},
+	}}}}, nil
+}
+
+func (m *mockSlskdClientTruncated) EnqueueDownloads(ctx context.Context, username string, files []slskd.EnqueueFile) (*slskd.EnqueueResult, error) {
+	m.retries++
+	return &slskd.EnqueueResult{}, nil
+}
+
+func TestMonitorDownloads_RetriesTruncatedFiles(t *testing.T) {
+	slskdClient := &mockSlskdClientTruncated{}
+	p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+	p.cfg.Slskd.StalledTimeout = 60
+	writeDownloadedFile(t, p, "Album", "01.flac", 400)
+
+	downloadList := []DownloadedItem{{AlbumID: 1, FolderName: "Album", Sources: []DownloadSource{{Username: "user", Directory: "Music/Album"}}}}
+	succeeded, err := p.monitorDownloads(context.Background(), downloadList, nil, func(DownloadedItem) {
+		t.Error("a truncated album must not be organized")
+	})
+	if err != nil {
+		t.Fatalf("monitorDownloads() error: %v", err)
+	}
+
+	if len(succeeded) != 0 {
+		t.Errorf("expected no verified albums, got %+v", succeeded)
+	}
+	if slskdClient.retries != 3 {
+		t.Errorf("expected the truncated file retried 3 times, got %d", slskdClient.retries)
+	}
+	if _, err := os.Stat(filepath.Join(p.downloadDir, "Album", "01.flac")); !os.IsNotExist(err) {
+		t.Errorf("expected the truncated file removed before retrying, stat error: %v", err)
+	}
+}
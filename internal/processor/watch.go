@@ -0,0 +1,76 @@
+package processor
+
+import (
+	"context"
+	"slices"
+
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// retryWatch holds the files of items whose failed files were re-enqueued,
+// keyed by item, so they can be checked one transfer at a time instead of
+// listing every download in slskd
+type retryWatch map[int][]sourceFile
+
+// transferKey identifies a file queued from a user
+func transferKey(username, filename string) string {
+	return username + "\x00" + filename
+}
+
+// watch follows an item's files by transfer ID once its failed files are
+// re-enqueued, or stops following it if slskd didn't report every new transfer
+func (w retryWatch) watch(idx int, kept, retried []sourceFile, requeued map[string]slskd.DownloadFile) {
+	files := slices.Clone(kept)
+	for _, file := range retried {
+		transfer, ok := requeued[transferKey(file.username, file.Filename)]
+		if !ok || transfer.ID == "" {
+			delete(w, idx)
+			return
+		}
+		files = append(files, sourceFile{username: file.username, directory: file.directory, DownloadFile: transfer})
+	}
+	w[idx] = files
+}
+
+// covers reports whether every pending item is watched, so the downloads
+// listing can be skipped
+func (w retryWatch) covers(pending map[int]bool) bool {
+	watching := false
+	for idx, ok := range pending {
+		if !ok {
+			continue
+		}
+		if _, watched := w[idx]; !watched {
+			return false
+		}
+		watching = true
+	}
+	return watching
+}
+
+// refreshWatched fetches the current state of each pending item's unfinished
+// files. An item whose transfer can't be looked up stops being watched, so
+// the downloads are listed again
+func (p *Processor) refreshWatched(ctx context.Context, w retryWatch, pending map[int]bool) {
+	for idx, files := range w {
+		if !pending[idx] {
+			delete(w, idx)
+			continue
+		}
+		for i, file := range files {
+			if file.IsCompleted() {
+				continue
+			}
+			current, err := p.slskd.GetDownload(ctx, file.username, file.ID)
+			if err != nil {
+				p.logger.Debug("failed to look up retried download, listing all downloads",
+					"username", file.username,
+					"file", file.Filename,
+					"error", err)
+				delete(w, idx)
+				break
+			}
+			files[i].DownloadFile = *current
+		}
+	}
+}
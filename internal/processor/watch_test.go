@@ -0,0 +1,126 @@
+package processor
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// mockSlskdClientRetrying fails one file of an album, which completes once
+// re-enqueued as transfer "5"
+type mockSlskdClientRetrying struct {
+	mockSlskdClient
+	reportIDs bool // EnqueueDownloads returns the new transfers
+	requeued  bool
+	listings  int
+	lookups   []string
+}
+
+func (m *mockSlskdClientRetrying) GetDownloads(ctx context.Context) (slskd.DownloadsResponse, error) {
+	m.listings++
+	retried := slskd.DownloadFile{ID: "2", Filename: `Music\Album\02.flac`, State: "Completed, Errored"}
+	if m.requeued {
+		retried = slskd.DownloadFile{ID: "5", Filename: `Music\Album\02.flac`, State: "Completed, Succeeded"}
+	}
+	return slskd.DownloadsResponse{{Username: "user", Directories: []slskd.DirectoryDownloads{{
+		Directory: `Music\Album`,
+		Files: []slskd.DownloadFile{
+			{ID: "1", Filename: `Music\Album\01.flac`, State: "Completed, Succeeded"},
+			retried,
+		},
+	}}}}, nil
+}
+
+func (m *mockSlskdClientRetrying) EnqueueDownloads(ctx context.Context, username string, files []slskd.EnqueueFile) (*slskd.EnqueueResult, error) {
+	m.requeued = true
+	result := &slskd.EnqueueResult{}
+	if m.reportIDs {
+		for _, file := range files {
+			result.Enqueued = append(result.Enqueued, slskd.DownloadFile{ID: "5", Filename: file.Filename, State: "Queued, Locally"})
+		}
+	}
+	return result, nil
+}
+
+func (m *mockSlskdClientRetrying) GetDownload(ctx context.Context, username, downloadID string) (*slskd.DownloadFile, error) {
+	m.lookups = append(m.lookups, downloadID)
+	if downloadID != "5" {
+		return nil, slskd.ErrNotFound
+	}
+	return &slskd.DownloadFile{ID: "5", Filename: `Music\Album\02.flac`, State: "Completed, Succeeded"}, nil
+}
+
+func TestMonitorDownloads_RetriedFilesCheckedByID(t *testing.T) {
+	tests := []struct {
+		name         string
+		reportIDs    bool
+		wantListings int
+		wantLookups  int
+	}{
+		{name: "new transfer IDs known", reportIDs: true, wantListings: 1, wantLookups: 1},
+		{name: "new transfer IDs unknown", reportIDs: false, wantListings: 2, wantLookups: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slskdClient := &mockSlskdClientRetrying{reportIDs: tt.reportIDs}
+			p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+			p.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+			p.cfg.Slskd.StalledTimeout = 60
+			p.cfg.Download.MaxFileRetries = intPtr(1)
+			writeDownloadedFile(t, p, "Album", "01.flac", 0)
+			writeDownloadedFile(t, p, "Album", "02.flac", 0)
+
+			downloadList := []DownloadedItem{{AlbumID: 3, FolderName: "Album", Sources: []DownloadSource{{Username: "user", Directory: "Music/Album"}}}}
+			succeeded, err := p.monitorDownloads(context.Background(), downloadList, nil, nil)
+			if err != nil {
+				t.Fatalf("monitorDownloads() error: %v", err)
+			}
+			if len(succeeded) != 1 {
+				t.Fatalf("expected the album to finish, got %d succeeded", len(succeeded))
+			}
+
+			if slskdClient.listings != tt.wantListings {
+				t.Errorf("expected %d downloads listings, got %d", tt.wantListings, slskdClient.listings)
+			}
+			if len(slskdClient.lookups) != tt.wantLookups {
+				t.Errorf("expected %d transfer lookups, got %v", tt.wantLookups, slskdClient.lookups)
+			}
+		})
+	}
+}
+
+func TestMonitorDownloads_RetriedLookupFailureListsDownloads(t *testing.T) {
+	slskdClient := &mockSlskdClientRetryLookupFailing{mockSlskdClientRetrying{reportIDs: true}}
+	p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+	p.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	p.cfg.Slskd.StalledTimeout = 60
+	p.cfg.Download.MaxFileRetries = intPtr(1)
+	writeDownloadedFile(t, p, "Album", "01.flac", 0)
+	writeDownloadedFile(t, p, "Album", "02.flac", 0)
+
+	downloadList := []DownloadedItem{{AlbumID: 3, FolderName: "Album", Sources: []DownloadSource{{Username: "user", Directory: "Music/Album"}}}}
+	succeeded, err := p.monitorDownloads(context.Background(), downloadList, nil, nil)
+	if err != nil {
+		t.Fatalf("monitorDownloads() error: %v", err)
+	}
+	if len(succeeded) != 1 {
+		t.Fatalf("expected the album to finish from the listing, got %d succeeded", len(succeeded))
+	}
+	if slskdClient.listings != 2 {
+		t.Errorf("expected the downloads to be listed again after the lookup failed, got %d listings", slskdClient.listings)
+	}
+}
+
+// mockSlskdClientRetryLookupFailing can't look up single transfers
+type mockSlskdClientRetryLookupFailing struct {
+	mockSlskdClientRetrying
+}
+
+func (m *mockSlskdClientRetryLookupFailing) GetDownload(ctx context.Context, username, downloadID string) (*slskd.DownloadFile, error) {
+	m.lookups = append(m.lookups, downloadID)
+	return nil, slskd.ErrNotFound
+}
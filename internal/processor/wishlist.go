@@ -0,0 +1,203 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// wishlistPageSize is the page size used when fetching the full wanted list
+const wishlistPageSize = 100
+
+// wishlistTagPrefix starts the filter term that marks a wishlist entry as
+// seekarr's, followed by the album ID. The term excludes files named after
+// it, which no real file is, so it doesn't narrow the results
+const wishlistTagPrefix = "-seekarr-album-"
+
+// wishlistTag returns the filter term marking an album's wishlist entry
+func wishlistTag(albumID int) string {
+	return wishlistTagPrefix + strconv.Itoa(albumID)
+}
+
+// wishlistTagAlbum returns the album a wishlist entry was created for, or
+// false if seekarr didn't create it
+func wishlistTagAlbum(entry slskd.WishlistEntry) (int, bool) {
+	for _, term := range strings.Fields(entry.Filter) {
+		rest, ok := strings.CutPrefix(term, wishlistTagPrefix)
+		if !ok {
+			continue
+		}
+		if albumID, err := strconv.Atoi(rest); err == nil {
+			return albumID, true
+		}
+	}
+	return 0, false
+}
+
+// registerWishlist hands a denylisted album off to slskd's wishlist so it keeps
+// being searched in the background
+func (p *Processor) registerWishlist(ctx context.Context, album lidarr.Album) {
+	if p.wishlist.Get(album.ID) != nil {
+		return // Already registered
+	}
+
+	query := albumQuery(album)
+	entry, err := p.slskd.CreateWishlistEntry(ctx, query, wishlistTag(album.ID))
+	if err != nil {
+		p.logger.Warn("failed to register wishlist search",
+			"album", album.Title,
+			"artist", album.Artist.ArtistName,
+			"error", err)
+		return
+	}
+
+	p.wishlist.Add(album.ID, entry.ID, query)
+	p.logger.Info("registered wishlist search for denylisted album",
+		"album", album.Title,
+		"artist", album.Artist.ArtistName,
+		"query", query,
+		"entryID", entry.ID)
+}
+
+// reconcileWishlist removes seekarr's wishlist entries for albums that are no
+// longer in Lidarr's wanted list (i.e. they have since been found and imported)
+// Only entries carrying seekarr's tag in slskd are deleted, so a user's own
+// wishlist items are left alone. Albums seekarr unmonitored with
+// remove_wanted_on_failure left the wanted list unfound, so their entries are
+// kept until Lidarr holds files for them
+func (p *Processor) reconcileWishlist(ctx context.Context) {
+	entries, err := p.slskd.GetWishlist(ctx)
+	if err != nil {
+		p.logger.Warn("failed to fetch slskd wishlist, skipping wishlist cleanup", "error", err)
+		return
+	}
+	changed := p.syncWishlistRecords(entries)
+
+	records := p.wishlist.Records()
+	if len(records) == 0 {
+		p.saveWishlist(changed)
+		return
+	}
+
+	wanted, err := p.fetchWantedIDs(ctx)
+	if err != nil {
+		p.logger.Warn("failed to fetch wanted list, skipping wishlist cleanup", "error", err)
+		p.saveWishlist(changed)
+		return
+	}
+
+	for _, record := range records {
+		if wanted[record.AlbumID] {
+			continue
+		}
+		if record.Unmonitored && !p.albumImported(ctx, record.AlbumID) {
+			continue
+		}
+
+		if err := p.slskd.DeleteWishlistEntry(ctx, record.EntryID); err != nil {
+			p.logger.Warn("failed to remove wishlist search",
+				"albumID", record.AlbumID,
+				"entryID", record.EntryID,
+				"error", err)
+			continue
+		}
+
+		p.wishlist.Remove(record.AlbumID)
+		changed = true
+		p.logger.Info("removed wishlist search for album no longer wanted",
+			"albumID", record.AlbumID,
+			"query", record.SearchText)
+	}
+
+	p.saveWishlist(changed)
+}
+
+// syncWishlistRecords matches the local records against the entries in slskd
+// Records whose entry is gone or no longer carries seekarr's tag are
+// forgotten, and tagged entries without a record, such as after the records
+// file was lost, are taken back. It reports whether the records changed
+func (p *Processor) syncWishlistRecords(entries []slskd.WishlistEntry) bool {
+	tagged := make(map[string]int)
+	for _, entry := range entries {
+		if albumID, ok := wishlistTagAlbum(entry); ok {
+			tagged[entry.ID] = albumID
+		}
+	}
+
+	changed := false
+	for _, record := range p.wishlist.Records() {
+		if albumID, ok := tagged[record.EntryID]; ok && albumID == record.AlbumID {
+			continue
+		}
+		p.logger.Info("forgetting wishlist search no longer tagged as seekarr's in slskd",
+			"albumID", record.AlbumID,
+			"entryID", record.EntryID,
+			"query", record.SearchText)
+		p.wishlist.Remove(record.AlbumID)
+		changed = true
+	}
+
+	for _, entry := range entries {
+		albumID, ok := tagged[entry.ID]
+		if !ok || p.wishlist.Get(albumID) != nil {
+			continue
+		}
+		p.logger.Info("recovered wishlist search from slskd",
+			"albumID", albumID,
+			"entryID", entry.ID,
+			"query", entry.SearchText)
+		p.wishlist.Add(albumID, entry.ID, entry.SearchText)
+		// Whether seekarr unmonitored the album was lost with the record, so
+		// the entry is kept until Lidarr holds files for the album
+		p.wishlist.MarkUnmonitored(albumID)
+		changed = true
+	}
+
+	return changed
+}
+
+// saveWishlist persists the wishlist records if they changed
+func (p *Processor) saveWishlist(changed bool) {
+	if !changed {
+		return
+	}
+	if err := p.wishlist.Save(); err != nil {
+		p.logger.Warn("failed to save wishlist", "error", err)
+	}
+}
+
+// albumImported reports whether Lidarr holds any files for an album, taking
+// an album it can't check as not imported
+func (p *Processor) albumImported(ctx context.Context, albumID int) bool {
+	files, err := p.lidarr.GetTrackFiles(ctx, albumID)
+	if err != nil {
+		p.logger.Debug("failed to fetch track files of unmonitored album", "albumID", albumID, "error", err)
+		return false
+	}
+	return len(files) > 0
+}
+
+// fetchWantedIDs fetches the whole wanted list of each configured search
+// source and returns the set of album IDs
+func (p *Processor) fetchWantedIDs(ctx context.Context) (map[int]bool, error) {
+	wanted := make(map[int]bool)
+
+	for _, source := range p.searchSources() {
+		albums, err := p.lidarr.GetAllWanted(ctx, lidarr.GetWantedOptions{
+			PageSize: wishlistPageSize,
+			Missing:  source != SourceCutoffUnmet,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("fetch %s: %w", source, err)
+		}
+		for _, album := range albums {
+			wanted[album.ID] = true
+		}
+	}
+
+	return wanted, nil
+}
@@ -0,0 +1,201 @@
+package processor
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/yuritomanek/seekarr/internal/lidarr"
+	"github.com/yuritomanek/seekarr/internal/slskd"
+)
+
+// mockSlskdClientWithWishlist keeps a wishlist and tracks calls changing it
+type mockSlskdClientWithWishlist struct {
+	mockSlskdClient
+	entries []slskd.WishlistEntry
+	created []string
+	deleted []string
+}
+
+func (m *mockSlskdClientWithWishlist) GetWishlist(ctx context.Context) ([]slskd.WishlistEntry, error) {
+	return m.entries, nil
+}
+
+func (m *mockSlskdClientWithWishlist) CreateWishlistEntry(ctx context.Context, searchText, filter string) (*slskd.WishlistEntry, error) {
+	m.created = append(m.created, searchText)
+	entry := slskd.WishlistEntry{ID: "wish-" + searchText, SearchText: searchText, Filter: filter}
+	m.entries = append(m.entries, entry)
+	return &entry, nil
+}
+
+func (m *mockSlskdClientWithWishlist) DeleteWishlistEntry(ctx context.Context, id string) error {
+	m.deleted = append(m.deleted, id)
+	m.entries = slices.DeleteFunc(m.entries, func(entry slskd.WishlistEntry) bool { return entry.ID == id })
+	return nil
+}
+
+// mockLidarrClientWithWanted returns a fixed wanted list
+type mockLidarrClientWithWanted struct {
+	mockLidarrClient
+	wanted []lidarr.Album
+}
+
+func (m *mockLidarrClientWithWanted) GetWanted(ctx context.Context, opts lidarr.GetWantedOptions) (*lidarr.WantedResponse, error) {
+	return &lidarr.WantedResponse{Records: m.wanted, TotalRecords: len(m.wanted)}, nil
+}
+
+func (m *mockLidarrClientWithWanted) GetAllWanted(ctx context.Context, opts lidarr.GetWantedOptions) ([]lidarr.Album, error) {
+	return m.wanted, nil
+}
+
+func TestRecordSearchFailure_RegistersWishlistAtLimit(t *testing.T) {
+	slskdClient := &mockSlskdClientWithWishlist{}
+	p := newTestProcessor(t, &mockLidarrClient{}, slskdClient)
+
+	album := lidarr.Album{ID: 42, Title: "Rare Album", Artist: lidarr.Artist{ArtistName: "Obscure Artist"}}
+	ctx := context.Background()
+
+	p.recordSearchFailure(ctx, album)
+	if len(slskdClient.created) != 0 {
+		t.Fatalf("wishlist entry created before reaching failure limit")
+	}
+
+	p.recordSearchFailure(ctx, album)
+	if len(slskdClient.created) != 1 {
+		t.Fatalf("expected 1 wishlist entry at failure limit, got %d", len(slskdClient.created))
+	}
+	if slskdClient.created[0] != "Obscure Artist Rare Album" {
+		t.Errorf("unexpected wishlist search text %q", slskdClient.created[0])
+	}
+	if albumID, ok := wishlistTagAlbum(slskdClient.entries[0]); !ok || albumID != 42 {
+		t.Errorf("expected the entry tagged for album 42, got filter %q", slskdClient.entries[0].Filter)
+	}
+
+	// Further failures must not register duplicates
+	p.recordSearchFailure(ctx, album)
+	if len(slskdClient.created) != 1 {
+		t.Errorf("expected wishlist entry to be registered once, got %d", len(slskdClient.created))
+	}
+}
+
+func TestReconcileWishlist(t *testing.T) {
+	slskdClient := &mockSlskdClientWithWishlist{entries: []slskd.WishlistEntry{
+		{ID: "wish-1", SearchText: "Artist One Album", Filter: wishlistTag(1)},
+		{ID: "wish-2", SearchText: "Artist Two Album", Filter: wishlistTag(2)},
+	}}
+	lidarrClient := &mockLidarrClientWithWanted{
+		wanted: []lidarr.Album{{ID: 1}}, // Album 1 still wanted, album 2 was imported
+	}
+	p := newTestProcessor(t, lidarrClient, slskdClient)
+
+	p.wishlist.Add(1, "wish-1", "Artist One Album")
+	p.wishlist.Add(2, "wish-2", "Artist Two Album")
+
+	p.reconcileWishlist(context.Background())
+
+	if len(slskdClient.deleted) != 1 || slskdClient.deleted[0] != "wish-2" {
+		t.Fatalf("expected only wish-2 to be deleted, got %v", slskdClient.deleted)
+	}
+
+	if p.wishlist.Get(1) == nil {
+		t.Error("record for still-wanted album should be kept")
+	}
+	if p.wishlist.Get(2) != nil {
+		t.Error("record for imported album should be removed")
+	}
+}
+
+func TestReconcileWishlist_LeavesUntaggedEntries(t *testing.T) {
+	// The user took over the entry for album 2, replacing its filter
+	slskdClient := &mockSlskdClientWithWishlist{entries: []slskd.WishlistEntry{
+		{ID: "wish-2", SearchText: "Artist Two Album", Filter: "minbr:320"},
+		{ID: "user-1", SearchText: "Some Other Album"},
+	}}
+	p := newTestProcessor(t, &mockLidarrClientWithWanted{}, slskdClient)
+	p.wishlist.Add(2, "wish-2", "Artist Two Album")
+	p.wishlist.Add(3, "wish-3", "Artist Three Album") // Deleted in slskd
+
+	p.reconcileWishlist(context.Background())
+
+	if len(slskdClient.deleted) != 0 {
+		t.Errorf("expected no untagged entries deleted, got %v", slskdClient.deleted)
+	}
+	if p.wishlist.Count() != 0 {
+		t.Errorf("expected the records of untagged and missing entries forgotten, %d left", p.wishlist.Count())
+	}
+}
+
+func TestReconcileWishlist_RecoversTaggedEntries(t *testing.T) {
+	// The records file was lost; both entries still carry seekarr's tag
+	slskdClient := &mockSlskdClientWithWishlist{entries: []slskd.WishlistEntry{
+		{ID: "wish-1", SearchText: "Artist One Album", Filter: wishlistTag(1)},
+		{ID: "wish-2", SearchText: "Artist Two Album", Filter: wishlistTag(2)},
+	}}
+	lidarrClient := &mockLidarrClientUnmonitoring{withFiles: map[int]bool{2: true}}
+	p := newTestProcessor(t, lidarrClient, slskdClient)
+
+	p.reconcileWishlist(context.Background())
+
+	// Neither album is wanted, but only album 2 has been imported
+	if len(slskdClient.deleted) != 1 || slskdClient.deleted[0] != "wish-2" {
+		t.Fatalf("expected only wish-2 deleted, got %v", slskdClient.deleted)
+	}
+	if record := p.wishlist.Get(1); record == nil || record.EntryID != "wish-1" {
+		t.Errorf("expected album 1's entry recovered, got %+v", record)
+	}
+}
+
+// mockLidarrClientUnmonitoring records unmonitored albums and the albums
+// Lidarr holds files for
+type mockLidarrClientUnmonitoring struct {
+	mockLidarrClient
+	unmonitored []int
+	withFiles   map[int]bool
+}
+
+func (m *mockLidarrClientUnmonitoring) GetAlbum(ctx context.Context, id int) (*lidarr.Album, error) {
+	return &lidarr.Album{ID: id, Monitored: true}, nil
+}
+
+func (m *mockLidarrClientUnmonitoring) UpdateAlbum(ctx context.Context, album *lidarr.Album) (*lidarr.Album, error) {
+	if !album.Monitored {
+		m.unmonitored = append(m.unmonitored, album.ID)
+	}
+	return album, nil
+}
+
+func (m *mockLidarrClientUnmonitoring) GetTrackFiles(ctx context.Context, albumID int) ([]lidarr.TrackFile, error) {
+	if m.withFiles[albumID] {
+		return []lidarr.TrackFile{{ID: 1, AlbumID: albumID}}, nil
+	}
+	return nil, nil
+}
+
+func TestReconcileWishlist_KeepsEntriesOfUnmonitoredAlbums(t *testing.T) {
+	slskdClient := &mockSlskdClientWithWishlist{}
+	lidarrClient := &mockLidarrClientUnmonitoring{}
+	p := newTestProcessor(t, lidarrClient, slskdClient)
+	p.cfg.Search.RemoveWantedOnFailure = true
+
+	album := lidarr.Album{ID: 42, Title: "Rare Album", Artist: lidarr.Artist{ArtistName: "Obscure Artist"}}
+	ctx := context.Background()
+	for range p.cfg.Search.MaxSearchFailures {
+		p.recordSearchFailure(ctx, album)
+	}
+	if len(slskdClient.created) != 1 || len(lidarrClient.unmonitored) != 1 {
+		t.Fatalf("expected a wishlist entry and the album unmonitored, got %v and %v", slskdClient.created, lidarrClient.unmonitored)
+	}
+
+	// Unmonitoring took the album off the wanted list without it being found
+	p.reconcileWishlist(ctx)
+	if len(slskdClient.deleted) != 0 || p.wishlist.Get(album.ID) == nil {
+		t.Fatalf("expected the wishlist entry kept, deleted %v", slskdClient.deleted)
+	}
+
+	// Found through the wishlist and imported
+	lidarrClient.withFiles = map[int]bool{album.ID: true}
+	p.reconcileWishlist(ctx)
+	if len(slskdClient.deleted) != 1 || p.wishlist.Get(album.ID) != nil {
+		t.Errorf("expected the wishlist entry removed once imported, deleted %v", slskdClient.deleted)
+	}
+}
@@ -1,29 +1,48 @@
 package slskd
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/yuritomanek/seekarr/internal/httpclient"
 )
 
 // Client defines the interface for interacting with Slskd API
 type Client interface {
 	GetVersion(ctx context.Context) (string, error)
+	GetServerState(ctx context.Context) (*ServerState, error)
 	Search(ctx context.Context, req SearchRequest) (*SearchResponse, error)
+	GetSearches(ctx context.Context) ([]SearchResponse, error)
 	GetSearchState(ctx context.Context, searchID string) (*SearchResponse, error)
 	GetSearchResults(ctx context.Context, searchID string) ([]SearchResult, error)
+	StreamSearchResults(ctx context.Context, searchID string, fn func(SearchResult) bool) error
+	StopSearch(ctx context.Context, searchID string) error
 	DeleteSearch(ctx context.Context, searchID string) error
 	GetDirectory(ctx context.Context, username, directory string) (*Directory, error)
-	EnqueueDownloads(ctx context.Context, username string, files []EnqueueFile) error
+	EnqueueDownloads(ctx context.Context, username string, files []EnqueueFile) (*EnqueueResult, error)
 	GetDownloads(ctx context.Context) (DownloadsResponse, error)
 	GetUserDownloads(ctx context.Context, username string) (*UserDownloads, error)
+	GetDownload(ctx context.Context, username, downloadID string) (*DownloadFile, error)
+	GetQueuePosition(ctx context.Context, username, downloadID string) (int, error)
+	SubscribeTransfers(ctx context.Context) (<-chan TransferEvent, error)
 	CancelDownload(ctx context.Context, username, downloadID string) error
+	RemoveDownload(ctx context.Context, username, downloadID string, deleteFile bool) error
 	RemoveCompletedDownloads(ctx context.Context) error
+	GetWishlist(ctx context.Context) ([]WishlistEntry, error)
+	CreateWishlistEntry(ctx context.Context, searchText, filter string) (*WishlistEntry, error)
+	DeleteWishlistEntry(ctx context.Context, id string) error
 }
 
 // client implements the Slskd API client
@@ -32,32 +51,97 @@ type client struct {
 	urlBase    string
 	apiKey     string
 	httpClient *http.Client
+	transport  *http.Transport // Underlying transport, even when wrapped
+	retry      RetryPolicy
+	logger     *slog.Logger
+
+	// session is set when the client logs in instead of using an API key
+	sessionMu sync.Mutex
+	session   *session
+
+	throttleWarned atomic.Bool // Rate limiting was logged, for requests without a notice of their own
+}
+
+// DefaultTimeout is how long a request may take unless WithTimeout says otherwise
+const DefaultTimeout = 30 * time.Second
+
+// Option configures optional client behaviour
+type Option func(*client)
+
+// WithTransport sets the HTTP transport used for requests (e.g. for tracing)
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *client) {
+		c.httpClient.Transport = rt
+	}
+}
+
+// WithTimeout limits how long a request may take, including reading the
+// response. Zero leaves requests bounded only by their context's deadline
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *client) {
+		c.httpClient.Timeout = timeout
+	}
+}
+
+// WrapTransport wraps the HTTP transport used for requests (e.g. for tracing),
+// keeping its TLS and proxy settings
+func WrapTransport(wrap func(http.RoundTripper) http.RoundTripper) Option {
+	return func(c *client) {
+		c.httpClient.Transport = wrap(c.httpClient.Transport)
+	}
+}
+
+// WithTLSConfig sets how HTTPS connections are verified, e.g. to trust a
+// private certificate authority
+func WithTLSConfig(config *tls.Config) Option {
+	return func(c *client) {
+		c.transport.TLSClientConfig = config
+	}
+}
+
+// WithConnectionPool tunes how connections to slskd are reused
+func WithConnectionPool(pool httpclient.ConnectionPool) Option {
+	return func(c *client) {
+		pool.Apply(c.transport)
+	}
+}
+
+// newTransport returns the default transport, taking proxies from the
+// environment (HTTPS_PROXY, HTTP_PROXY and NO_PROXY) and keeping connections
+// open as the default connection pool describes
+func newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	httpclient.ConnectionPool{}.Apply(transport)
+	return transport
 }
 
 // NewClient creates a new Slskd API client
-func NewClient(baseURL, apiKey, urlBase string) Client {
+func NewClient(baseURL, apiKey, urlBase string, opts ...Option) Client {
 	if urlBase == "" {
 		urlBase = "/"
 	}
-	return &client{
+	transport := newTransport()
+	c := &client{
 		baseURL:    strings.TrimSuffix(baseURL, "/"),
 		urlBase:    strings.Trim(urlBase, "/"),
 		apiKey:     apiKey,
-		httpClient: &http.Client{Timeout: 30 * time.Second},
+		httpClient: &http.Client{Timeout: DefaultTimeout, Transport: transport},
+		transport:  transport,
+		retry:      RetryPolicy{BaseDelay: time.Second, MaxDelay: 30 * time.Second},
+		logger:     slog.New(slog.DiscardHandler),
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // GetVersion fetches the Slskd version
 func (c *client) GetVersion(ctx context.Context) (string, error) {
 	endpoint := "/api/v0/application/version"
 
-	// Construct URL
-	fullPath := endpoint
-	if c.urlBase != "" && c.urlBase != "/" {
-		fullPath = "/" + c.urlBase + endpoint
-	}
-
-	u, err := url.Parse(c.baseURL + fullPath)
+	u, err := url.Parse(c.resolve(endpoint))
 	if err != nil {
 		return "", fmt.Errorf("parse url: %w", err)
 	}
@@ -67,7 +151,10 @@ func (c *client) GetVersion(ctx context.Context) (string, error) {
 		return "", fmt.Errorf("create request: %w", err)
 	}
 
-	req.Header.Set("X-API-Key", c.apiKey)
+	token, err := c.authorize(ctx, req.Header)
+	if err != nil {
+		return "", err
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -77,7 +164,10 @@ func (c *client) GetVersion(ctx context.Context) (string, error) {
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(bodyBytes))
+		if resp.StatusCode == http.StatusUnauthorized && token != "" {
+			c.expireSession(token)
+		}
+		return "", &StatusError{Code: resp.StatusCode, Body: string(bodyBytes)}
 	}
 
 	// Read response as plain string
@@ -91,6 +181,18 @@ func (c *client) GetVersion(ctx context.Context) (string, error) {
 	return version, nil
 }
 
+// GetServerState fetches slskd's connection state to the Soulseek server
+func (c *client) GetServerState(ctx context.Context) (*ServerState, error) {
+	endpoint := "/api/v0/server"
+
+	var state ServerState
+	if err := c.doRequest(ctx, "GET", endpoint, nil, nil, &state); err != nil {
+		return nil, fmt.Errorf("get server state: %w", err)
+	}
+
+	return &state, nil
+}
+
 // Search executes a search on Slskd
 func (c *client) Search(ctx context.Context, req SearchRequest) (*SearchResponse, error) {
 	endpoint := "/api/v0/searches"
@@ -103,12 +205,26 @@ func (c *client) Search(ctx context.Context, req SearchRequest) (*SearchResponse
 	return &response, nil
 }
 
-// GetSearchState fetches the state of a search
+// GetSearches lists every search in slskd's history
+func (c *client) GetSearches(ctx context.Context) ([]SearchResponse, error) {
+	endpoint := "/api/v0/searches"
+
+	var searches []SearchResponse
+	if err := c.doRequest(ctx, "GET", endpoint, nil, nil, &searches); err != nil {
+		return nil, fmt.Errorf("get searches: %w", err)
+	}
+
+	return searches, nil
+}
+
+// GetSearchState fetches the state of a search, leaving out its responses,
+// which some slskd versions would otherwise embed
 func (c *client) GetSearchState(ctx context.Context, searchID string) (*SearchResponse, error) {
 	endpoint := fmt.Sprintf("/api/v0/searches/%s", searchID)
+	params := url.Values{"includeResponses": {"false"}}
 
 	var response SearchResponse
-	if err := c.doRequest(ctx, "GET", endpoint, nil, nil, &response); err != nil {
+	if err := c.doRequest(ctx, "GET", endpoint, params, nil, &response); err != nil {
 		return nil, fmt.Errorf("get search state %s: %w", searchID, err)
 	}
 
@@ -119,7 +235,7 @@ func (c *client) GetSearchState(ctx context.Context, searchID string) (*SearchRe
 func (c *client) GetSearchResults(ctx context.Context, searchID string) ([]SearchResult, error) {
 	endpoint := fmt.Sprintf("/api/v0/searches/%s/responses", searchID)
 
-	var results []SearchResult
+	var results searchResults
 	if err := c.doRequest(ctx, "GET", endpoint, nil, nil, &results); err != nil {
 		return nil, fmt.Errorf("get search results %s: %w", searchID, err)
 	}
@@ -127,6 +243,30 @@ func (c *client) GetSearchResults(ctx context.Context, searchID string) ([]Searc
 	return results, nil
 }
 
+// StreamSearchResults decodes the results of a search one at a time, passing
+// each to fn until it returns false, so a search with thousands of responses
+// never has to be held in memory at once
+func (c *client) StreamSearchResults(ctx context.Context, searchID string, fn func(SearchResult) bool) error {
+	endpoint := fmt.Sprintf("/api/v0/searches/%s/responses", searchID)
+
+	if err := c.doRequest(ctx, "GET", endpoint, nil, nil, searchResultStream(fn)); err != nil {
+		return fmt.Errorf("get search results %s: %w", searchID, err)
+	}
+
+	return nil
+}
+
+// StopSearch stops a running search, keeping the responses received so far
+func (c *client) StopSearch(ctx context.Context, searchID string) error {
+	endpoint := fmt.Sprintf("/api/v0/searches/%s", searchID)
+
+	if err := c.doRequest(ctx, "PUT", endpoint, nil, nil, nil); err != nil {
+		return fmt.Errorf("stop search %s: %w", searchID, err)
+	}
+
+	return nil
+}
+
 // DeleteSearch deletes a search from Slskd history
 func (c *client) DeleteSearch(ctx context.Context, searchID string) error {
 	endpoint := fmt.Sprintf("/api/v0/searches/%s", searchID)
@@ -155,16 +295,18 @@ func (c *client) GetDirectory(ctx context.Context, username, directory string) (
 	return &response, nil
 }
 
-// EnqueueDownloads enqueues files for download from a user
-func (c *client) EnqueueDownloads(ctx context.Context, username string, files []EnqueueFile) error {
+// EnqueueDownloads enqueues files for download from a user, reporting which
+// files slskd accepted
+func (c *client) EnqueueDownloads(ctx context.Context, username string, files []EnqueueFile) (*EnqueueResult, error) {
 	endpoint := fmt.Sprintf("/api/v0/transfers/downloads/%s", username)
 
 	// Body should be an array of objects with filename and size
-	if err := c.doRequest(ctx, "POST", endpoint, nil, files, nil); err != nil {
-		return fmt.Errorf("enqueue downloads for %s: %w", username, err)
+	var result EnqueueResult
+	if err := c.doRequest(ctx, "POST", endpoint, nil, files, &result); err != nil {
+		return nil, fmt.Errorf("enqueue downloads for %s: %w", username, err)
 	}
 
-	return nil
+	return &result, nil
 }
 
 // GetDownloads fetches all downloads grouped by username
@@ -191,6 +333,32 @@ func (c *client) GetUserDownloads(ctx context.Context, username string) (*UserDo
 	return &response, nil
 }
 
+// GetDownload fetches a single download, so a known transfer can be checked
+// without listing every download
+func (c *client) GetDownload(ctx context.Context, username, downloadID string) (*DownloadFile, error) {
+	endpoint := fmt.Sprintf("/api/v0/transfers/downloads/%s/%s", username, downloadID)
+
+	var response DownloadFile
+	if err := c.doRequest(ctx, "GET", endpoint, nil, nil, &response); err != nil {
+		return nil, fmt.Errorf("get download %s for %s: %w", downloadID, username, err)
+	}
+
+	return &response, nil
+}
+
+// GetQueuePosition asks the uploader where a remotely queued download stands
+// in their queue, 0 being next
+func (c *client) GetQueuePosition(ctx context.Context, username, downloadID string) (int, error) {
+	endpoint := fmt.Sprintf("/api/v0/transfers/downloads/%s/%s/position", username, downloadID)
+
+	var position int
+	if err := c.doRequest(ctx, "GET", endpoint, nil, nil, &position); err != nil {
+		return 0, fmt.Errorf("get queue position of %s for %s: %w", downloadID, username, err)
+	}
+
+	return position, nil
+}
+
 // CancelDownload cancels a specific download
 func (c *client) CancelDownload(ctx context.Context, username, downloadID string) error {
 	endpoint := fmt.Sprintf("/api/v0/transfers/downloads/%s/%s", username, downloadID)
@@ -202,6 +370,23 @@ func (c *client) CancelDownload(ctx context.Context, username, downloadID string
 	return nil
 }
 
+// RemoveDownload removes a finished download from slskd's transfer list, also
+// deleting the downloaded file if deleteFile is set
+func (c *client) RemoveDownload(ctx context.Context, username, downloadID string, deleteFile bool) error {
+	endpoint := fmt.Sprintf("/api/v0/transfers/downloads/%s/%s", username, downloadID)
+
+	params := url.Values{"remove": {"true"}}
+	if deleteFile {
+		params.Set("deleteFile", "true")
+	}
+
+	if err := c.doRequest(ctx, "DELETE", endpoint, params, nil, nil); err != nil {
+		return fmt.Errorf("remove download %s for %s: %w", downloadID, username, err)
+	}
+
+	return nil
+}
+
 // RemoveCompletedDownloads removes all completed downloads from the list
 func (c *client) RemoveCompletedDownloads(ctx context.Context) error {
 	endpoint := "/api/v0/transfers/downloads/completed"
@@ -213,15 +398,52 @@ func (c *client) RemoveCompletedDownloads(ctx context.Context) error {
 	return nil
 }
 
-// doRequest executes an HTTP request to the Slskd API
-func (c *client) doRequest(ctx context.Context, method, endpoint string, params url.Values, body, result interface{}) error {
-	// Construct URL with optional url_base prefix
-	fullPath := endpoint
-	if c.urlBase != "" && c.urlBase != "/" {
-		fullPath = "/" + c.urlBase + endpoint
+// GetWishlist fetches all wishlist entries
+func (c *client) GetWishlist(ctx context.Context) ([]WishlistEntry, error) {
+	endpoint := "/api/v0/wishlist"
+
+	var entries []WishlistEntry
+	if err := c.doRequest(ctx, "GET", endpoint, nil, nil, &entries); err != nil {
+		return nil, fmt.Errorf("get wishlist: %w", err)
 	}
 
-	u, err := url.Parse(c.baseURL + fullPath)
+	return entries, nil
+}
+
+// CreateWishlistEntry adds a persistent background search to the wishlist
+// filter narrows the results slskd keeps, using the same syntax as its web UI
+func (c *client) CreateWishlistEntry(ctx context.Context, searchText, filter string) (*WishlistEntry, error) {
+	endpoint := "/api/v0/wishlist"
+
+	req := WishlistRequest{
+		SearchText: searchText,
+		Filter:     filter,
+		Enabled:    true,
+	}
+
+	var entry WishlistEntry
+	if err := c.doRequest(ctx, "POST", endpoint, nil, req, &entry); err != nil {
+		return nil, fmt.Errorf("create wishlist entry %q: %w", searchText, err)
+	}
+
+	return &entry, nil
+}
+
+// DeleteWishlistEntry removes an entry from the wishlist
+func (c *client) DeleteWishlistEntry(ctx context.Context, id string) error {
+	endpoint := fmt.Sprintf("/api/v0/wishlist/%s", id)
+
+	if err := c.doRequest(ctx, "DELETE", endpoint, nil, nil, nil); err != nil {
+		return fmt.Errorf("delete wishlist entry %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// doRequest executes an HTTP request to the Slskd API, retrying it as the
+// client's retry policy allows
+func (c *client) doRequest(ctx context.Context, method, endpoint string, params url.Values, body, result interface{}) error {
+	u, err := url.Parse(c.resolve(endpoint))
 	if err != nil {
 		return fmt.Errorf("parse url: %w", err)
 	}
@@ -230,21 +452,71 @@ func (c *client) doRequest(ctx context.Context, method, endpoint string, params
 		u.RawQuery = params.Encode()
 	}
 
-	var bodyReader io.Reader
+	var bodyBytes []byte
 	if body != nil {
-		bodyBytes, err := json.Marshal(body)
+		bodyBytes, err = json.Marshal(body)
 		if err != nil {
 			return fmt.Errorf("marshal request body: %w", err)
 		}
-		bodyReader = strings.NewReader(string(bodyBytes))
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, u.String(), bodyReader)
+	for attempt := 1; ; attempt++ {
+		err := c.send(ctx, method, u.String(), bodyBytes, result)
+		if sessionExpired(err) {
+			// Log in again and resend once, without counting it as a retry
+			err = c.send(ctx, method, u.String(), bodyBytes, result)
+		}
+		attempts := c.retry.attempts(method, err)
+		if err == nil || attempt >= attempts || !retryable(err) || ctx.Err() != nil {
+			return err
+		}
+
+		delay := c.retry.delay(attempt, err)
+		if throttled(err) && c.firstThrottle(ctx) {
+			c.logger.Warn("slskd is rate limiting requests, consider raising the poll intervals",
+				"method", method,
+				"endpoint", endpoint,
+				"retryIn", delay)
+		}
+		c.logger.Debug("retrying slskd request",
+			"method", method,
+			"endpoint", endpoint,
+			"attempt", attempt,
+			"maxAttempts", attempts,
+			"retryIn", delay,
+			"error", err)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w (retry cancelled: %w)", err, ctx.Err())
+		case <-time.After(delay):
+		}
+	}
+}
+
+// resolve returns the URL of an API endpoint, under the url_base prefix if set
+func (c *client) resolve(endpoint string) string {
+	if c.urlBase != "" && c.urlBase != "/" {
+		return c.baseURL + "/" + c.urlBase + endpoint
+	}
+	return c.baseURL + endpoint
+}
+
+// send makes a single attempt at a request
+func (c *client) send(ctx context.Context, method, rawURL string, body []byte, result interface{}) error {
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, bodyReader)
 	if err != nil {
 		return fmt.Errorf("create request: %w", err)
 	}
 
-	req.Header.Set("X-API-Key", c.apiKey)
+	token, err := c.authorize(ctx, req.Header)
+	if err != nil {
+		return err
+	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := c.httpClient.Do(req)
@@ -255,14 +527,95 @@ func (c *client) doRequest(ctx context.Context, method, endpoint string, params
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(bodyBytes))
+		statusErr := &StatusError{
+			Code:       resp.StatusCode,
+			Body:       string(bodyBytes),
+			retryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+		if resp.StatusCode == http.StatusUnauthorized && token != "" {
+			c.expireSession(token)
+			statusErr.sessionExpired = true
+		}
+		return statusErr
+	}
+
+	if stream, ok := result.(streamDecoder); ok {
+		if err := stream.decodeFrom(resp.Body); err != nil {
+			return fmt.Errorf("decode response: %w", err)
+		}
+		return nil
 	}
 
 	if result != nil {
-		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		// Some endpoints answer with no body, leaving result empty
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil && !errors.Is(err, io.EOF) {
 			return fmt.Errorf("decode response: %w", err)
 		}
 	}
 
 	return nil
 }
+
+// streamDecoder is a request result that decodes the response body itself
+type streamDecoder interface {
+	decodeFrom(r io.Reader) error
+}
+
+// searchResultStream passes each element of a search results array to the
+// function, stopping when it returns false
+type searchResultStream func(SearchResult) bool
+
+func (fn searchResultStream) decodeFrom(r io.Reader) error {
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil // null
+	}
+	if delim, ok := tok.(json.Delim); ok && delim == '{' {
+		if tok, err = seekResponses(dec); err != nil {
+			return err
+		}
+		if tok == nil {
+			return nil
+		}
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected an array of search results, got %v", tok)
+	}
+
+	for dec.More() {
+		var result SearchResult
+		if err := dec.Decode(&result); err != nil {
+			return err
+		}
+		if !fn(result) {
+			return nil // The rest of the body is discarded unread
+		}
+	}
+	return nil
+}
+
+// seekResponses reads the keys of an object wrapping search results up to its
+// responses field, returning the field's first token
+func seekResponses(dec *json.Decoder) (json.Token, error) {
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if key == "responses" {
+			return dec.Token()
+		}
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return nil, err
+		}
+	}
+	return nil, errNoResponses
+}
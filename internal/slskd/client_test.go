@@ -2,10 +2,16 @@ package slskd
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"testing"
+	"time"
 )
 
 func TestSearch(t *testing.T) {
@@ -65,6 +71,87 @@ func TestSearch(t *testing.T) {
 	}
 }
 
+func TestGetSearchState(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v0/searches/search-123" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if r.URL.RawQuery != "includeResponses=false" {
+			t.Errorf("expected responses to be left out, got query %q", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": "search-123", "state": "Completed, TimedOut", "responseCount": 12, "fileCount": 340}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", "/")
+
+	state, err := client.GetSearchState(context.Background(), "search-123")
+	if err != nil {
+		t.Fatalf("GetSearchState() error: %v", err)
+	}
+	if state.State != "Completed, TimedOut" || state.ResponseCount != 12 || state.FileCount != 340 {
+		t.Errorf("unexpected search state: %+v", state)
+	}
+}
+
+func TestStopSearch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			t.Errorf("expected PUT, got %s", r.Method)
+		}
+		if r.URL.Path != "/api/v0/searches/search-123" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", "/")
+	if err := client.StopSearch(context.Background(), "search-123"); err != nil {
+		t.Fatalf("StopSearch() error: %v", err)
+	}
+}
+
+func TestGetSearches(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/api/v0/searches" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[
+			{"id": "search-1", "searchText": "Artist Album", "state": "Completed, TimedOut", "startedAt": "2024-03-01T10:00:00Z", "endedAt": "2024-03-01T10:00:15Z"},
+			{"id": "search-2", "searchText": "Other Album", "state": "InProgress", "startedAt": "2024-03-02T10:00:00Z"}
+		]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", "/")
+
+	searches, err := client.GetSearches(context.Background())
+	if err != nil {
+		t.Fatalf("GetSearches() error: %v", err)
+	}
+
+	if len(searches) != 2 {
+		t.Fatalf("expected 2 searches, got %d", len(searches))
+	}
+	first := searches[0]
+	if first.ID != "search-1" || first.SearchText != "Artist Album" {
+		t.Errorf("unexpected search: %+v", first)
+	}
+	if want := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC); !first.StartedAt.Equal(want) {
+		t.Errorf("expected startedAt %v, got %v", want, first.StartedAt)
+	}
+	if first.EndedAt == nil || !first.EndedAt.Equal(time.Date(2024, 3, 1, 10, 0, 15, 0, time.UTC)) {
+		t.Errorf("unexpected endedAt: %v", first.EndedAt)
+	}
+	if searches[1].EndedAt != nil {
+		t.Errorf("expected no endedAt for a running search, got %v", searches[1].EndedAt)
+	}
+}
+
 func TestGetSearchResults(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/api/v0/searches/search-123/responses" {
@@ -118,6 +205,138 @@ func TestGetSearchResults(t *testing.T) {
 	}
 }
 
+func TestGetSearchResultsWireFormats(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantUsers []string
+		wantErr   bool
+	}{
+		{name: "array", body: `[{"username": "a"}, {"username": "b"}]`, wantUsers: []string{"a", "b"}},
+		{name: "wrapped", body: `{"responses": [{"username": "a"}, {"username": "b"}], "responseCount": 2}`, wantUsers: []string{"a", "b"}},
+		{name: "wrapped empty", body: `{"responses": []}`},
+		{name: "null", body: `null`},
+		{name: "object without responses", body: `{"username": "a"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "test-key", "/")
+
+			results, err := client.GetSearchResults(context.Background(), "search-123")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetSearchResults() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var users []string
+			for _, result := range results {
+				users = append(users, result.Username)
+			}
+			if !slices.Equal(users, tt.wantUsers) {
+				t.Errorf("expected results from %v, got %v", tt.wantUsers, users)
+			}
+		})
+	}
+}
+
+func TestStreamSearchResults(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		stopAfter int // Results read before fn returns false, 0 to read all
+		wantUsers []string
+		wantErr   bool
+	}{
+		{name: "all results", body: `[{"username": "a"}, {"username": "b"}, {"username": "c"}]`, wantUsers: []string{"a", "b", "c"}},
+		{name: "stops early", body: `[{"username": "a"}, {"username": "b"}, {"username": "c"}]`, stopAfter: 2, wantUsers: []string{"a", "b"}},
+		{name: "empty", body: `[]`},
+		{name: "null", body: `null`},
+		{name: "wrapped", body: `{"page": 1, "total": 2, "responses": [{"username": "a"}, {"username": "b"}]}`, wantUsers: []string{"a", "b"}},
+		{name: "wrapped null", body: `{"responses": null}`},
+		{name: "not an array", body: `{"username": "a"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v0/searches/search-123/responses" {
+					t.Errorf("unexpected path: %s", r.URL.Path)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "test-key", "/")
+
+			var users []string
+			err := client.StreamSearchResults(context.Background(), "search-123", func(result SearchResult) bool {
+				users = append(users, result.Username)
+				return tt.stopAfter == 0 || len(users) < tt.stopAfter
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("StreamSearchResults() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(users, tt.wantUsers) {
+				t.Errorf("expected results from %v, got %v", tt.wantUsers, users)
+			}
+		})
+	}
+}
+
+func TestSearchResultPeerFields(t *testing.T) {
+	var results []SearchResult
+	data := `[
+		{"username": "busy", "hasFreeUploadSlot": false, "queueLength": 120, "uploadSpeed": 524288,
+		 "fileCount": 1, "lockedFileCount": 1,
+		 "files": [{"filename": "a.flac", "isLocked": false}],
+		 "lockedFiles": [{"filename": "b.flac", "isLocked": true}]},
+		{"username": "old", "files": [{"filename": "c.flac"}]}
+	]`
+	if err := json.Unmarshal([]byte(data), &results); err != nil {
+		t.Fatalf("unmarshal search results: %v", err)
+	}
+
+	busy := results[0]
+	if busy.HasFreeUploadSlot == nil || *busy.HasFreeUploadSlot || busy.QueueLength != 120 {
+		t.Errorf("unexpected peer fields: slot %v, queue %d", busy.HasFreeUploadSlot, busy.QueueLength)
+	}
+	if busy.UploadSpeed != 524288 || busy.FileCount != 1 || busy.LockedFileCount != 1 {
+		t.Errorf("unexpected peer fields: speed %d, files %d, locked %d", busy.UploadSpeed, busy.FileCount, busy.LockedFileCount)
+	}
+	if len(busy.LockedFiles) != 1 || !busy.LockedFiles[0].IsLocked {
+		t.Errorf("expected one locked file, got %+v", busy.LockedFiles)
+	}
+
+	old := results[1]
+	if old.HasFreeUploadSlot != nil || old.Files[0].IsLocked {
+		t.Errorf("missing fields should decode as unknown and unlocked, got %+v", old)
+	}
+}
+
+func TestFileLength(t *testing.T) {
+	var dir Directory
+	if err := json.Unmarshal([]byte(`{"name": "Album", "files": [{"filename": "01.flac", "length": 245}, {"filename": "cover.jpg"}]}`), &dir); err != nil {
+		t.Fatalf("unmarshal directory: %v", err)
+	}
+	if dir.Files[0].Length == nil || *dir.Files[0].Length != 245 || dir.Files[1].Length != nil {
+		t.Errorf("unexpected directory file lengths: %v, %v", dir.Files[0].Length, dir.Files[1].Length)
+	}
+
+	var file DownloadFile
+	if err := json.Unmarshal([]byte(`{"id": "1", "filename": "01.flac", "length": 245}`), &file); err != nil {
+		t.Fatalf("unmarshal download: %v", err)
+	}
+	if file.Length == nil || *file.Length != 245 {
+		t.Errorf("unexpected download length: %v", file.Length)
+	}
+}
+
 func TestGetDirectory(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/api/v0/users/user1/directory" {
@@ -170,45 +389,108 @@ func TestGetDirectory(t *testing.T) {
 }
 
 func TestEnqueueDownloads(t *testing.T) {
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path != "/api/v0/transfers/downloads/user1" {
-			t.Errorf("unexpected path: %s", r.URL.Path)
-		}
-
-		if r.Method != "POST" {
-			t.Errorf("expected POST, got %s", r.Method)
-		}
-
-		var files []EnqueueFile
-		if err := json.NewDecoder(r.Body).Decode(&files); err != nil {
-			t.Fatalf("failed to decode request: %v", err)
-		}
-
-		if len(files) != 2 {
-			t.Errorf("expected 2 files, got %d", len(files))
-		}
-
-		if files[0].Filename != "Artist\\Album\\01 Track.flac" {
-			t.Errorf("expected filename 'Artist\\Album\\01 Track.flac', got %q", files[0].Filename)
-		}
-
-		if files[0].Size != 12345 {
-			t.Errorf("expected size 12345, got %d", files[0].Size)
-		}
+	tests := []struct {
+		name         string
+		response     string
+		wantEnqueued int
+		wantFailed   []EnqueueFailure
+	}{
+		{
+			name:         "all accepted",
+			response:     `{"enqueued": [{"id": "1", "filename": "Artist\\Album\\01 Track.flac"}, {"id": "2", "filename": "Artist\\Album\\02 Track.flac"}], "failed": []}`,
+			wantEnqueued: 2,
+		},
+		{
+			name:         "partially rejected",
+			response:     `{"enqueued": [{"id": "1", "filename": "Artist\\Album\\01 Track.flac"}], "failed": ["Artist\\Album\\02 Track.flac"]}`,
+			wantEnqueued: 1,
+			wantFailed:   []EnqueueFailure{{Filename: "Artist\\Album\\02 Track.flac"}},
+		},
+		{
+			name:         "rejected with reason",
+			response:     `{"failed": [{"filename": "Artist\\Album\\02 Track.flac", "reason": "File not shared."}]}`,
+			wantEnqueued: 0,
+			wantFailed:   []EnqueueFailure{{Filename: "Artist\\Album\\02 Track.flac", Reason: "File not shared."}},
+		},
+		{
+			name: "no body",
+		},
+	}
 
-		w.WriteHeader(http.StatusOK)
-	}))
-	defer server.Close()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v0/transfers/downloads/user1" {
+					t.Errorf("unexpected path: %s", r.URL.Path)
+				}
+
+				if r.Method != "POST" {
+					t.Errorf("expected POST, got %s", r.Method)
+				}
+
+				// slskd expects a bare array of {filename, size} objects
+				body, _ := io.ReadAll(r.Body)
+				want := `[{"filename":"Artist\\Album\\01 Track.flac","size":12345},{"filename":"Artist\\Album\\02 Track.flac","size":67890}]`
+				if string(body) != want {
+					t.Errorf("unexpected body:\n got %s\nwant %s", body, want)
+				}
+
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "test-key", "/")
+
+			result, err := client.EnqueueDownloads(context.Background(), "user1", []EnqueueFile{
+				{Filename: "Artist\\Album\\01 Track.flac", Size: 12345},
+				{Filename: "Artist\\Album\\02 Track.flac", Size: 67890},
+			})
+			if err != nil {
+				t.Fatalf("EnqueueDownloads() error: %v", err)
+			}
 
-	client := NewClient(server.URL, "test-key", "/")
+			if len(result.Enqueued) != tt.wantEnqueued {
+				t.Errorf("expected %d enqueued files, got %d", tt.wantEnqueued, len(result.Enqueued))
+			}
+			if !slices.Equal(result.Failed, tt.wantFailed) {
+				t.Errorf("expected failed files %v, got %v", tt.wantFailed, result.Failed)
+			}
+		})
+	}
+}
 
-	err := client.EnqueueDownloads(context.Background(), "user1", []EnqueueFile{
-		{Filename: "Artist\\Album\\01 Track.flac", Size: 12345},
-		{Filename: "Artist\\Album\\02 Track.flac", Size: 67890},
-	})
+func TestRemoveDownload(t *testing.T) {
+	tests := []struct {
+		name       string
+		deleteFile bool
+		wantQuery  string
+	}{
+		{name: "keep file", wantQuery: "remove=true"},
+		{name: "delete file", deleteFile: true, wantQuery: "deleteFile=true&remove=true"},
+	}
 
-	if err != nil {
-		t.Fatalf("EnqueueDownloads() error: %v", err)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != "DELETE" {
+					t.Errorf("expected DELETE, got %s", r.Method)
+				}
+				if r.URL.Path != "/api/v0/transfers/downloads/user1/file-1" {
+					t.Errorf("unexpected path: %s", r.URL.Path)
+				}
+				if r.URL.RawQuery != tt.wantQuery {
+					t.Errorf("expected query %q, got %q", tt.wantQuery, r.URL.RawQuery)
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, "test-key", "/")
+			if err := client.RemoveDownload(context.Background(), "user1", "file-1", tt.deleteFile); err != nil {
+				t.Fatalf("RemoveDownload() error: %v", err)
+			}
+		})
 	}
 }
 
@@ -266,6 +548,74 @@ func TestGetDownloads(t *testing.T) {
 	}
 }
 
+func TestGetDownload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("expected GET, got %s", r.Method)
+		}
+		switch r.URL.Path {
+		case "/api/v0/transfers/downloads/user1/download-1":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(DownloadFile{
+				ID:               "download-1",
+				Filename:         "Artist\\Album\\01 Track.flac",
+				State:            "Completed, Succeeded",
+				BytesTransferred: 35840000,
+				Size:             35840000,
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", "/")
+
+	file, err := client.GetDownload(context.Background(), "user1", "download-1")
+	if err != nil {
+		t.Fatalf("GetDownload() error: %v", err)
+	}
+	if file.ID != "download-1" || !file.IsCompleted() {
+		t.Errorf("unexpected download: %+v", file)
+	}
+
+	if _, err := client.GetDownload(context.Background(), "user1", "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a missing download, got %v", err)
+	}
+}
+
+func TestGetQueuePosition(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("expected GET, got %s", r.Method)
+		}
+		if r.URL.Path != "/api/v0/transfers/downloads/user1/download-1/position" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte("42"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", "/")
+
+	position, err := client.GetQueuePosition(context.Background(), "user1", "download-1")
+	if err != nil {
+		t.Fatalf("GetQueuePosition() error: %v", err)
+	}
+	if position != 42 {
+		t.Errorf("expected position 42, got %d", position)
+	}
+
+	var file DownloadFile
+	if err := json.Unmarshal([]byte(`{"id":"download-1","state":"Queued, Remotely","placeInQueue":7}`), &file); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if file.PlaceInQueue == nil || *file.PlaceInQueue != 7 {
+		t.Errorf("expected place in queue 7, got %v", file.PlaceInQueue)
+	}
+}
+
 func TestDownloadFileStates(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -326,3 +676,201 @@ func TestClientWithURLBase(t *testing.T) {
 		t.Errorf("expected version '0.22.3', got %q", version)
 	}
 }
+
+func TestWishlist(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/api/v0/wishlist":
+			var req WishlistRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Fatalf("failed to decode request: %v", err)
+			}
+			if req.SearchText != "Artist Album" {
+				t.Errorf("expected search text 'Artist Album', got %q", req.SearchText)
+			}
+			if req.Filter != "-tag" {
+				t.Errorf("expected filter '-tag', got %q", req.Filter)
+			}
+			if !req.Enabled {
+				t.Error("expected wishlist entry to be enabled")
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(WishlistEntry{ID: "wish-1", SearchText: req.SearchText, Filter: req.Filter, Enabled: true})
+
+		case r.Method == "GET" && r.URL.Path == "/api/v0/wishlist":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode([]WishlistEntry{{ID: "wish-1", SearchText: "Artist Album"}})
+
+		case r.Method == "DELETE" && r.URL.Path == "/api/v0/wishlist/wish-1":
+			w.WriteHeader(http.StatusNoContent)
+
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", "/")
+	ctx := context.Background()
+
+	entry, err := client.CreateWishlistEntry(ctx, "Artist Album", "-tag")
+	if err != nil {
+		t.Fatalf("CreateWishlistEntry() error: %v", err)
+	}
+	if entry.ID != "wish-1" {
+		t.Errorf("expected ID 'wish-1', got %q", entry.ID)
+	}
+
+	entries, err := client.GetWishlist(ctx)
+	if err != nil {
+		t.Fatalf("GetWishlist() error: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected 1 entry, got %d", len(entries))
+	}
+
+	if err := client.DeleteWishlistEntry(ctx, "wish-1"); err != nil {
+		t.Fatalf("DeleteWishlistEntry() error: %v", err)
+	}
+}
+
+// countingTransport counts requests passing through it
+type countingTransport struct {
+	requests int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestWithTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	rt := &countingTransport{}
+	client := NewClient(server.URL, "test-key", "", WithTransport(rt))
+
+	if _, err := client.GetDownloads(context.Background()); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+
+	if rt.requests != 1 {
+		t.Errorf("expected request to use custom transport, got %d requests", rt.requests)
+	}
+}
+
+// wrappingTransport counts the requests passed to the transport it wraps
+type wrappingTransport struct {
+	base     http.RoundTripper
+	requests int
+}
+
+func (t *wrappingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+	return t.base.RoundTrip(req)
+}
+
+func TestWithTLSConfig(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	trusted := x509.NewCertPool()
+	trusted.AddCert(server.Certificate())
+
+	tests := []struct {
+		name    string
+		config  *tls.Config
+		wrap    bool
+		wantErr bool
+	}{
+		{name: "untrusted certificate", wantErr: true},
+		{name: "trusted ca", config: &tls.Config{RootCAs: trusted}},
+		{name: "skip verify", config: &tls.Config{InsecureSkipVerify: true}},
+		{name: "trusted ca with wrapped transport", config: &tls.Config{RootCAs: trusted}, wrap: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wrapper := &wrappingTransport{}
+			opts := []Option{WithTLSConfig(tt.config)}
+			if tt.wrap {
+				opts = append(opts, WrapTransport(func(base http.RoundTripper) http.RoundTripper {
+					wrapper.base = base
+					return wrapper
+				}))
+			}
+			client := NewClient(server.URL, "test-key", "", opts...)
+
+			_, err := client.GetDownloads(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("request error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wrap && wrapper.requests != 1 {
+				t.Errorf("expected the request to use the wrapped transport, got %d requests", wrapper.requests)
+			}
+		})
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		timeout  time.Duration
+		deadline time.Duration // Context deadline, none when zero
+		wantErr  bool
+	}{
+		{name: "client timeout", timeout: 50 * time.Millisecond, wantErr: true},
+		{name: "no client timeout", timeout: 0},
+		{name: "context deadline only", timeout: 0, deadline: 50 * time.Millisecond, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.deadline > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.deadline)
+				defer cancel()
+			}
+
+			client := NewClient(server.URL, "test-key", "", WithTimeout(tt.timeout))
+			if _, err := client.GetDownloads(ctx); (err != nil) != tt.wantErr {
+				t.Errorf("GetDownloads() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetServerState(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v0/server" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"address":"vps.slsknet.org:2271","state":"Connected, LoggedIn","isConnected":true,"isLoggedIn":true,"isTransitioning":false}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", "/")
+
+	state, err := client.GetServerState(context.Background())
+	if err != nil {
+		t.Fatalf("GetServerState() error: %v", err)
+	}
+	if state.State != "Connected, LoggedIn" || !state.Ready() {
+		t.Errorf("expected a connected, logged in state, got %+v", state)
+	}
+}
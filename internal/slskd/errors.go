@@ -0,0 +1,48 @@
+package slskd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Errors matched by a StatusError with the corresponding HTTP status
+var (
+	ErrNotFound     = errors.New("not found")    // 404, e.g. a search slskd has already expired
+	ErrUnauthorized = errors.New("unauthorized") // 401 or 403, usually a wrong API key or password
+	ErrConflict     = errors.New("conflict")     // 409, e.g. a download that is already queued
+)
+
+// StatusError is an unexpected HTTP status returned by slskd
+type StatusError struct {
+	Code int
+	Body string
+
+	retryAfter     time.Duration
+	sessionExpired bool // slskd refused the session token, which was forgotten
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status %d: %s", e.Code, e.Body)
+}
+
+// Is matches the sentinel error for the status, so callers can use errors.Is
+func (e *StatusError) Is(target error) bool {
+	switch e.Code {
+	case http.StatusNotFound:
+		return target == ErrNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return target == ErrUnauthorized
+	case http.StatusConflict:
+		return target == ErrConflict
+	}
+	return false
+}
+
+// sessionExpired reports whether a request failed because its session token
+// expired, so it may succeed after logging in again
+func sessionExpired(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.sessionExpired
+}
@@ -0,0 +1,214 @@
+package slskd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// SignalR JSON hub protocol, as spoken by slskd's hubs
+const (
+	signalRSeparator    = '\x1e' // Ends every message
+	signalRInvocation   = 1
+	signalRPing         = 6
+	signalRClose        = 7
+	signalRPingInterval = 15 * time.Second // The server drops clients silent for 30s
+)
+
+// transferUpdateTarget is the hub method slskd invokes with a changed transfer
+const transferUpdateTarget = "UPDATE"
+
+// TransferEvent is a change to one download pushed by slskd
+type TransferEvent struct {
+	Username  string
+	Directory string // Remote directory, as in DirectoryDownloads
+	File      DownloadFile
+}
+
+// signalRMessage is a hub message; only the fields seekarr reads are decoded
+type signalRMessage struct {
+	Type      int               `json:"type"`
+	Target    string            `json:"target,omitempty"`
+	Arguments []json.RawMessage `json:"arguments,omitempty"`
+	Error     string            `json:"error,omitempty"`
+}
+
+// transfer is a transfer as slskd's hub sends it
+type transfer struct {
+	DownloadFile
+	Username  string `json:"username"`
+	Direction string `json:"direction"` // Download or Upload
+}
+
+// SubscribeTransfers connects to slskd's transfers hub and delivers download
+// changes until ctx is cancelled or the connection drops, when the channel is
+// closed. An error means events aren't available and downloads must be polled
+func (c *client) SubscribeTransfers(ctx context.Context) (<-chan TransferEvent, error) {
+	hubPath := "/hub/transfers"
+	if c.urlBase != "" && c.urlBase != "/" {
+		hubPath = "/" + c.urlBase + hubPath
+	}
+
+	u, err := url.Parse(c.baseURL + hubPath)
+	if err != nil {
+		return nil, fmt.Errorf("parse url: %w", err)
+	}
+	origin := u.Scheme + "://" + u.Host
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	default:
+		u.Scheme = "ws"
+	}
+
+	config, err := websocket.NewConfig(u.String(), origin)
+	if err != nil {
+		return nil, fmt.Errorf("configure websocket: %w", err)
+	}
+	if _, err := c.authorize(ctx, config.Header); err != nil {
+		return nil, err
+	}
+	config.TlsConfig = c.transport.TLSClientConfig
+
+	dialTimeout := c.httpClient.Timeout
+	if dialTimeout <= 0 {
+		dialTimeout = DefaultTimeout
+	}
+	dialCtx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+	conn, err := config.DialContext(dialCtx)
+	if err != nil {
+		return nil, fmt.Errorf("connect to transfers hub: %w", err)
+	}
+
+	if err := signalRHandshake(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("transfers hub handshake: %w", err)
+	}
+
+	events := make(chan TransferEvent)
+	done := make(chan struct{})
+	go func() {
+		// Unblocks the reader once the subscriber is gone
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		conn.Close()
+	}()
+	go c.pingHub(conn, done)
+	go func() {
+		defer close(events)
+		defer close(done)
+		if err := c.readTransfers(ctx, conn, events); err != nil && ctx.Err() == nil {
+			c.logger.Debug("transfers hub connection ended", "error", err)
+		}
+	}()
+
+	return events, nil
+}
+
+// signalRHandshake selects the JSON protocol and waits for the server to accept it
+func signalRHandshake(conn *websocket.Conn) error {
+	if err := websocket.Message.Send(conn, `{"protocol":"json","version":1}`+string(signalRSeparator)); err != nil {
+		return fmt.Errorf("send: %w", err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	defer conn.SetReadDeadline(time.Time{})
+
+	var reply []byte
+	if err := websocket.Message.Receive(conn, &reply); err != nil {
+		return fmt.Errorf("receive: %w", err)
+	}
+	var msg signalRMessage
+	if err := json.Unmarshal(bytes.TrimRight(reply, string(signalRSeparator)), &msg); err != nil {
+		return fmt.Errorf("decode: %w", err)
+	}
+	if msg.Error != "" {
+		return fmt.Errorf("rejected: %s", msg.Error)
+	}
+	return nil
+}
+
+// pingHub keeps the hub connection alive until done is closed
+func (c *client) pingHub(conn *websocket.Conn, done <-chan struct{}) {
+	ticker := time.NewTicker(signalRPingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := websocket.Message.Send(conn, fmt.Sprintf(`{"type":%d}`, signalRPing)+string(signalRSeparator)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readTransfers delivers the download changes read from the hub until it closes
+func (c *client) readTransfers(ctx context.Context, conn *websocket.Conn, events chan<- TransferEvent) error {
+	for {
+		var frame []byte
+		if err := websocket.Message.Receive(conn, &frame); err != nil {
+			return err
+		}
+
+		// A frame can carry several messages
+		for _, record := range bytes.Split(frame, []byte{signalRSeparator}) {
+			if len(bytes.TrimSpace(record)) == 0 {
+				continue
+			}
+			var msg signalRMessage
+			if err := json.Unmarshal(record, &msg); err != nil {
+				c.logger.Debug("skipping undecodable hub message", "error", err)
+				continue
+			}
+
+			switch msg.Type {
+			case signalRClose:
+				if msg.Error != "" {
+					return fmt.Errorf("closed by server: %s", msg.Error)
+				}
+				return nil
+			case signalRInvocation:
+				event, ok := transferEvent(msg)
+				if !ok {
+					continue
+				}
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+	}
+}
+
+// transferEvent reads the download change carried by a hub invocation
+func transferEvent(msg signalRMessage) (TransferEvent, bool) {
+	if msg.Target != transferUpdateTarget || len(msg.Arguments) == 0 {
+		return TransferEvent{}, false
+	}
+	var t transfer
+	if err := json.Unmarshal(msg.Arguments[0], &t); err != nil || t.ID == "" {
+		return TransferEvent{}, false
+	}
+	if t.Direction != "" && t.Direction != "Download" {
+		return TransferEvent{}, false
+	}
+
+	directory := ""
+	if i := strings.LastIndexAny(t.Filename, `\/`); i >= 0 {
+		directory = t.Filename[:i]
+	}
+	return TransferEvent{Username: t.Username, Directory: directory, File: t.DownloadFile}, true
+}
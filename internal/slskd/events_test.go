@@ -0,0 +1,70 @@
+package slskd
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+func TestSubscribeTransfers(t *testing.T) {
+	server := httptest.NewServer(websocket.Handler(func(conn *websocket.Conn) {
+		if conn.Request().URL.Path != "/slskd/hub/transfers" {
+			t.Errorf("unexpected path: %s", conn.Request().URL.Path)
+		}
+		if conn.Request().Header.Get("X-API-Key") != "test-key" {
+			t.Error("missing or invalid API key")
+		}
+
+		var handshake string
+		if err := websocket.Message.Receive(conn, &handshake); err != nil {
+			t.Errorf("receive handshake: %v", err)
+			return
+		}
+		if handshake != "{\"protocol\":\"json\",\"version\":1}\x1e" {
+			t.Errorf("unexpected handshake: %q", handshake)
+		}
+		websocket.Message.Send(conn, "{}\x1e")
+
+		// A ping, an upload and a download in one frame
+		websocket.Message.Send(conn, "{\"type\":6}\x1e"+
+			`{"type":1,"target":"UPDATE","arguments":[{"id":"up","username":"peer","direction":"Upload","filename":"Shared\\Song.flac"}]}`+"\x1e"+
+			`{"type":1,"target":"UPDATE","arguments":[{"id":"1","username":"user","direction":"Download","filename":"Music\\Album\\01.flac","state":"Completed, Succeeded","size":10}]}`+"\x1e")
+		websocket.Message.Send(conn, "{\"type\":7}\x1e")
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", "/slskd/")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	events, err := client.SubscribeTransfers(ctx)
+	if err != nil {
+		t.Fatalf("SubscribeTransfers() error: %v", err)
+	}
+
+	var received []TransferEvent
+	for event := range events {
+		received = append(received, event)
+	}
+
+	if len(received) != 1 {
+		t.Fatalf("expected 1 download event, got %d: %+v", len(received), received)
+	}
+	event := received[0]
+	if event.Username != "user" || event.Directory != `Music\Album` || event.File.ID != "1" || !event.File.IsCompleted() {
+		t.Errorf("unexpected event: %+v", event)
+	}
+}
+
+func TestSubscribeTransfers_Unavailable(t *testing.T) {
+	server := httptest.NewServer(nil) // Every path is a 404
+	defer server.Close()
+
+	client := NewClient(server.URL, "test-key", "")
+	if _, err := client.SubscribeTransfers(context.Background()); err == nil {
+		t.Error("expected an error without a transfers hub")
+	}
+}
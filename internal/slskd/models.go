@@ -1,6 +1,11 @@
 package slskd
 
-import "time"
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"time"
+)
 
 // SearchRequest represents a search request to Slskd
 type SearchRequest struct {
@@ -13,15 +18,27 @@ type SearchRequest struct {
 
 // SearchResponse represents a search response from Slskd
 type SearchResponse struct {
-	ID         string `json:"id"`
-	State      string `json:"state"` // InProgress, Completed
-	SearchText string `json:"searchText"`
+	ID         string     `json:"id"`
+	State      string     `json:"state"` // InProgress, Completed
+	SearchText string     `json:"searchText"`
+	StartedAt  time.Time  `json:"startedAt"`
+	EndedAt    *time.Time `json:"endedAt,omitempty"` // nil while the search is running
+
+	// Counted by slskd, so a search's yield is known without fetching its responses
+	ResponseCount int `json:"responseCount"`
+	FileCount     int `json:"fileCount"`
 }
 
 // SearchResult represents a single search result from a user
 type SearchResult struct {
-	Username string       `json:"username"`
-	Files    []SearchFile `json:"files"`
+	Username          string       `json:"username"`
+	Files             []SearchFile `json:"files"`
+	LockedFiles       []SearchFile `json:"lockedFiles"` // Shared only with peers the user chose
+	UploadSpeed       int          `json:"uploadSpeed"` // bytes per second
+	QueueLength       int          `json:"queueLength"`
+	HasFreeUploadSlot *bool        `json:"hasFreeUploadSlot,omitempty"` // nil when slskd didn't report it
+	FileCount         int          `json:"fileCount"`
+	LockedFileCount   int          `json:"lockedFileCount"`
 }
 
 // SearchFile represents a file in search results
@@ -31,6 +48,32 @@ type SearchFile struct {
 	BitRate    *int   `json:"bitRate,omitempty"`
 	SampleRate *int   `json:"sampleRate,omitempty"`
 	BitDepth   *int   `json:"bitDepth,omitempty"`
+	Length     *int   `json:"length,omitempty"` // seconds
+	IsLocked   bool   `json:"isLocked"`
+}
+
+// searchResults decodes the results of a search sent either as an array, or,
+// by some slskd versions, wrapped in an object with paging details
+type searchResults []SearchResult
+
+// errNoResponses is returned for search results wrapped without a responses field
+var errNoResponses = errors.New("search results object has no responses field")
+
+func (r *searchResults) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || trimmed[0] != '{' {
+		return json.Unmarshal(data, (*[]SearchResult)(r))
+	}
+
+	var wrapped struct {
+		Responses json.RawMessage `json:"responses"`
+	}
+	if err := json.Unmarshal(data, &wrapped); err != nil {
+		return err
+	}
+	if wrapped.Responses == nil {
+		return errNoResponses
+	}
+	return json.Unmarshal(wrapped.Responses, (*[]SearchResult)(r))
 }
 
 // DirectoryRequest represents a request to browse a user's directory
@@ -52,12 +95,7 @@ type DirectoryFile struct {
 	BitRate    *int   `json:"bitRate,omitempty"`
 	SampleRate *int   `json:"sampleRate,omitempty"`
 	BitDepth   *int   `json:"bitDepth,omitempty"`
-}
-
-// EnqueueRequest represents a request to enqueue files for download
-type EnqueueRequest struct {
-	Username string   `json:"username"`
-	Files    []string `json:"files"`
+	Length     *int   `json:"length,omitempty"` // seconds
 }
 
 // EnqueueFile represents a file to enqueue for download
@@ -66,6 +104,36 @@ type EnqueueFile struct {
 	Size     int64  `json:"size"`
 }
 
+// EnqueueResult reports which of the enqueued files slskd accepted. slskd
+// versions that answer with no body leave it empty
+type EnqueueResult struct {
+	Enqueued []DownloadFile   `json:"enqueued"`
+	Failed   []EnqueueFailure `json:"failed"`
+}
+
+// EnqueueFailure is a file slskd refused to enqueue
+type EnqueueFailure struct {
+	Filename string `json:"filename"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// UnmarshalJSON accepts a bare filename as well as an object, as slskd has
+// reported failures both ways
+func (f *EnqueueFailure) UnmarshalJSON(data []byte) error {
+	var filename string
+	if err := json.Unmarshal(data, &filename); err == nil {
+		*f = EnqueueFailure{Filename: filename}
+		return nil
+	}
+	type failure EnqueueFailure
+	var decoded failure
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*f = EnqueueFailure(decoded)
+	return nil
+}
+
 // DownloadsResponse represents the downloads grouped by username
 type DownloadsResponse []UserDownloads
 
@@ -88,10 +156,43 @@ type DownloadFile struct {
 	State            string     `json:"state"` // "Phase, Status" format
 	BytesTransferred int64      `json:"bytesTransferred"`
 	Size             int64      `json:"size"`
+	Length           *int       `json:"length,omitempty"`       // seconds, when the peer reported it
+	PlaceInQueue     *int       `json:"placeInQueue,omitempty"` // position in the uploader's queue, once known
+	AverageSpeed     float64    `json:"averageSpeed"`           // bytes per second
 	StartedAt        *time.Time `json:"startedAt,omitempty"`
 	EndedAt          *time.Time `json:"endedAt,omitempty"`
 }
 
+// WishlistRequest represents a request to add a wishlist search
+type WishlistRequest struct {
+	SearchText string `json:"searchText"`
+	Filter     string `json:"filter,omitempty"`
+	Enabled    bool   `json:"enabled"`
+}
+
+// WishlistEntry represents a persistent background search in Slskd's wishlist
+type WishlistEntry struct {
+	ID         string     `json:"id"`
+	SearchText string     `json:"searchText"`
+	Filter     string     `json:"filter,omitempty"`
+	Enabled    bool       `json:"enabled"`
+	CreatedAt  *time.Time `json:"createdAt,omitempty"`
+}
+
+// ServerState is slskd's connection to the Soulseek server
+type ServerState struct {
+	Address         string `json:"address"`
+	State           string `json:"state"` // e.g. "Connected, LoggedIn"
+	IsConnected     bool   `json:"isConnected"`
+	IsLoggedIn      bool   `json:"isLoggedIn"`
+	IsTransitioning bool   `json:"isTransitioning"`
+}
+
+// Ready reports whether slskd is connected and logged in, so searches can return results
+func (s *ServerState) Ready() bool {
+	return s.IsConnected && s.IsLoggedIn
+}
+
 // VersionResponse represents Slskd version information
 type VersionResponse struct {
 	Version string `json:"version"`
@@ -0,0 +1,115 @@
+package slskd
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yuritomanek/seekarr/internal/httpclient"
+)
+
+// newCountingTLSServer starts an HTTPS server answering server state requests,
+// counting the connections opened to it
+func newCountingTLSServer(t testing.TB) (*httptest.Server, *atomic.Int32) {
+	var conns atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(ServerState{IsConnected: true, IsLoggedIn: true})
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server, &conns
+}
+
+// trustServer returns a TLS config trusting the test server's certificate
+func trustServer(server *httptest.Server) *tls.Config {
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	return &tls.Config{RootCAs: roots}
+}
+
+func TestConnectionReuse(t *testing.T) {
+	tests := []struct {
+		name       string
+		pool       *httpclient.ConnectionPool
+		concurrent int
+		maxConns   int32
+	}{
+		{name: "sequential polls share one connection", concurrent: 1, maxConns: 1},
+		{name: "concurrent requests within the default pool", concurrent: httpclient.DefaultMaxIdleConns, maxConns: httpclient.DefaultMaxIdleConns},
+		{name: "HTTP/1.1 only", pool: &httpclient.ConnectionPool{DisableHTTP2: true}, concurrent: 1, maxConns: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, conns := newCountingTLSServer(t)
+			opts := []Option{WithTLSConfig(trustServer(server))}
+			if tt.pool != nil {
+				opts = append(opts, WithConnectionPool(*tt.pool))
+			}
+			client := NewClient(server.URL, "test-key", "/", opts...)
+
+			for range 10 {
+				var wg sync.WaitGroup
+				for range tt.concurrent {
+					wg.Go(func() {
+						if _, err := client.GetServerState(context.Background()); err != nil {
+							t.Errorf("GetServerState() error: %v", err)
+						}
+					})
+				}
+				wg.Wait()
+			}
+
+			if got := conns.Load(); got > tt.maxConns {
+				t.Errorf("expected at most %d connections for %d requests, got %d", tt.maxConns, 10*tt.concurrent, got)
+			}
+		})
+	}
+}
+
+func TestWithConnectionPool(t *testing.T) {
+	c := NewClient("http://localhost:5030", "test-key", "/",
+		WithConnectionPool(httpclient.ConnectionPool{MaxIdleConns: 8, IdleConnTimeout: 30 * time.Second, DisableHTTP2: true}),
+	).(*client)
+
+	if c.transport.MaxIdleConnsPerHost != 8 {
+		t.Errorf("expected 8 idle connections per host, got %d", c.transport.MaxIdleConnsPerHost)
+	}
+	if c.transport.IdleConnTimeout != 30*time.Second {
+		t.Errorf("expected a 30s idle timeout, got %s", c.transport.IdleConnTimeout)
+	}
+	if c.transport.ForceAttemptHTTP2 || c.transport.TLSNextProto == nil {
+		t.Error("expected HTTP/2 to be disabled")
+	}
+
+	defaults := NewClient("http://localhost:5030", "test-key", "/").(*client)
+	if defaults.transport.MaxIdleConnsPerHost != httpclient.DefaultMaxIdleConns || !defaults.transport.ForceAttemptHTTP2 {
+		t.Errorf("expected the default pool, got %d idle connections, HTTP/2 %v",
+			defaults.transport.MaxIdleConnsPerHost, defaults.transport.ForceAttemptHTTP2)
+	}
+}
+
+func BenchmarkPollingOverTLS(b *testing.B) {
+	server, conns := newCountingTLSServer(b)
+	client := NewClient(server.URL, "test-key", "/", WithTLSConfig(trustServer(server)))
+
+	for b.Loop() {
+		if _, err := client.GetServerState(context.Background()); err != nil {
+			b.Fatalf("GetServerState() error: %v", err)
+		}
+	}
+	b.ReportMetric(float64(conns.Load()), "conns")
+}
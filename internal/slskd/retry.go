@@ -0,0 +1,124 @@
+package slskd
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// RetryPolicy controls how requests failing with a connection error or a 5xx
+// response are retried
+type RetryPolicy struct {
+	MaxAttempts int           // Attempts per request, including the first; 1 or less disables retries
+	BaseDelay   time.Duration // Wait before the first retry, doubled for each one after (default: 1s)
+	MaxDelay    time.Duration // Longest wait between attempts, including Retry-After (default: 30s)
+	RetryPOST   bool          // Also retry POSTs, which slskd may have acted on before failing
+}
+
+// WithRetry retries failed GET and DELETE requests, and POSTs if the policy
+// allows or the connection couldn't be made
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *client) {
+		if policy.BaseDelay <= 0 {
+			policy.BaseDelay = time.Second
+		}
+		if policy.MaxDelay <= 0 {
+			policy.MaxDelay = 30 * time.Second
+		}
+		c.retry = policy
+	}
+}
+
+// WithLogger sets the logger retries are reported to
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *client) {
+		c.logger = logger
+	}
+}
+
+// throttledAttempts is how many times a request rate-limited by slskd is sent,
+// whatever the retry policy, since slskd didn't act on it
+const throttledAttempts = 5
+
+// attempts returns how many times a request with method may be sent after
+// failing with err. A POST that never reached slskd is safe to send again
+func (p RetryPolicy) attempts(method string, err error) int {
+	if throttled(err) {
+		return max(p.MaxAttempts, throttledAttempts)
+	}
+	if p.MaxAttempts <= 1 || (method == http.MethodPost && !p.RetryPOST && !dialFailed(err)) {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// delay returns the wait before retrying after the given attempt failed with err
+func (p RetryPolicy) delay(attempt int, err error) time.Duration {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.retryAfter > 0 {
+		return min(statusErr.retryAfter, p.MaxDelay)
+	}
+	return min(p.BaseDelay<<min(attempt-1, 16), p.MaxDelay)
+}
+
+// retryable reports whether a failed request may succeed if sent again
+func retryable(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Code >= 500 || statusErr.Code == http.StatusTooManyRequests
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// dialFailed reports whether a request failed before reaching slskd
+func dialFailed(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// throttled reports whether slskd refused a request for exceeding its rate limit
+func throttled(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.Code == http.StatusTooManyRequests
+}
+
+// throttleNoticeKey holds whether throttling was already reported in a context
+type throttleNoticeKey struct{}
+
+// WithThrottleNotice returns a context in which only the first request slskd
+// rate-limits is logged as a warning, e.g. once per run. Without it the warning
+// is logged once for the client's lifetime
+func WithThrottleNotice(ctx context.Context) context.Context {
+	return context.WithValue(ctx, throttleNoticeKey{}, new(atomic.Bool))
+}
+
+// firstThrottle reports whether this is the first rate-limited request in ctx,
+// or for the client if ctx has no notice of its own
+func (c *client) firstThrottle(ctx context.Context) bool {
+	warned, ok := ctx.Value(throttleNoticeKey{}).(*atomic.Bool)
+	if !ok {
+		warned = &c.throttleWarned
+	}
+	return !warned.Swap(true)
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return max(time.Until(at), 0)
+	}
+	return 0
+}
@@ -0,0 +1,202 @@
+package slskd
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDoRequest_Retry(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		status    int // Returned by every attempt but the last
+		retryPOST bool
+		wantCalls int32
+		wantErr   bool
+	}{
+		{name: "GET retried on 503", method: "GET", status: http.StatusServiceUnavailable, wantCalls: 3},
+		{name: "DELETE retried on 502", method: "DELETE", status: http.StatusBadGateway, wantCalls: 3},
+		{name: "GET not retried on 404", method: "GET", status: http.StatusNotFound, wantCalls: 1, wantErr: true},
+		{name: "POST not retried by default", method: "POST", status: http.StatusServiceUnavailable, wantCalls: 1, wantErr: true},
+		{name: "POST retried when allowed", method: "POST", status: http.StatusServiceUnavailable, retryPOST: true, wantCalls: 3},
+		{name: "POST retried on 429", method: "POST", status: http.StatusTooManyRequests, wantCalls: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if calls.Add(1) < 3 {
+					w.WriteHeader(tt.status)
+					return
+				}
+				w.Write([]byte("{}"))
+			}))
+			defer server.Close()
+
+			c := NewClient(server.URL, "key", "", WithRetry(RetryPolicy{
+				MaxAttempts: 3,
+				BaseDelay:   time.Millisecond,
+				RetryPOST:   tt.retryPOST,
+			})).(*client)
+
+			err := c.doRequest(context.Background(), tt.method, "/api/v0/test", nil, nil, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("doRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("expected %d calls, got %d", tt.wantCalls, got)
+			}
+		})
+	}
+}
+
+func TestDoRequest_RetryConnectionError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	c := NewClient(url, "key", "", WithRetry(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond})).(*client)
+	if _, err := c.GetDownloads(context.Background()); err == nil {
+		t.Error("expected an error once every attempt failed")
+	}
+	err := c.doRequest(context.Background(), "POST", "/api/v0/searches", nil, nil, nil)
+	if err == nil {
+		t.Fatal("expected an error once every attempt failed")
+	}
+	if got := c.retry.attempts("POST", err); got != 2 {
+		t.Errorf("expected a refused POST to be retried, got %d attempts", got)
+	}
+}
+
+func TestDoRequest_RetryStopsOnCancel(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "key", "", WithRetry(RetryPolicy{MaxAttempts: 3, MaxDelay: time.Minute}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := client.GetDownloads(ctx); err == nil {
+		t.Fatal("expected an error")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the Retry-After wait to end with the context, took %v", elapsed)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected 1 call, got %d", got)
+	}
+}
+
+func TestDoRequest_Throttled(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Every other request is rate limited
+		if calls.Add(1)%2 == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	// Retries are disabled, but a rate-limited request is always retried
+	client := NewClient(server.URL, "key", "", WithLogger(logger), WithRetry(RetryPolicy{MaxAttempts: 1, BaseDelay: time.Millisecond}))
+
+	run := WithThrottleNotice(context.Background())
+	for range 3 {
+		if _, err := client.GetDownloads(run); err != nil {
+			t.Fatalf("GetDownloads() error: %v", err)
+		}
+	}
+	if got := calls.Load(); got != 6 {
+		t.Errorf("expected 6 calls, got %d", got)
+	}
+	if got := strings.Count(logs.String(), "rate limiting"); got != 1 {
+		t.Errorf("expected one rate limiting warning per run, got %d:\n%s", got, logs.String())
+	}
+
+	// A new run warns again
+	if _, err := client.GetDownloads(WithThrottleNotice(context.Background())); err != nil {
+		t.Fatalf("GetDownloads() error: %v", err)
+	}
+	if got := strings.Count(logs.String(), "rate limiting"); got != 2 {
+		t.Errorf("expected a second warning for the next run, got %d", got)
+	}
+}
+
+func TestDoRequest_ThrottledGivesUp(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "key", "", WithRetry(RetryPolicy{BaseDelay: time.Millisecond}))
+	if _, err := client.GetDownloads(context.Background()); err == nil {
+		t.Fatal("expected an error once every attempt was rate limited")
+	}
+	if got := calls.Load(); got != throttledAttempts {
+		t.Errorf("expected %d calls, got %d", throttledAttempts, got)
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+	tests := []struct {
+		name    string
+		attempt int
+		err     error
+		want    time.Duration
+	}{
+		{name: "first retry", attempt: 1, err: &StatusError{Code: 503}, want: time.Second},
+		{name: "doubles", attempt: 3, err: &StatusError{Code: 503}, want: 4 * time.Second},
+		{name: "capped", attempt: 10, err: &StatusError{Code: 503}, want: 5 * time.Second},
+		{name: "retry after", attempt: 1, err: &StatusError{Code: 503, retryAfter: 3 * time.Second}, want: 3 * time.Second},
+		{name: "retry after capped", attempt: 1, err: &StatusError{Code: 503, retryAfter: time.Hour}, want: 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.delay(tt.attempt, tt.err); got != tt.want {
+				t.Errorf("delay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{header: "", want: 0},
+		{header: "120", want: 2 * time.Minute},
+		{header: "-5", want: 0},
+		{header: "soon", want: 0},
+		{header: "Mon, 02 Jan 2006 15:04:05 GMT", want: 0}, // In the past
+	}
+
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.header); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package slskd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// sessionRefresh is how long before its expiry a session token is renewed
+const sessionRefresh = time.Minute
+
+// session logs in to slskd with a username and password, for instances
+// without API keys
+type session struct {
+	username string
+	password string
+
+	token   string
+	expires time.Time // Zero if slskd didn't say
+}
+
+// sessionRequest is the body of a login
+type sessionRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// sessionResponse is the token slskd returns for a login
+type sessionResponse struct {
+	Token   string `json:"token"`
+	Expires int64  `json:"expires"` // Unix seconds
+}
+
+// WithCredentials logs in with a username and password and authenticates
+// requests with the session token, when the client has no API key
+func WithCredentials(username, password string) Option {
+	return func(c *client) {
+		c.session = &session{username: username, password: password}
+	}
+}
+
+// authorize sets the header slskd authenticates a request with, logging in
+// first if there's no current session. It returns the session token used, if any
+func (c *client) authorize(ctx context.Context, header http.Header) (string, error) {
+	if c.apiKey != "" || c.session == nil {
+		header.Set("X-API-Key", c.apiKey)
+		return "", nil
+	}
+
+	c.sessionMu.Lock()
+	defer c.sessionMu.Unlock()
+	s := c.session
+	if s.token == "" || (!s.expires.IsZero() && time.Until(s.expires) < sessionRefresh) {
+		if err := c.login(ctx); err != nil {
+			return "", err
+		}
+	}
+	header.Set("Authorization", "Bearer "+s.token)
+	return s.token, nil
+}
+
+// expireSession forgets a token slskd no longer accepts, unless another
+// request already replaced it
+func (c *client) expireSession(token string) {
+	c.sessionMu.Lock()
+	defer c.sessionMu.Unlock()
+	if c.session.token == token {
+		c.session.token = ""
+	}
+}
+
+// login starts a session, replacing any token held. The caller holds sessionMu
+func (c *client) login(ctx context.Context) error {
+	body, err := json.Marshal(sessionRequest{Username: c.session.username, Password: c.session.password})
+	if err != nil {
+		return fmt.Errorf("log in: marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.resolve("/api/v0/session"), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("log in: create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("log in: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("log in: %w", &StatusError{Code: resp.StatusCode, Body: string(bodyBytes)})
+	}
+
+	var response sessionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("log in: decode response: %w", err)
+	}
+	if response.Token == "" {
+		return fmt.Errorf("log in: no token in response")
+	}
+
+	c.session.token = response.Token
+	c.session.expires = time.Time{}
+	if response.Expires > 0 {
+		c.session.expires = time.Unix(response.Expires, 0)
+	}
+	c.logger.Debug("logged in to slskd", "username", c.session.username, "expires", c.session.expires)
+	return nil
+}
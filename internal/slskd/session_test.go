@@ -0,0 +1,136 @@
+package slskd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// sessionServer issues numbered tokens and accepts only the latest one
+type sessionServer struct {
+	mu       sync.Mutex
+	password string
+	logins   int
+	expires  time.Time
+	valid    string
+}
+
+func (s *sessionServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.URL.Path == "/api/v0/session" {
+		var req sessionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Username != "admin" || req.Password != s.password {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		s.logins++
+		s.valid = fmt.Sprintf("token-%d", s.logins)
+		json.NewEncoder(w).Encode(map[string]any{"token": s.valid, "tokenType": "Bearer", "expires": s.expires.Unix()})
+		return
+	}
+
+	if r.Header.Get("X-API-Key") != "" || r.Header.Get("Authorization") != "Bearer "+s.valid {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	json.NewEncoder(w).Encode(ServerState{IsConnected: true, IsLoggedIn: true})
+}
+
+// expire makes the server refuse the current token
+func (s *sessionServer) expire() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.valid = "expired"
+}
+
+func TestWithCredentials(t *testing.T) {
+	t.Run("logs in once and reuses the token", func(t *testing.T) {
+		srv := &sessionServer{password: "secret", expires: time.Now().Add(time.Hour)}
+		server := httptest.NewServer(srv)
+		defer server.Close()
+
+		client := NewClient(server.URL, "", "/", WithCredentials("admin", "secret"))
+		for range 3 {
+			if _, err := client.GetServerState(context.Background()); err != nil {
+				t.Fatalf("GetServerState() error: %v", err)
+			}
+		}
+		if srv.logins != 1 {
+			t.Errorf("expected 1 login, got %d", srv.logins)
+		}
+	})
+
+	t.Run("logs in again when the token is refused", func(t *testing.T) {
+		srv := &sessionServer{password: "secret", expires: time.Now().Add(time.Hour)}
+		server := httptest.NewServer(srv)
+		defer server.Close()
+
+		client := NewClient(server.URL, "", "/", WithCredentials("admin", "secret"))
+		if _, err := client.GetServerState(context.Background()); err != nil {
+			t.Fatalf("GetServerState() error: %v", err)
+		}
+		srv.expire()
+		if _, err := client.GetServerState(context.Background()); err != nil {
+			t.Fatalf("GetServerState() after expiry error: %v", err)
+		}
+		if srv.logins != 2 {
+			t.Errorf("expected 2 logins, got %d", srv.logins)
+		}
+	})
+
+	t.Run("renews a token about to expire", func(t *testing.T) {
+		srv := &sessionServer{password: "secret", expires: time.Now().Add(sessionRefresh / 2)}
+		server := httptest.NewServer(srv)
+		defer server.Close()
+
+		client := NewClient(server.URL, "", "/", WithCredentials("admin", "secret"))
+		for range 2 {
+			if _, err := client.GetServerState(context.Background()); err != nil {
+				t.Fatalf("GetServerState() error: %v", err)
+			}
+		}
+		if srv.logins != 2 {
+			t.Errorf("expected 2 logins, got %d", srv.logins)
+		}
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		srv := &sessionServer{password: "secret"}
+		server := httptest.NewServer(srv)
+		defer server.Close()
+
+		client := NewClient(server.URL, "", "/", WithCredentials("admin", "wrong"))
+		_, err := client.GetServerState(context.Background())
+		if !errors.Is(err, ErrUnauthorized) {
+			t.Errorf("expected ErrUnauthorized, got %v", err)
+		}
+	})
+
+	t.Run("API key takes precedence", func(t *testing.T) {
+		var gotKey, gotAuth string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/api/v0/session" {
+				t.Error("unexpected login with an API key set")
+			}
+			gotKey, gotAuth = r.Header.Get("X-API-Key"), r.Header.Get("Authorization")
+			json.NewEncoder(w).Encode(ServerState{})
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, "test-key", "/", WithCredentials("admin", "secret"))
+		if _, err := client.GetServerState(context.Background()); err != nil {
+			t.Fatalf("GetServerState() error: %v", err)
+		}
+		if gotKey != "test-key" || gotAuth != "" {
+			t.Errorf("expected only the API key, got key %q, authorization %q", gotKey, gotAuth)
+		}
+	})
+}
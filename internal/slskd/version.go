@@ -0,0 +1,53 @@
+package slskd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MinimumVersion is the oldest slskd release whose API seekarr supports
+const MinimumVersion = "0.18.0"
+
+// CheckVersion returns an error if an slskd version reported by GetVersion is
+// older than MinimumVersion. Versions that can't be parsed, such as
+// development builds, are assumed to be supported
+func CheckVersion(version string) error {
+	v, ok := parseVersion(version)
+	if !ok {
+		return nil
+	}
+	minimum, _ := parseVersion(MinimumVersion)
+	for i := range v {
+		if v[i] != minimum[i] {
+			if v[i] < minimum[i] {
+				return fmt.Errorf("slskd %s is not supported, upgrade to %s or later", version, MinimumVersion)
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// parseVersion reads the major, minor and patch numbers of a version such as
+// "0.21.3", "v0.21.3.0" or "0.21.3+65a2c1". Development builds report 0.0.0
+// and aren't parsed
+func parseVersion(version string) ([3]int, bool) {
+	var v [3]int
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(version, "+-"); i >= 0 {
+		version = version[:i]
+	}
+	parts := strings.Split(version, ".")
+	if len(parts) < 3 {
+		return v, false
+	}
+	for i := range v {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil || n < 0 {
+			return v, false
+		}
+		v[i] = n
+	}
+	return v, v != [3]int{}
+}
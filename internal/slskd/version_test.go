@@ -0,0 +1,29 @@
+package slskd
+
+import "testing"
+
+func TestCheckVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		wantErr bool
+	}{
+		{version: "0.21.3", wantErr: false},
+		{version: "0.18.0", wantErr: false},
+		{version: "v1.0.0", wantErr: false},
+		{version: "0.22.1.0", wantErr: false},
+		{version: "0.19.5+65a2c1f", wantErr: false},
+		{version: "0.17.9", wantErr: true},
+		{version: "0.9.12", wantErr: true},
+		{version: "0.0.0", wantErr: false},   // Development build
+		{version: "unknown", wantErr: false}, // Unparseable
+		{version: "", wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			if err := CheckVersion(tt.version); (err != nil) != tt.wantErr {
+				t.Errorf("CheckVersion(%q) error = %v, wantErr %v", tt.version, err, tt.wantErr)
+			}
+		})
+	}
+}
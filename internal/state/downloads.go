@@ -0,0 +1,168 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Downloads tracks albums queued in slskd that have not been organized yet
+// It survives restarts so downloads that finish while seekarr is down are still imported
+type Downloads struct {
+	mu       sync.RWMutex
+	entries  map[string]*PendingDownload
+	filePath string
+}
+
+// PendingDownload is an album whose files were enqueued in slskd
+type PendingDownload struct {
+	AlbumID      int             `json:"album_id"`
+	ReleaseID    int             `json:"release_id,omitempty"`
+	ArtistID     int             `json:"artist_id,omitempty"`
+	ArtistName   string          `json:"artist_name"`
+	ArtistFolder string          `json:"artist_folder,omitempty"`
+	AlbumName    string          `json:"album_name"`
+	FolderName   string          `json:"folder_name"`
+	MediumCount  int             `json:"medium_count"`
+	Quality      string          `json:"quality,omitempty"`
+	Sources      []PendingSource `json:"sources"`
+	Tracks       []PendingTrack  `json:"tracks"`
+	Companions   []PendingTrack  `json:"companions,omitempty"`
+	AlbumDir     string          `json:"album_dir,omitempty"` // Set once organized, while the import is outstanding
+	QueuedAt     time.Time       `json:"queued_at"`
+}
+
+// PendingSource is a remote directory a pending album is downloaded from
+type PendingSource struct {
+	Username  string `json:"username"`
+	Directory string `json:"directory"`
+}
+
+// PendingTrack is a file expected in a pending album's download folder
+type PendingTrack struct {
+	Filename     string `json:"filename"`
+	MediumNumber int    `json:"medium_number"`
+	Folder       string `json:"folder,omitempty"`
+}
+
+// NewDownloads creates a new pending download tracker
+func NewDownloads(filePath string) (*Downloads, error) {
+	d := &Downloads{
+		entries:  make(map[string]*PendingDownload),
+		filePath: filePath,
+	}
+
+	// Load existing entries if they exist
+	if err := d.Load(); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("load pending downloads: %w", err)
+	}
+
+	return d, nil
+}
+
+// Load reads the pending downloads from file
+func (d *Downloads) Load() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	data, err := os.ReadFile(d.filePath)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, &d.entries); err != nil {
+		return fmt.Errorf("unmarshal pending downloads: %w", err)
+	}
+
+	return nil
+}
+
+// Save writes the pending downloads to file atomically
+func (d *Downloads) Save() error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	// Create parent directory if needed
+	dir := filepath.Dir(d.filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(d.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal pending downloads: %w", err)
+	}
+
+	tmpFile, err := os.CreateTemp(dir, ".pending_downloads.*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("write pending downloads: %w", err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("close temp file: %w", err)
+	}
+
+	// Atomically rename
+	if err := os.Rename(tmpPath, d.filePath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("rename temp file: %w", err)
+	}
+
+	return nil
+}
+
+// Add records a pending download, replacing any earlier one for the album
+func (d *Downloads) Add(download PendingDownload) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if download.QueuedAt.IsZero() {
+		download.QueuedAt = time.Now()
+	}
+	d.entries[strconv.Itoa(download.AlbumID)] = &download
+}
+
+// Remove forgets the pending download for an album
+func (d *Downloads) Remove(albumID int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.entries, strconv.Itoa(albumID))
+}
+
+// Pending returns a snapshot of all pending downloads, oldest first
+func (d *Downloads) Pending() []PendingDownload {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	pending := make([]PendingDownload, 0, len(d.entries))
+	for _, entry := range d.entries {
+		pending = append(pending, *entry)
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		if !pending[i].QueuedAt.Equal(pending[j].QueuedAt) {
+			return pending[i].QueuedAt.Before(pending[j].QueuedAt)
+		}
+		return pending[i].AlbumID < pending[j].AlbumID
+	})
+	return pending
+}
+
+// Count returns the number of pending downloads
+func (d *Downloads) Count() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return len(d.entries)
+}
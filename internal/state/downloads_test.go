@@ -0,0 +1,67 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDownloads_AddRemove(t *testing.T) {
+	downloads, err := NewDownloads(filepath.Join(t.TempDir(), "pending_downloads.json"))
+	if err != nil {
+		t.Fatalf("NewDownloads() error: %v", err)
+	}
+
+	start := time.Now()
+	downloads.Add(PendingDownload{AlbumID: 2, QueuedAt: start.Add(-time.Minute)})
+	downloads.Add(PendingDownload{AlbumID: 1, QueuedAt: start})
+	downloads.Add(PendingDownload{AlbumID: 3})
+
+	pending := downloads.Pending()
+	if len(pending) != 3 {
+		t.Fatalf("expected 3 pending downloads, got %d", len(pending))
+	}
+	if pending[0].AlbumID != 2 || pending[1].AlbumID != 1 {
+		t.Errorf("expected oldest first, got %d, %d", pending[0].AlbumID, pending[1].AlbumID)
+	}
+	if pending[2].QueuedAt.IsZero() {
+		t.Error("QueuedAt should default to now")
+	}
+
+	downloads.Remove(2)
+	if downloads.Count() != 2 {
+		t.Errorf("expected 2 pending downloads after Remove(), got %d", downloads.Count())
+	}
+}
+
+func TestDownloads_SaveAndLoad(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "pending_downloads.json")
+
+	downloads, err := NewDownloads(filePath)
+	if err != nil {
+		t.Fatalf("NewDownloads() error: %v", err)
+	}
+	downloads.Add(PendingDownload{
+		AlbumID:    7,
+		AlbumName:  "Album",
+		FolderName: "Album",
+		Sources:    []PendingSource{{Username: "user", Directory: "Music/Album"}},
+		Tracks:     []PendingTrack{{Filename: "01 - Song.flac", MediumNumber: 1}},
+	})
+	if err := downloads.Save(); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	loaded, err := NewDownloads(filePath)
+	if err != nil {
+		t.Fatalf("NewDownloads() reload error: %v", err)
+	}
+	pending := loaded.Pending()
+	if len(pending) != 1 {
+		t.Fatalf("expected 1 pending download after reload, got %d", len(pending))
+	}
+	got := pending[0]
+	if got.AlbumName != "Album" || len(got.Sources) != 1 || got.Sources[0].Username != "user" || len(got.Tracks) != 1 {
+		t.Errorf("pending download not restored: %+v", got)
+	}
+}
@@ -0,0 +1,95 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LastRun remembers when the previous run finished, across restarts
+type LastRun struct {
+	mu       sync.Mutex
+	filePath string
+	at       time.Time
+}
+
+// NewLastRun creates a last run record stored at the given file path
+func NewLastRun(filePath string) (*LastRun, error) {
+	lr := &LastRun{filePath: filePath}
+
+	if err := lr.Load(); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("load last run: %w", err)
+	}
+
+	return lr, nil
+}
+
+// Load reads the last run's finish time from file
+func (lr *LastRun) Load() error {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	data, err := os.ReadFile(lr.filePath)
+	if err != nil {
+		return err
+	}
+
+	content := strings.TrimSpace(string(data))
+	if content == "" {
+		return nil
+	}
+
+	at, err := time.Parse(time.RFC3339, content)
+	if err != nil {
+		return fmt.Errorf("parse last run time: %w", err)
+	}
+
+	lr.at = at
+	return nil
+}
+
+// At returns when the last run finished, zero if no run was recorded
+func (lr *LastRun) At() time.Time {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	return lr.at
+}
+
+// Record saves the finish time of a run, replacing the file atomically
+func (lr *LastRun) Record(at time.Time) error {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	dir := filepath.Dir(lr.filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	tmpFile, err := os.CreateTemp(dir, ".last_run.*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+
+	if _, err := tmpFile.WriteString(at.UTC().Format(time.RFC3339)); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("write last run time: %w", err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("close temp file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, lr.filePath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("rename temp file: %w", err)
+	}
+
+	lr.at = at
+	return nil
+}
@@ -0,0 +1,44 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLastRun(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "last_run")
+
+	lr, err := NewLastRun(filePath)
+	if err != nil {
+		t.Fatalf("NewLastRun() error: %v", err)
+	}
+	if !lr.At().IsZero() {
+		t.Errorf("expected no last run, got %v", lr.At())
+	}
+
+	started := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+	if err := lr.Record(started); err != nil {
+		t.Fatalf("Record() error: %v", err)
+	}
+
+	reloaded, err := NewLastRun(filePath)
+	if err != nil {
+		t.Fatalf("NewLastRun() error: %v", err)
+	}
+	if !reloaded.At().Equal(started) {
+		t.Errorf("expected %v after reload, got %v", started, reloaded.At())
+	}
+}
+
+func TestLastRun_Corrupt(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "last_run")
+	if err := os.WriteFile(filePath, []byte("yesterday"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	if _, err := NewLastRun(filePath); err == nil {
+		t.Error("expected an error for an unparseable time")
+	}
+}
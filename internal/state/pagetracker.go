@@ -76,6 +76,15 @@ func (pt *PageTracker) Next(totalPages int) error {
 	return pt.saveAtomic()
 }
 
+// Reset moves back to page 1 and saves it atomically
+func (pt *PageTracker) Reset() error {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
+	pt.current = 1
+	return pt.saveAtomic()
+}
+
 // saveAtomic writes the page number to a temporary file and atomically renames it
 // This prevents corruption if the process crashes during write
 func (pt *PageTracker) saveAtomic() error {
@@ -0,0 +1,210 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Reputation remembers how downloads from each Soulseek user have gone
+type Reputation struct {
+	mu       sync.RWMutex
+	entries  map[string]*UserReputation
+	filePath string
+}
+
+// UserReputation tracks download outcomes for one user
+type UserReputation struct {
+	Username            string    `json:"username"`
+	Successes           int       `json:"successes"`
+	Failures            int       `json:"failures"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	ErroredTransfers    int       `json:"errored_transfers"` // Files that failed for good since the last successful download
+	LastQuality         string    `json:"last_quality,omitempty"`
+	LastSeen            time.Time `json:"last_seen"`
+	IgnoredUntil        time.Time `json:"ignored_until,omitzero"`
+}
+
+// NewReputation creates a new user reputation store
+func NewReputation(filePath string) (*Reputation, error) {
+	r := &Reputation{
+		entries:  make(map[string]*UserReputation),
+		filePath: filePath,
+	}
+
+	// Load existing reputation if it exists
+	if err := r.Load(); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("load user reputation: %w", err)
+	}
+
+	return r, nil
+}
+
+// Load reads the user reputation from file
+func (r *Reputation) Load() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := os.ReadFile(r.filePath)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, &r.entries); err != nil {
+		return fmt.Errorf("unmarshal user reputation: %w", err)
+	}
+
+	return nil
+}
+
+// Save writes the user reputation to file atomically
+func (r *Reputation) Save() error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Create parent directory if needed
+	dir := filepath.Dir(r.filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(r.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal user reputation: %w", err)
+	}
+
+	// Write to temporary file
+	tmpFile, err := os.CreateTemp(dir, ".user_reputation.*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("write user reputation: %w", err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("close temp file: %w", err)
+	}
+
+	// Atomically rename
+	if err := os.Rename(tmpPath, r.filePath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("rename temp file: %w", err)
+	}
+
+	return nil
+}
+
+// RecordSuccess records a user delivering every file of a download
+func (r *Reputation) RecordSuccess(username, quality string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := r.entry(username)
+	entry.Successes++
+	entry.ConsecutiveFailures = 0
+	entry.ErroredTransfers = 0
+	if quality != "" {
+		entry.LastQuality = quality
+	}
+	entry.LastSeen = time.Now()
+}
+
+// RecordFailure records a user whose files failed for good and returns how
+// many downloads from them have failed in a row
+func (r *Reputation) RecordFailure(username string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := r.entry(username)
+	entry.Failures++
+	entry.ConsecutiveFailures++
+	entry.LastSeen = time.Now()
+	return entry.ConsecutiveFailures
+}
+
+// RecordErroredTransfers records files from a user that failed for good and
+// returns how many have failed since their last successful download
+func (r *Reputation) RecordErroredTransfers(username string, count int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := r.entry(username)
+	entry.ErroredTransfers += count
+	entry.LastSeen = time.Now()
+	return entry.ErroredTransfers
+}
+
+// Ignore marks a user to be left out of searches until the given time
+func (r *Reputation) Ignore(username string, until time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entry(username).IgnoredUntil = until
+}
+
+// IsIgnored reports whether a user is ignored at the given time
+func (r *Reputation) IsIgnored(username string, now time.Time) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, exists := r.entries[username]
+	return exists && entry.IgnoredUntil.After(now)
+}
+
+// ExpireIgnores lifts ignores that ended by the given time and returns the users
+// concerned. Their errored transfers are forgotten so they start afresh
+func (r *Reputation) ExpireIgnores(now time.Time) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var expired []string
+	for username, entry := range r.entries {
+		if entry.IgnoredUntil.IsZero() || entry.IgnoredUntil.After(now) {
+			continue
+		}
+		entry.IgnoredUntil = time.Time{}
+		entry.ErroredTransfers = 0
+		expired = append(expired, username)
+	}
+	sort.Strings(expired)
+	return expired
+}
+
+// entry returns the entry for a user, creating it if needed. Callers hold mu
+func (r *Reputation) entry(username string) *UserReputation {
+	entry, exists := r.entries[username]
+	if !exists {
+		entry = &UserReputation{Username: username}
+		r.entries[username] = entry
+	}
+	return entry
+}
+
+// Get returns a copy of a user's reputation, or nil if nothing is known about them
+func (r *Reputation) Get(username string) *UserReputation {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, exists := r.entries[username]
+	if !exists {
+		return nil
+	}
+	copied := *entry
+	return &copied
+}
+
+// Count returns the number of users with a recorded reputation
+func (r *Reputation) Count() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.entries)
+}
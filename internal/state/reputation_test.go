@@ -0,0 +1,118 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReputation_RecordOutcomes(t *testing.T) {
+	r, err := NewReputation(filepath.Join(t.TempDir(), "user_reputation.json"))
+	if err != nil {
+		t.Fatalf("NewReputation() error: %v", err)
+	}
+
+	if r.Get("peer") != nil {
+		t.Fatal("expected no reputation for an unknown user")
+	}
+
+	r.RecordSuccess("peer", "flac")
+	if got := r.RecordFailure("peer"); got != 1 {
+		t.Errorf("expected 1 consecutive failure, got %d", got)
+	}
+	if got := r.RecordFailure("peer"); got != 2 {
+		t.Errorf("expected 2 consecutive failures, got %d", got)
+	}
+
+	entry := r.Get("peer")
+	if entry.Successes != 1 || entry.Failures != 2 || entry.ConsecutiveFailures != 2 {
+		t.Errorf("unexpected reputation: %+v", entry)
+	}
+	if entry.LastQuality != "flac" {
+		t.Errorf("expected last quality flac, got %q", entry.LastQuality)
+	}
+
+	r.RecordSuccess("peer", "")
+	entry = r.Get("peer")
+	if entry.ConsecutiveFailures != 0 {
+		t.Errorf("expected a success to reset consecutive failures, got %d", entry.ConsecutiveFailures)
+	}
+	if entry.LastQuality != "flac" {
+		t.Errorf("expected unknown quality to keep the last one, got %q", entry.LastQuality)
+	}
+
+	// Get returns a copy
+	entry.Successes = 100
+	if r.Get("peer").Successes != 2 {
+		t.Error("modifying the returned entry changed the store")
+	}
+}
+
+func TestReputation_SaveAndLoad(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "user_reputation.json")
+
+	r, err := NewReputation(filePath)
+	if err != nil {
+		t.Fatalf("NewReputation() error: %v", err)
+	}
+	r.RecordSuccess("good", "mp3 320")
+	r.RecordFailure("bad")
+
+	if err := r.Save(); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	loaded, err := NewReputation(filePath)
+	if err != nil {
+		t.Fatalf("NewReputation() error: %v", err)
+	}
+	if loaded.Count() != 2 {
+		t.Fatalf("expected 2 users, got %d", loaded.Count())
+	}
+	if entry := loaded.Get("good"); entry.Successes != 1 || entry.LastQuality != "mp3 320" {
+		t.Errorf("unexpected reputation for good: %+v", entry)
+	}
+	if entry := loaded.Get("bad"); entry.Failures != 1 || entry.ConsecutiveFailures != 1 {
+		t.Errorf("unexpected reputation for bad: %+v", entry)
+	}
+}
+
+func TestReputation_IgnoreAndExpire(t *testing.T) {
+	r, err := NewReputation(filepath.Join(t.TempDir(), "user_reputation.json"))
+	if err != nil {
+		t.Fatalf("NewReputation() error: %v", err)
+	}
+	now := time.Now()
+
+	if got := r.RecordErroredTransfers("peer", 2); got != 2 {
+		t.Errorf("expected 2 errored transfers, got %d", got)
+	}
+	if got := r.RecordErroredTransfers("peer", 1); got != 3 {
+		t.Errorf("expected 3 errored transfers, got %d", got)
+	}
+	r.RecordSuccess("other", "")
+	r.RecordErroredTransfers("other", 1)
+	r.RecordSuccess("other", "")
+	if entry := r.Get("other"); entry.ErroredTransfers != 0 {
+		t.Errorf("expected a success to reset errored transfers, got %d", entry.ErroredTransfers)
+	}
+
+	r.Ignore("peer", now.Add(time.Hour))
+	if !r.IsIgnored("peer", now) || r.IsIgnored("other", now) || r.IsIgnored("unknown", now) {
+		t.Error("expected only peer to be ignored")
+	}
+	if expired := r.ExpireIgnores(now); len(expired) != 0 {
+		t.Errorf("expected no expired ignores, got %v", expired)
+	}
+
+	later := now.Add(2 * time.Hour)
+	if r.IsIgnored("peer", later) {
+		t.Error("expected the ignore to have run out")
+	}
+	if expired := r.ExpireIgnores(later); len(expired) != 1 || expired[0] != "peer" {
+		t.Errorf("expected peer's ignore to expire, got %v", expired)
+	}
+	if entry := r.Get("peer"); !entry.IgnoredUntil.IsZero() || entry.ErroredTransfers != 0 {
+		t.Errorf("expected the ignore and errored transfers cleared, got %+v", entry)
+	}
+}
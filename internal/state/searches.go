@@ -0,0 +1,144 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Searches tracks the slskd searches started by seekarr
+// Only searches recorded here are ever deleted, so searches made in slskd's UI are left alone
+type Searches struct {
+	mu       sync.RWMutex
+	entries  map[string]*SearchRecord
+	filePath string
+}
+
+// SearchRecord is a search seekarr started in slskd
+type SearchRecord struct {
+	ID         string    `json:"id"`
+	SearchText string    `json:"search_text"`
+	StartedAt  time.Time `json:"started_at"`
+}
+
+// NewSearches creates a new search tracker
+func NewSearches(filePath string) (*Searches, error) {
+	s := &Searches{
+		entries:  make(map[string]*SearchRecord),
+		filePath: filePath,
+	}
+
+	// Load existing records if they exist
+	if err := s.Load(); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("load searches: %w", err)
+	}
+
+	return s, nil
+}
+
+// Load reads the search records from file
+func (s *Searches) Load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.filePath)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, &s.entries); err != nil {
+		return fmt.Errorf("unmarshal searches: %w", err)
+	}
+
+	return nil
+}
+
+// Save writes the search records to file atomically
+func (s *Searches) Save() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Create parent directory if needed
+	dir := filepath.Dir(s.filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal searches: %w", err)
+	}
+
+	tmpFile, err := os.CreateTemp(dir, ".searches.*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("write searches: %w", err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("close temp file: %w", err)
+	}
+
+	// Atomically rename
+	if err := os.Rename(tmpPath, s.filePath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("rename temp file: %w", err)
+	}
+
+	return nil
+}
+
+// Add records a search seekarr started
+func (s *Searches) Add(id, searchText string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[id] = &SearchRecord{
+		ID:         id,
+		SearchText: searchText,
+		StartedAt:  time.Now(),
+	}
+}
+
+// Remove forgets a search
+func (s *Searches) Remove(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, id)
+}
+
+// Get returns the record for a search, or nil if seekarr didn't start it
+func (s *Searches) Get(id string) *SearchRecord {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.entries[id]
+}
+
+// Records returns a snapshot of all tracked searches
+func (s *Searches) Records() []SearchRecord {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	records := make([]SearchRecord, 0, len(s.entries))
+	for _, r := range s.entries {
+		records = append(records, *r)
+	}
+	return records
+}
+
+// Count returns the number of tracked searches
+func (s *Searches) Count() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.entries)
+}
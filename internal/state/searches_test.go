@@ -0,0 +1,56 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSearches_AddGetRemove(t *testing.T) {
+	searches, err := NewSearches(filepath.Join(t.TempDir(), "searches.json"))
+	if err != nil {
+		t.Fatalf("NewSearches() error: %v", err)
+	}
+
+	searches.Add("search-1", "Artist Album")
+
+	record := searches.Get("search-1")
+	if record == nil {
+		t.Fatal("Get() returned nil after Add()")
+	}
+	if record.SearchText != "Artist Album" {
+		t.Errorf("expected search text 'Artist Album', got %q", record.SearchText)
+	}
+	if record.StartedAt.IsZero() {
+		t.Error("StartedAt should be set")
+	}
+
+	searches.Remove("search-1")
+	if searches.Get("search-1") != nil {
+		t.Error("Get() should return nil after Remove()")
+	}
+}
+
+func TestSearches_SaveAndLoad(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "searches.json")
+
+	searches, err := NewSearches(filePath)
+	if err != nil {
+		t.Fatalf("NewSearches() error: %v", err)
+	}
+	searches.Add("search-1", "Artist One Album")
+	searches.Add("search-2", "Artist Two Album")
+	if err := searches.Save(); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	loaded, err := NewSearches(filePath)
+	if err != nil {
+		t.Fatalf("NewSearches() error: %v", err)
+	}
+	if loaded.Count() != 2 {
+		t.Errorf("expected 2 searches after reload, got %d", loaded.Count())
+	}
+	if record := loaded.Get("search-2"); record == nil || record.SearchText != "Artist Two Album" {
+		t.Errorf("unexpected record after reload: %+v", record)
+	}
+}
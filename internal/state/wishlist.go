@@ -0,0 +1,159 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Wishlist tracks the slskd wishlist entries created by seekarr
+// The entries are also tagged in slskd, which is checked before one is deleted
+type Wishlist struct {
+	mu       sync.RWMutex
+	entries  map[string]*WishlistRecord
+	filePath string
+}
+
+// WishlistRecord links a Lidarr album to the slskd wishlist entry registered for it
+type WishlistRecord struct {
+	AlbumID    int       `json:"album_id"`
+	EntryID    string    `json:"entry_id"`
+	SearchText string    `json:"search_text"`
+	CreatedAt  time.Time `json:"created_at"`
+	// Unmonitored is set once seekarr itself unmonitored the album, which
+	// takes it off the wanted list without it having been found
+	Unmonitored bool `json:"unmonitored,omitempty"`
+}
+
+// NewWishlist creates a new wishlist tracker
+func NewWishlist(filePath string) (*Wishlist, error) {
+	w := &Wishlist{
+		entries:  make(map[string]*WishlistRecord),
+		filePath: filePath,
+	}
+
+	// Load existing records if they exist
+	if err := w.Load(); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("load wishlist: %w", err)
+	}
+
+	return w, nil
+}
+
+// Load reads the wishlist records from file
+func (w *Wishlist) Load() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	data, err := os.ReadFile(w.filePath)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, &w.entries); err != nil {
+		return fmt.Errorf("unmarshal wishlist: %w", err)
+	}
+
+	return nil
+}
+
+// Save writes the wishlist records to file atomically
+func (w *Wishlist) Save() error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	// Create parent directory if needed
+	dir := filepath.Dir(w.filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(w.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal wishlist: %w", err)
+	}
+
+	tmpFile, err := os.CreateTemp(dir, ".wishlist.*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("write wishlist: %w", err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("close temp file: %w", err)
+	}
+
+	// Atomically rename
+	if err := os.Rename(tmpPath, w.filePath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("rename temp file: %w", err)
+	}
+
+	return nil
+}
+
+// Add records a wishlist entry created for an album
+func (w *Wishlist) Add(albumID int, entryID, searchText string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.entries[strconv.Itoa(albumID)] = &WishlistRecord{
+		AlbumID:    albumID,
+		EntryID:    entryID,
+		SearchText: searchText,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// MarkUnmonitored records that seekarr unmonitored an album with a wishlist entry
+func (w *Wishlist) MarkUnmonitored(albumID int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if record, ok := w.entries[strconv.Itoa(albumID)]; ok {
+		record.Unmonitored = true
+	}
+}
+
+// Remove forgets the wishlist entry recorded for an album
+func (w *Wishlist) Remove(albumID int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.entries, strconv.Itoa(albumID))
+}
+
+// Get returns the wishlist record for an album, or nil if none exists
+func (w *Wishlist) Get(albumID int) *WishlistRecord {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.entries[strconv.Itoa(albumID)]
+}
+
+// Records returns a snapshot of all tracked wishlist records
+func (w *Wishlist) Records() []WishlistRecord {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	records := make([]WishlistRecord, 0, len(w.entries))
+	for _, r := range w.entries {
+		records = append(records, *r)
+	}
+	return records
+}
+
+// Count returns the number of tracked wishlist entries
+func (w *Wishlist) Count() int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return len(w.entries)
+}
@@ -0,0 +1,95 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestNewWishlist(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "wishlist.json")
+
+	wl, err := NewWishlist(filePath)
+	if err != nil {
+		t.Fatalf("NewWishlist() error: %v", err)
+	}
+
+	if wl.Count() != 0 {
+		t.Errorf("new wishlist should be empty, got %d entries", wl.Count())
+	}
+}
+
+func TestWishlist_AddGetRemove(t *testing.T) {
+	tmpDir := t.TempDir()
+	wl, err := NewWishlist(filepath.Join(tmpDir, "wishlist.json"))
+	if err != nil {
+		t.Fatalf("NewWishlist() error: %v", err)
+	}
+
+	wl.Add(123, "entry-1", "Artist Album")
+
+	record := wl.Get(123)
+	if record == nil {
+		t.Fatal("Get() returned nil after Add()")
+	}
+	if record.EntryID != "entry-1" {
+		t.Errorf("expected entry ID 'entry-1', got %q", record.EntryID)
+	}
+	if record.SearchText != "Artist Album" {
+		t.Errorf("expected search text 'Artist Album', got %q", record.SearchText)
+	}
+	if record.CreatedAt.IsZero() {
+		t.Error("CreatedAt should be set")
+	}
+	if record.Unmonitored {
+		t.Error("Unmonitored should start unset")
+	}
+
+	wl.MarkUnmonitored(123)
+	wl.MarkUnmonitored(456) // No record, nothing to mark
+	if !wl.Get(123).Unmonitored {
+		t.Error("expected record marked unmonitored")
+	}
+	if wl.Get(456) != nil {
+		t.Error("MarkUnmonitored() should not add a record")
+	}
+
+	wl.Remove(123)
+	if wl.Get(123) != nil {
+		t.Error("Get() should return nil after Remove()")
+	}
+}
+
+func TestWishlist_SaveAndLoad(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "wishlist.json")
+
+	wl, err := NewWishlist(filePath)
+	if err != nil {
+		t.Fatalf("NewWishlist() error: %v", err)
+	}
+
+	wl.Add(1, "entry-1", "Artist One Album")
+	wl.Add(2, "entry-2", "Artist Two Album")
+
+	if err := wl.Save(); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	loaded, err := NewWishlist(filePath)
+	if err != nil {
+		t.Fatalf("NewWishlist() reload error: %v", err)
+	}
+
+	if loaded.Count() != 2 {
+		t.Fatalf("expected 2 records after reload, got %d", loaded.Count())
+	}
+
+	if r := loaded.Get(2); r == nil || r.EntryID != "entry-2" {
+		t.Errorf("expected record for album 2 with entry-2, got %+v", r)
+	}
+
+	if len(loaded.Records()) != 2 {
+		t.Errorf("expected 2 records from Records(), got %d", len(loaded.Records()))
+	}
+}